@@ -0,0 +1,257 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// activityLimit is the number of transactions shown in the activity feed.
+const activityLimit = 15
+
+// activityEntry is a transaction touching an application, for the activity
+// feed of the details view.
+type activityEntry struct {
+	Hash     string
+	Height   int64
+	Time     time.Time
+	Code     int
+	Reason   string // Failure reason when Code is set
+	Messages []map[string]interface{}
+}
+
+type activityLoadedMsg struct {
+	address string
+	entries []activityEntry
+	err     error
+}
+
+// activityQueries are the tx event queries of the transactions touching an
+// application: those it signed (stake, unstake, delegations, transfers, sends)
+// and the bank sends it received.
+func activityQueries(address string) []string {
+	return []string{
+		fmt.Sprintf("message.sender='%s'", address),
+		fmt.Sprintf("transfer.recipient='%s'", address),
+	}
+}
+
+// QueryActivity returns the latest transactions matching an event query,
+// newest first.
+func QueryActivity(query, rpcEndpoint, pocketdHome, networkName string) ([]activityEntry, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"q", "txs", "--query", query, "--order_by", "desc", "--limit", fmt.Sprint(activityLimit),
+		"-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Txs []struct {
+			TxHash    string  `json:"txhash"`
+			Height    flexInt `json:"height"`
+			Timestamp string  `json:"timestamp"`
+			Code      int     `json:"code"`
+			Codespace string  `json:"codespace"`
+			RawLog    string  `json:"raw_log"`
+			Tx        struct {
+				Body struct {
+					Messages []map[string]interface{} `json:"messages"`
+				} `json:"body"`
+			} `json:"tx"`
+		} `json:"txs"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	var entries []activityEntry
+	for _, tx := range response.Txs {
+		entry := activityEntry{
+			Hash:     tx.TxHash,
+			Height:   int64(tx.Height),
+			Code:     tx.Code,
+			Messages: tx.Tx.Body.Messages,
+		}
+		entry.Time, _ = time.Parse(time.RFC3339, tx.Timestamp)
+		if tx.Code != 0 {
+			entry.Reason = txFailureReason(uint32(tx.Code), tx.Codespace, tx.RawLog)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// loadActivityCmd queries the transactions touching address and merges them
+// into one feed, newest first.
+func (m model) loadActivityCmd(address string) tea.Cmd {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil
+	}
+	networkName, pocketdHome := m.currentNetwork, m.config.pocketdHome(m.currentNetwork)
+	return func() tea.Msg {
+		seen := make(map[string]bool)
+		var entries []activityEntry
+		for _, query := range activityQueries(address) {
+			var found []activityEntry
+			err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+				var err error
+				found, err = QueryActivity(query, endpoint, pocketdHome, networkName)
+				return err
+			})
+			if err != nil {
+				return activityLoadedMsg{address: address, err: err}
+			}
+			for _, entry := range found {
+				if !seen[entry.Hash] {
+					seen[entry.Hash] = true
+					entries = append(entries, entry)
+				}
+			}
+		}
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].Height > entries[j].Height })
+		if len(entries) > activityLimit {
+			entries = entries[:activityLimit]
+		}
+		return activityLoadedMsg{address: address, entries: entries}
+	}
+}
+
+// refreshActivity queries the activity feed of the application in the
+// details view.
+func (m *model) refreshActivity() tea.Cmd {
+	m.activity = nil
+	m.activityErr = nil
+	cmd := m.loadActivityCmd(m.selectedAppAddress)
+	if cmd == nil {
+		return nil
+	}
+	m.activityLoading = true
+	return tea.Batch(cmd, m.startSpinner())
+}
+
+// describeCoins renders a coin or a list of coins of a message, upokt in the
+// display unit.
+func (m model) describeCoins(value interface{}) string {
+	var coins []txCoin
+	data, _ := json.Marshal(value)
+	if json.Unmarshal(data, &coins) != nil {
+		var coin txCoin
+		if json.Unmarshal(data, &coin) != nil {
+			return "?"
+		}
+		coins = []txCoin{coin}
+	}
+	return m.formatCoins(coins)
+}
+
+// describeMessage summarizes a message from the point of view of the
+// application at address.
+func (m model) describeMessage(address string, message map[string]interface{}) string {
+	field := func(key string) string {
+		value, _ := message[key].(string)
+		return value
+	}
+	short := func(other string) string {
+		if label := m.appLabel(other); label != "" {
+			return label
+		}
+		if m.config != nil && other == m.config.Config.Networks[m.currentNetwork].Bank {
+			return "the bank"
+		}
+		return TruncateAddress(other, 20)
+	}
+	msgType, _ := message["@type"].(string)
+	if dot := strings.LastIndex(msgType, "."); dot >= 0 {
+		msgType = msgType[dot+1:]
+	}
+
+	switch msgType {
+	case "MsgSend":
+		if field("to_address") == address {
+			return fmt.Sprintf("received %s from %s", m.describeCoins(message["amount"]), short(field("from_address")))
+		}
+		return fmt.Sprintf("sent %s to %s", m.describeCoins(message["amount"]), short(field("to_address")))
+	case "MsgMultiSend":
+		outputs, _ := message["outputs"].([]interface{})
+		for _, output := range outputs {
+			if out, ok := output.(map[string]interface{}); ok && out["address"] == address {
+				return fmt.Sprintf("received %s in a multi-send", m.describeCoins(out["coins"]))
+			}
+		}
+		return fmt.Sprintf("multi-send to %d addresses", len(outputs))
+	case "MsgStakeApplication":
+		var services []string
+		configs, _ := message["services"].([]interface{})
+		for _, config := range configs {
+			if service, ok := config.(map[string]interface{}); ok {
+				if id, ok := service["service_id"].(string); ok {
+					services = append(services, id)
+				}
+			}
+		}
+		return fmt.Sprintf("staked %s for %s", m.describeCoins(message["stake"]), strings.Join(services, ", "))
+	case "MsgUnstakeApplication":
+		return "started unstaking"
+	case "MsgDelegateToGateway":
+		return "delegated to " + short(field("gateway_address"))
+	case "MsgUndelegateFromGateway":
+		return "undelegated from " + short(field("gateway_address"))
+	case "MsgTransferApplication":
+		return "transfer to " + short(field("destination_address"))
+	case "MsgGrantAllowance":
+		return "fee grant from " + short(field("granter"))
+	case "":
+		return "unknown message"
+	}
+	return msgType
+}
+
+// renderActivity renders the activity feed of the application shown in the
+// details view.
+func (m model) renderActivity() string {
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")) // Soft grey-green
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")) // Red for errors
+
+	var lines []string
+	for _, entry := range m.activity {
+		var summaries []string
+		for _, message := range entry.Messages {
+			summaries = append(summaries, m.describeMessage(m.selectedAppAddress, message))
+		}
+		when := fmt.Sprintf("#%d", entry.Height)
+		if !entry.Time.IsZero() {
+			when = entry.Time.Local().Format("2006-01-02 15:04") + " " + when
+		}
+		line := fmt.Sprintf("  %-28s %s  %s", when, strings.Join(summaries, "; "), TruncateAddress(entry.Hash, 12))
+		if entry.Code != 0 {
+			lines = append(lines, errorStyle.Render(truncateToWidth(line+" ❌ "+entry.Reason, m.width-4)))
+			continue
+		}
+		lines = append(lines, textStyle.Render(truncateToWidth(line, m.width-4)))
+	}
+
+	switch {
+	case m.activityErr != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  Failed to query activity: %v", m.activityErr)))
+	case m.activityLoading:
+		lines = append(lines, textStyle.Render("  "+m.spinner()+" Querying recent transactions..."))
+	case len(lines) == 0:
+		lines = append(lines, textStyle.Render("  No transactions indexed for this address"))
+	}
+	return strings.Join(lines, "\n")
+}
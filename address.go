@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// addressPrefix is the bech32 human-readable part of Pocket account
+// addresses.
+const addressPrefix = "pokt"
+
+// addressBytes is the length of the public key hash an account address
+// encodes.
+const addressBytes = 20
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// validateAddress checks that address is a bech32 Pocket account address,
+// so a typo is reported before pocketd is invoked and a fee is spent.
+func validateAddress(address string) error {
+	if address == "" {
+		return fmt.Errorf("address is empty")
+	}
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return fmt.Errorf("invalid address %s: mixes upper and lower case", address)
+	}
+	lower := strings.ToLower(address)
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 {
+		return fmt.Errorf("invalid address %s: not a bech32 address (expected %s1...)", address, addressPrefix)
+	}
+	if prefix := lower[:separator]; prefix != addressPrefix {
+		return fmt.Errorf("invalid address %s: prefix %q, expected %q", address, prefix, addressPrefix)
+	}
+
+	data := make([]byte, 0, len(lower)-separator-1)
+	for i := separator + 1; i < len(lower); i++ {
+		value := strings.IndexByte(bech32Charset, lower[i])
+		if value < 0 {
+			return fmt.Errorf("invalid address %s: invalid character %q at position %d", address, lower[i], i+1)
+		}
+		data = append(data, byte(value))
+	}
+	if len(data) < 6 {
+		return fmt.Errorf("invalid address %s: too short", address)
+	}
+	if bech32Polymod(append(bech32ExpandPrefix(addressPrefix), data...)) != 1 {
+		return fmt.Errorf("invalid address %s: checksum mismatch, check for a typo", address)
+	}
+
+	// Drop the checksum and regroup the 5-bit words into bytes
+	words := data[:len(data)-6]
+	if length := len(words) * 5 / 8; length != addressBytes {
+		return fmt.Errorf("invalid address %s: encodes %d bytes, expected %d", address, length, addressBytes)
+	}
+	if padding := len(words) * 5 % 8; padding >= 5 || words[len(words)-1]&(1<<padding-1) != 0 {
+		return fmt.Errorf("invalid address %s: invalid padding", address)
+	}
+	return nil
+}
+
+// bech32ExpandPrefix expands the human-readable part for the checksum.
+func bech32ExpandPrefix(prefix string) []byte {
+	expanded := make([]byte, 0, len(prefix)*2+1)
+	for i := 0; i < len(prefix); i++ {
+		expanded = append(expanded, prefix[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(prefix); i++ {
+		expanded = append(expanded, prefix[i]&31)
+	}
+	return expanded
+}
+
+// bech32Polymod computes the bech32 checksum of values, which is 1 for a
+// valid address.
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	checksum := uint32(1)
+	for _, value := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(value)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+	return checksum
+}
+
+// validateConfigAddresses checks the bank, gateway and application addresses
+// of every network.
+func validateConfigAddresses(config *Config) error {
+	for name, network := range config.Config.Networks {
+		if network.Bank != "" {
+			if err := validateAddress(network.Bank); err != nil {
+				return fmt.Errorf("network %s: bank: %v", name, err)
+			}
+		}
+		for _, gateway := range network.Gateways {
+			if err := validateAddress(gateway); err != nil {
+				return fmt.Errorf("network %s: gateway: %v", name, err)
+			}
+		}
+		for _, address := range network.Applications {
+			if err := validateAddress(address); err != nil {
+				return fmt.Errorf("network %s: application: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
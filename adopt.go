@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// unmanagedMarker flags applications delegated to a configured gateway but
+// missing from the config, which bulk commands skip.
+const unmanagedMarker = "❔"
+
+// isUnmanaged reports whether app is delegated to one of the network's
+// gateways without being listed in its applications.
+func (m model) isUnmanaged(app Application) bool {
+	if m.config == nil {
+		return false
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || slices.Contains(network.Applications, app.Address) {
+		return false
+	}
+	for _, gateway := range app.DelegateeGateways {
+		if slices.Contains(network.Gateways, gateway) {
+			return true
+		}
+	}
+	return false
+}
+
+// unmanagedApplications returns the addresses of the unmanaged applications
+// delegated to the current gateway.
+func (m model) unmanagedApplications() []string {
+	var addresses []string
+	for _, app := range m.applications {
+		if m.isUnmanaged(app) && slices.Contains(app.DelegateeGateways, m.currentGateway) {
+			addresses = append(addresses, app.Address)
+		}
+	}
+	return addresses
+}
+
+// handleAdoptCommand adds unmanaged applications to the config: "adopt"
+// takes every one delegated to the current gateway, "adopt <address>" one.
+func (m model) handleAdoptCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) > 2 {
+		m.err = fmt.Errorf("usage: adopt [<address>]")
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	addresses := m.unmanagedApplications()
+	if len(parts) == 2 {
+		if err := validateAddress(parts[1]); err != nil {
+			m.err = err
+			return m, nil
+		}
+		if slices.Contains(m.config.Config.Networks[m.currentNetwork].Applications, parts[1]) {
+			return m, m.notify(toastInfo, fmt.Sprintf("%s is already configured", TruncateAddress(parts[1], 20)))
+		}
+		addresses = []string{parts[1]}
+	}
+	if len(addresses) == 0 {
+		return m, m.notify(toastInfo, "No unmanaged applications are delegated to this gateway")
+	}
+	return m, m.adoptApplications(addresses)
+}
+
+// adoptApplications adds addresses to the applications of the current
+// gateway in the config file.
+func (m model) adoptApplications(addresses []string) tea.Cmd {
+	entries := make([]importEntry, len(addresses))
+	for i, address := range addresses {
+		entries[i] = importEntry{Address: address}
+	}
+	summary := fmt.Sprintf("Adopted %s into %s", TruncateAddress(addresses[0], 20), m.currentNetwork)
+	if len(addresses) > 1 {
+		summary = fmt.Sprintf("Adopted %d applications into %s", len(addresses), m.currentNetwork)
+	}
+	logger.Info("adopting applications", "network", m.currentNetwork, "gateway", m.currentGateway, "addresses", addresses)
+	network, gateway := m.currentNetwork, m.currentGateway
+	return saveConfigCmd(summary, func(root *yaml.Node) error {
+		return importApplications(root, network, gateway, entries)
+	})
+}
+
+// adoptSelected adopts the application under the cursor if it is unmanaged.
+func (m model) adoptSelected() (model, tea.Cmd) {
+	if m.cursor >= len(m.applications) {
+		return m, nil
+	}
+	app := m.applications[m.cursor]
+	if !m.isUnmanaged(app) {
+		return m, m.notify(toastInfo, fmt.Sprintf("%s is already configured", TruncateAddress(app.Address, 20)))
+	}
+	return m, m.adoptApplications([]string{app.Address})
+}
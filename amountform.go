@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// amountForm asks for the amount of an upstake ("u") or fund ("f") of the
+// selected application, showing its effect before submission.
+type amountForm struct {
+	action  string // "u" or "f"
+	address string
+	input   string
+}
+
+// openAmountForm starts an amount form for action on the selected row.
+func (m model) openAmountForm(action string) model {
+	if len(m.applications) == 0 || m.cursor >= len(m.applications) {
+		return m
+	}
+	m.amountForm = &amountForm{action: action, address: m.applications[m.cursor].Address}
+	m.state = stateAmountForm
+	return m
+}
+
+// formApplication returns the application of the open form.
+func (m model) formApplication() (Application, bool) {
+	for _, app := range m.applications {
+		if app.Address == m.amountForm.address {
+			return app, true
+		}
+	}
+	return Application{}, false
+}
+
+// suggestedAmount returns the amount that brings the application of the form
+// to its target, and the target it reaches, or 0 when it is already there.
+func (m model) suggestedAmount(app Application) (int64, string) {
+	network := m.config.Config.Networks[m.currentNetwork]
+	targets := network.targetsFor(app.Address)
+	if m.amountForm.action == "u" {
+		warning, _ := m.stakeThresholds()
+		target, reason := warning, "warning threshold"
+		if targets.Stake > target {
+			target, reason = targets.Stake, "target stake"
+		}
+		if minStake := m.minStake(); minStake > target {
+			target, reason = minStake, "minimum stake"
+		}
+		if stake := stakeUpokt(app); stake < target {
+			return target - stake, reason
+		}
+		return 0, ""
+	}
+
+	target, reason := targets.MinBalance, "target balance"
+	if network.AutoFund.MinBalance > 0 && network.AutoFund.topUpTo() > target {
+		target, reason = network.AutoFund.topUpTo(), "auto-fund top-up"
+	}
+	if app.BalanceUpokt < target {
+		return target - app.BalanceUpokt, reason
+	}
+	return 0, ""
+}
+
+func (m model) updateAmountForm(msg tea.KeyMsg) (model, tea.Cmd) {
+	form := m.amountForm
+	switch msg.String() {
+	case "esc":
+		m.amountForm = nil
+		m.state = stateTable
+	case "tab":
+		if app, ok := m.formApplication(); ok {
+			if amount, _ := m.suggestedAmount(app); amount > 0 {
+				form.input = fmt.Sprintf("%dupokt", amount)
+			}
+		}
+	case "enter":
+		if strings.TrimSpace(form.input) == "" {
+			m.err = fmt.Errorf("enter an amount, or press Tab for the suggested one")
+			return m, nil
+		}
+		// Submit through command mode, so roles, guards and --memo apply
+		m.amountForm = nil
+		m.commandInput = fmt.Sprintf("%s %s %s", form.action, form.address, strings.TrimSpace(form.input))
+		return m.updateCommand(msg)
+	case "backspace":
+		if len(form.input) > 0 {
+			form.input = form.input[:len(form.input)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			form.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// renderAmountForm shows the amount form with the current state of the
+// application and the result of the typed amount.
+func (m model) renderAmountForm() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("230")).
+		Bold(true).
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 2)
+
+	form := m.amountForm
+	if form == nil || m.config == nil {
+		return ""
+	}
+	app, ok := m.formApplication()
+	if !ok {
+		return textStyle.Render("Application no longer loaded. Press ESC to return.")
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	unit := " " + m.unitLabel()
+	row := func(label, value string) string {
+		return textStyle.Render(fmt.Sprintf("%-18s %s", label, value))
+	}
+
+	title := "⬆️  UPSTAKE APPLICATION"
+	if form.action == "f" {
+		title = "💸 FUND APPLICATION"
+	}
+	content := []string{headerStyle.Render(title), ""}
+	address := app.Address
+	if label := m.appLabel(app.Address); label != "" {
+		address += " (" + label + ")"
+	}
+	content = append(content, row("Application", address))
+	content = append(content, row("Stake", m.formatAmount(stakeUpokt(app))+unit))
+	content = append(content, row("Balance", m.formatAmount(app.BalanceUpokt)+unit))
+	bank := int64(math.Round(m.bankBalance * upoktPerPOKT))
+	content = append(content, row("Bank balance", m.formatAmount(bank)+unit))
+	if suggested, reason := m.suggestedAmount(app); suggested > 0 {
+		content = append(content, row("Suggested", fmt.Sprintf("%s%s to reach the %s (Tab)", m.formatAmount(suggested), unit, reason)))
+	} else {
+		content = append(content, row("Suggested", "-"))
+	}
+
+	content = append(content, "")
+	content = append(content, inputStyle.Render("Amount: "+form.input+"█"))
+	content = append(content, textStyle.Render("upokt unless a unit is given: 1500pokt, 2.5kpokt, 250000000upokt"))
+	content = append(content, "")
+
+	amount, err := parseAmount(strings.Fields(form.input + " ")[0])
+	switch {
+	case strings.TrimSpace(form.input) == "":
+	case err != nil:
+		content = append(content, errorStyle.Render(err.Error()))
+	case form.action == "u":
+		// Upstakes are signed by the application, which pays the fee unless
+		// the bank grants it
+		appFee := network.appFeeUpokt()
+		content = append(content, row("Fee", m.formatFee(network, network.feeAmount())+feePayer(appFee)))
+		content = append(content, row("Resulting stake", m.formatAmount(stakeUpokt(app)+amount)+unit))
+		balanceAfter := app.BalanceUpokt - amount - appFee
+		content = append(content, row("Resulting balance", m.formatAmount(balanceAfter)+unit))
+		content = append(content, row("Resulting bank", m.formatAmount(bank-(network.txFeeUpokt()-appFee))+unit))
+		if balanceAfter < 0 {
+			content = append(content, warningStyle.Render("The application balance cannot cover the amount plus fee; fund it first"))
+		}
+		if err := m.checkMinStake(stakeUpokt(app), amount); err != nil {
+			content = append(content, warningStyle.Render(err.Error()))
+		}
+	default:
+		content = append(content, row("Fee", m.formatFee(network, network.feeAmount())+" (paid by the bank)"))
+		content = append(content, row("Resulting balance", m.formatAmount(app.BalanceUpokt+amount)+unit))
+		bankAfter := bank - amount - network.txFeeUpokt()
+		content = append(content, row("Resulting bank", m.formatAmount(bankAfter)+unit))
+		if bankAfter < 0 {
+			content = append(content, warningStyle.Render("The bank balance cannot cover the amount plus fee"))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("Enter to submit • Tab for the suggested amount • ESC to cancel"))
+	return strings.Join(content, "\n")
+}
+
+// feePayer describes who pays an application fee of appFee.
+func feePayer(appFee int64) string {
+	if appFee == 0 {
+		return " (paid by the bank's fee grant)"
+	}
+	return " (paid by the application)"
+}
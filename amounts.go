@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// amountEntry is one line of an amounts file.
+type amountEntry struct {
+	address string
+	amount  int64
+}
+
+// readAmountsFile parses a CSV file of "address,amount" lines, amounts in
+// upokt. Blank lines, # comments and an "address,amount" header are ignored.
+func readAmountsFile(path string) ([]amountEntry, error) {
+	path = expandHome(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	var entries []amountEntry
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != 2 {
+			return nil, fmt.Errorf("line %d: expected address,amount", line)
+		}
+		address, amountStr := strings.TrimSpace(record[0]), strings.TrimSpace(record[1])
+		if len(entries) == 0 && strings.EqualFold(address, "address") {
+			continue // Header
+		}
+		amount, err := parseAmount(amountStr)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if seen[address] {
+			return nil, fmt.Errorf("line %d: duplicate address %s", line, address)
+		}
+		seen[address] = true
+		entries = append(entries, amountEntry{address: address, amount: amount})
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no amounts in %s", path)
+	}
+	return entries, nil
+}
+
+// handleAmountsFileCommand runs "fa[!] @<file>" or "ua[!] @<file>", which fund
+// or upstake each listed application by its own amount. The transactions run
+// one at a time like a reconcile.
+func (m model) handleAmountsFileCommand(cmd, action string, includeUnstaking bool) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	entries, err := readAmountsFile(strings.TrimPrefix(parts[1], "@"))
+	if err != nil {
+		m.err = fmt.Errorf("failed to read amounts: %v", err)
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	if m.reconcileCh != nil {
+		return m, m.notify(toastWarning, "Another reconcile is still running")
+	}
+
+	loaded := make(map[string]*Application, len(m.applications))
+	for i := range m.applications {
+		loaded[m.applications[i].Address] = &m.applications[i]
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	plan := &stakePlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now(),
+		Operator:         m.operator(),
+		Network:          m.currentNetwork,
+		Bank:             network.Bank,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	var skipped, short, belowMin []string
+	for _, entry := range entries {
+		app := loaded[entry.address]
+		if app == nil {
+			m.err = fmt.Errorf("application not found: %s", entry.address)
+			return m, nil
+		}
+		item := planItem{Action: action, Address: entry.address, AmountUpokt: entry.amount}
+		switch action {
+		case planFund:
+			item.CurrentUpokt = app.BalanceUpokt
+			plan.BankRequiredUpokt += entry.amount + network.txFeeUpokt()
+		case planUpstake:
+			if isUnstaking(*app) && !includeUnstaking {
+				skipped = append(skipped, entry.address)
+				continue
+			}
+			item.ServiceID = app.ServiceID
+			item.CurrentUpokt = stakeUpokt(*app)
+			if app.BalanceUpokt < entry.amount+network.appFeeUpokt() {
+				short = append(short, TruncateAddress(entry.address, 13))
+			}
+			if m.checkMinStake(item.CurrentUpokt, entry.amount) != nil {
+				belowMin = append(belowMin, TruncateAddress(entry.address, 13))
+			}
+		}
+		item.TargetUpokt = item.CurrentUpokt + entry.amount
+		plan.Items = append(plan.Items, item)
+	}
+
+	var notices []tea.Cmd
+	if len(skipped) > 0 {
+		notices = append(notices, m.notify(toastInfo, fmt.Sprintf("Skipping %d unstaking apps (--include-unstaking to upstake them): %s", len(skipped), describeSkipped(skipped))))
+	}
+	if len(plan.Items) == 0 {
+		return m, tea.Batch(append(notices, m.notify(toastInfo, "Nothing to submit"))...)
+	}
+
+	// Refuse batches that would fail midway unless overridden with "!"
+	if !strings.HasSuffix(parts[0], "!") {
+		var refusal error
+		switch {
+		case m.balancesReady() != nil:
+			refusal = m.balancesReady()
+		case action == planFund && plan.BankRequiredUpokt > plan.BankBalanceUpokt:
+			refusal = fmt.Errorf("insufficient bank balance: need %s %s (amounts + fees), have %s",
+				m.formatAmount(plan.BankRequiredUpokt), m.unitLabel(), m.formatAmount(plan.BankBalanceUpokt))
+		case len(belowMin) > 0:
+			refusal = fmt.Errorf("%d apps would stay below the minimum stake of %s %s: %s",
+				len(belowMin), m.formatAmount(m.minStake()), m.unitLabel(), strings.Join(belowMin, ", "))
+		case len(short) > 0:
+			refusal = fmt.Errorf("%d apps cannot cover their amount plus fee: %s", len(short), strings.Join(short, ", "))
+		}
+		if refusal != nil {
+			return m, m.notify(toastError, fmt.Sprintf("%s refused: %v (use %s! to override)", parts[0], refusal, parts[0]))
+		}
+	}
+
+	m, run := m.startPlan(plan, cmd)
+	return m, tea.Batch(append(notices, run)...)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appTxSubmittedMsg reports a broadcast delegate or unstake of one
+// application.
+type appTxSubmittedMsg struct {
+	txID   int
+	kind   string
+	txHash string
+}
+
+// findApplication returns the loaded application at address.
+func (m model) findApplication(address string) (Application, bool) {
+	for _, app := range m.applications {
+		if app.Address == address {
+			return app, true
+		}
+	}
+	return Application{}, false
+}
+
+// handleDelegateCommand delegates an application to a gateway: "delegate
+// <address> [gateway]", the current gateway by default.
+func (m model) handleDelegateCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) < 2 || len(parts) > 3 {
+		m.err = fmt.Errorf("usage: delegate <address> [gateway]")
+		return m, nil
+	}
+	address, gateway := parts[1], m.currentGateway
+	if len(parts) == 3 {
+		gateway = parts[2]
+	}
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := validateAddress(gateway); err != nil {
+		m.err = fmt.Errorf("gateway: %v", err)
+		return m, nil
+	}
+	if app, ok := m.findApplication(address); ok {
+		if slices.Contains(app.DelegateeGateways, gateway) {
+			m.err = fmt.Errorf("application %s is already delegated to %s", TruncateAddress(address, 13), TruncateAddress(gateway, 13))
+			return m, nil
+		}
+		if isUnstaking(app) {
+			m.err = fmt.Errorf("application %s is unstaking", TruncateAddress(address, 13))
+			return m, nil
+		}
+	}
+
+	config, networkName := m.config, m.currentNetwork
+	txID := m.trackTx("delegate", cmd, []string{address}, 0)
+	return m, m.submitTracked(cmd, m.executeAppTx(txID, "delegate", address, func() (string, error) {
+		return delegateToGateway(address, gateway, config, networkName)
+	}), txID)
+}
+
+// handleUnstakeCommand starts the unstake of an application: "unstake
+// <address>". Its stake returns to its balance after the unbonding period.
+func (m model) handleUnstakeCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: unstake <address>")
+		return m, nil
+	}
+	address := parts[1]
+	app, ok := m.findApplication(address)
+	switch {
+	case !ok:
+		m.err = fmt.Errorf("application not found: %s", address)
+		return m, nil
+	case isUnstaking(app):
+		m.err = fmt.Errorf("application %s is already unstaking", TruncateAddress(address, 13))
+		return m, nil
+	}
+
+	config, networkName := m.config, m.currentNetwork
+	txID := m.trackTx("unstake", cmd, []string{address}, 0)
+	return m, m.submitTracked(cmd, m.executeAppTx(txID, "unstake", address, func() (string, error) {
+		return unstakeApplication(address, config, networkName)
+	}), txID)
+}
+
+// executeAppTx broadcasts the transaction of the application address sent by
+// send.
+func (m model) executeAppTx(txID int, kind, address string, send func() (string, error)) tea.Cmd {
+	return func() tea.Msg {
+		txHash, err := send()
+		if err != nil {
+			return newTxFailedMsg(txID, kind, []string{address}, 0, err)
+		}
+		return appTxSubmittedMsg{txID: txID, kind: kind, txHash: txHash}
+	}
+}
+
+// prefillAppCommand opens command mode with the command of action prefilled
+// for the application shown in the details view.
+func (m model) prefillAppCommand(action string) model {
+	m.state = stateCommand
+	m.commandInput = fmt.Sprintf("%s %s ", action, m.selectedAppAddress)
+	if action == "unstake" {
+		m.commandInput = strings.TrimSpace(m.commandInput)
+	}
+	return m
+}
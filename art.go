@@ -0,0 +1,55 @@
+package main
+
+import (
+	_ "embed"
+	"os"
+	"strings"
+)
+
+// The default art is built into the binary, so gasms runs from any directory.
+var (
+	//go:embed art/splash.txt
+	defaultSplash string
+	//go:embed art/logo.txt
+	defaultLogo string
+)
+
+// readArt returns the content of the art file at path, or fallback if path is
+// empty or unreadable.
+func readArt(path, fallback string) string {
+	if path == "" {
+		return fallback
+	}
+	path = expandHome(path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		logger.Warn("failed to read art file; using the default", "path", path, "error", err)
+		return fallback
+	}
+	return string(content)
+}
+
+// splashArt returns the splash screen shown while loading, from path or the
+// built-in art.
+func splashArt(path string) string {
+	if art := readArt(path, defaultSplash); strings.TrimSpace(art) != "" {
+		return art
+	}
+	return "GASMS\nLoading..."
+}
+
+// logoLine returns the first line of the logo shown in the header, from path
+// or the built-in logo.
+func logoLine(path string) string {
+	lines := strings.Split(readArt(path, defaultLogo), "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
+		return strings.TrimSpace(lines[0])
+	}
+	return "GASMS"
+}
+
+// applyArt loads the splash and logo files set in the config.
+func (m *model) applyArt() {
+	m.splashArt = splashArt(m.config.Config.Splash)
+	m.logoLine = logoLine(m.config.Config.Logo)
+}
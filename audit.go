@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// auditRecord is one entry of the append-only audit trail of mutating
+// operations. A transaction produces a record when it is broadcast (or
+// rejected) and another once its final result is known.
+type auditRecord struct {
+	Time        time.Time `json:"time"`
+	Operator    string    `json:"operator"`
+	Network     string    `json:"network"`
+	Bank        string    `json:"bank,omitempty"`
+	Command     string    `json:"command"`
+	Kind        string    `json:"kind"`
+	Addresses   []string  `json:"addresses"`
+	AmountUpokt int64     `json:"amount_upokt"`
+	FeeUpokt    int64     `json:"fee_upokt,omitempty"`
+	TxHash      string    `json:"tx_hash,omitempty"`
+	Height      int64     `json:"height,omitempty"`
+	Result      string    `json:"result"`
+	Error       string    `json:"error,omitempty"`
+}
+
+func auditPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "audit.jsonl"), nil
+}
+
+// auditOperator identifies who ran GASMS as user@host.
+func auditOperator() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return operatorAt(name)
+}
+
+// operatorAt identifies name as user@host.
+func operatorAt(name string) string {
+	host, err := os.Hostname()
+	if err != nil {
+		return name
+	}
+	return name + "@" + host
+}
+
+// appendAudit appends a record to the audit trail. Existing records are never
+// rewritten.
+func appendAudit(record auditRecord) error {
+	path, err := auditPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// auditTx records the current state of a tracked transaction.
+func auditTx(tx trackedTx) {
+	record := auditRecord{
+		Time:        time.Now(),
+		Operator:    tx.operator,
+		Network:     tx.network,
+		Bank:        tx.bank,
+		Command:     tx.command,
+		Kind:        tx.kind,
+		Addresses:   tx.addresses,
+		AmountUpokt: tx.amount,
+		FeeUpokt:    tx.fee,
+		TxHash:      tx.hash,
+		Height:      tx.height,
+		Result:      string(tx.status),
+		Error:       tx.err,
+	}
+	if err := appendAudit(record); err != nil {
+		logger.Error("failed to write audit record", "tx_id", tx.id, "error", err)
+	}
+}
+
+// loadAudit reads every record of the audit trail, oldest first.
+func loadAudit() ([]auditRecord, error) {
+	path, err := auditPath()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []auditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record auditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue // Skip a torn final line rather than hiding the rest
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// handleAuditCommand handles "audit" and "audit export <file>".
+func (m model) handleAuditCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	records, err := loadAudit()
+	if err != nil {
+		m.err = fmt.Errorf("failed to read audit log: %w", err)
+		return m, nil
+	}
+
+	if len(parts) == 1 {
+		m.auditRecords = records
+		m.auditCursor = 0
+		m.state = stateAudit
+		return m, nil
+	}
+
+	if parts[1] != "export" || len(parts) != 3 {
+		m.err = fmt.Errorf("usage: audit or audit export <file>")
+		return m, nil
+	}
+	if records == nil {
+		records = []auditRecord{}
+	}
+	if err := writeJSONFile(parts[2], records); err != nil {
+		return m, m.notify(toastError, fmt.Sprintf("Audit export failed: %v", err))
+	}
+	return m, m.notify(toastSuccess, fmt.Sprintf("Exported %d audit records to %s", len(records), parts[2]))
+}
+
+func (m model) updateAudit(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.auditCursor > 0 {
+			m.auditCursor--
+		}
+	case "down", "j":
+		if m.auditCursor < len(m.auditRecords)-1 {
+			m.auditCursor++
+		}
+	case "g":
+		m.auditCursor = 0
+	case "G":
+		m.auditCursor = max(len(m.auditRecords)-1, 0)
+	}
+	return m, nil
+}
+
+// renderAudit lists the audit trail, newest first.
+func (m model) renderAudit() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🧾 AUDIT LOG (%d records) 🧾", len(m.auditRecords))), ""}
+
+	if len(m.auditRecords) == 0 {
+		content = append(content, textStyle.Render("No mutating operations recorded yet."))
+	}
+
+	// Keep the cursor in view, leaving room for the title, footer and command area
+	visible := max(m.height-12, 1)
+	start := 0
+	if m.auditCursor >= visible {
+		start = m.auditCursor - visible + 1
+	}
+	for i := start; i < len(m.auditRecords) && i < start+visible; i++ {
+		record := m.auditRecords[len(m.auditRecords)-1-i]
+		target := fmt.Sprintf("%d apps", len(record.Addresses))
+		if len(record.Addresses) == 1 {
+			target = TruncateAddress(record.Addresses[0], 20)
+		}
+		line := fmt.Sprintf("%s  %s  %-12s %-11s %-11s %-20s %s %s",
+			record.Time.Local().Format("2006-01-02 15:04:05"), record.Operator,
+			record.Network, record.Kind, record.Result, target,
+			m.formatAmount(record.AmountUpokt), m.unitLabel())
+		if record.TxHash != "" {
+			line += "  " + record.TxHash
+		}
+		if record.Error != "" {
+			line += "  " + record.Error
+		}
+		line = truncateToWidth(line, max(m.width-4, 10))
+
+		switch {
+		case i == m.auditCursor:
+			content = append(content, selectedStyle.Render(line))
+		case record.Result == string(txFailed):
+			content = append(content, errorStyle.Render(line))
+		case record.Result == string(txIncluded):
+			content = append(content, successStyle.Render(line))
+		default:
+			content = append(content, textStyle.Render(line))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("j/k to scroll • :audit export <file> writes JSON • ESC or Q to return"))
+
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	autoFundSuggest = "suggest"
+	autoFundExecute = "execute"
+)
+
+// autoFundPlan returns the bank sends that top up every configured
+// application whose balance fell below the auto-fund floor, or nil if the
+// network has no auto-fund policy.
+func (m model) autoFundPlan() *stakePlan {
+	if m.config == nil {
+		return nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || network.AutoFund.MinBalance <= 0 {
+		return nil
+	}
+	policy := network.AutoFund
+
+	configured := make(map[string]bool, len(network.Applications))
+	for _, address := range network.Applications {
+		configured[address] = true
+	}
+	plan := &stakePlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now(),
+		Operator:         m.operator(),
+		Network:          m.currentNetwork,
+		Bank:             network.Bank,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	for _, app := range m.applications {
+		if !configured[app.Address] || app.BalanceUpokt >= policy.MinBalance {
+			continue
+		}
+		target := policy.topUpTo()
+		plan.Items = append(plan.Items, planItem{
+			Action:       planFund,
+			Address:      app.Address,
+			AmountUpokt:  target - app.BalanceUpokt,
+			CurrentUpokt: app.BalanceUpokt,
+			TargetUpokt:  target,
+		})
+		plan.BankRequiredUpokt += target - app.BalanceUpokt + network.txFeeUpokt()
+	}
+	return plan
+}
+
+// evaluateAutoFund applies the auto-fund policy after a refresh: it suggests
+// the top-ups, or submits them when the policy executes automatically.
+// Suggestions and refusals are notified once until the set of applications
+// below the floor changes.
+func (m *model) evaluateAutoFund() tea.Cmd {
+	plan := m.autoFundPlan()
+	if plan == nil || len(plan.Items) == 0 {
+		m.autoFundKey = ""
+		return nil
+	}
+	if m.reconcileCh != nil || m.stagedPlan != nil || m.balancesReady() != nil {
+		return nil
+	}
+
+	addresses := make([]string, len(plan.Items))
+	for i, item := range plan.Items {
+		addresses[i] = item.Address
+	}
+	key := m.currentNetwork + ":" + strings.Join(addresses, ",")
+	seen := key == m.autoFundKey
+	m.autoFundKey = key
+
+	network := m.config.Config.Networks[m.currentNetwork]
+	summary := fmt.Sprintf("%d apps below the %s %s balance floor need %s %s",
+		len(plan.Items), m.formatAmount(network.AutoFund.MinBalance), m.unitLabel(),
+		m.formatAmount(plan.BankRequiredUpokt), m.unitLabel())
+	if plan.BankRequiredUpokt > plan.BankBalanceUpokt {
+		if seen {
+			return nil
+		}
+		return m.notify(toastError, fmt.Sprintf("Auto-fund: %s, bank has %s", summary, m.formatAmount(plan.BankBalanceUpokt)))
+	}
+	if m.role() != roleAdmin {
+		if seen {
+			return nil
+		}
+		return m.notify(toastWarning, "Auto-fund: "+summary)
+	}
+	if network.AutoFund.Mode != autoFundExecute {
+		if seen {
+			return nil
+		}
+		return m.notify(toastWarning, fmt.Sprintf("Auto-fund: %s (:autofund to send)", summary))
+	}
+
+	logger.Info("auto-fund executing", "network", m.currentNetwork, "apps", len(plan.Items), "bank_required_upokt", plan.BankRequiredUpokt)
+	started, cmd := m.startPlan(plan, "autofund")
+	*m = started
+	return tea.Batch(cmd, m.notify(toastInfo, "Auto-fund: sending top-ups for "+summary))
+}
+
+// handleAutoFundCommand submits the top-ups suggested by the auto-fund policy.
+func (m model) handleAutoFundCommand() (model, tea.Cmd) {
+	plan := m.autoFundPlan()
+	if plan == nil {
+		m.err = fmt.Errorf("no auto_fund policy configured for network: %s", m.currentNetwork)
+		return m, nil
+	}
+	if m.reconcileCh != nil {
+		return m, m.notify(toastWarning, "Another reconcile is still running")
+	}
+	if err := m.balancesReady(); err != nil {
+		return m, m.notify(toastWarning, fmt.Sprintf("Auto-fund refused: %v", err))
+	}
+	if len(plan.Items) == 0 {
+		return m, m.notify(toastInfo, "Nothing to fund: every application is above the balance floor")
+	}
+	if plan.BankRequiredUpokt > plan.BankBalanceUpokt {
+		return m, m.notify(toastError, fmt.Sprintf("Auto-fund refused: need %s %s, bank has %s",
+			m.formatAmount(plan.BankRequiredUpokt), m.unitLabel(), m.formatAmount(plan.BankBalanceUpokt)))
+	}
+	return m.startPlan(plan, "autofund")
+}
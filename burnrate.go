@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	burnRateWindow  = 7 * 24 * time.Hour // History used to estimate the burn rate
+	burnRateMinSpan = time.Hour          // Shorter histories give no estimate
+)
+
+// stakeThresholds returns the warning and danger stake thresholds in upokt.
+func (m model) stakeThresholds() (warning, danger int64) {
+	// Default thresholds if config is not available
+	warning = 2000000000 // 2000 POKT
+	danger = 1000000000  // 1000 POKT
+
+	// Use config thresholds if available
+	if m.config != nil {
+		warning = m.config.Config.Thresholds.WarningThreshold
+		danger = m.config.Config.Thresholds.DangerThreshold
+	}
+	return warning, danger
+}
+
+// burnRate returns the average stake consumed per day over the samples taken
+// since the start of the window. Only decreases count, so upstakes between
+// samples do not hide consumption.
+func burnRate(samples []historySample, since time.Time) (perDay float64, ok bool) {
+	start := len(samples)
+	for start > 0 && !samples[start-1].Time.Before(since) {
+		start--
+	}
+	samples = samples[start:]
+	if len(samples) < 2 {
+		return 0, false
+	}
+	span := samples[len(samples)-1].Time.Sub(samples[0].Time)
+	if span < burnRateMinSpan {
+		return 0, false
+	}
+
+	var burned int64
+	for i := 1; i < len(samples); i++ {
+		if drop := samples[i-1].StakeUpokt - samples[i].StakeUpokt; drop > 0 {
+			burned += drop
+		}
+	}
+	return float64(burned) / span.Hours() * 24, true
+}
+
+// appBurnRate returns the estimated daily stake consumption of an application.
+func (m model) appBurnRate(address string) (float64, bool) {
+	return burnRate(m.history[address], time.Now().Add(-burnRateWindow))
+}
+
+// daysToDanger estimates the days until app falls below the danger threshold
+// at its current burn rate: 0 if it already has, +Inf if it burns nothing.
+func (m model) daysToDanger(app Application) (float64, bool) {
+	rate, ok := m.appBurnRate(app.Address)
+	if !ok {
+		return 0, false
+	}
+	_, danger := m.stakeThresholds()
+	headroom := stakeUpokt(app) - danger
+	switch {
+	case headroom <= 0:
+		return 0, true
+	case rate <= 0:
+		return math.Inf(1), true
+	default:
+		return float64(headroom) / rate, true
+	}
+}
+
+func (m model) formatBurnRate(app Application) string {
+	rate, ok := m.appBurnRate(app.Address)
+	if !ok {
+		return "-"
+	}
+	return m.formatAmount(int64(math.Round(rate)))
+}
+
+func (m model) formatDaysToDanger(app Application) string {
+	days, ok := m.daysToDanger(app)
+	switch {
+	case !ok:
+		return "-"
+	case math.IsInf(days, 1):
+		return "∞"
+	case days == 0:
+		return "now"
+	default:
+		return fmt.Sprintf("%.1f", days)
+	}
+}
+
+// dangerSortKeys returns the days to danger of every application, with
+// unknown estimates sorted last, alongside applications that burn nothing.
+func (m model) dangerSortKeys() map[string]float64 {
+	keys := make(map[string]float64, len(m.applications))
+	for _, app := range m.applications {
+		days, ok := m.daysToDanger(app)
+		if !ok {
+			days = math.Inf(1)
+		}
+		keys[app.Address] = days
+	}
+	return keys
+}
+
+// burnSortKeys returns the daily burn rate of every application, with unknown
+// rates sorted after every known one.
+func (m model) burnSortKeys() map[string]float64 {
+	keys := make(map[string]float64, len(m.applications))
+	for _, app := range m.applications {
+		rate, ok := m.appBurnRate(app.Address)
+		if !ok {
+			rate = -1
+		}
+		keys[app.Address] = rate
+	}
+	return keys
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// applicationCache is the last successful refresh of a network/gateway pair,
+// shown immediately on startup while fresh data loads.
+type applicationCache struct {
+	Network      string        `json:"network"`
+	Gateway      string        `json:"gateway"`
+	SavedAt      time.Time     `json:"saved_at"`
+	BankBalance  float64       `json:"bank_balance"`
+	Applications []Application `json:"applications"`
+}
+
+func cachePath(network, gateway string) (string, error) {
+	return dataPath("cache", safeFileName(network+"_"+gateway)+".json")
+}
+
+// saveApplicationCache persists a completed refresh for network and gateway.
+func saveApplicationCache(network, gateway string, apps []Application, bankBalance float64) error {
+	path, err := cachePath(network, gateway)
+	if err != nil {
+		return err
+	}
+	return writeJSONFile(path, applicationCache{
+		Network:      network,
+		Gateway:      gateway,
+		SavedAt:      time.Now(),
+		BankBalance:  bankBalance,
+		Applications: apps,
+	})
+}
+
+func saveApplicationCacheCmd(network, gateway string, apps []Application, bankBalance float64) tea.Cmd {
+	snapshot := append([]Application(nil), apps...)
+	return func() tea.Msg {
+		// A failed cache write only costs the next cold start
+		_ = saveApplicationCache(network, gateway, snapshot, bankBalance)
+		return nil
+	}
+}
+
+// loadApplicationCache returns the cached refresh for network and gateway.
+func loadApplicationCache(network, gateway string) (*applicationCache, error) {
+	path, err := cachePath(network, gateway)
+	if err != nil {
+		return nil, err
+	}
+	var cache applicationCache
+	if err := readJSONFile(path, &cache); err != nil {
+		return nil, err
+	}
+	return &cache, nil
+}
+
+// showCachedApplications displays cached data for network and gateway (if
+// any) until the refresh that is about to start replaces it.
+func (m *model) showCachedApplications(network, gateway string) {
+	m.stakedApps = nil
+	cache, err := loadApplicationCache(network, gateway)
+	if err != nil {
+		// No usable cache; the table stays empty until the refresh completes
+		m.applications = nil
+		m.staleSince = time.Time{}
+		m.refreshedFor, m.refreshedAt = "", time.Time{}
+		return
+	}
+	m.applications = cache.Applications
+	m.bankBalance = cache.BankBalance
+	m.staleSince = cache.SavedAt
+	m.refreshedFor, m.refreshedAt = refreshKey(network, gateway), cache.SavedAt
+	m.sortApplications()
+	if m.cursor >= len(m.applications) {
+		m.cursor = 0
+	}
+}
+
+// formatAge renders a duration as a short human readable age.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
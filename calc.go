@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stakeCalc is the what-if calculator of :calc: a hypothetical stake applied
+// to one application or to every loaded application of a service, computed
+// locally without any transaction.
+type stakeCalc struct {
+	address string // Application of the selected row
+	service string // Service of the group; "" for the application alone
+	group   bool   // Apply the stake to the service group
+	input   string
+}
+
+// calcResult is the effect of the hypothetical stake on one application.
+type calcResult struct {
+	app      Application
+	upstake  int64 // Stake to add, 0 if the stake already reaches it
+	shortage int64 // Balance the bank must fund before the upstake
+}
+
+// handleCalcCommand opens the calculator for "calc [<address>|<service>]",
+// on the selected application by default.
+func (m model) handleCalcCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) > 2 {
+		m.err = fmt.Errorf("usage: calc [address|service]")
+		return m, nil
+	}
+	if len(m.applications) == 0 {
+		m.err = fmt.Errorf("no applications loaded")
+		return m, nil
+	}
+
+	calc := &stakeCalc{}
+	if len(parts) == 1 {
+		if m.cursor >= len(m.applications) {
+			m.err = fmt.Errorf("no application selected")
+			return m, nil
+		}
+		calc.address = m.applications[m.cursor].Address
+	} else {
+		target := parts[1]
+		for _, app := range m.applications {
+			if app.Address == target {
+				calc.address = app.Address
+			}
+		}
+		if calc.address == "" {
+			for _, app := range m.applications {
+				if slices.Contains(app.ServiceIDs, target) {
+					calc.address, calc.service, calc.group = app.Address, target, true
+					break
+				}
+			}
+		}
+		if calc.address == "" {
+			if err := validateAddress(target); err == nil {
+				m.err = fmt.Errorf("application %s is not loaded", target)
+			} else {
+				m.err = fmt.Errorf("no loaded application is staked for service %s", target)
+			}
+			return m, nil
+		}
+	}
+	if calc.service == "" {
+		if app, ok := m.calcApplication(calc.address); ok && len(app.ServiceIDs) > 0 {
+			calc.service = app.ServiceIDs[0]
+		}
+	}
+	m.stakeCalc = calc
+	m.state = stateCalc
+	return m, nil
+}
+
+// calcApplication returns the loaded application with address.
+func (m model) calcApplication(address string) (Application, bool) {
+	for _, app := range m.applications {
+		if app.Address == address {
+			return app, true
+		}
+	}
+	return Application{}, false
+}
+
+// calcApplications returns the applications the calculator applies to.
+func (m model) calcApplications() []Application {
+	calc := m.stakeCalc
+	if !calc.group {
+		if app, ok := m.calcApplication(calc.address); ok {
+			return []Application{app}
+		}
+		return nil
+	}
+	var apps []Application
+	for _, app := range m.applications {
+		if slices.Contains(app.ServiceIDs, calc.service) && !isUnstaking(app) {
+			apps = append(apps, app)
+		}
+	}
+	return apps
+}
+
+// calcResults works out the upstake of each application to reach stake, and
+// what the bank must fund first when its balance cannot pay for it.
+func (m model) calcResults(stake int64) []calcResult {
+	appFee := m.config.Config.Networks[m.currentNetwork].appFeeUpokt()
+	var results []calcResult
+	for _, app := range m.calcApplications() {
+		result := calcResult{app: app}
+		if current := stakeUpokt(app); stake > current {
+			result.upstake = stake - current
+			if need := result.upstake + appFee; need > app.BalanceUpokt {
+				result.shortage = need - app.BalanceUpokt
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// computeUnitCapacity estimates the compute units a stake pays for at the
+// current shared parameters.
+func (m model) computeUnitCapacity(stake int64) (float64, bool) {
+	if m.params == nil || m.paramsNetwork != m.currentNetwork || m.params.shared.ComputeUnitsToTokensMultiplier == 0 {
+		return 0, false
+	}
+	perUnit := float64(m.params.shared.claimedUpokt(1_000_000)) / 1_000_000
+	if perUnit <= 0 {
+		return 0, false
+	}
+	return float64(stake) / perUnit, true
+}
+
+// calcRunway estimates the days a stake lasts above the danger threshold at
+// the current burn rate of app.
+func (m model) calcRunway(app Application, stake int64) string {
+	rate, ok := m.appBurnRate(app.Address)
+	if !ok {
+		return "-"
+	}
+	_, danger := m.stakeThresholds()
+	switch {
+	case stake <= danger:
+		return "now"
+	case rate <= 0:
+		return "∞"
+	}
+	return fmt.Sprintf("%.1f", float64(stake-danger)/rate)
+}
+
+// thresholdStatus returns the status icon of a stake against the thresholds.
+func (m model) thresholdStatus(stake int64) string {
+	warning, danger := m.stakeThresholds()
+	switch {
+	case stake >= warning:
+		return "🟢"
+	case stake >= danger:
+		return "🟡"
+	}
+	return "🔴"
+}
+
+func (m model) updateCalc(msg tea.KeyMsg) (model, tea.Cmd) {
+	calc := m.stakeCalc
+	switch msg.String() {
+	case "esc":
+		m.stakeCalc = nil
+		m.state = stateTable
+	case "tab":
+		// Switch between the application and its service group
+		if calc.service != "" {
+			calc.group = !calc.group
+		}
+	case "backspace":
+		if len(calc.input) > 0 {
+			calc.input = calc.input[:len(calc.input)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			calc.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// renderCalc shows the hypothetical stake and its effect on capacity,
+// thresholds, fees and the bank.
+func (m model) renderCalc() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("230")).
+		Bold(true).
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 2)
+
+	calc := m.stakeCalc
+	if calc == nil || m.config == nil {
+		return ""
+	}
+	unit := " " + m.unitLabel()
+	row := func(label, value string) string {
+		return textStyle.Render(fmt.Sprintf("%-18s %s", label, value))
+	}
+
+	scope := calc.address
+	if label := m.appLabel(calc.address); label != "" {
+		scope += " (" + label + ")"
+	}
+	if calc.group {
+		scope = "service " + calc.service
+	}
+	apps := m.calcApplications()
+	content := []string{headerStyle.Render("🧮 WHAT-IF STAKE • " + scope), ""}
+	if len(apps) == 0 {
+		content = append(content, textStyle.Render("No loaded applications. Press ESC to return."))
+		return strings.Join(content, "\n")
+	}
+	warning, danger := m.stakeThresholds()
+	content = append(content, row("Thresholds", fmt.Sprintf("warning %s%s • danger %s%s", m.formatAmount(warning), unit, m.formatAmount(danger), unit)))
+	if minStake := m.minStake(); minStake > 0 {
+		content = append(content, row("Minimum stake", m.formatAmount(minStake)+unit))
+	}
+	bank := int64(math.Round(m.bankBalance * upoktPerPOKT))
+	content = append(content, row("Bank balance", m.formatAmount(bank)+unit))
+
+	content = append(content, "")
+	content = append(content, inputStyle.Render("Stake per application: "+calc.input+"█"))
+	content = append(content, textStyle.Render("upokt unless a unit is given: 1500pokt, 2.5kpokt, 250000000upokt"))
+	content = append(content, "")
+
+	stake, err := parseAmount(strings.TrimSpace(calc.input))
+	switch {
+	case strings.TrimSpace(calc.input) == "":
+		stake = -1
+	case err != nil:
+		content = append(content, errorStyle.Render(err.Error()))
+		stake = -1
+	}
+
+	content = append(content, textStyle.Render(fmt.Sprintf("%-44s %16s %16s %4s %10s", "APPLICATION", "NOW", "UPSTAKE", "", "DAYS")))
+	for _, app := range apps {
+		name := app.Address
+		if label := m.appLabel(app.Address); label != "" {
+			name = label
+		}
+		current := stakeUpokt(app)
+		upstake, status, days := "-", m.thresholdStatus(current), m.calcRunway(app, current)
+		if stake >= 0 {
+			upstake = "0"
+			if stake > current {
+				upstake = m.formatAmount(stake - current)
+			}
+			// Stakes above the hypothetical one stay as they are
+			after := current
+			if stake > current {
+				after = stake
+			}
+			status, days = m.thresholdStatus(after), m.calcRunway(app, after)
+		}
+		content = append(content, textStyle.Render(fmt.Sprintf("%-44s %16s %16s %4s %10s",
+			truncateToWidth(name, 44), m.formatAmount(current), upstake, status, days)))
+	}
+	content = append(content, "")
+
+	if stake >= 0 {
+		results := m.calcResults(stake)
+		network := m.config.Config.Networks[m.currentNetwork]
+		appFee := network.appFeeUpokt()
+		var upstakes, funds int
+		var totalUpstake, totalFund int64
+		for _, result := range results {
+			if result.upstake > 0 {
+				upstakes++
+				totalUpstake += result.upstake
+			}
+			if result.shortage > 0 {
+				funds++
+				totalFund += result.shortage
+			}
+		}
+		fees := int64(upstakes+funds) * network.txFeeUpokt()
+		bankCost := totalFund + int64(funds)*network.txFeeUpokt() + int64(upstakes)*(network.txFeeUpokt()-appFee)
+
+		if capacity, ok := m.computeUnitCapacity(stake); ok {
+			content = append(content, row("Relay capacity", fmt.Sprintf("≈ %s compute units per application (relays at 1 CU each)", formatCount(capacity))))
+		} else {
+			content = append(content, row("Relay capacity", "- (module params not loaded, :params)"))
+		}
+		content = append(content, row("Total upstake", fmt.Sprintf("%s%s over %d application(s)", m.formatAmount(totalUpstake), unit, upstakes)))
+		if funds > 0 {
+			content = append(content, row("Funding needed", fmt.Sprintf("%s%s to %d application(s) whose balance is short", m.formatAmount(totalFund), unit, funds)))
+		}
+		content = append(content, row("Fees", fmt.Sprintf("%s%s for %d transaction(s)", m.formatAmount(fees), unit, upstakes+funds)))
+		content = append(content, row("Resulting bank", m.formatAmount(bank-bankCost)+unit))
+		if bank-bankCost < 0 {
+			content = append(content, warningStyle.Render("The bank balance cannot cover the funding and fees"))
+		}
+		if stake < danger {
+			content = append(content, warningStyle.Render("The stake is below the danger threshold"))
+		}
+		if err := m.checkMinStake(stake, 0); err != nil {
+			content = append(content, warningStyle.Render(err.Error()))
+		}
+	}
+
+	content = append(content, "")
+	help := "Type a stake • ESC to return • nothing is submitted"
+	if calc.service != "" {
+		help = "Type a stake • Tab switches between the application and service " + calc.service + " • ESC to return • nothing is submitted"
+	}
+	content = append(content, textStyle.Render(help))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultCommandTimeout = 60 * time.Second
+	// A second ctrl+c within this window quits instead of cancelling again
+	quitConfirmWindow = 3 * time.Second
+)
+
+// errCancelled is returned by pocketd calls and batches cancelled with esc or
+// ctrl+c.
+var errCancelled = errors.New("cancelled")
+
+// operationScope holds the context that in-flight refreshes and batches run
+// under, so that the operator can cancel them all at once.
+type operationScope struct {
+	mu      sync.Mutex
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration // Bound on a single pocketd call
+}
+
+var operations = newOperationScope()
+
+func newOperationScope() *operationScope {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &operationScope{
+		ctx:     ctx,
+		cancel:  func() { cancel(errCancelled) },
+		timeout: defaultCommandTimeout,
+	}
+}
+
+// configure applies the command-timeout setting of the config.
+func (s *operationScope) configure(timeout string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeout = defaultCommandTimeout
+	if d, err := time.ParseDuration(timeout); err == nil && d > 0 {
+		s.timeout = d
+	}
+}
+
+// context returns the context of the operations currently in flight. Batches
+// take it when they start and stop once it is cancelled.
+func (s *operationScope) context() context.Context {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.ctx
+}
+
+// cancelAll cancels every operation in flight. Operations started afterwards
+// run under a fresh context.
+func (s *operationScope) cancelAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cancel()
+	ctx, cancel := context.WithCancelCause(context.Background())
+	s.ctx = ctx
+	s.cancel = func() { cancel(errCancelled) }
+}
+
+// commandContext returns the context a pocketd call with args runs under.
+// Queries are killed when cancelled; broadcasts are left to finish, since the
+// node may already have received them, and are only bounded by the timeout.
+func (s *operationScope) commandContext(args []string) (context.Context, context.CancelFunc, time.Duration) {
+	s.mu.Lock()
+	parent, timeout := s.ctx, s.timeout
+	s.mu.Unlock()
+	if len(args) > 0 && args[0] == "tx" {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	return ctx, cancel, timeout
+}
+
+// isCancelled reports whether err comes from an operation cancelled by the
+// operator. Some query errors are flattened to text on their way up, so the
+// message is checked as well.
+func isCancelled(err error) bool {
+	return err != nil && (errors.Is(err, errCancelled) || strings.Contains(err.Error(), "pocketd command failed: "+errCancelled.Error()) ||
+		strings.Contains(err.Error(), "query failed: "+errCancelled.Error()))
+}
+
+// sleepContext sleeps for d, returning false early if ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// inFlight reports whether a refresh or batch is running that esc or ctrl+c
+// can cancel.
+func (m model) inFlight() bool {
+	if m.loading || len(m.pendingBalances) > 0 || m.reconcileCh != nil || m.rewardsLoading || m.sessionsLoading || m.activityLoading || m.paramsLoading || m.passthrough.cancellable() ||
+		(m.queryConsole != nil && m.queryConsole.running) || (m.txLookup != nil && m.txLookup.loading) {
+		return true
+	}
+	for _, tx := range m.txs {
+		if tx.status == txSubmitting {
+			return true
+		}
+	}
+	return false
+}
+
+// cancelInFlight cancels the refreshes and batches in flight (esc or ctrl+c)
+// so that a hanging RPC does not leave the UI loading forever. Transactions
+// already broadcast are still followed.
+func (m *model) cancelInFlight() tea.Cmd {
+	operations.cancelAll()
+	m.cancelledAt = time.Now()
+	logger.Warn("in-flight operations cancelled", "network", m.currentNetwork, "operator", m.operator())
+	m.loading = false
+	m.processingUpstakeAll = false
+	m.pendingBalances = make(map[string]bool)
+	m.rewardsLoading = false
+	m.sessionsLoading = false
+	m.activityLoading = false
+	m.paramsLoading = false
+	if m.reconcileCh != nil {
+		return m.notify(toastWarning, "Cancelled; the reconcile stops after its current transaction • ctrl+c again to quit")
+	}
+	return m.notify(toastWarning, "Cancelled in-flight requests; batches stop after their current transaction • ctrl+c again to quit")
+}
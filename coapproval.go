@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// approvalPrefix starts every message a second approver signs, so their
+// key cannot be used to sign anything else through GASMS.
+const approvalPrefix = "gasms approval "
+
+// TwoPerson requires a signature from a second approver before submissions
+// moving at least Threshold upokt are broadcast. The approver keeps the
+// private key on their own machine; GASMS only holds PublicKey, so the
+// operator submitting cannot approve on their own. Only the base config
+// applies; see configTwoPerson.
+type TwoPerson struct {
+	Threshold  int64  `yaml:"threshold"`             // upokt
+	PublicKey  string `yaml:"public-key"`            // Base64 ed25519 public key printed by approver-init
+	SecretFile string `yaml:"secret-file,omitempty"` // TOTP secret of earlier versions, refused
+}
+
+func (t TwoPerson) enabled() bool {
+	return t.Threshold > 0 && t.PublicKey != ""
+}
+
+// validate refuses the TOTP setup of earlier versions, whose secret the
+// operator could read, and keys that do not decode.
+func (t TwoPerson) validate() error {
+	if t.SecretFile != "" {
+		return fmt.Errorf("two-person.secret-file is no longer supported: run gasms approver-init on the second approver's machine and set two-person.public-key")
+	}
+	if t.Threshold > 0 && t.PublicKey == "" {
+		return fmt.Errorf("two-person.threshold needs two-person.public-key")
+	}
+	if t.PublicKey != "" {
+		if _, err := t.publicKey(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t TwoPerson) publicKey() (ed25519.PublicKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(t.PublicKey))
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("two-person.public-key is not a base64 ed25519 public key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// configTwoPerson returns the two-person settings that apply under config:
+// those of the base config roles are taken from, so that an operator cannot
+// point GASMS at a key of their own with a profile or -config file.
+func configTwoPerson(config *Config) TwoPerson {
+	if source, err := roleConfig(config); err == nil && source != nil {
+		return source.Config.TwoPerson
+	}
+	return TwoPerson{}
+}
+
+// verifyApproval checks the base64 signature of challenge by the second
+// approver's key.
+func verifyApproval(twoPerson TwoPerson, challenge, signature string) error {
+	key, err := twoPerson.publicKey()
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil || !ed25519.Verify(key, []byte(approvalPrefix+challenge), sig) {
+		return fmt.Errorf("invalid approval signature")
+	}
+	return nil
+}
+
+// submissionChallenge returns a fresh challenge for the second approver to
+// sign for entry. It covers the transactions and a random nonce, so each
+// signature approves one submission once.
+func submissionChallenge(entry queuedSubmission) (string, error) {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	digest := sha256.New()
+	fmt.Fprintf(digest, "%s\n%s\n%x\n", entry.network, entry.command, nonce)
+	for _, row := range entry.rows {
+		fmt.Fprintf(digest, "%s %s %d\n", row.kind, row.target, row.amount)
+	}
+	return hex.EncodeToString(digest.Sum(nil)[:16]), nil
+}
+
+// planChallenge returns the challenge of a plan file: the SHA-256 of its
+// contents, so a signature approves exactly the reviewed plan.
+func planChallenge(data []byte) string {
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+// submissionTotal returns the amount moved by a submission, in upokt.
+func submissionTotal(entry queuedSubmission) int64 {
+	var total int64
+	for _, row := range entry.rows {
+		total += row.amount
+	}
+	return total
+}
+
+// requiresCoApproval reports whether entry needs the second approver's
+// signature.
+func (m model) requiresCoApproval(entry queuedSubmission) bool {
+	twoPerson := configTwoPerson(m.config)
+	return twoPerson.enabled() && submissionTotal(entry) >= twoPerson.Threshold
+}
+
+// authorize broadcasts entry, first asking for the second approver's
+// signature when it moves at least the two-person threshold.
+func (m model) authorize(entry queuedSubmission) (model, tea.Cmd) {
+	if !m.requiresCoApproval(entry) {
+		return entry.approve(m)
+	}
+	challenge, err := submissionChallenge(entry)
+	if err != nil {
+		return m, m.rejectSubmission(entry, fmt.Sprintf("second approval unavailable: %v", err))
+	}
+	logger.Info("second approval required", "command", entry.command, "network", entry.network, "total_upokt", submissionTotal(entry), "challenge", challenge)
+	m.coApproval = &entry
+	m.coApprovalChallenge = challenge
+	m.coApprovalInput = ""
+	m.coApprovalErr = nil
+	m.state = stateCoApproval
+	return m, nil
+}
+
+func (m model) updateCoApproval(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		entry := *m.coApproval
+		if entry.plan && m.reconcileCh != nil {
+			m.coApprovalErr = fmt.Errorf("another reconcile is still running; approve this one when it finishes")
+			return m, nil
+		}
+		if err := verifyApproval(configTwoPerson(m.config), m.coApprovalChallenge, m.coApprovalInput); err != nil {
+			logger.Warn("second approval refused", "command", entry.command, "error", err)
+			m.coApprovalErr = err
+			m.coApprovalInput = ""
+			return m, nil
+		}
+		// The challenge is used once: the next submission gets a new one
+		m.coApproval = nil
+		m.coApprovalChallenge = ""
+		m.state = stateTable
+		logger.Info("second approval accepted", "command", entry.command, "network", entry.network, "operator", m.operator(), "total_upokt", submissionTotal(entry))
+		return entry.approve(m)
+	case "esc":
+		entry := *m.coApproval
+		m.coApproval = nil
+		m.coApprovalChallenge = ""
+		m.state = stateTable
+		if m.approvalRequired() {
+			// Back to the queue, to be approved later
+			m.txQueue = append([]queuedSubmission{entry}, m.txQueue...)
+			return m, m.notify(toastInfo, "Second approval cancelled; the transactions are back in :queue")
+		}
+		return m, m.rejectSubmission(entry, "second approval cancelled")
+	case "backspace":
+		if len(m.coApprovalInput) > 0 {
+			m.coApprovalInput = m.coApprovalInput[:len(m.coApprovalInput)-1]
+		}
+	default:
+		// Signatures are pasted: 88 base64 characters
+		if msg.Type == tea.KeyRunes && len(m.coApprovalInput)+len(msg.Runes) <= 128 {
+			m.coApprovalInput += strings.TrimSpace(string(msg.Runes))
+		}
+	}
+	return m, nil
+}
+
+// renderCoApproval shows the challenge for the second approver to sign and
+// asks for their signature.
+func (m model) renderCoApproval() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 2)
+
+	entry := m.coApproval
+	if entry == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render("🔐 SECOND APPROVAL REQUIRED"), ""}
+	content = append(content, textStyle.Render("Command: "+entry.command))
+	content = append(content, textStyle.Render(fmt.Sprintf("Moves %s %s in %d transactions on %s, at or above the two-person threshold of %s %s.",
+		m.formatAmount(submissionTotal(*entry)), m.unitLabel(), len(entry.rows), entry.network,
+		m.formatAmount(configTwoPerson(m.config).Threshold), m.unitLabel())))
+	content = append(content, "")
+	content = append(content, warningStyle.Render("A second approver must review the transactions and sign this challenge with their own key:"))
+	content = append(content, textStyle.Render("  gasms approver-sign "+m.coApprovalChallenge))
+	content = append(content, textStyle.Render(fmt.Sprintf("Signature: %s█", m.coApprovalInput)))
+	if m.coApprovalErr != nil {
+		content = append(content, errorStyle.Render(m.coApprovalErr.Error()))
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render("Paste the signature, Enter to approve and broadcast • ESC to cancel"))
+	return strings.Join(content, "\n")
+}
+
+// promptApproval checks the second approver's signature of a plan for
+// "gasms apply": the one given with -approval-signature, the one in
+// <plan>.sig next to the plan, or one read from stdin.
+func promptApproval(config *Config, planPath string, data []byte, total int64, signature string) error {
+	twoPerson := configTwoPerson(config)
+	if !twoPerson.enabled() || total < twoPerson.Threshold {
+		return nil
+	}
+	challenge := planChallenge(data)
+	if signature == "" {
+		if saved, err := os.ReadFile(planPath + ".sig"); err == nil {
+			signature = string(saved)
+		}
+	}
+	if signature == "" {
+		fmt.Printf("\nThis plan moves %s, at or above the two-person threshold of %s.\n", formatPlanAmount(total), formatPlanAmount(twoPerson.Threshold))
+		fmt.Printf("The second approver signs it with \"gasms approver-sign -plan %s\" or \"gasms approver-sign %s\".\nSignature: ", planPath, challenge)
+		signature, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	}
+	if err := verifyApproval(twoPerson, challenge, signature); err != nil {
+		return fmt.Errorf("second approval failed: %w", err)
+	}
+	logger.Info("second approval accepted", "command", "apply", "operator", auditOperator(), "total_upokt", total, "plan", planPath)
+	return nil
+}
+
+// approverKeyFile is where approver-init and approver-sign keep the second
+// approver's private key by default, on the approver's own machine.
+const approverKeyFile = "~/.gasms/approver.key"
+
+// runApproverInit implements "gasms approver-init [key-file]", run by the
+// second approver on their own machine. It creates their signing key and
+// prints the public key to set as two-person.public-key.
+func runApproverInit(args []string) error {
+	flags := flag.NewFlagSet("approver-init", flag.ExitOnError)
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		return fmt.Errorf("usage: gasms approver-init [key-file]")
+	}
+	path := expandHome(approverKeyFile)
+	if flags.NArg() == 1 {
+		path = expandHome(flags.Arg(0))
+	}
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(private.Seed())+"\n"), 0600); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote the approver's private key to %s. Keep it on this machine only.\n\n", path)
+	fmt.Printf("Set the public key in the config.yaml roles are read from:\n  two-person:\n    public-key: %s\n", base64.StdEncoding.EncodeToString(public))
+	return nil
+}
+
+// runApproverSign implements "gasms approver-sign [-key file] <challenge>" and
+// "gasms approver-sign [-key file] -plan <plan.json>", run by the second
+// approver to sign a challenge shown by GASMS or a plan file they reviewed.
+func runApproverSign(args []string) error {
+	flags := flag.NewFlagSet("approver-sign", flag.ExitOnError)
+	keyPath := flags.String("key", approverKeyFile, "private key written by approver-init")
+	planPath := flags.String("plan", "", "plan file to approve; the signature is also written next to it")
+	flags.Parse(args)
+	if (*planPath == "") == (flags.NArg() == 0) || flags.NArg() > 1 {
+		return fmt.Errorf("usage: gasms approver-sign [-key file] <challenge> or gasms approver-sign [-key file] -plan <plan.json>")
+	}
+	data, err := os.ReadFile(expandHome(*keyPath))
+	if err != nil {
+		return err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("%s is not an approver key", *keyPath)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+
+	challenge := strings.TrimSpace(flags.Arg(0))
+	if *planPath != "" {
+		plan, err := os.ReadFile(*planPath)
+		if err != nil {
+			return err
+		}
+		challenge = planChallenge(plan)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(approvalPrefix+challenge)))
+	if *planPath != "" {
+		if err := os.WriteFile(*planPath+".sig", []byte(signature+"\n"), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote the approval of %s to %s.sig.\n", *planPath, *planPath)
+	}
+	fmt.Println(signature)
+	return nil
+}
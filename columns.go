@@ -0,0 +1,316 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tableColumnDef defines a column that can be shown in the applications table.
+type tableColumnDef struct {
+	id       string // Identifier used in config and the :columns command
+	title    string
+	sortKey  string // Sort field indicated in the header ("" if not sortable)
+	width    int
+	minWidth int
+	flex     bool
+	priority int
+	truncate func(s string, width int) string
+	value    func(m model, app Application) string
+}
+
+// tableColumnDefs lists every column the applications table knows how to render.
+var tableColumnDefs = []tableColumnDef{
+	{
+		id: "status", title: "ℹ️  Status", sortKey: "status",
+		width: 10, minWidth: 2, priority: 8,
+		value: func(m model, app Application) string {
+			status, _ := m.getStakeStatus(app, lipgloss.NewStyle(), lipgloss.NewStyle(), false)
+			if m.isUnmanaged(app) {
+				status += " " + unmanagedMarker
+			}
+			return status
+		},
+	},
+	{
+		id: "address", title: "📫 App Address", sortKey: "address",
+		width: 43, minWidth: 13, flex: true, priority: 9, truncate: TruncateAddress,
+		value: func(m model, app Application) string { return app.Address },
+	},
+	{
+		id: "label", title: "🏷️ Label",
+		width: 16, minWidth: 6, priority: 4,
+		value: func(m model, app Application) string {
+			if label := m.appLabel(app.Address); label != "" {
+				return label
+			}
+			return "-"
+		},
+	},
+	{
+		id: "stake", title: "🪙 Stake ({unit})", sortKey: "stake",
+		width: 20, minWidth: 10, priority: 7,
+		value: func(m model, app Application) string { return m.formatAmount(stakeUpokt(app)) },
+	},
+	{
+		id: "balance", title: "💰 Balance ({unit})", sortKey: "balance",
+		width: 20, minWidth: 10, priority: 6,
+		value: func(m model, app Application) string {
+			if m.pendingBalances[app.Address] {
+				return "…"
+			}
+			if app.BalanceUnknown {
+				return "? unknown"
+			}
+			return m.formatAmount(app.BalanceUpokt)
+		},
+	},
+	{
+		id: "service", title: "⚡ Service ID", sortKey: "service",
+		width: 28, minWidth: 12, priority: 5,
+		value: func(m model, app Application) string { return app.ServiceID },
+	},
+	{
+		id: "gateway", title: "🧱 Gateway", sortKey: "gateway",
+		width: 20, minWidth: 13, priority: 2, truncate: TruncateAddress,
+		value: func(m model, app Application) string {
+			if gateway := m.rowGateway(app); gateway != "" {
+				return gateway
+			}
+			return "-"
+		},
+	},
+	{
+		id: "unstaking", title: "⏳ Unstaking",
+		width: 26, minWidth: 8, priority: 3,
+		value: func(m model, app Application) string { return m.unstakingBadge(app) },
+	},
+	{
+		id: "transfer", title: "🔀 Transfer",
+		width: 38, minWidth: 8, priority: 3,
+		value: func(m model, app Application) string { return m.transferBadge(app) },
+	},
+	{
+		id: "delegations", title: "🔗 Delegations",
+		width: 15, minWidth: 5, priority: 4,
+		value: func(m model, app Application) string { return m.formatDelegations(app) },
+	},
+	{
+		id: "burn", title: "🔥 Burn/day ({unit})", sortKey: "burn",
+		width: 18, minWidth: 8, priority: 3,
+		value: func(m model, app Application) string { return m.formatBurnRate(app) },
+	},
+	{
+		id: "danger_days", title: "⏰ Days to Danger", sortKey: "danger",
+		width: 17, minWidth: 6, priority: 3,
+		value: func(m model, app Application) string { return m.formatDaysToDanger(app) },
+	},
+	{
+		id: "relays", title: "📶 Relays", sortKey: "relays",
+		width: 12, minWidth: 6, priority: 3,
+		value: func(m model, app Application) string { return m.formatRelays(app) },
+	},
+	{
+		id: "relay_errors", title: "⚠️ Relay Errors",
+		width: 16, minWidth: 6, priority: 3,
+		value: func(m model, app Application) string { return m.formatRelayErrorRate(app) },
+	},
+	{
+		id: "stake_fiat", title: "💵 Stake ({fiat})",
+		width: 16, minWidth: 8, priority: 1,
+		value: func(m model, app Application) string { return m.formatFiat(app.StakePOKT) },
+	},
+	{
+		id: "balance_fiat", title: "💵 Balance ({fiat})",
+		width: 16, minWidth: 8, priority: 1,
+		value: func(m model, app Application) string {
+			if m.pendingBalances[app.Address] {
+				return "…"
+			}
+			if app.BalanceUnknown {
+				return "?"
+			}
+			return m.formatFiat(app.BalancePOKT)
+		},
+	},
+}
+
+// defaultColumns is the column order used when none is configured.
+var defaultColumns = []string{"status", "address", "stake", "balance", "service", "gateway"}
+
+// defaultFiatColumns are appended to the defaults when the price feed is enabled.
+var defaultFiatColumns = []string{"stake_fiat", "balance_fiat"}
+
+// defaultRelayColumns are appended to the defaults when the network has relay
+// metrics.
+var defaultRelayColumns = []string{"relays", "relay_errors"}
+
+// defaultDelegationColumns are appended to the defaults when an application
+// is near the chain's max_delegated_gateways.
+var defaultDelegationColumns = []string{"delegations"}
+
+func findColumnDef(id string) (tableColumnDef, bool) {
+	for _, def := range tableColumnDefs {
+		if def.id == id {
+			return def, true
+		}
+	}
+	return tableColumnDef{}, false
+}
+
+func columnIDs() []string {
+	ids := make([]string, len(tableColumnDefs))
+	for i, def := range tableColumnDefs {
+		ids[i] = def.id
+	}
+	return ids
+}
+
+// validateColumns checks that every id names a known column and none repeat.
+func validateColumns(ids []string) error {
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := findColumnDef(id); !ok {
+			return fmt.Errorf("unknown column: %s (available: %s)", id, strings.Join(columnIDs(), ", "))
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate column: %s", id)
+		}
+		seen[id] = true
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("at least one column must be visible")
+	}
+	return nil
+}
+
+// activeColumns returns the definitions of the visible columns in display order.
+func (m model) activeColumns() []tableColumnDef {
+	ids := m.visibleColumns
+	if len(ids) == 0 {
+		ids = m.defaultColumnIDs()
+	}
+	var defs []tableColumnDef
+	for _, id := range ids {
+		if def, ok := findColumnDef(id); ok {
+			defs = append(defs, def)
+		}
+	}
+	return defs
+}
+
+// defaultColumnIDs returns the columns shown when none are configured.
+func (m model) defaultColumnIDs() []string {
+	ids := append([]string(nil), defaultColumns...)
+	if m.fiatEnabled() {
+		ids = append(ids, defaultFiatColumns...)
+	}
+	if m.relayMetricsEnabled() {
+		ids = append(ids, defaultRelayColumns...)
+	}
+	if m.nearMaxDelegationCount() > 0 {
+		ids = append(ids, defaultDelegationColumns...)
+	}
+	return ids
+}
+
+// tableColumns returns the layout of the visible application table columns.
+func (m model) tableColumns() []layoutColumn {
+	defs := m.activeColumns()
+	cols := make([]layoutColumn, len(defs))
+	for i, def := range defs {
+		title := strings.ReplaceAll(def.title, "{unit}", m.unitLabel())
+		if m.config != nil {
+			title = strings.ReplaceAll(title, "{fiat}", m.config.Config.PriceFeed.currency())
+		}
+		if def.sortKey != "" {
+			title = m.getColumnHeader(title, def.sortKey)
+		}
+		cols[i] = layoutColumn{
+			title:    title,
+			width:    def.width,
+			minWidth: def.minWidth,
+			flex:     def.flex,
+			priority: def.priority,
+			truncate: def.truncate,
+		}
+	}
+	return cols
+}
+
+// tableCells returns the cell values of app for the visible columns.
+func (m model) tableCells(app Application) []string {
+	defs := m.activeColumns()
+	cells := make([]string, len(defs))
+	for i, def := range defs {
+		cells[i] = def.value(m, app)
+	}
+	return cells
+}
+
+// handleColumnsCommand changes the visible columns:
+//
+//	columns status,address,stake   show exactly these columns in this order
+//	columns +unstaking -gateway    show or hide individual columns
+//	columns reset                  restore the configured/default columns
+func (m model) handleColumnsCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) < 2 {
+		m.err = fmt.Errorf("usage: columns <col,col,...> | +<col> | -<col> | reset (available: %s)", strings.Join(columnIDs(), ", "))
+		return m, nil
+	}
+
+	if parts[1] == "reset" {
+		m.visibleColumns = nil
+		if m.config != nil {
+			m.visibleColumns = m.config.Config.Columns
+		}
+		return m, nil
+	}
+
+	current := append([]string(nil), m.visibleColumns...)
+	if len(current) == 0 {
+		current = m.defaultColumnIDs()
+	}
+
+	var next []string
+	if strings.HasPrefix(parts[1], "+") || strings.HasPrefix(parts[1], "-") {
+		next = current
+		for _, arg := range parts[1:] {
+			id := strings.TrimLeft(arg, "+-")
+			if _, ok := findColumnDef(id); !ok {
+				m.err = fmt.Errorf("unknown column: %s (available: %s)", id, strings.Join(columnIDs(), ", "))
+				return m, nil
+			}
+			next = removeColumn(next, id)
+			if strings.HasPrefix(arg, "+") {
+				next = append(next, id)
+			}
+		}
+	} else {
+		for _, id := range strings.Split(strings.Join(parts[1:], ","), ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				next = append(next, id)
+			}
+		}
+	}
+
+	if err := validateColumns(next); err != nil {
+		m.err = err
+		return m, nil
+	}
+	m.visibleColumns = next
+	return m, nil
+}
+
+func removeColumn(ids []string, id string) []string {
+	var out []string
+	for _, existing := range ids {
+		if existing != id {
+			out = append(out, existing)
+		}
+	}
+	return out
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"os"
 
 	"gopkg.in/yaml.v3"
@@ -12,19 +13,224 @@ type Config struct {
 		Networks       map[string]Network `yaml:"networks"`
 		KeyringBackend string             `yaml:"keyring-backend,omitempty"`
 		PocketdHome    string             `yaml:"pocketd-home,omitempty"`
+		Passphrase     KeyringPassphrase  `yaml:"keyring-passphrase,omitempty"` // Source of the file/os keyring passphrase
+		Columns        []string           `yaml:"columns,omitempty"`
+		Denomination   string             `yaml:"denomination,omitempty"`
+		Precision      *int               `yaml:"precision,omitempty"`
+		PriceFeed      PriceFeed          `yaml:"price-feed,omitempty"`
+		StatusInterval string             `yaml:"status-interval,omitempty"`
+		WatchBlocks    bool               `yaml:"watch-blocks,omitempty"`
+		DrainKeep      *int64             `yaml:"drain-keep,omitempty"`      // upokt left on drained applications
+		Memo           string             `yaml:"memo,omitempty"`            // Attached to every transaction unless overridden with --memo
+		ApprovalQueue  bool               `yaml:"approval-queue,omitempty"`  // Hold transactions in :queue until approved
+		TwoPerson      TwoPerson          `yaml:"two-person,omitempty"`      // Second approver for large submissions
+		RateLimit      RateLimit          `yaml:"rate-limit,omitempty"`      // Throttling and backoff of pocketd calls
+		Receipts       ReceiptExport      `yaml:"receipts,omitempty"`        // Export of batch receipts
+		Roles          Roles              `yaml:"roles,omitempty"`           // Commands allowed per user
+		ReadOnly       bool               `yaml:"read-only,omitempty"`       // Disable every command that submits transactions
+		CommandTimeout string             `yaml:"command-timeout,omitempty"` // Bound on a single pocketd call (default 60s)
+		Schedules      []Schedule         `yaml:"schedules,omitempty"`       // Operations run at cron times
+		Daemon         Daemon             `yaml:"daemon,omitempty"`          // Settings of gasms daemon
+		SSH            SSHServer          `yaml:"ssh,omitempty"`             // Settings of gasms serve
+		Splash         string             `yaml:"splash,omitempty"`          // File of the splash screen (default built in)
+		Logo           string             `yaml:"logo,omitempty"`            // File whose first line is the header logo (default built in)
+		Cooldown       string             `yaml:"cooldown,omitempty"`        // Delay before a submission is broadcast, cancellable with ESC
+		Plugins        []Plugin           `yaml:"plugins,omitempty"`         // Custom commands run as external programs
+		Pocketd        string             `yaml:"pocketd,omitempty"`         // Path of the pocketd executable (default pocketd on PATH)
+		MultiSendChunk int                `yaml:"multisend-chunk,omitempty"` // Recipients per fund-all multi-send (default 100)
+		Filters        map[string]string  `yaml:"filters,omitempty"`         // Saved searches by name, recalled with :filter
 	} `yaml:"config"`
 }
 
+type PriceFeed struct {
+	Enabled  bool   `yaml:"enabled"`
+	URL      string `yaml:"url,omitempty"`
+	Path     string `yaml:"path,omitempty"`
+	Currency string `yaml:"currency,omitempty"`
+	TTL      string `yaml:"ttl,omitempty"`
+}
+
 type Thresholds struct {
 	WarningThreshold int64 `yaml:"warning_threshold"`
 	DangerThreshold  int64 `yaml:"danger_threshold"`
 }
 
 type Network struct {
-	RPCEndpoint  string   `yaml:"rpc_endpoint"`
-	Gateways     []string `yaml:"gateways"`
-	Applications []string `yaml:"applications"`
-	Bank         string   `yaml:"bank"`
+	RPCEndpoint    string               `yaml:"rpc_endpoint"`
+	RPCEndpoints   []string             `yaml:"rpc_endpoints,omitempty"` // Failover endpoints, tried after rpc_endpoint
+	GRPCEndpoint   string               `yaml:"grpc_endpoint,omitempty"` // host:port of a gRPC server for :query grpc, https:// for TLS
+	Gateways       []string             `yaml:"-"`                       // Gateway addresses in config order, from GatewaySpec
+	GatewaySpec    gatewaySet           `yaml:"gateways"`                // List of gateways, or mapping of gateway to its applications
+	Applications   []string             `yaml:"applications"`
+	Bank           string               `yaml:"bank"`
+	Targets        Targets              `yaml:"targets,omitempty"`            // Desired state used by "gasms plan"
+	AppTargets     map[string]Targets   `yaml:"app_targets,omitempty"`        // Per-application overrides of Targets
+	AutoFund       AutoFund             `yaml:"auto_fund,omitempty"`          // Balance floor kept by bank sends
+	AutoUpstake    AutoUpstake          `yaml:"auto_upstake,omitempty"`       // Stake floor kept by gasms daemon
+	Labels         map[string]string    `yaml:"labels,omitempty"`             // Display names of applications by address
+	FeeGrant       bool                 `yaml:"fee_grant,omitempty"`          // Charge application transaction fees to the bank's fee grant
+	KeyringBackend string               `yaml:"keyring_backend,omitempty"`    // Overrides the global keyring-backend
+	PocketdHome    string               `yaml:"pocketd_home,omitempty"`       // Overrides the global pocketd-home
+	RelayMetrics   RelayMetrics         `yaml:"relay_metrics,omitempty"`      // Relay counts from the PATH gateway's Prometheus
+	Faucet         Faucet               `yaml:"faucet,omitempty"`             // Token faucet of a test network, used by :faucet
+	GatewayHealth  map[string]string    `yaml:"gateway_health,omitempty"`     // HTTP health check URL by gateway address
+	Passphrase     KeyringPassphrase    `yaml:"keyring_passphrase,omitempty"` // Overrides the global keyring-passphrase
+	Fee            FeeToken             `yaml:"fee,omitempty"`                // Token and amount of transaction fees (default 20000upokt)
+	StakeTemplates map[string]yaml.Node `yaml:"stake_templates,omitempty"`    // Extra stake config fields by service ID, "*" for every service
+}
+
+// keyringBackend returns the keyring backend of network, falling back to the
+// global keyring-backend.
+func (c *Config) keyringBackend(network string) string {
+	if backend := c.Config.Networks[network].KeyringBackend; backend != "" {
+		return backend
+	}
+	return c.Config.KeyringBackend
+}
+
+// pocketdHome returns the pocketd home of network, falling back to the global
+// pocketd-home. Queries run without --home when it is empty.
+func (c *Config) pocketdHome(network string) string {
+	if home := c.Config.Networks[network].PocketdHome; home != "" {
+		return home
+	}
+	return c.Config.PocketdHome
+}
+
+// txHome returns the pocketd home transactions on network sign with, which
+// defaults to ~/.pocket.
+func (c *Config) txHome(network string) string {
+	if home := c.pocketdHome(network); home != "" {
+		return home
+	}
+	return defaultPocketdHome()
+}
+
+// defaultMultiSendChunk keeps a fund-all multi-send well within the block gas
+// and transaction size limits.
+const defaultMultiSendChunk = 100
+
+// multiSendChunk returns the most recipients of one fund-all multi-send.
+func (c *Config) multiSendChunk() int {
+	if c.Config.MultiSendChunk > 0 {
+		return c.Config.MultiSendChunk
+	}
+	return defaultMultiSendChunk
+}
+
+// gatewaySet decodes "gateways" as either a list of gateway addresses or a
+// mapping of gateway address to the applications that belong to it.
+type gatewaySet struct {
+	names []string
+	apps  map[string][]string // nil when gateways is a list
+}
+
+func (g *gatewaySet) UnmarshalYAML(value *yaml.Node) error {
+	switch value.Kind {
+	case yaml.SequenceNode:
+		return value.Decode(&g.names)
+	case yaml.MappingNode:
+		g.apps = make(map[string][]string)
+		for i := 0; i+1 < len(value.Content); i += 2 {
+			gateway := value.Content[i].Value
+			var apps []string
+			if err := value.Content[i+1].Decode(&apps); err != nil {
+				return fmt.Errorf("applications of gateway %s: %w", gateway, err)
+			}
+			g.names = append(g.names, gateway)
+			g.apps[gateway] = apps
+		}
+		return nil
+	case yaml.ScalarNode:
+		if value.Tag == "!!null" {
+			return nil
+		}
+	}
+	return fmt.Errorf("line %d: gateways must be a list or a mapping of gateway to applications", value.Line)
+}
+
+// UnmarshalYAML fills Gateways from the gateways key and adds applications
+// listed under a gateway to Applications.
+func (n *Network) UnmarshalYAML(value *yaml.Node) error {
+	type plain Network
+	if err := value.Decode((*plain)(n)); err != nil {
+		return err
+	}
+	n.Gateways = n.GatewaySpec.names
+	listed := make(map[string]bool, len(n.Applications))
+	for _, address := range n.Applications {
+		listed[address] = true
+	}
+	for _, gateway := range n.Gateways {
+		for _, address := range n.GatewaySpec.apps[gateway] {
+			if !listed[address] {
+				listed[address] = true
+				n.Applications = append(n.Applications, address)
+			}
+		}
+	}
+	return nil
+}
+
+// applicationsFor returns the applications that bulk operations touch under
+// gateway: the ones mapped to it, or every application when gateways is a
+// plain list.
+func (n Network) applicationsFor(gateway string) []string {
+	if apps, ok := n.GatewaySpec.apps[gateway]; ok {
+		return apps
+	}
+	return n.Applications
+}
+
+// AutoFund keeps the balance of every application above a floor, in upokt.
+// Applications below MinBalance are topped up to TopUpTo after a refresh,
+// either on confirmation ("suggest") or automatically ("execute").
+type AutoFund struct {
+	MinBalance int64  `yaml:"min_balance,omitempty"`
+	TopUpTo    int64  `yaml:"top_up_to,omitempty"` // Defaults to MinBalance
+	Mode       string `yaml:"mode,omitempty"`      // suggest (default) or execute
+}
+
+// topUpTo returns the balance applications are topped up to.
+func (a AutoFund) topUpTo() int64 {
+	if a.TopUpTo < a.MinBalance {
+		return a.MinBalance
+	}
+	return a.TopUpTo
+}
+
+// Targets declares the desired state of applications, in upokt. Zero values
+// are not enforced.
+type Targets struct {
+	Stake      int64 `yaml:"stake,omitempty"`       // Minimum stake of each application
+	MinBalance int64 `yaml:"min_balance,omitempty"` // Minimum bank balance of each application
+}
+
+// targetsFor returns the targets of an application, with its overrides applied.
+func (n Network) targetsFor(address string) Targets {
+	targets := n.Targets
+	if override, ok := n.AppTargets[address]; ok {
+		if override.Stake != 0 {
+			targets.Stake = override.Stake
+		}
+		if override.MinBalance != 0 {
+			targets.MinBalance = override.MinBalance
+		}
+	}
+	return targets
+}
+
+// hasTargets reports whether any desired state is declared for the network.
+func (n Network) hasTargets() bool {
+	if n.Targets.Stake > 0 || n.Targets.MinBalance > 0 {
+		return true
+	}
+	for _, targets := range n.AppTargets {
+		if targets.Stake > 0 || targets.MinBalance > 0 {
+			return true
+		}
+	}
+	return false
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -38,6 +244,39 @@ func LoadConfig(path string) (*Config, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := config.Config.Roles.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateSchedules(&config); err != nil {
+		return nil, err
+	}
+	if err := validateConfigAddresses(&config); err != nil {
+		return nil, err
+	}
+	if err := validatePlugins(&config); err != nil {
+		return nil, err
+	}
+	if err := validateFaucets(&config); err != nil {
+		return nil, err
+	}
+	if err := validateKeyringPassphrases(&config); err != nil {
+		return nil, err
+	}
+	if err := validateFeeTokens(&config); err != nil {
+		return nil, err
+	}
+	if err := config.Config.TwoPerson.validate(); err != nil {
+		return nil, err
+	}
+	if err := config.Config.SSH.validate(); err != nil {
+		return nil, err
+	}
+	if err := validateStakeTemplates(&config); err != nil {
+		return nil, err
+	}
+	if config.Config.MultiSendChunk < 0 {
+		return nil, fmt.Errorf("multisend-chunk must be positive, got %d", config.Config.MultiSendChunk)
+	}
 
 	return &config, nil
 }
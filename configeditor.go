@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// configRow is one editable line of the config editor.
+type configRow struct {
+	field string // warning_threshold, danger_threshold, bank, gateways or applications
+	value string // Current value, or the list item ("" for the add row of a list)
+}
+
+// configRows lists the editable settings of the current network.
+func (m model) configRows() []configRow {
+	if m.config == nil {
+		return nil
+	}
+	thresholds := m.config.Config.Thresholds
+	network := m.config.Config.Networks[m.currentNetwork]
+	rows := []configRow{
+		{field: "warning_threshold", value: strconv.FormatInt(thresholds.WarningThreshold, 10)},
+		{field: "danger_threshold", value: strconv.FormatInt(thresholds.DangerThreshold, 10)},
+		{field: "bank", value: network.Bank},
+	}
+	for _, gateway := range network.Gateways {
+		rows = append(rows, configRow{field: "gateways", value: gateway})
+	}
+	rows = append(rows, configRow{field: "gateways"})
+	for _, address := range network.Applications {
+		rows = append(rows, configRow{field: "applications", value: address})
+	}
+	return append(rows, configRow{field: "applications"})
+}
+
+// validateConfigEdit checks a new value for row before it is written.
+func (m model) validateConfigEdit(row configRow, value string) error {
+	thresholds := m.config.Config.Thresholds
+	network := m.config.Config.Networks[m.currentNetwork]
+	switch row.field {
+	case "warning_threshold", "danger_threshold":
+		amount, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || amount <= 0 {
+			return fmt.Errorf("threshold must be a positive integer (upokt): %s", value)
+		}
+		if row.field == "warning_threshold" && amount <= thresholds.DangerThreshold {
+			return fmt.Errorf("warning threshold must exceed the danger threshold (%d)", thresholds.DangerThreshold)
+		}
+		if row.field == "danger_threshold" && amount >= thresholds.WarningThreshold {
+			return fmt.Errorf("danger threshold must be below the warning threshold (%d)", thresholds.WarningThreshold)
+		}
+	case "bank":
+		return validateAddress(value)
+	case "gateways", "applications":
+		if err := validateAddress(value); err != nil {
+			return err
+		}
+		existing := network.Gateways
+		if row.field == "applications" {
+			existing = network.Applications
+		}
+		for _, address := range existing {
+			if address == value && address != row.value {
+				return fmt.Errorf("%s is already in %s", TruncateAddress(value, 20), row.field)
+			}
+		}
+	}
+	return nil
+}
+
+// setConfigValue replaces (or, for the add row of a list, appends) the value
+// of row in the config document. Applications added to a network whose
+// gateways are a mapping go under gateway.
+func setConfigValue(root *yaml.Node, network, gateway string, row configRow, value string) error {
+	switch row.field {
+	case "warning_threshold", "danger_threshold":
+		config := mappingValue(root, "config", yaml.MappingNode, true)
+		thresholds := mappingValue(config, "thresholds", yaml.MappingNode, true)
+		mappingValue(thresholds, row.field, yaml.ScalarNode, true).Value = value
+		return nil
+	}
+
+	node, err := networkNode(root, network)
+	if err != nil {
+		return err
+	}
+	if row.field == "bank" {
+		mappingValue(node, "bank", yaml.ScalarNode, true).Value = value
+		return nil
+	}
+	// Replace the existing entry, wherever it is listed
+	if row.value != "" {
+		if list, i := findConfigItem(node, row); list != nil {
+			list.Content[i].Value = value
+			return nil
+		}
+		return fmt.Errorf("%s not found in %s", TruncateAddress(row.value, 20), row.field)
+	}
+
+	if row.field == "gateways" {
+		gateways := mappingValue(node, "gateways", yaml.SequenceNode, true)
+		switch gateways.Kind {
+		case yaml.MappingNode:
+			gateways.Content = append(gateways.Content, scalarNode(value), &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle})
+		case yaml.SequenceNode:
+			gateways.Content = append(gateways.Content, scalarNode(value))
+		default:
+			return fmt.Errorf("gateways of %s is not a list", network)
+		}
+		return nil
+	}
+	list, err := applicationList(node, network, gateway)
+	if err != nil {
+		return err
+	}
+	list.Content = append(list.Content, scalarNode(value))
+	return nil
+}
+
+// findConfigItem locates the entry of row in the network document: in the
+// gateways list or mapping keys, or in the applications list or the
+// applications of any gateway. It returns the node holding the entry and its
+// index, or nil if it is not listed.
+func findConfigItem(node *yaml.Node, row configRow) (*yaml.Node, int) {
+	gateways := mappingValue(node, "gateways", yaml.SequenceNode, false)
+	var lists []*yaml.Node
+	if row.field == "gateways" {
+		lists = append(lists, gateways)
+	} else {
+		lists = append(lists, mappingValue(node, "applications", yaml.SequenceNode, false))
+		if gateways != nil && gateways.Kind == yaml.MappingNode {
+			for i := 1; i < len(gateways.Content); i += 2 {
+				lists = append(lists, gateways.Content[i])
+			}
+		}
+	}
+	for _, list := range lists {
+		if list == nil {
+			continue
+		}
+		step := 1
+		if list.Kind == yaml.MappingNode {
+			step = 2 // Gateway mapping: keys are the gateways
+		} else if list.Kind != yaml.SequenceNode {
+			continue
+		}
+		for i := 0; i < len(list.Content); i += step {
+			if list.Content[i].Value == row.value {
+				return list, i
+			}
+		}
+	}
+	return nil, 0
+}
+
+// removeConfigValue removes the list item of row from the config document.
+// Removing a mapped gateway also removes the applications listed under it.
+func removeConfigValue(root *yaml.Node, network string, row configRow) error {
+	node, err := networkNode(root, network)
+	if err != nil {
+		return err
+	}
+	list, i := findConfigItem(node, row)
+	if list == nil {
+		return fmt.Errorf("%s not found in %s", TruncateAddress(row.value, 20), row.field)
+	}
+	if list.Kind == yaml.MappingNode {
+		list.Content = append(list.Content[:i], list.Content[i+2:]...)
+	} else {
+		list.Content = append(list.Content[:i], list.Content[i+1:]...)
+	}
+	return nil
+}
+
+func (m model) updateConfigEditor(msg tea.KeyMsg) (model, tea.Cmd) {
+	rows := m.configRows()
+	if m.configCursor >= len(rows) {
+		m.configCursor = max(len(rows)-1, 0)
+	}
+
+	if m.configEditing {
+		switch msg.String() {
+		case "enter":
+			row := rows[m.configCursor]
+			value := strings.TrimSpace(m.configInput)
+			if err := m.validateConfigEdit(row, value); err != nil {
+				m.err = err
+				return m, nil
+			}
+			m.configEditing = false
+			m.err = nil
+			if value == row.value {
+				return m, nil
+			}
+			network, gateway := m.currentNetwork, m.currentGateway
+			summary := fmt.Sprintf("Saved %s: %s", row.field, value)
+			return m, saveConfigCmd(summary, func(root *yaml.Node) error {
+				return setConfigValue(root, network, gateway, row, value)
+			})
+		case "esc":
+			m.configEditing = false
+			m.err = nil
+		case "backspace":
+			if len(m.configInput) > 0 {
+				m.configInput = m.configInput[:len(m.configInput)-1]
+			}
+		default:
+			if msg.Type == tea.KeyRunes {
+				m.configInput += string(msg.Runes)
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.configCursor > 0 {
+			m.configCursor--
+		}
+	case "down", "j":
+		if m.configCursor < len(rows)-1 {
+			m.configCursor++
+		}
+	case "enter", "e":
+		if len(rows) > 0 {
+			m.configEditing = true
+			m.configInput = rows[m.configCursor].value
+		}
+	case "x", "delete":
+		if len(rows) == 0 {
+			break
+		}
+		row := rows[m.configCursor]
+		if (row.field != "gateways" && row.field != "applications") || row.value == "" {
+			break
+		}
+		if row.field == "gateways" && len(m.config.Config.Networks[m.currentNetwork].Gateways) == 1 {
+			m.err = fmt.Errorf("cannot remove the only gateway of %s", m.currentNetwork)
+			break
+		}
+		network := m.currentNetwork
+		summary := fmt.Sprintf("Removed %s from %s", TruncateAddress(row.value, 20), row.field)
+		return m, saveConfigCmd(summary, func(root *yaml.Node) error {
+			return removeConfigValue(root, network, row)
+		})
+	}
+	return m, nil
+}
+
+// renderConfigEditor lists the editable settings of the current network.
+func (m model) renderConfigEditor() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🛠️  CONFIG • %s • %s", configFile, m.currentNetwork)), ""}
+	section := ""
+	for i, row := range m.configRows() {
+		heading := "Thresholds (upokt)"
+		switch row.field {
+		case "bank":
+			heading = "Bank"
+		case "gateways":
+			heading = "Gateways"
+		case "applications":
+			heading = "Applications"
+		}
+		if heading != section {
+			if section != "" {
+				content = append(content, "")
+			}
+			content = append(content, sectionStyle.Render(heading))
+			section = heading
+		}
+
+		value := row.value
+		if value == "" {
+			value = "+ add"
+		}
+		line := "  " + value
+		if row.field == "warning_threshold" || row.field == "danger_threshold" {
+			line = fmt.Sprintf("  %-18s %s", row.field, value)
+		}
+		if i == m.configCursor && m.configEditing {
+			line = fmt.Sprintf("  ✏️  %s█", m.configInput)
+		}
+		if i == m.configCursor {
+			content = append(content, selectedStyle.Render(line))
+		} else {
+			content = append(content, textStyle.Render(line))
+		}
+	}
+
+	content = append(content, "")
+	if m.configEditing {
+		content = append(content, textStyle.Render("Enter to save • ESC to cancel"))
+	} else {
+		content = append(content, textStyle.Render("j/k to select • enter to edit or add • x to remove • ESC or Q to return"))
+	}
+	content = append(content, textStyle.Render("Changes are validated and written to "+configFile+" immediately, keeping its comments."))
+	return strings.Join(content, "\n")
+}
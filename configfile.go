@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFile is the config used without a profile.
+const defaultConfigFile = "config.yaml"
+
+// configFile is the config read by the TUI and rewritten by in-app edits:
+// the config of the active profile, or config.yaml without one.
+var configFile = defaultConfigFile
+
+type configSavedMsg struct {
+	config  *Config
+	summary string // Describes the edit for the notification
+	err     error
+}
+
+// editConfigFile applies edit to the YAML document at path and atomically
+// writes it back, keeping comments and key order. The result must still load
+// as a valid config.
+func editConfigFile(path string, edit func(root *yaml.Node) error) (*Config, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("%s is empty", path)
+	}
+	if err := edit(doc.Content[0]); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	var config Config
+	if err := yaml.Unmarshal(buf.Bytes(), &config); err != nil {
+		return nil, fmt.Errorf("edited config is invalid: %w", err)
+	}
+	if err := writeFileAtomic(path, buf.Bytes(), info.Mode().Perm()); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// mappingValue returns the value of key in a mapping node, adding an empty
+// node of kind when create is set and the key is missing.
+func mappingValue(node *yaml.Node, key string, kind yaml.Kind, create bool) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			value := node.Content[i+1]
+			// An empty key ("applications:") decodes as null
+			if create && value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+				value.Kind, value.Tag, value.Value = kind, "", ""
+			}
+			return value
+		}
+	}
+	if !create {
+		return nil
+	}
+	value := &yaml.Node{Kind: kind}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+	return value
+}
+
+// networkNode returns the mapping of a network in the config document.
+func networkNode(root *yaml.Node, network string) (*yaml.Node, error) {
+	config := mappingValue(root, "config", yaml.MappingNode, false)
+	networks := mappingValue(config, "networks", yaml.MappingNode, false)
+	node := mappingValue(networks, network, yaml.MappingNode, false)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("network %s not found in %s", network, configFile)
+	}
+	return node, nil
+}
+
+// applicationList returns the sequence new applications of network are added
+// to: the list of gateway when gateways map to their applications, the
+// applications list otherwise.
+func applicationList(node *yaml.Node, network, gateway string) (*yaml.Node, error) {
+	gateways := mappingValue(node, "gateways", yaml.SequenceNode, false)
+	if gateways != nil && gateways.Kind == yaml.MappingNode {
+		if list := mappingValue(gateways, gateway, yaml.SequenceNode, true); list.Kind == yaml.SequenceNode {
+			list.Style = 0
+			return list, nil
+		}
+		return nil, fmt.Errorf("applications of gateway %s is not a list", TruncateAddress(gateway, 20))
+	}
+	list := mappingValue(node, "applications", yaml.SequenceNode, true)
+	if list.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("applications of %s is not a list", network)
+	}
+	return list, nil
+}
+
+// scalarNode returns a plain YAML scalar.
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+}
+
+// saveConfigCmd runs a config edit in the background.
+func saveConfigCmd(summary string, edit func(root *yaml.Node) error) tea.Cmd {
+	return func() tea.Msg {
+		config, err := editConfigFile(configFile, edit)
+		return configSavedMsg{config: config, summary: summary, err: err}
+	}
+}
+
+// applySavedConfig switches to a rewritten config, keeping the current
+// network and gateway, and reloads the applications.
+func (m *model) applySavedConfig(msg configSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		logger.Error("failed to save config", "error", msg.err)
+		return m.notify(toastError, fmt.Sprintf("Config not saved: %v", msg.err))
+	}
+	m.config = msg.config
+	m.applyArt()
+	rpcPool.configure(m.config.Config.Networks)
+	pocketdLimiter.configure(m.config.Config.RateLimit)
+	keyrings.configure(m.config)
+	pocketdBinary.configure(m.config.Config.Pocketd)
+	operations.configure(m.config.Config.CommandTimeout)
+	logger.Info("config saved", "summary", msg.summary)
+	cmds := []tea.Cmd{m.notify(toastSuccess, msg.summary)}
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+		// The current gateway may have been removed
+		gateway := network.Gateways[0]
+		for _, gw := range network.Gateways {
+			if gw == m.currentGateway {
+				gateway = gw
+			}
+		}
+		m.currentGateway = gateway
+		cmds = append(cmds, m.reloadApplications(network, m.currentNetwork, m.currentGateway))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// coolingSubmission is a submission held for the pre-submit cooldown, during
+// which ESC cancels it before anything reaches the chain.
+type coolingSubmission struct {
+	entry queuedSubmission
+	until time.Time
+}
+
+// cooldownDoneMsg releases the cooling submission id once its cooldown ends.
+type cooldownDoneMsg struct {
+	id int
+}
+
+// cooldown returns the configured pre-submit cooldown, 0 if disabled.
+func (m model) cooldown() time.Duration {
+	if m.config == nil || m.config.Config.Cooldown == "" {
+		return 0
+	}
+	cooldown, err := time.ParseDuration(m.config.Config.Cooldown)
+	if err != nil || cooldown < 0 {
+		return 0
+	}
+	return cooldown
+}
+
+// holdSubmission starts the cooldown of entry; it is authorized when the
+// cooldown ends unless cancelled first.
+func (m *model) holdSubmission(entry queuedSubmission, cooldown time.Duration) tea.Cmd {
+	for _, id := range entry.txIDs {
+		if tx := m.findTx(id); tx != nil {
+			tx.status = txCooling
+			tx.updatedAt = time.Now()
+		}
+	}
+	m.cooling = append(m.cooling, coolingSubmission{entry: entry, until: time.Now().Add(cooldown)})
+	logger.Info("submission held for cooldown", "command", entry.command, "network", entry.network, "cooldown", cooldown)
+	return tea.Batch(
+		tea.Tick(cooldown, func(time.Time) tea.Msg { return cooldownDoneMsg{id: entry.id} }),
+		m.startSpinner(),
+	)
+}
+
+// releaseCooling authorizes the cooling submission id once its cooldown ends.
+func (m model) releaseCooling(id int) (model, tea.Cmd) {
+	for i, cooling := range m.cooling {
+		if cooling.entry.id != id {
+			continue
+		}
+		m.cooling = append(m.cooling[:i:i], m.cooling[i+1:]...)
+		entry := cooling.entry
+		if entry.network != m.currentNetwork {
+			return m, m.rejectSubmission(entry, "network switched during the cooldown")
+		}
+		for _, txID := range entry.txIDs {
+			if tx := m.findTx(txID); tx != nil && tx.status == txCooling {
+				tx.status = txQueued
+				tx.updatedAt = time.Now()
+			}
+		}
+		return m.authorize(entry)
+	}
+	return m, nil // Cancelled during the cooldown
+}
+
+// cancelCooling drops the most recent cooling submission.
+func (m *model) cancelCooling() tea.Cmd {
+	last := m.cooling[len(m.cooling)-1]
+	m.cooling = m.cooling[:len(m.cooling)-1]
+	return m.rejectSubmission(last.entry, "cancelled during the cooldown")
+}
+
+// cooldownLeft returns the whole seconds left before the cooling submission
+// holding the tracked transaction txID is broadcast.
+func (m model) cooldownLeft(txID int) (int, bool) {
+	for _, cooling := range m.cooling {
+		for _, id := range cooling.entry.txIDs {
+			if id == txID {
+				return secondsUntil(cooling.until), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// secondsUntil rounds the time left until t up to whole seconds.
+func secondsUntil(t time.Time) int {
+	return int(math.Ceil(time.Until(t).Seconds()))
+}
+
+// cooldownStatus describes the most recent cooling submission for the
+// command area.
+func (m model) cooldownStatus() string {
+	last := m.cooling[len(m.cooling)-1]
+	return fmt.Sprintf("⏳ Broadcasting in %ds: %s • ESC to cancel", max(secondsUntil(last.until), 0), last.entry.command)
+}
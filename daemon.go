@@ -0,0 +1,569 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultDaemonListen   = "127.0.0.1:9464"
+	defaultDaemonInterval = 5 * time.Minute
+	daemonCommand         = "daemon"
+)
+
+// Daemon configures "gasms daemon".
+type Daemon struct {
+	Listen   string `yaml:"listen,omitempty"`   // Address of /healthz and /metrics (default 127.0.0.1:9464)
+	Interval string `yaml:"interval,omitempty"` // Time between refreshes (default 5m)
+	Webhook  string `yaml:"webhook,omitempty"`  // POST alerts as {"text": ...} here (default receipts.webhook)
+}
+
+func (d Daemon) interval() time.Duration {
+	interval, err := time.ParseDuration(d.Interval)
+	if err != nil || interval < time.Minute {
+		return defaultDaemonInterval
+	}
+	return interval
+}
+
+// AutoUpstake keeps the stake of every staked application above a floor, in
+// upokt. Applications below MinStake are upstaked to TopUpTo by gasms daemon,
+// which only alerts ("suggest") or sends them ("execute").
+type AutoUpstake struct {
+	MinStake int64  `yaml:"min_stake,omitempty"`
+	TopUpTo  int64  `yaml:"top_up_to,omitempty"` // Defaults to MinStake
+	Mode     string `yaml:"mode,omitempty"`      // suggest (default) or execute
+}
+
+// topUpTo returns the stake applications are upstaked to.
+func (a AutoUpstake) topUpTo() int64 {
+	if a.TopUpTo < a.MinStake {
+		return a.MinStake
+	}
+	return a.TopUpTo
+}
+
+// daemonApp is the state of an application at the last refresh.
+type daemonApp struct {
+	address   string
+	serviceID string
+	staked    bool
+	stake     int64
+	balance   int64
+}
+
+// daemonNetwork is what the daemon knows of a network, exposed on /metrics.
+type daemonNetwork struct {
+	apps         []daemonApp
+	bank         int64
+	refreshedAt  time.Time // Last successful refresh
+	refreshErr   error
+	refreshFails int
+	txs          map[string]int // Transactions sent, by result
+}
+
+// daemon runs the automation of the TUI headlessly.
+type daemon struct {
+	configPath string
+
+	mu       sync.Mutex
+	config   *Config
+	started  time.Time
+	networks map[string]*daemonNetwork
+	alerted  map[string]bool // Conditions already alerted, until they clear
+	alerts   int
+
+	txMu sync.Mutex // Serializes transactions from the bank
+}
+
+// runDaemon implements "gasms daemon", which refreshes every network,
+// alerts, applies the auto-fund and auto-upstake policies and runs the
+// schedules until terminated. SIGHUP reloads the config.
+func runDaemon(args []string) error {
+	flags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	flags.Parse(args)
+
+	config, err := loadCLIConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	d := &daemon{
+		configPath: *configPath,
+		config:     config,
+		started:    time.Now(),
+		networks:   make(map[string]*daemonNetwork),
+		alerted:    make(map[string]bool),
+	}
+
+	listen := config.Config.Daemon.Listen
+	if listen == "" {
+		listen = defaultDaemonListen
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", d.serveHealth)
+	mux.HandleFunc("/metrics", d.serveMetrics)
+	server := &http.Server{Addr: listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	logger.Info("daemon started", "listen", listen, "networks", len(config.Config.Networks),
+		"interval", config.Config.Daemon.interval().String(), "schedules", len(config.Config.Schedules))
+	fmt.Printf("gasms daemon serving /healthz and /metrics on %s\n", listen)
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	go d.refreshLoop(ctx)
+	go d.scheduleLoop(ctx)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM)
+	for {
+		select {
+		case err := <-serverErr:
+			return fmt.Errorf("health endpoint: %w", err)
+		case sig := <-signals:
+			if sig == syscall.SIGHUP {
+				d.reload()
+				continue
+			}
+			logger.Info("daemon stopping", "signal", sig.String())
+			stop()
+			operations.cancelAll()
+			shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			// Wait for a transaction being sent to be recorded
+			d.txMu.Lock()
+			defer d.txMu.Unlock()
+			return server.Shutdown(shutdown)
+		}
+	}
+}
+
+// reload rereads the config, keeping the current one if it is invalid. The
+// listen address only changes on restart.
+func (d *daemon) reload() {
+	config, err := loadCLIConfig(d.configPath)
+	if err != nil {
+		logger.Error("config reload failed; keeping the current config", "error", err)
+		d.alert("reload", fmt.Sprintf("GASMS daemon: config reload failed, keeping the current config: %v", err))
+		return
+	}
+	d.mu.Lock()
+	d.config = config
+	for name := range d.networks {
+		if _, exists := config.Config.Networks[name]; !exists {
+			delete(d.networks, name)
+		}
+	}
+	delete(d.alerted, "reload")
+	d.mu.Unlock()
+	logger.Info("config reloaded", "networks", len(config.Config.Networks), "schedules", len(config.Config.Schedules))
+}
+
+func (d *daemon) currentConfig() *Config {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.config
+}
+
+// refreshLoop refreshes every network right away, then at every interval.
+func (d *daemon) refreshLoop(ctx context.Context) {
+	for {
+		config := d.currentConfig()
+		names := make([]string, 0, len(config.Config.Networks))
+		for name := range config.Config.Networks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if ctx.Err() != nil {
+				return
+			}
+			d.refreshNetwork(config, name)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(config.Config.Daemon.interval()):
+		}
+	}
+}
+
+// scheduleLoop runs the schedules due at the start of every minute.
+func (d *daemon) scheduleLoop(ctx context.Context) {
+	for {
+		now := time.Now()
+		at := now.Truncate(time.Minute).Add(time.Minute)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(at.Sub(now)):
+		}
+		config := d.currentConfig()
+		for _, schedule := range config.Config.Schedules {
+			if !schedule.due(at) || !claimRun(schedule, at) {
+				continue
+			}
+			d.txMu.Lock()
+			report := runScheduled(config, schedule)
+			d.txMu.Unlock()
+			d.recordReport(report)
+		}
+	}
+}
+
+// refreshNetwork queries the applications and bank of network, alerts on
+// low stakes and applies its policies.
+func (d *daemon) refreshNetwork(config *Config, name string) {
+	network := config.Config.Networks[name]
+	var apps []daemonApp
+	var bank int64
+	err := func() error {
+		for _, address := range network.Applications {
+			observed, err := observeApplication(config, name, address)
+			if err != nil {
+				return fmt.Errorf("failed to query %s: %w", address, err)
+			}
+			app := daemonApp{address: address, balance: observed.balance}
+			if observed.app != nil {
+				app.staked = true
+				app.serviceID = observed.app.ServiceID
+				app.stake = stakeUpokt(*observed.app)
+			}
+			apps = append(apps, app)
+		}
+		if network.Bank == "" {
+			return nil
+		}
+		return withFailover(name, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			bank, err = QueryBankBalanceUpokt(network.Bank, endpoint, config.keyringBackend(name), config.pocketdHome(name))
+			return err
+		})
+	}()
+
+	d.mu.Lock()
+	state := d.networks[name]
+	if state == nil {
+		state = &daemonNetwork{txs: make(map[string]int)}
+		d.networks[name] = state
+	}
+	state.refreshErr = err
+	if err != nil {
+		state.refreshFails++
+	} else {
+		state.apps, state.bank, state.refreshedAt = apps, bank, time.Now()
+	}
+	d.mu.Unlock()
+
+	if err != nil {
+		logger.Error("daemon refresh failed", "network", name, "error", err)
+		d.alert("refresh:"+name, fmt.Sprintf("GASMS daemon: refresh of %s failed: %v", name, err))
+		return
+	}
+	d.clear("refresh:" + name)
+	logger.Info("daemon refresh", "network", name, "applications", len(apps), "bank_upokt", bank)
+
+	d.alertStakes(config, name, apps)
+	d.applyPolicies(config, name, apps, bank)
+}
+
+// alertStakes alerts once for every application whose stake fell below the
+// warning or danger threshold.
+func (d *daemon) alertStakes(config *Config, name string, apps []daemonApp) {
+	thresholds := config.Config.Thresholds
+	for _, app := range apps {
+		key := "stake:" + name + ":" + app.address
+		switch {
+		case !app.staked:
+			d.clear(key)
+		case thresholds.DangerThreshold > 0 && app.stake < thresholds.DangerThreshold:
+			d.clear(key + ":warning")
+			d.alert(key+":danger", fmt.Sprintf("GASMS %s: %s stake %s is below the danger threshold of %s",
+				name, app.address, formatPlanAmount(app.stake), formatPlanAmount(thresholds.DangerThreshold)))
+		case thresholds.WarningThreshold > 0 && app.stake < thresholds.WarningThreshold:
+			d.clear(key + ":danger")
+			d.alert(key+":warning", fmt.Sprintf("GASMS %s: %s stake %s is below the warning threshold of %s",
+				name, app.address, formatPlanAmount(app.stake), formatPlanAmount(thresholds.WarningThreshold)))
+		default:
+			d.clear(key + ":danger")
+			d.clear(key + ":warning")
+		}
+	}
+}
+
+// policyPlan returns the transactions the auto-fund and auto-upstake policies
+// of network send, and summaries of those they only suggest. Applications are
+// funded for their own upstakes first, as in "gasms plan".
+func policyPlan(config *Config, name string, apps []daemonApp, bank int64) (*stakePlan, []string) {
+	network := config.Config.Networks[name]
+	fund, upstake := network.AutoFund, network.AutoUpstake
+	plan := &stakePlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now(),
+		Operator:         auditOperator(),
+		Network:          name,
+		Bank:             network.Bank,
+		BankBalanceUpokt: bank,
+	}
+	var funds, upstakes []planItem
+	var fundSuggested, upstakeSuggested int
+	for _, app := range apps {
+		target := app.balance
+		if fund.MinBalance > 0 && app.balance < fund.MinBalance {
+			if fund.Mode == autoFundExecute {
+				target = fund.topUpTo()
+			} else {
+				fundSuggested++
+			}
+		}
+		if upstake.MinStake > 0 && app.staked && app.stake < upstake.MinStake {
+			if upstake.Mode == autoFundExecute {
+				amount := upstake.topUpTo() - app.stake
+				upstakes = append(upstakes, planItem{
+					Action:       planUpstake,
+					Address:      app.address,
+					ServiceID:    app.serviceID,
+					AmountUpokt:  amount,
+					CurrentUpokt: app.stake,
+					TargetUpokt:  upstake.topUpTo(),
+				})
+				// The application pays for its upstake and keeps its floor
+				if required := amount + network.appFeeUpokt() + fund.MinBalance; target < required {
+					target = required
+				}
+			} else {
+				upstakeSuggested++
+			}
+		}
+		if target > app.balance {
+			funds = append(funds, planItem{
+				Action:       planFund,
+				Address:      app.address,
+				AmountUpokt:  target - app.balance,
+				CurrentUpokt: app.balance,
+				TargetUpokt:  target,
+			})
+			plan.BankRequiredUpokt += target - app.balance + network.txFeeUpokt()
+		}
+	}
+	plan.Items = append(funds, upstakes...)
+
+	var suggestions []string
+	if fundSuggested > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%d apps are below the auto-fund floor of %s",
+			fundSuggested, formatPlanAmount(fund.MinBalance)))
+	}
+	if upstakeSuggested > 0 {
+		suggestions = append(suggestions, fmt.Sprintf("%d apps are below the auto-upstake floor of %s",
+			upstakeSuggested, formatPlanAmount(upstake.MinStake)))
+	}
+	return plan, suggestions
+}
+
+// applyPolicies sends the transactions of the executing policies of network
+// and alerts the suggested ones.
+func (d *daemon) applyPolicies(config *Config, name string, apps []daemonApp, bank int64) {
+	plan, suggestions := policyPlan(config, name, apps, bank)
+	if len(suggestions) > 0 {
+		d.alert("suggest:"+name+":"+strings.Join(suggestions, ","), fmt.Sprintf("GASMS %s: %s", name, strings.Join(suggestions, "; ")))
+	} else {
+		d.clearPrefix("suggest:" + name + ":")
+	}
+	if len(plan.Items) == 0 {
+		d.clear("policy:" + name)
+		return
+	}
+	command := daemonCommand + " policies"
+	if err := unattendedRefusal(config, plan); err != nil {
+		logger.Warn("daemon policies refused", "network", name, "items", len(plan.Items), "error", err)
+		d.alert("policy:"+name, fmt.Sprintf("GASMS %s: %d policy transactions not sent: %v", name, len(plan.Items), err))
+		return
+	}
+	d.clear("policy:" + name)
+
+	started := time.Now()
+	logger.Info("daemon policies executing", "network", name, "items", len(plan.Items), "bank_required_upokt", plan.BankRequiredUpokt)
+	ctx := operations.context()
+	d.txMu.Lock()
+	var receipts []planReceipt
+	for _, item := range plan.Items {
+		if ctx.Err() != nil {
+			break // Stopping
+		}
+		receipt := applyItem(config, plan, item)
+		auditPlanReceipt(plan, command, receipt)
+		receipts = append(receipts, receipt)
+	}
+	d.txMu.Unlock()
+	report := planReport("policies", command, plan, receipts, started)
+	logger.Info("daemon policies finished", "network", name, "included", report.Included, "failed", report.Failed)
+	exportUnattended(config, report)
+	d.recordReport(report)
+}
+
+// recordReport counts the transactions of a finished run and alerts its
+// failures.
+func (d *daemon) recordReport(report batchReport) {
+	d.mu.Lock()
+	if state := d.networks[report.Network]; state != nil {
+		state.txs[string(txIncluded)] += report.Included
+		state.txs[string(txFailed)] += report.Failed
+	}
+	d.mu.Unlock()
+	if report.Failed > 0 || report.Error != "" {
+		d.post(report.Text)
+	}
+}
+
+// alert posts text the first time condition key is seen, until it clears.
+func (d *daemon) alert(key, text string) {
+	d.mu.Lock()
+	seen := d.alerted[key]
+	d.alerted[key] = true
+	d.mu.Unlock()
+	if seen {
+		return
+	}
+	logger.Warn("alert", "text", text)
+	d.post(text)
+}
+
+func (d *daemon) clear(key string) {
+	d.mu.Lock()
+	delete(d.alerted, key)
+	d.mu.Unlock()
+}
+
+func (d *daemon) clearPrefix(prefix string) {
+	d.mu.Lock()
+	for key := range d.alerted {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.alerted, key)
+		}
+	}
+	d.mu.Unlock()
+}
+
+// post sends text to the alert webhook, if any.
+func (d *daemon) post(text string) {
+	config := d.currentConfig()
+	d.mu.Lock()
+	d.alerts++
+	d.mu.Unlock()
+	webhook := config.Config.Daemon.Webhook
+	if webhook == "" {
+		webhook = config.Config.Receipts.Webhook
+	}
+	if webhook == "" {
+		return
+	}
+	data, _ := json.Marshal(map[string]string{"text": text})
+	if err := postReceipts(webhook, data); err != nil {
+		logger.Error("failed to post alert", "error", err)
+	}
+}
+
+// health returns why the daemon is unhealthy, or nil: every network must have
+// been refreshed within three intervals.
+func (d *daemon) health() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	stale := 3 * d.config.Config.Daemon.interval()
+	var errs []error
+	for name := range d.config.Config.Networks {
+		state := d.networks[name]
+		switch {
+		case state == nil || state.refreshedAt.IsZero():
+			if time.Since(d.started) > stale {
+				errs = append(errs, fmt.Errorf("%s: never refreshed", name))
+			} else if state == nil || state.refreshErr == nil {
+				errs = append(errs, fmt.Errorf("%s: first refresh running", name))
+			} else {
+				errs = append(errs, fmt.Errorf("%s: %v", name, state.refreshErr))
+			}
+		case time.Since(state.refreshedAt) > stale:
+			errs = append(errs, fmt.Errorf("%s: last refreshed %s ago: %v", name,
+				time.Since(state.refreshedAt).Round(time.Second), state.refreshErr))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (d *daemon) serveHealth(w http.ResponseWriter, r *http.Request) {
+	if err := d.health(); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "ok")
+}
+
+// serveMetrics writes the last refreshed state in the Prometheus text format.
+func (d *daemon) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	names := make([]string, 0, len(d.networks))
+	for name := range d.networks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	metric := func(name, help, kind string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	metric("gasms_application_stake_upokt", "Stake of the application at the last refresh.", "gauge")
+	for _, name := range names {
+		for _, app := range d.networks[name].apps {
+			if app.staked {
+				fmt.Fprintf(&b, "gasms_application_stake_upokt{network=%q,address=%q} %d\n", name, app.address, app.stake)
+			}
+		}
+	}
+	metric("gasms_application_balance_upokt", "Liquid balance of the application at the last refresh.", "gauge")
+	for _, name := range names {
+		for _, app := range d.networks[name].apps {
+			fmt.Fprintf(&b, "gasms_application_balance_upokt{network=%q,address=%q} %d\n", name, app.address, app.balance)
+		}
+	}
+	metric("gasms_bank_balance_upokt", "Balance of the bank at the last refresh.", "gauge")
+	for _, name := range names {
+		if bank := d.config.Config.Networks[name].Bank; bank != "" && !d.networks[name].refreshedAt.IsZero() {
+			fmt.Fprintf(&b, "gasms_bank_balance_upokt{network=%q,address=%q} %d\n", name, bank, d.networks[name].bank)
+		}
+	}
+	metric("gasms_last_refresh_timestamp_seconds", "Time of the last successful refresh.", "gauge")
+	for _, name := range names {
+		if refreshed := d.networks[name].refreshedAt; !refreshed.IsZero() {
+			fmt.Fprintf(&b, "gasms_last_refresh_timestamp_seconds{network=%q} %d\n", name, refreshed.Unix())
+		}
+	}
+	metric("gasms_refresh_failures_total", "Refreshes that failed.", "counter")
+	for _, name := range names {
+		fmt.Fprintf(&b, "gasms_refresh_failures_total{network=%q} %d\n", name, d.networks[name].refreshFails)
+	}
+	metric("gasms_transactions_total", "Transactions sent by policies and schedules, by result.", "counter")
+	for _, name := range names {
+		for _, result := range []string{string(txIncluded), string(txFailed)} {
+			fmt.Fprintf(&b, "gasms_transactions_total{network=%q,result=%q} %d\n", name, result, d.networks[name].txs[result])
+		}
+	}
+	metric("gasms_alerts_total", "Alerts raised.", "counter")
+	fmt.Fprintf(&b, "gasms_alerts_total %d\n", d.alerts)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprint(w, b.String())
+}
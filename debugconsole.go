@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// commandLogSize bounds how many pocketd invocations are kept in memory
+	commandLogSize = 200
+	// debugPaneEntries is how many invocations the debug pane shows
+	debugPaneEntries = 5
+	// debugOutputWidth bounds the output excerpt kept per invocation
+	debugOutputWidth = 300
+)
+
+// commandRecord is a single pocketd invocation shown in the debug console.
+type commandRecord struct {
+	Args     []string
+	Started  time.Time
+	Duration time.Duration
+	ExitCode int // -1 if the command could not be started
+	Output   string
+}
+
+// commandLog is a bounded, concurrency-safe history of pocketd invocations.
+type commandLog struct {
+	mu      sync.Mutex
+	records []commandRecord
+}
+
+var pocketdLog = &commandLog{}
+
+func (l *commandLog) add(record commandRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.records = append(l.records, record)
+	if len(l.records) > commandLogSize {
+		l.records = l.records[len(l.records)-commandLogSize:]
+	}
+}
+
+// recent returns up to n of the most recent invocations, newest first.
+func (l *commandLog) recent(n int) []commandRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var records []commandRecord
+	for i := len(l.records) - 1; i >= 0 && len(records) < n; i-- {
+		records = append(records, l.records[i])
+	}
+	return records
+}
+
+func (l *commandLog) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.records)
+}
+
+// runPocketd executes pocketd with args and returns its combined output,
+// recording the invocation for the debug console.
+func runPocketd(args []string) ([]byte, error) {
+	stdin, err := keyrings.stdin(args)
+	if err != nil {
+		return nil, err
+	}
+	pocketdLimiter.wait()
+	ctx, cancel, timeout := operations.commandContext(args)
+	defer cancel()
+	started := time.Now()
+	cmd := exec.CommandContext(ctx, pocketdBinary.name(), args...)
+	// Do not wait on output pipes held open by children of a killed pocketd
+	cmd.WaitDelay = time.Second
+	cmd.Stdin = stdin
+	output, err := cmd.CombinedOutput()
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("pocketd timed out after %s: %w", timeout, ctx.Err())
+	case ctx.Err() != nil:
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		switch {
+		case stdin != nil && isPassphraseRejected(string(output)):
+			keyrings.forget(args)
+			err = fmt.Errorf("keyring passphrase rejected: %w", err)
+		case stdin == nil && strings.Contains(string(output), "Enter keyring passphrase"):
+			// The backend is not given on the command line, so it was not prompted for
+			err = fmt.Errorf("pocketd asked for a keyring passphrase: set keyring-backend in %s: %w", configFile, err)
+		}
+	}
+
+	exitCode := 0
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			exitCode = exitErr.ExitCode()
+		} else {
+			exitCode = -1
+		}
+	}
+
+	excerpt := strings.Join(strings.Fields(string(output)), " ")
+	if err != nil && len(output) == 0 {
+		excerpt = err.Error()
+	}
+	excerpt = truncateToWidth(excerpt, debugOutputWidth)
+
+	record := commandRecord{
+		Args:     append([]string(nil), args...),
+		Started:  started,
+		Duration: time.Since(started),
+		ExitCode: exitCode,
+		Output:   excerpt,
+	}
+	pocketdLog.add(record)
+
+	if exitCode != 0 {
+		logger.Warn("pocketd command failed", "args", record.Args, "duration_ms", record.Duration.Milliseconds(), "exit_code", exitCode, "output", excerpt)
+	} else {
+		logger.Debug("pocketd command", "args", record.Args, "duration_ms", record.Duration.Milliseconds())
+	}
+	return output, err
+}
+
+type debugTickMsg struct{}
+
+// debugTickCmd redraws the debug pane while it is open so commands finishing
+// in the background show up without a key press.
+func debugTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg {
+		return debugTickMsg{}
+	})
+}
+
+func (m model) toggleDebug() (model, tea.Cmd) {
+	m.showDebug = !m.showDebug
+	if m.showDebug {
+		return m, debugTickCmd()
+	}
+	return m, nil
+}
+
+// debugPaneHeight returns the number of lines taken by the debug pane.
+func (m model) debugPaneHeight() int {
+	if !m.showDebug {
+		return 0
+	}
+	return lipgloss.Height(m.renderDebugPane())
+}
+
+// renderDebugPane renders the most recent pocketd invocations.
+func (m model) renderDebugPane() string {
+	width := m.width
+	if width < 20 {
+		width = 80
+	}
+
+	borderStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("65")) // Muted green
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true)
+	okStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("46")) // Bright green
+	failStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")) // Bright red
+	outputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("243")) // Grey
+
+	lines := []string{
+		borderStyle.Render(strings.Repeat("─", width)),
+		titleStyle.Render(fmt.Sprintf("🐞 DEBUG: pocketd commands (%d recorded, ctrl+l to close)", pocketdLog.len())),
+	}
+
+	records := pocketdLog.recent(debugPaneEntries)
+	if len(records) == 0 {
+		lines = append(lines, outputStyle.Render("No pocketd commands executed yet"))
+	}
+	for _, record := range records {
+		status := okStyle.Render("exit 0")
+		if record.ExitCode != 0 {
+			status = failStyle.Render(fmt.Sprintf("exit %d", record.ExitCode))
+		}
+		command := "pocketd " + strings.Join(record.Args, " ")
+		header := fmt.Sprintf("%s %s %6dms ", record.Started.Format("15:04:05"), status, record.Duration.Milliseconds())
+		lines = append(lines, header+truncateToWidth(command, max(width-lipgloss.Width(header), 10)))
+		lines = append(lines, outputStyle.Render("  "+truncateToWidth(record.Output, width-2)))
+	}
+
+	return strings.Join(lines, "\n")
+}
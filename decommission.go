@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// Steps of a decommission, in order
+const (
+	decommissionUndelegate = "undelegate"
+	decommissionUnstake    = "unstake"
+	decommissionUnbond     = "unbond"
+	decommissionSweep      = "sweep"
+	decommissionConfig     = "config"
+)
+
+const decommissionUsage = "decommission <address>"
+
+// unstakeApplication starts the unstake of the application address, signed
+// by the application. The stake returns to its balance after the unbonding
+// period.
+func unstakeApplication(address string, config *Config, networkName string) (string, error) {
+	return applicationTx(address, []string{"unstake-application"}, config, networkName)
+}
+
+// undelegateFromGateway removes the delegation of the application address
+// to gateway, signed by the application.
+func undelegateFromGateway(address, gateway string, config *Config, networkName string) (string, error) {
+	return applicationTx(address, []string{"undelegate-from-gateway", gateway}, config, networkName)
+}
+
+// handleDecommissionCommand stages "decommission <address>", or shows the
+// last decommission when no address is given.
+func (m model) handleDecommissionCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) == 1 {
+		return m.showLastJob(jobDecommission, decommissionUsage)
+	}
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: %s", decommissionUsage)
+		return m, nil
+	}
+	address := parts[1]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("no config loaded")
+		return m, nil
+	}
+	if m.config.Config.Networks[m.currentNetwork].Bank == "" {
+		m.err = fmt.Errorf("no bank configured for %s to sweep the balance to", m.currentNetwork)
+		return m, nil
+	}
+	if err := m.checkJobFree(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	j := m.newJob(jobDecommission, address,
+		decommissionUndelegate, "Undelegate from every gateway",
+		decommissionUnstake, "Unstake",
+		decommissionUnbond, "Wait for the unbonding period",
+		decommissionSweep, "Sweep the balance to the bank",
+		decommissionConfig, "Remove from "+configFile,
+	)
+	return m.showJob(j)
+}
+
+// decommissionStep runs the current decommission step against the queried
+// state.
+func (m *model) decommissionStep(j *job, msg jobStateMsg) tea.Cmd {
+	switch j.steps[j.current].name {
+	case decommissionUndelegate:
+		if msg.app == nil || len(msg.app.DelegateeGateways) == 0 {
+			return m.finishJobStep(j, "no delegations")
+		}
+		if isUnstaking(*msg.app) {
+			return m.finishJobStep(j, "already unstaking")
+		}
+		// One gateway per transaction; the step runs again after each one
+		gateway := msg.app.DelegateeGateways[0]
+		j.steps[j.current].detail = fmt.Sprintf("undelegating from %s (%d left)", TruncateAddress(gateway, 20), len(msg.app.DelegateeGateways))
+		command := fmt.Sprintf("decommission undelegate %s %s", j.address, gateway)
+		return m.submitJobTx(j, "undelegate", command, 0, func(config *Config, network string) (string, error) {
+			return undelegateFromGateway(j.address, gateway, config, network)
+		})
+
+	case decommissionUnstake:
+		if msg.app == nil {
+			return m.finishJobStep(j, "not staked")
+		}
+		if isUnstaking(*msg.app) {
+			return m.finishJobStep(j, fmt.Sprintf("unstaking at %d", msg.app.UnstakingHeight))
+		}
+		stake := stakeUpokt(*msg.app)
+		command := fmt.Sprintf("decommission unstake %s", j.address)
+		return m.submitJobTx(j, "unstake", command, stake, func(config *Config, network string) (string, error) {
+			return unstakeApplication(j.address, config, network)
+		})
+
+	case decommissionUnbond:
+		if msg.app == nil {
+			return m.finishJobStep(j, fmt.Sprintf("unbonded, balance %s %s", m.formatAmount(msg.balance), m.unitLabel()))
+		}
+		if !isUnstaking(*msg.app) {
+			return m.failJobStep(j, "the application is staked and not unstaking")
+		}
+		detail := fmt.Sprintf("unstaking at %d", msg.app.UnstakingHeight)
+		if left, ok := m.blocksUntil(msg.app.UnstakingHeight); ok {
+			detail = fmt.Sprintf("unstaking, %d blocks left", left)
+		}
+		return m.waitJobStep(j, detail+" • checked every 30s")
+
+	case decommissionSweep:
+		if msg.app != nil {
+			return m.failJobStep(j, "the application is still staked")
+		}
+		// The application pays the fee of the sweep
+		amount := msg.balance - m.config.Config.Networks[j.network].txFeeUpokt()
+		if amount <= 0 {
+			return m.finishJobStep(j, "nothing to sweep")
+		}
+		command := fmt.Sprintf("decommission sweep %s %d", j.address, amount)
+		return m.submitJobTx(j, "sweep", command, amount, func(config *Config, network string) (string, error) {
+			return bankSend(j.address, j.bank, amount, config, network)
+		})
+
+	case decommissionConfig:
+		if !slices.Contains(m.config.Config.Networks[j.network].Applications, j.address) {
+			return m.finishJobStep(j, "not in "+configFile)
+		}
+		network, address := j.network, j.address
+		return m.saveJobConfig(j, fmt.Sprintf("Removed %s from %s", TruncateAddress(address, 20), network), func(root *yaml.Node) error {
+			return removeApplication(root, network, address)
+		})
+	}
+	return nil
+}
+
+// removeApplication removes address from the applications of network in
+// the config document, wherever it is listed, along with its label and
+// app_targets override.
+func removeApplication(root *yaml.Node, network, address string) error {
+	row := configRow{field: "applications", value: address}
+	if err := removeConfigValue(root, network, row); err != nil {
+		return err
+	}
+	node, err := networkNode(root, network)
+	if err != nil {
+		return err
+	}
+	// An application may be listed under several gateways
+	for list, _ := findConfigItem(node, row); list != nil; list, _ = findConfigItem(node, row) {
+		if err := removeConfigValue(root, network, row); err != nil {
+			return err
+		}
+	}
+	for _, key := range []string{"labels", "app_targets"} {
+		mapping := mappingValue(node, key, yaml.MappingNode, false)
+		if mapping == nil || mapping.Kind != yaml.MappingNode {
+			continue
+		}
+		for i := 0; i+1 < len(mapping.Content); i += 2 {
+			if mapping.Content[i].Value == address {
+				mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+				break
+			}
+		}
+	}
+	return nil
+}
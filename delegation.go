@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// delegationWarnMargin is how close to max_delegated_gateways an application
+// gets before it is flagged: one more delegation would reach the limit.
+const delegationWarnMargin = 1
+
+// maxDelegations returns the on-chain max_delegated_gateways, or 0 while the
+// parameters of the current network are unknown.
+func (m model) maxDelegations() int64 {
+	if m.params == nil || m.paramsNetwork != m.currentNetwork {
+		return 0
+	}
+	return m.params.application.MaxDelegatedGateways
+}
+
+// delegationsNearMax reports whether app can take at most
+// delegationWarnMargin more gateways.
+func (m model) delegationsNearMax(app Application) bool {
+	limit := m.maxDelegations()
+	return limit > 0 && int64(len(app.DelegateeGateways)) >= limit-delegationWarnMargin
+}
+
+// formatDelegations renders the delegation count of app against the limit,
+// flagging applications near or at it.
+func (m model) formatDelegations(app Application) string {
+	count := int64(len(app.DelegateeGateways))
+	limit := m.maxDelegations()
+	if limit == 0 {
+		return fmt.Sprintf("%d", count)
+	}
+	text := fmt.Sprintf("%d/%d", count, limit)
+	switch {
+	case count >= limit:
+		text += " ⛔"
+	case m.delegationsNearMax(app):
+		text += " ⚠️"
+	}
+	return text
+}
+
+// nearMaxDelegationCount returns how many loaded applications are near or at
+// the delegation limit.
+func (m model) nearMaxDelegationCount() int {
+	count := 0
+	for _, app := range m.applications {
+		if m.delegationsNearMax(app) {
+			count++
+		}
+	}
+	return count
+}
+
+// delegationSummary flags applications near the delegation limit in the
+// header.
+func (m model) delegationSummary() string {
+	if count := m.nearMaxDelegationCount(); count > 0 {
+		return fmt.Sprintf(" (🔗 %d near max delegations)", count)
+	}
+	return ""
+}
+
+// renderDelegations lists the gateways app delegates to for the details view.
+func (m model) renderDelegations(address string) string {
+	var app Application
+	found := false
+	for _, candidate := range m.applications {
+		if candidate.Address == address {
+			app, found = candidate, true
+			break
+		}
+	}
+	if !found {
+		return "  Not loaded"
+	}
+	lines := []string{"  Delegations: " + m.formatDelegations(app)}
+	for _, gateway := range app.DelegateeGateways {
+		marker := "  "
+		if gateway == m.currentGateway {
+			marker = "* "
+		}
+		lines = append(lines, "    "+marker+gateway)
+	}
+	limit := m.maxDelegations()
+	switch count := int64(len(app.DelegateeGateways)); {
+	case limit > 0 && count >= limit:
+		lines = append(lines, "  ⛔ At max_delegated_gateways: undelegate from a gateway before adding another")
+	case m.delegationsNearMax(app):
+		lines = append(lines, fmt.Sprintf("  ⚠️ %d more delegation(s) allowed by max_delegated_gateways", limit-count))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// discrepancy is an application whose on-chain state disagrees with config.
+type discrepancy struct {
+	address    string
+	configured bool   // Listed in config but not delegated to a configured gateway
+	detail     string // What the chain reports instead
+}
+
+// discrepancies cross-checks the configured applications of the current
+// network with the chain. It returns ok=false until on-chain data is loaded.
+func (m model) discrepancies() (result []discrepancy, ok bool) {
+	if m.config == nil || m.stakedApps == nil {
+		return nil, false
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil, false
+	}
+
+	ourGateways := make(map[string]bool, len(network.Gateways))
+	for _, gateway := range network.Gateways {
+		ourGateways[gateway] = true
+	}
+	delegatesToUs := func(app Application) bool {
+		for _, gateway := range app.DelegateeGateways {
+			if ourGateways[gateway] {
+				return true
+			}
+		}
+		return false
+	}
+
+	configured := make(map[string]bool, len(network.Applications))
+	for _, address := range network.Applications {
+		configured[address] = true
+		app, staked := m.stakedApps[address]
+		switch {
+		case !staked:
+			result = append(result, discrepancy{address: address, configured: true, detail: "not staked"})
+		case !delegatesToUs(app):
+			detail := "staked, no gateway delegations"
+			if len(app.DelegateeGateways) > 0 {
+				var gateways []string
+				for _, gateway := range app.DelegateeGateways {
+					gateways = append(gateways, TruncateAddress(gateway, 20))
+				}
+				detail = "delegated to other gateways: " + strings.Join(gateways, ", ")
+			}
+			result = append(result, discrepancy{address: address, configured: true, detail: detail})
+		}
+	}
+
+	for _, app := range m.stakedApps {
+		if configured[app.Address] || !delegatesToUs(app) {
+			continue
+		}
+		result = append(result, discrepancy{
+			address: app.Address,
+			detail:  fmt.Sprintf("staked %s %s for %s, missing from config", m.formatAmount(stakeUpokt(app)), m.unitLabel(), app.ServiceID),
+		})
+	}
+	// Map iteration order is random; keep the panel stable with configured
+	// applications first
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].configured != result[j].configured {
+			return result[i].configured
+		}
+		return result[i].address < result[j].address
+	})
+	return result, true
+}
+
+func (m model) updateDiscrepancies(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "a":
+		if m.role() != roleAdmin {
+			return m, nil
+		}
+		if unmanaged := m.unmanagedApplications(); len(unmanaged) > 0 {
+			m.state = stateTable
+			return m, m.adoptApplications(unmanaged)
+		}
+	}
+	return m, nil
+}
+
+// renderDiscrepancies lists applications whose on-chain state disagrees with
+// the configuration of the current network.
+func (m model) renderDiscrepancies() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🔍 DISCREPANCIES • %s", m.currentNetwork)), ""}
+
+	list, ok := m.discrepancies()
+	switch {
+	case !ok:
+		content = append(content, textStyle.Render("Waiting for on-chain data; discrepancies are checked after the next refresh."))
+	case len(list) == 0:
+		content = append(content, successStyle.Render("✓ Every configured application is delegated to a configured gateway, and every delegated application is configured."))
+	default:
+		var missing, unconfigured []string
+		for _, d := range list {
+			line := warningStyle.Render(fmt.Sprintf("%-45s %s", d.address, d.detail))
+			if d.configured {
+				missing = append(missing, line)
+			} else {
+				unconfigured = append(unconfigured, line)
+			}
+		}
+		if len(missing) > 0 {
+			content = append(content, sectionStyle.Render(fmt.Sprintf("Configured but not on chain for our gateways (%d)", len(missing))))
+			content = append(content, missing...)
+			content = append(content, "")
+		}
+		if len(unconfigured) > 0 {
+			content = append(content, sectionStyle.Render(fmt.Sprintf("Delegated to our gateways but not in config (%d)", len(unconfigured))))
+			content = append(content, unconfigured...)
+			content = append(content, "")
+		}
+	}
+
+	content = append(content, "")
+	if unmanaged := m.unmanagedApplications(); len(unmanaged) > 0 && m.role() == roleAdmin {
+		content = append(content, textStyle.Render(fmt.Sprintf("Press A to adopt the %d delegated to %s into config • ESC or Q to return", len(unmanaged), TruncateAddress(m.currentGateway, 20))))
+	} else {
+		content = append(content, textStyle.Render("Press ESC or Q to return"))
+	}
+	return strings.Join(content, "\n")
+}
+
+// discrepancySummary is the header hint shown when discrepancies exist.
+func (m model) discrepancySummary() string {
+	list, ok := m.discrepancies()
+	if !ok || len(list) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (⚠ %d discrepancies, :disc)", len(list))
+}
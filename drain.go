@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultDrainKeepUpokt is left on each drained application unless drain-keep
+// is configured: enough for a few transaction fees.
+const defaultDrainKeepUpokt = 10 * defaultFeeAmount
+
+// drainItem is the send of one application's balance back to the bank.
+type drainItem struct {
+	txID    int
+	address string
+	amount  int64
+	txHash  string
+	err     string
+}
+
+type drainCompletedMsg struct {
+	items []drainItem
+}
+
+// drainKeep returns the balance left on each application by a drain.
+func (m model) drainKeep() int64 {
+	if m.config != nil && m.config.Config.DrainKeep != nil {
+		return *m.config.Config.DrainKeep
+	}
+	return defaultDrainKeepUpokt
+}
+
+// drainableUpokt returns the balance of app that a drain sends to the bank:
+// everything but the keep-amount and the fee of the send itself.
+func (m model) drainableUpokt(app Application) int64 {
+	return app.BalanceUpokt - m.drainKeep() - m.txFeeUpokt()
+}
+
+// handleDrainCommand sends application balances back to the bank: "drain
+// <address>" for one application, "drain-all" for every configured one.
+func (m model) handleDrainCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	all := parts[0] == "drain-all"
+	if (all && len(parts) != 1) || (!all && len(parts) != 2) {
+		m.err = fmt.Errorf("usage: drain <address> or drain-all")
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	if network.Bank == "" {
+		m.err = fmt.Errorf("bank address not configured for network: %s", m.currentNetwork)
+		return m, nil
+	}
+	if err := m.balancesReady(); err != nil {
+		return m, m.notify(toastError, fmt.Sprintf("Drain refused: %v", err))
+	}
+
+	var items []drainItem
+	if all {
+		addresses := network.applicationsFor(m.currentGateway)
+		configured := make(map[string]bool, len(addresses))
+		for _, address := range addresses {
+			configured[address] = true
+		}
+		for _, app := range m.applications {
+			if amount := m.drainableUpokt(app); configured[app.Address] && amount > 0 {
+				items = append(items, drainItem{address: app.Address, amount: amount})
+			}
+		}
+		if len(items) == 0 {
+			return m, m.notify(toastInfo, fmt.Sprintf("No application holds more than the %s %s kept for fees", m.formatAmount(m.drainKeep()+m.txFeeUpokt()), m.unitLabel()))
+		}
+	} else {
+		address := parts[1]
+		var app *Application
+		for i := range m.applications {
+			if m.applications[i].Address == address {
+				app = &m.applications[i]
+				break
+			}
+		}
+		if app == nil {
+			m.err = fmt.Errorf("application not found: %s", address)
+			return m, nil
+		}
+		amount := m.drainableUpokt(*app)
+		if amount <= 0 {
+			m.err = fmt.Errorf("balance %s %s does not exceed the %s %s kept for fees",
+				m.formatAmount(app.BalanceUpokt), m.unitLabel(), m.formatAmount(m.drainKeep()+m.txFeeUpokt()), m.unitLabel())
+			return m, nil
+		}
+		items = append(items, drainItem{address: address, amount: amount})
+	}
+
+	txIDs := make([]int, len(items))
+	for i := range items {
+		items[i].txID = m.trackTx("drain", fmt.Sprintf("drain %s", items[i].address), []string{items[i].address}, items[i].amount)
+		txIDs[i] = items[i].txID
+	}
+	return m, m.submitTracked(cmd, m.executeDrain(items), txIDs...)
+}
+
+// executeDrain sends each item to the bank, signed by the application.
+func (m model) executeDrain(items []drainItem) tea.Cmd {
+	bank := m.config.Config.Networks[m.currentNetwork].Bank
+	config, networkName := m.config, m.currentNetwork
+	return func() tea.Msg {
+		ctx := operations.context()
+		for i := range items {
+			if ctx.Err() != nil {
+				items[i].err = errCancelled.Error()
+				continue
+			}
+			txHash, err := bankSend(items[i].address, bank, items[i].amount, config, networkName)
+			items[i].txHash = txHash
+			if err != nil {
+				items[i].err = err.Error()
+			}
+		}
+		return drainCompletedMsg{items: items}
+	}
+}
+
+// drainCompleted follows the broadcast drains and refreshes the applications.
+func (m *model) drainCompleted(msg drainCompletedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	var drained int64
+	sent := 0
+	for _, item := range msg.items {
+		if item.err != "" {
+			m.txFailedWith(item.txID, item.txHash, item.err)
+			cmds = append(cmds, m.notify(toastError, fmt.Sprintf("Drain of %s failed: %s", TruncateAddress(item.address, 13), item.err)))
+			continue
+		}
+		drained += item.amount
+		sent++
+		cmds = append(cmds, m.txBroadcasted(item.txID, item.txHash))
+	}
+	if sent > 0 {
+		cmds = append(cmds, m.notify(toastSuccess, fmt.Sprintf("Draining %s %s from %d apps to the bank", m.formatAmount(drained), m.unitLabel(), sent)))
+	}
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+		cmds = append(cmds, m.reloadApplications(network, m.currentNetwork, m.currentGateway))
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// editPlanFields are the columns of an edit-plan file.
+var editPlanFields = []string{"address", "current_stake", "target_stake", "balance", "fund"}
+
+// editPlanFile is a plan file opened in the editor, with the values it was
+// written with so unchanged rows stage nothing.
+type editPlanFile struct {
+	path    string
+	network string
+	targets map[string]string // Target stake as written, by address
+}
+
+type planEditedMsg struct {
+	file editPlanFile
+	err  error
+}
+
+// editorCommand returns the command that edits path: $VISUAL or $EDITOR,
+// which may carry arguments, or the platform's default editor.
+func editorCommand(path string) *exec.Cmd {
+	editor := strings.Fields(os.Getenv("VISUAL"))
+	if len(editor) == 0 {
+		editor = strings.Fields(os.Getenv("EDITOR"))
+	}
+	if len(editor) == 0 {
+		editor = []string{"vi"}
+		if runtime.GOOS == "windows" {
+			editor = []string{"notepad"}
+		}
+	}
+	return exec.Command(editor[0], append(editor[1:], path)...)
+}
+
+// handleEditPlanCommand writes the applications of the table to a plan file
+// and opens it in the editor: "edit-plan".
+func (m model) handleEditPlanCommand() (model, tea.Cmd) {
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	if err := m.balancesReady(); err != nil {
+		return m, m.notify(toastWarning, fmt.Sprintf("Cannot edit a plan yet: %v", err))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# gasms edit-plan • network %s • gateway %s\n", m.currentNetwork, m.currentGateway)
+	fmt.Fprintf(&buf, "# Amounts are in %s unless a unit is given (1500pokt, 2.5kpokt, 250000000upokt).\n", m.unitLabel())
+	buf.WriteString("# Raise target_stake to upstake, set fund to send from the bank; rows left as they\n")
+	buf.WriteString("# are or deleted stage nothing. Save and quit to review the transactions.\n")
+	fmt.Fprintf(&buf, "# %-43s %16s %16s %16s %10s\n", editPlanFields[0], editPlanFields[1], editPlanFields[2], editPlanFields[3], editPlanFields[4])
+	file := editPlanFile{network: m.currentNetwork, targets: make(map[string]string)}
+	for _, app := range m.applications {
+		if isUnstaking(app) {
+			continue
+		}
+		stake := m.formatAmount(stakeUpokt(app))
+		file.targets[app.Address] = stake
+		fmt.Fprintf(&buf, "%-45s %16s %16s %16s %10s\n", app.Address, stake, stake, m.formatAmount(app.BalanceUpokt), "0")
+	}
+	if len(file.targets) == 0 {
+		return m, m.notify(toastInfo, "No staked applications to plan")
+	}
+
+	path, err := writeTempFile("plan-*.txt", buf.Bytes())
+	if err != nil {
+		m.err = fmt.Errorf("failed to write the plan file: %v", err)
+		return m, nil
+	}
+	file.path = path
+	return m, tea.ExecProcess(editorCommand(path), func(err error) tea.Msg {
+		return planEditedMsg{file: file, err: err}
+	})
+}
+
+// planEdited parses the saved plan file and stages its transactions.
+func (m model) planEdited(msg planEditedMsg) (model, tea.Cmd) {
+	data, readErr := os.ReadFile(msg.file.path)
+	os.Remove(msg.file.path)
+	switch {
+	case msg.err != nil:
+		m.err = fmt.Errorf("editor failed: %v", msg.err)
+		return m, nil
+	case readErr != nil:
+		m.err = fmt.Errorf("failed to read the plan file: %v", readErr)
+		return m, nil
+	case msg.file.network != m.currentNetwork:
+		m.err = fmt.Errorf("the network changed to %s while editing the %s plan", m.currentNetwork, msg.file.network)
+		return m, nil
+	}
+	plan, err := m.parseEditedPlan(msg.file, data)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	next, cmd := m.stagePlan(plan, "Nothing to do: the plan was saved without changes")
+	if next.stagedPlan != nil {
+		next.state = statePlanReview
+	}
+	return next, cmd
+}
+
+// parseEditedPlan turns the rows of an edited plan file into a plan: a fund
+// for each fund amount, raised to what the application needs to pay for its
+// upstake, and an upstake for each raised target stake.
+func (m model) parseEditedPlan(file editPlanFile, data []byte) (*stakePlan, error) {
+	network := m.config.Config.Networks[m.currentNetwork]
+	plan := &stakePlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now(),
+		Operator:         m.operator(),
+		Network:          m.currentNetwork,
+		Bank:             network.Bank,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	var funds, upstakes []planItem
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != len(editPlanFields) {
+			return nil, fmt.Errorf("plan line %d: expected %d columns (%s), got %d", line, len(editPlanFields), strings.Join(editPlanFields, ", "), len(fields))
+		}
+		address := fields[0]
+		written, planned := file.targets[address]
+		if !planned {
+			return nil, fmt.Errorf("plan line %d: %s was not in the plan", line, address)
+		}
+		if seen[address] {
+			return nil, fmt.Errorf("plan line %d: %s is listed twice", line, address)
+		}
+		seen[address] = true
+		app, ok := m.findApplication(address)
+		if !ok {
+			return nil, fmt.Errorf("plan line %d: %s is no longer loaded", line, address)
+		}
+
+		targets := Targets{}
+		if fields[2] != written {
+			target, err := m.parseDisplayAmount(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("plan line %d: target_stake: %v", line, err)
+			}
+			if target <= stakeUpokt(app) {
+				return nil, fmt.Errorf("plan line %d: target stake %s %s is not above the current stake of %s %s",
+					line, m.formatAmount(target), m.unitLabel(), m.formatAmount(stakeUpokt(app)), m.unitLabel())
+			}
+			targets.Stake = target
+		}
+		fund, upstake, note := planApplication(address, &app, app.BalanceUpokt, targets, m.minStake(), network.appFeeUpokt())
+		if note != nil {
+			return nil, fmt.Errorf("plan line %d: %s", line, note.Reason)
+		}
+		if amount := fields[4]; amount != "0" {
+			requested, err := m.parseDisplayAmount(amount)
+			if err != nil {
+				return nil, fmt.Errorf("plan line %d: fund: %v", line, err)
+			}
+			if fund == nil || fund.AmountUpokt < requested {
+				fund = &planItem{
+					Action:       planFund,
+					Address:      address,
+					AmountUpokt:  requested,
+					CurrentUpokt: app.BalanceUpokt,
+					TargetUpokt:  app.BalanceUpokt + requested,
+				}
+			}
+		}
+		if fund != nil {
+			funds = append(funds, *fund)
+			plan.BankRequiredUpokt += fund.AmountUpokt + network.txFeeUpokt()
+		}
+		if upstake != nil {
+			upstakes = append(upstakes, *upstake)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read the plan file: %v", err)
+	}
+	plan.Items = append(funds, upstakes...)
+	return plan, nil
+}
+
+func (m model) updatePlanReview(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.stagedPlan == nil {
+		m.state = stateTable
+		return m, nil
+	}
+	switch msg.String() {
+	case "y", "n", "esc":
+		m.state = stateTable
+		return m.confirmStagedPlan(msg, "edit-plan")
+	}
+	return m, nil
+}
+
+// renderPlanReview lists the transactions of an edited plan for
+// confirmation.
+func (m model) renderPlanReview() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	columnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	plan := m.stagedPlan
+	if plan == nil {
+		return ""
+	}
+	unit := m.unitLabel()
+	content := []string{headerStyle.Render("📝 EDITED PLAN • " + plan.Network), ""}
+	content = append(content, columnStyle.Render(fmt.Sprintf("%-8s %-45s %16s %16s %16s", "Action", "Address", "Amount", "Current", "Target")))
+	visible := max(m.height-14, 1)
+	for i, item := range plan.Items {
+		if i == visible && len(plan.Items) > visible {
+			content = append(content, textStyle.Render(fmt.Sprintf("… and %d more", len(plan.Items)-visible)))
+			break
+		}
+		content = append(content, textStyle.Render(fmt.Sprintf("%-8s %-45s %16s %16s %16s", item.Action, item.Address,
+			m.formatAmount(item.AmountUpokt), m.formatAmount(item.CurrentUpokt), m.formatAmount(item.TargetUpokt))))
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render(fmt.Sprintf("Bank: %s %s, needs %s %s for funds and fees",
+		m.formatAmount(plan.BankBalanceUpokt), unit, m.formatAmount(plan.BankRequiredUpokt), unit)))
+	if plan.BankRequiredUpokt > plan.BankBalanceUpokt {
+		content = append(content, warningStyle.Render("The bank balance cannot cover the funds of this plan"))
+	}
+	content = append(content, "")
+	content = append(content, warningStyle.Render("Submit? y to confirm • n or ESC to cancel"))
+	return strings.Join(content, "\n")
+}
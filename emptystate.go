@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tableGateway returns the gateway the table is filtered by, "" when it shows
+// every application staked on the network.
+func (m model) tableGateway() string {
+	if m.showAllApps {
+		return ""
+	}
+	return m.currentGateway
+}
+
+// allAppsLabel marks the gateway in the header when the table is not
+// filtered by it.
+func (m model) allAppsLabel() string {
+	if m.showAllApps {
+		return " (showing all applications, A to filter)"
+	}
+	return ""
+}
+
+// toggleAllApplications switches the table between the applications
+// delegated to the current gateway and every application staked on the
+// network.
+func (m model) toggleAllApplications() (model, tea.Cmd) {
+	if m.config == nil {
+		return m, nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || len(network.Gateways) == 0 {
+		return m, nil
+	}
+	m.showAllApps = !m.showAllApps
+	m.cursor = 0
+	logger.Info("application filter changed", "network", m.currentNetwork, "gateway", m.tableGateway())
+	return m, m.reloadApplications(network, m.currentNetwork, m.currentGateway)
+}
+
+// showsEmptyGateway reports whether a completed refresh found no
+// applications, so the table shows why instead of an empty grid.
+func (m model) showsEmptyGateway() bool {
+	return len(m.applications) == 0 && !m.loading && !m.refreshedAt.IsZero() && m.staleSince.IsZero()
+}
+
+// renderEmptyGateway explains an empty table: the gateway address, how many
+// applications the network has, and where to look for the missing ones.
+func (m model) renderEmptyGateway() string {
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+
+	if m.showAllApps {
+		return strings.Join([]string{
+			"",
+			titleStyle.Render(fmt.Sprintf("🫙 No applications are staked on %s", m.currentNetwork)),
+			"",
+			keyStyle.Render("Press A to show the applications of the current gateway again"),
+		}, "\n")
+	}
+
+	content := []string{
+		"",
+		titleStyle.Render("🫙 No applications are delegated to this gateway"),
+		"",
+		textStyle.Render("Gateway: " + m.currentGateway),
+	}
+	if m.stakedApps != nil {
+		content = append(content, textStyle.Render(fmt.Sprintf(
+			"The query succeeded: %d applications are staked on %s, none of them with this gateway among their delegatee gateways.",
+			len(m.stakedApps), m.currentNetwork)))
+	}
+	content = append(content, "", textStyle.Render("Things to check:"))
+	content = append(content, textStyle.Render("  • The applications delegated to this address, not to another gateway or an application address"))
+	if list, ok := m.discrepancies(); ok {
+		configured := 0
+		for _, d := range list {
+			if d.configured {
+				configured++
+			}
+		}
+		if configured > 0 {
+			content = append(content, textStyle.Render(fmt.Sprintf("  • %d configured applications are unstaked or delegated elsewhere, see :discrepancies", configured)))
+		}
+	}
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 1 {
+		content = append(content, textStyle.Render(fmt.Sprintf("  • %d other gateways are configured for %s, switch with :gateway", len(network.Gateways)-1, m.currentNetwork)))
+	}
+	content = append(content, textStyle.Render("  • The network is the one the applications are staked on (n to switch)"))
+	content = append(content, "")
+	if len(m.stakedApps) > 0 {
+		content = append(content, keyStyle.Render(fmt.Sprintf("Press A to show all %d applications staked on %s", len(m.stakedApps), m.currentNetwork)))
+	}
+	return strings.Join(content, "\n")
+}
+
+// rowGateway returns the gateways an application row is delegated to: its
+// own delegations in the all-applications view, the current gateway
+// otherwise. It is "" for an application delegated to no gateway.
+func (m model) rowGateway(app Application) string {
+	if m.showAllApps {
+		return strings.Join(app.DelegateeGateways, ",")
+	}
+	return m.currentGateway
+}
+
+// groupsByGateway reports whether the table groups its rows under a header
+// per gateway: sorted by gateway in the all-applications view.
+func (m model) groupsByGateway() bool {
+	return m.showAllApps && m.sortBy == "gateway"
+}
+
+// startsGatewayGroup reports whether row i of the table is preceded by a
+// gateway group header when the visible rows begin at start.
+func (m model) startsGatewayGroup(i, start int) bool {
+	if !m.groupsByGateway() {
+		return false
+	}
+	return i == start || m.rowGateway(m.applications[i]) != m.rowGateway(m.applications[i-1])
+}
+
+// gatewayGroupStart returns the first visible row of a grouped table with
+// room for displayRows lines, so the cursor row stays visible below the
+// group headers.
+func (m model) gatewayGroupStart(displayRows int) int {
+	start := max(m.cursor-displayRows+1, 0)
+	for start < m.cursor {
+		lines := 0
+		for i := start; i <= m.cursor; i++ {
+			lines++
+			if m.startsGatewayGroup(i, start) {
+				lines++
+			}
+		}
+		if lines <= displayRows {
+			break
+		}
+		start++
+	}
+	return start
+}
+
+// renderGatewayGroupHeader renders the header above the rows delegated to
+// the gateways of row i, with how many applications they have.
+func (m model) renderGatewayGroupHeader(i int) string {
+	groupStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true)
+
+	gateway := m.rowGateway(m.applications[i])
+	count := 0
+	for _, app := range m.applications {
+		if m.rowGateway(app) == gateway {
+			count++
+		}
+	}
+	label := gateway
+	if label == "" {
+		label = "no gateway"
+	}
+	return groupStyle.Render(fmt.Sprintf("🧱 %s (%d apps)", label, count))
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxErrorLog bounds the error history; the oldest entries are dropped first.
+const maxErrorLog = 100
+
+// errorsHint points from an error toast to the error history.
+const errorsHint = " (:errors)"
+
+// errorEntry is an error of this session, listed in :errors.
+type errorEntry struct {
+	at      time.Time
+	command string // Command or operation that failed
+	text    string
+	output  string // pocketd output included in the error, if any
+}
+
+// errorSource names the command or operation behind msg, for the errors it
+// may cause.
+func errorSource(m model, msg tea.Msg) string {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.state == stateCommand && msg.String() == "enter" {
+			return strings.TrimSpace(m.commandInput)
+		}
+		if m.state == stateAmountForm && msg.String() == "enter" {
+			return fmt.Sprintf("%s %s %s", m.amountForm.action, m.amountForm.address, strings.TrimSpace(m.amountForm.input))
+		}
+		return "key " + msg.String()
+	case txFailedMsg:
+		if tx := m.findTx(msg.txID); tx != nil {
+			return tx.command
+		}
+		return msg.operation
+	case txStatusMsg:
+		if tx := m.findTx(msg.txID); tx != nil {
+			return tx.command
+		}
+	case applicationsLoadedMsg, balanceLoadedMsg:
+		return "refresh"
+	case applicationDetailsLoadedMsg, detailsBalancesLoadedMsg:
+		return "show"
+	case pluginDoneMsg:
+		return msg.name
+	case runbookTickMsg:
+		if msg.runbook != nil {
+			return "run " + msg.runbook.path
+		}
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(fmt.Sprintf("%T", msg), "main."), "Msg")
+}
+
+// recordError adds text to the error history, splitting off the pocketd
+// output it includes.
+func (m *model) recordError(text string) {
+	text = strings.TrimSuffix(text, errorsHint)
+	entry := errorEntry{at: time.Now(), command: m.errorSource, text: text}
+	if i := strings.Index(text, "output: "); i >= 0 {
+		entry.text = strings.TrimRight(text[:i], ", ")
+		entry.output = strings.TrimSpace(text[i+len("output: "):])
+	}
+	m.errorLog = append(m.errorLog, entry)
+	if len(m.errorLog) > maxErrorLog {
+		m.errorLog = m.errorLog[len(m.errorLog)-maxErrorLog:]
+	}
+}
+
+// selectedError returns the index in errorLog of the selected entry.
+func (m model) selectedError() int {
+	return len(m.errorLog) - 1 - m.errorCursor
+}
+
+func (m model) updateErrors(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.errorCursor > 0 {
+			m.errorCursor--
+			m.errorExpanded = false
+		}
+	case "down", "j":
+		if m.errorCursor < len(m.errorLog)-1 {
+			m.errorCursor++
+			m.errorExpanded = false
+		}
+	case "enter":
+		m.errorExpanded = !m.errorExpanded && len(m.errorLog) > 0
+	case "d":
+		if len(m.errorLog) == 0 {
+			return m, nil
+		}
+		i := m.selectedError()
+		m.errorLog = append(m.errorLog[:i:i], m.errorLog[i+1:]...)
+		m.errorCursor = min(m.errorCursor, max(len(m.errorLog)-1, 0))
+		m.errorExpanded = false
+	case "D":
+		m.errorLog = nil
+		m.errorCursor = 0
+		m.errorExpanded = false
+	}
+	return m, nil
+}
+
+// renderErrors lists the errors of this session, newest first, with the
+// details of the selected one when expanded.
+func (m model) renderErrors() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 6).
+		Width(max(m.width-4, 20))
+
+	content := []string{headerStyle.Render(fmt.Sprintf("❌ ERRORS • %d", len(m.errorLog))), ""}
+	if len(m.errorLog) == 0 {
+		content = append(content, textStyle.Render("No errors in this session."))
+	}
+	for cursor := 0; cursor < len(m.errorLog); cursor++ {
+		entry := m.errorLog[len(m.errorLog)-1-cursor]
+		command := entry.command
+		if command == "" {
+			command = "-"
+		}
+		line := truncateToWidth(fmt.Sprintf("%s  %-24s %s", entry.at.Format("15:04:05"), truncateToWidth(command, 24), entry.text), max(m.width-6, 20))
+		if cursor != m.errorCursor {
+			content = append(content, textStyle.Render(line))
+			continue
+		}
+		content = append(content, selectedStyle.Render(line))
+		if m.errorExpanded {
+			content = append(content, detailStyle.Render(entry.text))
+			if entry.output != "" {
+				content = append(content, detailStyle.Foreground(lipgloss.Color("108")).Render("output: "+entry.output))
+			}
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("j/k to select • Enter for details • d to dismiss • D to dismiss all • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
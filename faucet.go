@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const faucetTimeout = 30 * time.Second
+
+// txHashPattern matches a transaction hash returned as plain text.
+var txHashPattern = regexp.MustCompile(`^[0-9A-Fa-f]{64}$`)
+
+// Faucet requests testnet tokens for an address over HTTP. {address} and
+// {denom} in the URL and body are replaced by the recipient and denom.
+type Faucet struct {
+	URL    string `yaml:"url"`
+	Method string `yaml:"method,omitempty"` // Default POST
+	Body   string `yaml:"body,omitempty"`   // JSON request body, none by default
+	Denom  string `yaml:"denom,omitempty"`  // Default upokt
+}
+
+func (f Faucet) enabled() bool {
+	return f.URL != ""
+}
+
+func (f Faucet) method() string {
+	if f.Method != "" {
+		return strings.ToUpper(f.Method)
+	}
+	return http.MethodPost
+}
+
+func (f Faucet) denom() string {
+	if f.Denom != "" {
+		return f.Denom
+	}
+	return "upokt"
+}
+
+// expand fills the placeholders of a URL or body for address.
+func (f Faucet) expand(template, address string) string {
+	return strings.NewReplacer("{address}", address, "{denom}", f.denom()).Replace(template)
+}
+
+// isMainnet reports whether network holds real funds, where no faucet is
+// allowed.
+func isMainnet(network string) bool {
+	return network == "pocket"
+}
+
+// validateFaucets refuses a faucet configured on mainnet.
+func validateFaucets(config *Config) error {
+	for name, network := range config.Config.Networks {
+		if network.Faucet.enabled() && isMainnet(name) {
+			return fmt.Errorf("network %s: faucets are only supported on test networks", name)
+		}
+	}
+	return nil
+}
+
+// faucetCompletedMsg reports the transaction the faucet sent.
+type faucetCompletedMsg struct {
+	txID    int
+	address string
+	txHash  string // "" if the faucet did not return one
+}
+
+// requestFaucet asks the faucet for tokens for address and returns the hash
+// of the transaction it sent, if it reports one.
+func requestFaucet(faucet Faucet, address string) (string, error) {
+	var body io.Reader
+	if faucet.Body != "" {
+		body = strings.NewReader(faucet.expand(faucet.Body, address))
+	}
+	req, err := http.NewRequest(faucet.method(), faucet.expand(faucet.URL, address), body)
+	if err != nil {
+		return "", fmt.Errorf("invalid faucet request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	client := &http.Client{Timeout: faucetTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("faucet request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	output, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read faucet response: %w", err)
+	}
+	text := strings.TrimSpace(string(output))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("faucet returned %s, output: %s", resp.Status, text)
+	}
+	if txHashPattern.MatchString(text) {
+		return strings.ToUpper(text), nil
+	}
+	var response map[string]interface{}
+	if err := json.Unmarshal(output, &response); err == nil {
+		for _, key := range []string{"tx_hash", "txhash", "txHash", "hash"} {
+			if hash, ok := response[key].(string); ok && hash != "" {
+				return strings.ToUpper(hash), nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// handleFaucetCommand requests tokens for "faucet <address>" from the faucet
+// of the current test network and tracks the transaction it sends.
+func (m model) handleFaucetCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: faucet <address>")
+		return m, nil
+	}
+	address := parts[1]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("no config loaded")
+		return m, nil
+	}
+	if isMainnet(m.currentNetwork) {
+		m.err = fmt.Errorf("the faucet is not available on %s", m.currentNetwork)
+		return m, nil
+	}
+	faucet := m.config.Config.Networks[m.currentNetwork].Faucet
+	if !faucet.enabled() {
+		m.err = fmt.Errorf("no faucet configured for %s", m.currentNetwork)
+		return m, nil
+	}
+
+	txID := m.trackTx("faucet", cmd, []string{address}, 0)
+	logger.Info("faucet requested", "network", m.currentNetwork, "address", address)
+	return m, tea.Batch(m.notify(toastInfo, "Requesting tokens for "+address+" from the faucet"), func() tea.Msg {
+		hash, err := requestFaucet(faucet, address)
+		if err != nil {
+			return newTxFailedMsg(txID, "faucet", []string{address}, 0, err)
+		}
+		return faucetCompletedMsg{txID: txID, address: address, txHash: hash}
+	})
+}
+
+// faucetCompleted tracks the transaction sent by the faucet and refreshes
+// the applications, whose balances it changes.
+func (m *model) faucetCompleted(msg faucetCompletedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.txHash == "" {
+		// Nothing to poll; the refresh shows whether the tokens arrived
+		if tx := m.findTx(msg.txID); tx != nil {
+			tx.status = txUnconfirmed
+			tx.err = "the faucet returned no transaction hash"
+			tx.updatedAt = time.Now()
+			auditTx(*tx)
+		}
+		cmds = append(cmds, m.notify(toastWarning, "Faucet accepted the request for "+msg.address+" without a transaction hash"))
+	} else {
+		cmds = append(cmds, m.txBroadcasted(msg.txID, msg.txHash), m.notify(toastSuccess, "FAUCET TXHASH: "+msg.txHash))
+	}
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+		cmds = append(cmds, m.reloadApplications(network, m.currentNetwork, m.currentGateway))
+	}
+	return tea.Batch(cmds...)
+}
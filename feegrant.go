@@ -0,0 +1,428 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// feeGrantMessages are the transactions an application may charge to the
+// bank's fee grant.
+var feeGrantMessages = []string{
+	"/pocket.application.MsgStakeApplication",
+	"/pocket.application.MsgTransferApplication",
+}
+
+// feeGrant is a fee allowance granted by the bank to an application.
+type feeGrant struct {
+	Grantee         string
+	SpendLimit      int64     // In the fee denomination, 0 = unlimited
+	Expiration      time.Time // Zero = never
+	AllowedMessages []string  // Empty = any message
+}
+
+type feeGrantsLoadedMsg struct {
+	network string
+	grants  map[string]feeGrant
+	err     error
+}
+
+// feeGrantItem is the grant of a fee allowance to one application.
+type feeGrantItem struct {
+	txID    int
+	address string
+	txHash  string
+	err     string
+}
+
+type feeGrantsSubmittedMsg struct {
+	items []feeGrantItem
+}
+
+// feeGranterArgs returns the flag that charges the fee of a transaction
+// signed by an application to the bank, when fee_grant is enabled.
+func feeGranterArgs(network Network) []string {
+	if !network.FeeGrant || network.Bank == "" {
+		return nil
+	}
+	return []string{"--fee-granter=" + network.Bank}
+}
+
+// appFeeUpokt returns the fee an application pays from its own balance for a
+// transaction it signs: nothing when the bank grants the fees.
+func (n Network) appFeeUpokt() int64 {
+	if n.FeeGrant && n.Bank != "" {
+		return 0
+	}
+	return n.txFeeUpokt()
+}
+
+// QueryFeeGrants returns the fee allowances granted by granter, by grantee,
+// with their spend limits in denom.
+func QueryFeeGrants(granter, denom, rpcEndpoint, pocketdHome, networkName string) (map[string]feeGrant, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"q", "feegrant", "grants-by-granter", granter, "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID, "--limit", "1000"}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	// Allowances are BasicAllowance, or AllowedMsgAllowance wrapping one
+	type basicAllowance struct {
+		SpendLimit []struct {
+			Denom  string  `json:"denom"`
+			Amount flexInt `json:"amount"`
+		} `json:"spend_limit"`
+		Expiration *time.Time `json:"expiration"`
+	}
+	var response struct {
+		Allowances []struct {
+			Grantee   string `json:"grantee"`
+			Allowance struct {
+				basicAllowance
+				Allowance       *basicAllowance `json:"allowance"`
+				AllowedMessages []string        `json:"allowed_messages"`
+			} `json:"allowance"`
+		} `json:"allowances"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	grants := make(map[string]feeGrant, len(response.Allowances))
+	for _, a := range response.Allowances {
+		basic := a.Allowance.basicAllowance
+		if a.Allowance.Allowance != nil {
+			basic = *a.Allowance.Allowance
+		}
+		grant := feeGrant{Grantee: a.Grantee, AllowedMessages: a.Allowance.AllowedMessages}
+		for _, coin := range basic.SpendLimit {
+			if coin.Denom == denom {
+				grant.SpendLimit = int64(coin.Amount)
+			}
+		}
+		if basic.Expiration != nil {
+			grant.Expiration = *basic.Expiration
+		}
+		grants[a.Grantee] = grant
+	}
+	return grants, nil
+}
+
+func loadFeeGrantsCmd(network, bank, denom, rpcEndpoint, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		var grants map[string]feeGrant
+		err := withFailover(network, rpcEndpoint, func(endpoint string) error {
+			var err error
+			grants, err = QueryFeeGrants(bank, denom, endpoint, pocketdHome, network)
+			return err
+		})
+		return feeGrantsLoadedMsg{network: network, grants: grants, err: err}
+	}
+}
+
+// refreshFeeGrants queries the fee grants of the bank of the current network.
+func (m *model) refreshFeeGrants() tea.Cmd {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || network.Bank == "" {
+		return nil
+	}
+	m.feeGrantsNetwork = m.currentNetwork
+	m.feeGrantsLoading = true
+	return tea.Batch(loadFeeGrantsCmd(m.currentNetwork, network.Bank, network.feeDenom(), network.RPCEndpoint, m.config.pocketdHome(m.currentNetwork)), m.startSpinner())
+}
+
+// applyFeeGrants stores the fee grants loaded for the current network.
+func (m *model) applyFeeGrants(msg feeGrantsLoadedMsg) {
+	if msg.network != m.feeGrantsNetwork {
+		return
+	}
+	m.feeGrantsLoading = false
+	m.feeGrantsErr = msg.err
+	if msg.err != nil {
+		logger.Error("failed to load fee grants", "network", msg.network, "error", msg.err)
+		return
+	}
+	m.feeGrants = msg.grants
+}
+
+// grantFeeAllowance grants grantee an allowance to charge the fees of its
+// stake and transfer transactions to the bank, up to spendLimit in the fee
+// denomination (0 for no limit). Signed by the bank.
+func grantFeeAllowance(grantee string, spendLimit int64, config *Config, networkName string) (string, error) {
+	network := config.Config.Networks[networkName]
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return "", err
+	}
+
+	var output []byte
+	err = withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		args := []string{"tx", "feegrant", "grant",
+			network.Bank,
+			grantee,
+			"--allowed-messages=" + strings.Join(feeGrantMessages, ","),
+			"--node=" + node,
+			"--chain-id=" + chainID,
+			network.feesArg()}
+		if spendLimit > 0 {
+			args = append(args, fmt.Sprintf("--spend-limit=%d%s", spendLimit, network.feeDenom()))
+		}
+		args = append(args, memoArgs(config)...)
+
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
+
+		args = append(args, "-y")
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
+}
+
+// handleGrantCommand grants fee allowances from the bank: "grant <address>
+// [spend_limit]" for one application, "grant-all [spend_limit]" for every
+// application of the current gateway without one. Spend limits are in the fee
+// denomination, upokt unless the network pays fees in another token.
+func (m model) handleGrantCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	all := parts[0] == "grant-all"
+	limitArg := 2 // Index of the optional spend limit
+	if all {
+		limitArg = 1
+	}
+	if len(parts) < limitArg || len(parts) > limitArg+1 {
+		m.err = fmt.Errorf("usage: grant <address> [spend_limit] or grant-all [spend_limit]")
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	if network.Bank == "" {
+		m.err = fmt.Errorf("bank address not configured for network: %s", m.currentNetwork)
+		return m, nil
+	}
+	var spendLimit int64
+	if len(parts) > limitArg {
+		var err error
+		if network.feeDenom() == unitUPOKT {
+			spendLimit, err = parseAmount(parts[limitArg])
+		} else if spendLimit, err = strconv.ParseInt(parts[limitArg], 10, 64); err != nil || spendLimit < 0 {
+			err = fmt.Errorf("expected a whole amount of %s, got %q", network.feeDenom(), parts[limitArg])
+		}
+		if err != nil {
+			m.err = fmt.Errorf("spend limit: %v", err)
+			return m, nil
+		}
+	}
+	grants := m.feeGrants
+	if m.feeGrantsNetwork != m.currentNetwork {
+		grants = nil
+	}
+
+	var addresses []string
+	if all {
+		if grants == nil {
+			return m, tea.Batch(m.notify(toastWarning, "Fee grants not loaded yet; retry grant-all once :grants has loaded"), m.refreshFeeGrants())
+		}
+		for _, address := range network.applicationsFor(m.currentGateway) {
+			if _, granted := grants[address]; !granted {
+				addresses = append(addresses, address)
+			}
+		}
+		if len(addresses) == 0 {
+			return m, m.notify(toastInfo, "Every application of the current gateway already has a fee grant")
+		}
+	} else {
+		address := parts[1]
+		if err := validateAddress(address); err != nil {
+			m.err = err
+			return m, nil
+		}
+		if _, granted := grants[address]; granted {
+			m.err = fmt.Errorf("%s already has a fee grant from the bank", TruncateAddress(address, 13))
+			return m, nil
+		}
+		addresses = []string{address}
+	}
+
+	items := make([]feeGrantItem, len(addresses))
+	txIDs := make([]int, len(addresses))
+	for i, address := range addresses {
+		items[i] = feeGrantItem{
+			txID:    m.trackTx("feegrant", fmt.Sprintf("grant %s", address), []string{address}, network.feeUpokt(spendLimit)),
+			address: address,
+		}
+		txIDs[i] = items[i].txID
+	}
+	return m, m.submitTracked(cmd, m.executeFeeGrants(items, spendLimit), txIDs...)
+}
+
+// executeFeeGrants grants each item in turn. The grants are all signed by the
+// bank, so each one waits for the previous to be included.
+func (m model) executeFeeGrants(items []feeGrantItem, spendLimit int64) tea.Cmd {
+	config, networkName := m.config, m.currentNetwork
+	return func() tea.Msg {
+		ctx := operations.context()
+		for i := range items {
+			if ctx.Err() != nil {
+				items[i].err = errCancelled.Error()
+				continue
+			}
+			txHash, err := grantFeeAllowance(items[i].address, spendLimit, config, networkName)
+			items[i].txHash = txHash
+			if err != nil {
+				items[i].err = err.Error()
+				continue
+			}
+			if i < len(items)-1 {
+				if _, err := waitForInclusion(config, networkName, txHash); err != nil {
+					items[i].err = fmt.Sprintf("inclusion: %v", err)
+				}
+			}
+		}
+		return feeGrantsSubmittedMsg{items: items}
+	}
+}
+
+// feeGrantsSubmitted follows the broadcast grants and reloads the bank's grants.
+func (m *model) feeGrantsSubmitted(msg feeGrantsSubmittedMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	granted := 0
+	for _, item := range msg.items {
+		if item.err != "" {
+			m.txFailedWith(item.txID, item.txHash, item.err)
+			cmds = append(cmds, m.notify(toastError, fmt.Sprintf("Fee grant to %s failed: %s", TruncateAddress(item.address, 13), item.err)))
+			continue
+		}
+		granted++
+		cmds = append(cmds, m.txBroadcasted(item.txID, item.txHash))
+	}
+	if granted > 0 {
+		cmds = append(cmds, m.notify(toastSuccess, fmt.Sprintf("Granted fee allowances to %d apps", granted)))
+	}
+	return tea.Batch(append(cmds, m.refreshFeeGrants())...)
+}
+
+func (m model) updateFeeGrants(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "r":
+		return m, m.refreshFeeGrants()
+	}
+	return m, nil
+}
+
+// renderFeeGrants lists the fee grants of the bank to the configured
+// applications of the current network.
+func (m model) renderFeeGrants() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 2)
+
+	network := m.config.Config.Networks[m.currentNetwork]
+	content := []string{headerStyle.Render(fmt.Sprintf("🎟️  FEE GRANTS • %s • bank %s", m.currentNetwork, TruncateAddress(network.Bank, 20))), ""}
+
+	mode := "disabled: applications pay their own fees (set fee_grant: true to charge them to the bank)"
+	if network.FeeGrant {
+		mode = "enabled: stake and transfer fees are charged to the bank"
+	}
+	content = append(content, textStyle.Render("fee_grant "+mode), "")
+
+	switch {
+	case m.feeGrantsLoading:
+		content = append(content, textStyle.Render(m.spinner()+" Querying fee grants..."))
+	case m.feeGrantsErr != nil:
+		content = append(content, errorStyle.Render(fmt.Sprintf("Failed to load fee grants: %v", m.feeGrantsErr)))
+	case m.feeGrantsNetwork != m.currentNetwork || m.feeGrants == nil:
+		content = append(content, textStyle.Render("Fee grants not loaded; press r to load them."))
+	default:
+		configured := make(map[string]bool, len(network.Applications))
+		var missing []string
+		content = append(content, sectionStyle.Render(fmt.Sprintf("%-45s %-16s %s", "Application", "Spend limit", "Expires")))
+		for _, address := range network.Applications {
+			configured[address] = true
+			grant, granted := m.feeGrants[address]
+			if !granted {
+				missing = append(missing, address)
+				continue
+			}
+			limit := "unlimited"
+			switch {
+			case grant.SpendLimit > 0 && network.feeDenom() == unitUPOKT:
+				limit = m.formatAmount(grant.SpendLimit) + " " + m.unitLabel()
+			case grant.SpendLimit > 0:
+				limit = fmt.Sprintf("%d %s", grant.SpendLimit, network.feeDenom())
+			}
+			expires := "never"
+			if !grant.Expiration.IsZero() {
+				expires = grant.Expiration.Local().Format("2006-01-02 15:04")
+			}
+			content = append(content, successStyle.Render(fmt.Sprintf("%-45s %-16s %s", address, limit, expires)))
+		}
+		for _, address := range missing {
+			content = append(content, warningStyle.Render(fmt.Sprintf("%-45s %s", address, "no grant")))
+		}
+
+		var others []string
+		for grantee := range m.feeGrants {
+			if !configured[grantee] {
+				others = append(others, grantee)
+			}
+		}
+		sort.Strings(others)
+		if len(others) > 0 {
+			content = append(content, "", sectionStyle.Render(fmt.Sprintf("Other grantees of the bank (%d)", len(others))))
+			for _, grantee := range others {
+				content = append(content, textStyle.Render(grantee))
+			}
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("grant <address> [limit] or grant-all [limit] to add grants • r to refresh • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
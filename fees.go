@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+)
+
+// defaultFeeAmount is the fixed fee paid by stake-application and bank send
+// unless the network configures its own.
+const defaultFeeAmount = 20000
+
+// feeDenomPattern matches a Cosmos SDK coin denomination.
+var feeDenomPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9/:._-]{2,127}$`)
+
+// FeeToken is the token transaction fees are paid in, for devnets and chains
+// whose fees are not paid in upokt.
+type FeeToken struct {
+	Denom    string `yaml:"denom,omitempty"`     // Default upokt
+	Amount   int64  `yaml:"amount,omitempty"`    // Fee of stake, send and transfer transactions (default 20000)
+	GasPrice string `yaml:"gas_price,omitempty"` // Price per unit of gas of fund-all multi-sends (default 1)
+}
+
+// feeDenom returns the denomination fees are paid in on n.
+func (n Network) feeDenom() string {
+	if n.Fee.Denom != "" {
+		return n.Fee.Denom
+	}
+	return unitUPOKT
+}
+
+// feeAmount returns the fee of a fixed-fee transaction on n, in its fee
+// denomination.
+func (n Network) feeAmount() int64 {
+	if n.Fee.Amount > 0 {
+		return n.Fee.Amount
+	}
+	return defaultFeeAmount
+}
+
+// gasPrice returns the price of a unit of gas on n, in its fee denomination.
+func (n Network) gasPrice() float64 {
+	if price, err := strconv.ParseFloat(n.Fee.GasPrice, 64); err == nil && price > 0 {
+		return price
+	}
+	return 1
+}
+
+// feesArg returns the --fees flag of a fixed-fee transaction on n.
+func (n Network) feesArg() string {
+	return fmt.Sprintf("--fees=%d%s", n.feeAmount(), n.feeDenom())
+}
+
+// gasPricesArg returns the --gas-prices flag of a --gas=auto transaction on n.
+func (n Network) gasPricesArg() string {
+	return "--gas-prices=" + strconv.FormatFloat(n.gasPrice(), 'f', -1, 64) + n.feeDenom()
+}
+
+// feeUpokt returns the upokt a fee in n's fee denomination takes from the
+// balance paying it: the fee itself when fees are paid in upokt, nothing
+// otherwise.
+func (n Network) feeUpokt(fee int64) int64 {
+	if n.feeDenom() != unitUPOKT {
+		return 0
+	}
+	return fee
+}
+
+// txFeeUpokt returns the upokt a fixed-fee transaction on n costs its payer.
+func (n Network) txFeeUpokt() int64 {
+	return n.feeUpokt(n.feeAmount())
+}
+
+// gasFee returns the fee of gas units of gas on n, in its fee denomination.
+func (n Network) gasFee(gas int64) int64 {
+	return int64(math.Ceil(float64(gas) * n.gasPrice()))
+}
+
+// txFeeUpokt returns the upokt a fixed-fee transaction on the current network
+// costs its payer.
+func (m model) txFeeUpokt() int64 {
+	if m.config == nil {
+		return defaultFeeAmount
+	}
+	return m.config.Config.Networks[m.currentNetwork].txFeeUpokt()
+}
+
+// formatFee renders fee in n's fee denomination, upokt in the display unit.
+func (m model) formatFee(n Network, fee int64) string {
+	if n.feeDenom() == unitUPOKT {
+		return m.formatAmount(fee) + " " + m.unitLabel()
+	}
+	return fmt.Sprintf("%d %s", fee, n.feeDenom())
+}
+
+// fixedFee renders the fee of a fixed-fee transaction on the current network.
+func (m model) fixedFee() string {
+	var network Network
+	if m.config != nil {
+		network = m.config.Config.Networks[m.currentNetwork]
+	}
+	return m.formatFee(network, network.feeAmount())
+}
+
+// validateFeeTokens checks the fee token of every network.
+func validateFeeTokens(config *Config) error {
+	for name, network := range config.Config.Networks {
+		fee := network.Fee
+		if fee.Denom != "" && !feeDenomPattern.MatchString(fee.Denom) {
+			return fmt.Errorf("network %s: invalid fee denom: %s", name, fee.Denom)
+		}
+		if fee.Amount < 0 {
+			return fmt.Errorf("network %s: fee amount must be positive, got %d", name, fee.Amount)
+		}
+		if fee.GasPrice != "" {
+			if price, err := strconv.ParseFloat(fee.GasPrice, 64); err != nil || price <= 0 {
+				return fmt.Errorf("network %s: invalid fee gas_price: %s", name, fee.GasPrice)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// savedFilterNames returns the names of the saved filters in order.
+func (m model) savedFilterNames() []string {
+	if m.config == nil {
+		return nil
+	}
+	var names []string
+	for name := range m.config.Config.Filters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// handleFilterCommand recalls or saves a named search: "filter" opens the
+// picker, "filter <name>" applies a saved filter and "filter save <name>"
+// saves the current search under name.
+func (m model) handleFilterCommand(cmd string) (model, tea.Cmd) {
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	parts := strings.Fields(cmd)
+	switch {
+	case len(parts) == 1:
+		if len(m.config.Config.Filters) == 0 {
+			return m, m.notify(toastInfo, "No saved filters: search with /, then :filter save <name>")
+		}
+		m.filterCursor = 0
+		m.state = stateFilterPicker
+		return m, nil
+	case parts[1] == "save":
+		if len(parts) != 3 {
+			m.err = fmt.Errorf("usage: filter save <name>")
+			return m, nil
+		}
+		return m.saveFilter(parts[2])
+	case len(parts) == 2:
+		return m.applyFilter(parts[1]), nil
+	}
+	m.err = fmt.Errorf("usage: filter [<name>|save <name>]")
+	return m, nil
+}
+
+// applyFilter runs the saved filter name as the search.
+func (m model) applyFilter(name string) model {
+	expression, ok := m.config.Config.Filters[name]
+	if !ok {
+		m.err = fmt.Errorf("no saved filter %q", name)
+		return m
+	}
+	m.searchInput = expression
+	m.performSearch()
+	m.state = stateTable
+	return m
+}
+
+// saveFilter writes the current search to the filters of the config.
+func (m model) saveFilter(name string) (model, tea.Cmd) {
+	expression := strings.TrimSpace(m.searchInput)
+	if expression == "" {
+		m.err = fmt.Errorf("nothing to save: search with / first")
+		return m, nil
+	}
+	if _, err := m.parseSearch(expression); err != nil {
+		m.err = err
+		return m, nil
+	}
+	summary := fmt.Sprintf("Saved filter %s: %s", name, expression)
+	return m, saveConfigCmd(summary, func(root *yaml.Node) error {
+		config := mappingValue(root, "config", yaml.MappingNode, false)
+		filters := mappingValue(config, "filters", yaml.MappingNode, true)
+		if filters == nil || filters.Kind != yaml.MappingNode {
+			return fmt.Errorf("filters of %s is not a mapping", configFile)
+		}
+		filters.Style = 0
+		if value := mappingValue(filters, name, yaml.ScalarNode, false); value != nil {
+			value.Kind, value.Tag, value.Value = yaml.ScalarNode, "", expression
+			return nil
+		}
+		filters.Content = append(filters.Content, scalarNode(name), scalarNode(expression))
+		return nil
+	})
+}
+
+func (m model) updateFilterPicker(msg tea.KeyMsg) (model, tea.Cmd) {
+	names := m.savedFilterNames()
+	switch msg.String() {
+	case "enter":
+		if m.filterCursor < len(names) {
+			return m.applyFilter(names[m.filterCursor]), nil
+		}
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.filterCursor > 0 {
+			m.filterCursor--
+		}
+	case "down", "j":
+		if m.filterCursor < len(names)-1 {
+			m.filterCursor++
+		}
+	}
+	return m, nil
+}
+
+// renderFilterPicker lists the saved filters with their search expressions.
+func (m model) renderFilterPicker() string {
+	headerStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("0")).   // Black background
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 1)
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Align(lipgloss.Center)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")). // Dark grey background
+		Foreground(lipgloss.Color("150")). // Light grey-green text
+		Bold(true)
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")) // Soft grey-green
+
+	header := headerStyle.Render("Select Filter (Enter to search, Esc to cancel)")
+	rows := []string{"", titleStyle.Width(m.width).Render("Saved Filters"), ""}
+
+	names := m.savedFilterNames()
+	nameWidth := 0
+	for _, name := range names {
+		nameWidth = max(nameWidth, len(name))
+	}
+	for i, name := range names {
+		indicator := "  "
+		if m.config.Config.Filters[name] == m.searchInput {
+			indicator = "* "
+		}
+		row := fmt.Sprintf("%s%-*s  /%s", indicator, nameWidth, name, m.config.Config.Filters[name])
+		if i == m.filterCursor {
+			row = selectedStyle.Render(row)
+		} else {
+			row = normalStyle.Render(row)
+		}
+		rows = append(rows, row)
+	}
+	return header + "\n" + strings.Join(rows, "\n")
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fundChunkMsg carries the outcome of one multi-send of a chunked fund-all,
+// a fundCompletedMsg or a txFailedMsg.
+type fundChunkMsg struct {
+	ch  <-chan tea.Msg
+	msg tea.Msg
+}
+
+// fundUnsentMsg carries the funds of a chunked fund-all that were cancelled
+// before being sent, to be saved for "gasms resume".
+type fundUnsentMsg struct {
+	network string
+	unsent  []planItem
+}
+
+// runFundAllChunks sends the multi-sends of a fund-all one at a time. They
+// are all signed by the bank, so each waits for the previous one to be
+// included before it is broadcast with the next account sequence. The
+// chunks after a failed or cancelled one are not sent; those of cancelled
+// ones are saved for "gasms resume".
+func (m model) runFundAllChunks(txIDs []int, amount int64, chunks [][]string) <-chan tea.Msg {
+	ch := make(chan tea.Msg)
+	config, network, applications := m.config, m.currentNetwork, m.applications
+	ctx := operations.context()
+	go func() {
+		defer close(ch)
+		var stopped error
+		var unsent []planItem
+		for i, recipients := range chunks {
+			total := amount * int64(len(recipients))
+			if stopped == nil && ctx.Err() != nil {
+				stopped = errCancelled
+			}
+			if stopped != nil {
+				ch <- newTxFailedMsg(txIDs[i], "fund-all", recipients, total,
+					fmt.Errorf("chunk %d of %d not sent: %w", i+1, len(chunks), stopped))
+				if errors.Is(stopped, errCancelled) {
+					unsent = append(unsent, fundResumeItems(applications, recipients, amount)...)
+				}
+				continue
+			}
+
+			txHash, err := fundAllApplications(amount, recipients, config, network)
+			if err != nil {
+				ch <- newTxFailedMsg(txIDs[i], "fund-all", recipients, total, fmt.Errorf("chunk %d of %d: %w", i+1, len(chunks), err))
+				stopped = fmt.Errorf("chunk %d failed", i+1)
+				continue
+			}
+			ch <- fundCompletedMsg{txID: txIDs[i], txHash: txHash}
+			if i == len(chunks)-1 {
+				break
+			}
+
+			status, err := waitForInclusion(config, network, txHash)
+			switch {
+			case errors.Is(err, errCancelled):
+				stopped = errCancelled
+			case err != nil:
+				stopped = fmt.Errorf("chunk %d %v", i+1, err)
+			case status.code != 0:
+				stopped = fmt.Errorf("chunk %d failed: %s", i+1, status.failure())
+			}
+		}
+		if len(unsent) > 0 {
+			ch <- fundUnsentMsg{network: network, unsent: unsent}
+		}
+	}()
+	return ch
+}
+
+func waitForFundChunkCmd(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return nil
+		}
+		return fundChunkMsg{ch: ch, msg: msg}
+	}
+}
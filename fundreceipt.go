@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fundAllReceipt is the breakdown of the last fund-all, shown once its first
+// multi-send is broadcast and updated as its multi-sends are included.
+type fundAllReceipt struct {
+	network    string
+	bank       string
+	amount     int64 // Received by each recipient, in upokt
+	chunks     []fundAllChunk
+	bankBefore int64 // Bank balance when submitted, in upokt
+}
+
+// fundAllChunk is one multi-send of a fund-all.
+type fundAllChunk struct {
+	txID         int
+	recipients   []string
+	estimatedFee int64
+	final        *trackedTx // The transaction once pruned from the tracker
+}
+
+// newFundAllReceipt starts the receipt of the fund-all whose multi-sends to
+// chunks are tracked as txIDs.
+func (m model) newFundAllReceipt(txIDs []int, amount int64, chunks [][]string) *fundAllReceipt {
+	network := m.config.Config.Networks[m.currentNetwork]
+	receipt := &fundAllReceipt{
+		network:    m.currentNetwork,
+		bank:       network.Bank,
+		amount:     amount,
+		bankBefore: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	for i, recipients := range chunks {
+		receipt.chunks = append(receipt.chunks, fundAllChunk{
+			txID:         txIDs[i],
+			recipients:   recipients,
+			estimatedFee: estimateMultiSendFee(network, len(recipients)),
+		})
+	}
+	return receipt
+}
+
+// keepFundAllTx keeps the final state of a transaction of the receipt when
+// the tracker drops it.
+func (m *model) keepFundAllTx(tx trackedTx) {
+	if m.fundAllReceipt == nil {
+		return
+	}
+	for i := range m.fundAllReceipt.chunks {
+		if m.fundAllReceipt.chunks[i].txID == tx.id {
+			m.fundAllReceipt.chunks[i].final = &tx
+		}
+	}
+}
+
+// chunkTx returns the transaction of chunk, nil until it is tracked.
+func (m model) chunkTx(chunk fundAllChunk) *trackedTx {
+	if chunk.final != nil {
+		return chunk.final
+	}
+	for i := range m.txs {
+		if m.txs[i].id == chunk.txID {
+			return &m.txs[i]
+		}
+	}
+	return nil
+}
+
+func (m model) updateFundAllReceipt(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	}
+	return m, nil
+}
+
+// renderFundAllReceipt shows who a fund-all paid, how much, the fee and the
+// resulting bank balance.
+func (m model) renderFundAllReceipt() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	receiptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+
+	receipt := m.fundAllReceipt
+	if receipt == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render("📜 FUND ALL RECEIPT 📜"), ""}
+	content = append(content, receiptStyle.Render(fmt.Sprintf("Network: %s • Bank: %s", receipt.network, receipt.bank)))
+
+	// One TX line per multi-send, with the progress of a chunked fund-all.
+	// Only fees paid in upokt are read back from the chain.
+	network := m.config.Config.Networks[receipt.network]
+	paidFees := network.feeDenom() == unitUPOKT
+	var fee, sent int64
+	var recipients []string
+	feeNote, balanceNote := "", ""
+	included := 0
+	for i, chunk := range receipt.chunks {
+		recipients = append(recipients, chunk.recipients...)
+		label := "TX"
+		if len(receipt.chunks) > 1 {
+			label = fmt.Sprintf("Chunk %d (%d recipients)", i+1, len(chunk.recipients))
+		}
+		chunkFee, chunkSent := chunk.estimatedFee, receipt.amount*int64(len(chunk.recipients))
+		tx := m.chunkTx(chunk)
+		switch {
+		case tx == nil:
+			feeNote, balanceNote = " (estimated)", " (expected)"
+			content = append(content, receiptStyle.Render(label+": not tracked"))
+		case tx.status == txIncluded:
+			if paidFees {
+				chunkFee = tx.fee
+			} else {
+				feeNote = " (estimated)"
+			}
+			included++
+			content = append(content, successStyle.Render(fmt.Sprintf("%s: %s • included at height %d", label, tx.hash, tx.height)))
+		case tx.status == txFailed || tx.status == txRejected:
+			chunkSent = 0 // Only a fee is paid for a failed transaction
+			if paidFees {
+				chunkFee = tx.fee
+			} else {
+				feeNote = " (estimated)"
+			}
+			content = append(content, errorStyle.Render(fmt.Sprintf("%s: %s • %s: %s", label, tx.hash, tx.status, tx.err)))
+		default:
+			feeNote, balanceNote = " (estimated)", " (expected)"
+			content = append(content, receiptStyle.Render(fmt.Sprintf("%s: %s • %s %s", label, tx.hash, m.spinner(), tx.status)))
+		}
+		fee += chunkFee
+		sent += chunkSent
+	}
+	if len(receipt.chunks) > 1 {
+		content = append(content, receiptStyle.Render(fmt.Sprintf("Progress: %d of %d multi-sends included", included, len(receipt.chunks))))
+	}
+	content = append(content, "")
+
+	// One line per recipient with the amount it receives
+	content = append(content, receiptStyle.Render(fmt.Sprintf("Recipients (%d):", len(recipients))))
+	visible := max(m.height-18-len(receipt.chunks), 1)
+	for i, address := range recipients {
+		if i == visible && len(recipients) > visible {
+			content = append(content, receiptStyle.Render(fmt.Sprintf("   … and %d more", len(recipients)-visible)))
+			break
+		}
+		name := TruncateAddress(address, 42)
+		if label := m.appLabel(address); label != "" {
+			name += " (" + label + ")"
+		}
+		content = append(content, receiptStyle.Render(fmt.Sprintf("%d. %s - +%s %s", i+1, name, m.formatAmount(receipt.amount), m.unitLabel())))
+	}
+	content = append(content, "")
+
+	total := receipt.amount * int64(len(recipients))
+	content = append(content, receiptStyle.Render(fmt.Sprintf("Total: %d × %s = %s %s",
+		len(recipients), m.formatAmount(receipt.amount), m.formatAmount(total), m.unitLabel())))
+	content = append(content, receiptStyle.Render(fmt.Sprintf("Fee: %s%s", m.formatFee(network, fee), feeNote)))
+	content = append(content, receiptStyle.Render(fmt.Sprintf("Bank balance: %s → %s %s%s",
+		m.formatAmount(receipt.bankBefore), m.formatAmount(receipt.bankBefore-sent-network.feeUpokt(fee)), m.unitLabel(), balanceNote)))
+
+	content = append(content, "")
+	content = append(content, receiptStyle.Render("Press ESC or Q to return to main view"))
+	return strings.Join(content, "\n")
+}
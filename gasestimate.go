@@ -0,0 +1,382 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultGasLimit is the gas limit of transactions sent with a fixed fee and
+// no --gas, like stake-application.
+const defaultGasLimit = 200000
+
+// gasEstimatePattern matches the estimate pocketd prints on a dry run.
+var gasEstimatePattern = regexp.MustCompile(`gas estimate: (\d+)`)
+
+// simulatedTx is a representative transaction of a bulk operation, simulated
+// with a dry run.
+type simulatedTx struct {
+	description string
+	gas         int64 // Gas limit the transaction would be sent with
+	fee         int64 // upokt
+	count       int   // Transactions of the batch it stands for
+}
+
+// gasEstimate is the simulated gas and fees of a fund-all or upstake-all,
+// shown before it runs.
+type gasEstimate struct {
+	command    string // Run on confirmation, without --estimate
+	kind       string // "fund-all" or "upstake-all"
+	network    string
+	amount     int64 // Per recipient, in upokt
+	recipients []string
+	loading    bool
+	err        error
+	samples    []simulatedTx
+}
+
+// gasEstimatedMsg carries the simulations of the estimate of command.
+type gasEstimatedMsg struct {
+	command string
+	samples []simulatedTx
+	err     error
+}
+
+// txs returns how many transactions the batch sends.
+func (e gasEstimate) txs() int {
+	n := 0
+	for _, sample := range e.samples {
+		n += sample.count
+	}
+	return n
+}
+
+// totals returns the gas and fees of the whole batch.
+func (e gasEstimate) totals() (gas, fees int64) {
+	for _, sample := range e.samples {
+		gas += sample.gas * int64(sample.count)
+		fees += sample.fee * int64(sample.count)
+	}
+	return gas, fees
+}
+
+// cutEstimateFlag removes --estimate from cmd, reporting whether it was there.
+func cutEstimateFlag(cmd string) (string, bool) {
+	parts := strings.Fields(cmd)
+	kept := parts[:0]
+	found := false
+	for _, part := range parts {
+		if part == "--estimate" {
+			found = true
+			continue
+		}
+		kept = append(kept, part)
+	}
+	return strings.Join(kept, " "), found
+}
+
+// startGasEstimate simulates representative transactions of a bulk operation
+// on recipients and shows the estimate for the whole batch.
+func (m model) startGasEstimate(command, kind string, amount int64, recipients []string) (model, tea.Cmd) {
+	if len(recipients) == 0 {
+		m.err = fmt.Errorf("no applications to estimate %s for", kind)
+		return m, nil
+	}
+	m.gasEstimate = &gasEstimate{
+		command:    command,
+		kind:       kind,
+		network:    m.currentNetwork,
+		amount:     amount,
+		recipients: recipients,
+		loading:    true,
+	}
+	m.state = stateGasEstimate
+	logger.Info("estimating gas", "command", command, "network", m.currentNetwork, "recipients", len(recipients))
+
+	config, network := m.config, m.currentNetwork
+	if kind == "fund-all" {
+		return m, tea.Batch(func() tea.Msg {
+			samples, err := simulateFundAll(config, network, amount, recipients)
+			return gasEstimatedMsg{command: command, samples: samples, err: err}
+		}, m.startSpinner())
+	}
+
+	// Any loaded application stands for the others: upstakes differ only
+	// in their amounts
+	var sample *Application
+	for i, app := range m.applications {
+		if slices.Contains(recipients, app.Address) {
+			sample = &m.applications[i]
+			break
+		}
+	}
+	if sample == nil {
+		m.gasEstimate.loading = false
+		m.gasEstimate.err = fmt.Errorf("none of the applications to upstake are loaded")
+		return m, nil
+	}
+	app := *sample
+	return m, tea.Batch(func() tea.Msg {
+		gas, err := simulateUpstake(config, network, app, amount)
+		if err != nil {
+			return gasEstimatedMsg{command: command, err: err}
+		}
+		return gasEstimatedMsg{command: command, samples: []simulatedTx{{
+			description: "upstake of " + TruncateAddress(app.Address, 20),
+			gas:         gas,
+			fee:         config.Config.Networks[network].feeAmount(),
+			count:       len(recipients),
+		}}}
+	}, m.startSpinner())
+}
+
+// applyGasEstimate records the simulations of the estimate shown.
+func (m *model) applyGasEstimate(msg gasEstimatedMsg) {
+	if m.gasEstimate == nil || m.gasEstimate.command != msg.command {
+		return
+	}
+	m.gasEstimate.loading = false
+	m.gasEstimate.samples = msg.samples
+	m.gasEstimate.err = msg.err
+	if msg.err != nil {
+		logger.Error("gas estimate failed", "command", msg.command, "error", msg.err)
+	}
+}
+
+// simulateFundAll simulates the multi-sends of a fund-all: its first chunk,
+// and its last one when it has fewer recipients.
+func simulateFundAll(config *Config, networkName string, amount int64, recipients []string) ([]simulatedTx, error) {
+	chunks := chunkAddresses(recipients, config.multiSendChunk())
+	sizes := []int{len(chunks[0])}
+	counts := map[int]int{}
+	for _, chunk := range chunks {
+		counts[len(chunk)]++
+	}
+	if last := len(chunks[len(chunks)-1]); last != sizes[0] {
+		sizes = append(sizes, last)
+	}
+
+	var samples []simulatedTx
+	for _, size := range sizes {
+		gas, err := simulateMultiSend(config, networkName, amount, recipients[:size])
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, simulatedTx{
+			description: fmt.Sprintf("multi-send to %d recipients", size),
+			gas:         gas,
+			fee:         config.Config.Networks[networkName].gasFee(gas),
+			count:       counts[size],
+		})
+	}
+	return samples, nil
+}
+
+// simulateMultiSend dry-runs a multi-send from the bank to addresses and
+// returns the gas limit it would be sent with.
+func simulateMultiSend(config *Config, networkName string, amount int64, addresses []string) (int64, error) {
+	network := config.Config.Networks[networkName]
+	if network.Bank == "" {
+		return 0, fmt.Errorf("bank address not configured for network: %s", networkName)
+	}
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return 0, err
+	}
+	var gas int64
+	err = withFailover(networkName, network.RPCEndpoint, func(node string) error {
+		args := []string{"tx", "bank", "multi-send", network.Bank}
+		args = append(args, addresses...)
+		args = append(args, fmt.Sprintf("%dupokt", amount*int64(len(addresses))),
+			"--node="+node,
+			"--chain-id="+chainID,
+			"--split",
+			"--dry-run",
+			"--gas=auto",
+			fmt.Sprintf("--gas-adjustment=%g", multiSendGasAdjustment))
+		args = AppendPocketdFlags(args, "", config.pocketdHome(networkName))
+		var err error
+		gas, err = simulateGas(args)
+		return err
+	})
+	return gas, err
+}
+
+// simulateUpstake dry-runs the upstake of app by amount and returns the gas it
+// uses.
+func simulateUpstake(config *Config, networkName string, app Application, amount int64) (int64, error) {
+	network := config.Config.Networks[networkName]
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return 0, err
+	}
+	configFile, err := writeStakeConfig(network, app.Address, app.ServiceIDs, stakeUpokt(app)+amount, app.DelegateeGateways)
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(configFile)
+
+	var gas int64
+	err = withFailover(networkName, network.RPCEndpoint, func(node string) error {
+		args := []string{"tx", "application", "stake-application",
+			"--config=" + configFile,
+			"--from=" + app.Address,
+			"--node=" + node,
+			"--chain-id=" + chainID,
+			network.feesArg(),
+			"--dry-run",
+			"--gas=auto"}
+		args = append(args, feeGranterArgs(network)...)
+		args = AppendPocketdFlags(args, "", config.pocketdHome(networkName))
+		var err error
+		gas, err = simulateGas(args)
+		return err
+	})
+	return gas, err
+}
+
+// simulateGas runs a pocketd dry run and returns its gas estimate. A dry run
+// signs nothing, so it needs no keyring.
+func simulateGas(args []string) (int64, error) {
+	output, err := runPocketd(args)
+	if err != nil {
+		return 0, fmt.Errorf("pocketd simulation failed: %v, output: %s", err, strings.TrimSpace(string(output)))
+	}
+	match := gasEstimatePattern.FindSubmatch(output)
+	if match == nil {
+		return 0, fmt.Errorf("no gas estimate in pocketd output: %s", strings.TrimSpace(string(output)))
+	}
+	return strconv.ParseInt(string(match[1]), 10, 64)
+}
+
+func (m model) updateGasEstimate(msg tea.KeyMsg) (model, tea.Cmd) {
+	estimate := m.gasEstimate
+	switch msg.String() {
+	case "y", "Y":
+		if estimate.loading || estimate.network != m.currentNetwork {
+			return m, nil
+		}
+		m.gasEstimate = nil
+		m.state = stateTable
+		if estimate.kind == "fund-all" {
+			return m.handleFundAllCommand(estimate.command)
+		}
+		return m.handleUpstakeAllCommand(estimate.command)
+	case "n", "N", "esc", "q":
+		m.gasEstimate = nil
+		m.state = stateTable
+	}
+	return m, nil
+}
+
+// renderGasEstimate shows the simulated gas and fees of a bulk operation next
+// to the balances that pay for it.
+func (m model) renderGasEstimate() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	estimate := m.gasEstimate
+	if estimate == nil {
+		return ""
+	}
+	unit := m.unitLabel()
+	content := []string{headerStyle.Render("⛽ GAS ESTIMATE • " + estimate.command), ""}
+	total := estimate.amount * int64(len(estimate.recipients))
+	content = append(content, textStyle.Render(fmt.Sprintf("Amount: %d × %s = %s %s",
+		len(estimate.recipients), m.formatAmount(estimate.amount), m.formatAmount(total), unit)))
+	content = append(content, "")
+
+	switch {
+	case estimate.loading:
+		content = append(content, textStyle.Render(m.spinner()+" Simulating a representative transaction..."))
+		return strings.Join(content, "\n")
+	case estimate.err != nil:
+		content = append(content, errorStyle.Render("Simulation failed: "+estimate.err.Error()))
+		content = append(content, "")
+		content = append(content, warningStyle.Render("y to run anyway • n or ESC to cancel"))
+		return strings.Join(content, "\n")
+	}
+
+	network := m.config.Config.Networks[estimate.network]
+	for _, sample := range estimate.samples {
+		line := fmt.Sprintf("Simulated %s: %d gas, %s fee", sample.description, sample.gas, m.formatFee(network, sample.fee))
+		if sample.count > 1 {
+			line += fmt.Sprintf(" (× %d)", sample.count)
+		}
+		content = append(content, textStyle.Render(line))
+	}
+	gas, fees := estimate.totals()
+	content = append(content, textStyle.Render(fmt.Sprintf("Batch: %d transactions, %d gas, %s in fees",
+		estimate.txs(), gas, m.formatFee(network, fees))))
+	content = append(content, "")
+
+	bank := int64(math.Round(m.bankBalance * upoktPerPOKT))
+	if estimate.kind == "fund-all" {
+		reserved := estimateChunkedFees(network, len(estimate.recipients), m.config.multiSendChunk())
+		content = append(content, textStyle.Render(fmt.Sprintf("The balance check reserves ~%s for fees", m.formatFee(network, reserved))))
+		required := total + network.feeUpokt(fees)
+		line := fmt.Sprintf("Bank balance: %s %s • needed %s %s • after %s %s",
+			m.formatAmount(bank), unit, m.formatAmount(required), unit, m.formatAmount(bank-required), unit)
+		if bank >= required {
+			content = append(content, successStyle.Render(line))
+		} else {
+			content = append(content, errorStyle.Render(line+fmt.Sprintf(" • short by %s %s", m.formatAmount(required-bank), unit)))
+		}
+	} else {
+		for _, sample := range estimate.samples {
+			if sample.gas > defaultGasLimit {
+				content = append(content, warningStyle.Render(fmt.Sprintf("%d gas is above the %d gas limit upstakes are sent with; they would run out of gas", sample.gas, defaultGasLimit)))
+			}
+		}
+		if network.FeeGrant && network.Bank != "" {
+			line := fmt.Sprintf("Fees are paid by the bank's fee grant • bank balance %s %s", m.formatAmount(bank), unit)
+			if bank >= network.feeUpokt(fees) {
+				content = append(content, successStyle.Render(line))
+			} else {
+				content = append(content, errorStyle.Render(line))
+			}
+		} else {
+			short := 0
+			required := estimate.amount + network.appFeeUpokt()
+			for _, app := range m.applications {
+				if slices.Contains(estimate.recipients, app.Address) && app.BalanceUpokt < required {
+					short++
+				}
+			}
+			line := fmt.Sprintf("Fees are paid by each application: %s + %s %s each", m.formatAmount(estimate.amount), m.formatAmount(network.appFeeUpokt()), unit)
+			if short > 0 {
+				content = append(content, errorStyle.Render(line+fmt.Sprintf(" • %d applications cannot cover it", short)))
+			} else {
+				content = append(content, successStyle.Render(line))
+			}
+		}
+	}
+	content = append(content, "")
+	content = append(content, warningStyle.Render("Run it? y to confirm • n or ESC to cancel"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	gatewayHealthInterval = time.Minute // How often gateways are checked again
+	gatewayHealthTimeout  = 10 * time.Second
+)
+
+// gatewayHealth is the on-chain record of a configured gateway and the
+// result of its optional HTTP health check.
+type gatewayHealth struct {
+	address         string
+	staked          bool
+	stakeUpokt      int64
+	unstakingHeight int64  // Session end height of a pending unstake (0 if none)
+	queryErr        string // The record could not be queried
+	healthURL       string
+	healthErr       string // The health check failed ("" if it passed or is not configured)
+}
+
+// ok reports whether the gateway is staked and healthy.
+func (h gatewayHealth) ok() bool {
+	return h.queryErr == "" && h.staked && h.unstakingHeight == 0 && h.healthErr == ""
+}
+
+// icon summarizes the health of the gateway for the header strip.
+func (h gatewayHealth) icon() string {
+	switch {
+	case h.queryErr != "":
+		return "⚪"
+	case !h.staked:
+		return "🔴"
+	case h.unstakingHeight > 0 || h.healthErr != "":
+		return "🟡"
+	}
+	return "🟢"
+}
+
+// problem describes what is wrong with the gateway, "" if nothing.
+func (h gatewayHealth) problem() string {
+	switch {
+	case h.queryErr != "":
+		return "unknown"
+	case !h.staked:
+		return "not staked"
+	case h.unstakingHeight > 0:
+		return "unstaking"
+	case h.healthErr != "":
+		return "health check failed"
+	}
+	return ""
+}
+
+type gatewayHealthMsg struct {
+	network  string
+	gateways []gatewayHealth
+}
+
+// QueryGateway returns the on-chain record of a gateway. A gateway that is
+// not staked is reported with staked=false and no error.
+func QueryGateway(rpcEndpoint, address, pocketdHome, networkName string) (gatewayHealth, error) {
+	health := gatewayHealth{address: address}
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return health, err
+	}
+	args := []string{"q", "gateway", "show-gateway", address, "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		if strings.Contains(string(output), "not found") {
+			return health, nil
+		}
+		return health, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Gateway struct {
+			Stake struct {
+				Amount flexInt `json:"amount"`
+			} `json:"stake"`
+			UnstakeSessionEndHeight flexInt `json:"unstake_session_end_height"`
+		} `json:"gateway"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return health, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	health.staked = response.Gateway.Stake.Amount > 0
+	health.stakeUpokt = int64(response.Gateway.Stake.Amount)
+	health.unstakingHeight = int64(response.Gateway.UnstakeSessionEndHeight)
+	return health, nil
+}
+
+// checkGatewayURL runs the HTTP health check of a gateway; any 2xx passes.
+func checkGatewayURL(url string) error {
+	client := &http.Client{Timeout: gatewayHealthTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("returned %s", resp.Status)
+	}
+	return nil
+}
+
+func loadGatewayHealthCmd(networkName string, network Network, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		gateways := make([]gatewayHealth, len(network.Gateways))
+		for i, address := range network.Gateways {
+			var health gatewayHealth
+			err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+				var err error
+				health, err = QueryGateway(endpoint, address, pocketdHome, networkName)
+				return err
+			})
+			if err != nil {
+				health.queryErr = err.Error()
+			}
+			if url := network.GatewayHealth[address]; url != "" {
+				health.healthURL = url
+				if err := checkGatewayURL(url); err != nil {
+					health.healthErr = err.Error()
+				}
+			}
+			gateways[i] = health
+		}
+		return gatewayHealthMsg{network: networkName, gateways: gateways}
+	}
+}
+
+// gatewayHealthRefreshCmd checks the gateways of the current network when
+// the last check is older than the interval or of another network.
+func (m *model) gatewayHealthRefreshCmd() tea.Cmd {
+	if m.config == nil || m.gatewayHealthLoading {
+		return nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || len(network.Gateways) == 0 {
+		return nil
+	}
+	if m.gatewayHealthNetwork == m.currentNetwork && time.Since(m.gatewayHealthAt) < gatewayHealthInterval {
+		return nil
+	}
+	m.gatewayHealthLoading = true
+	return loadGatewayHealthCmd(m.currentNetwork, network, m.config.pocketdHome(m.currentNetwork))
+}
+
+// applyGatewayHealth stores the gateway checks and warns about gateways that
+// stopped being staked or healthy since the last check.
+func (m *model) applyGatewayHealth(msg gatewayHealthMsg) tea.Cmd {
+	m.gatewayHealthLoading = false
+	if msg.network != m.currentNetwork {
+		return nil
+	}
+	previous := make(map[string]gatewayHealth)
+	if m.gatewayHealthNetwork == msg.network {
+		for _, health := range m.gatewayHealth {
+			previous[health.address] = health
+		}
+	}
+	m.gatewayHealth = msg.gateways
+	m.gatewayHealthNetwork = msg.network
+	m.gatewayHealthAt = time.Now()
+
+	var cmds []tea.Cmd
+	for _, health := range msg.gateways {
+		if health.queryErr != "" {
+			logger.Warn("failed to query gateway", "network", msg.network, "gateway", health.address, "error", health.queryErr)
+			continue
+		}
+		before, known := previous[health.address]
+		if health.ok() || (known && before.problem() == health.problem()) {
+			continue
+		}
+		logger.Warn("gateway unhealthy", "network", msg.network, "gateway", health.address, "problem", health.problem(), "health_error", health.healthErr)
+		level := toastWarning
+		if !health.staked {
+			level = toastError
+		}
+		cmds = append(cmds, m.notify(level, fmt.Sprintf("Gateway %s is %s", TruncateAddress(health.address, 20), health.problem())))
+	}
+	return tea.Batch(cmds...)
+}
+
+// gatewayHealthLine renders the health strip of the configured gateways for
+// the header, naming the first one with a problem.
+func (m model) gatewayHealthLine() string {
+	if m.gatewayHealthNetwork != m.currentNetwork || len(m.gatewayHealth) == 0 {
+		return "🩺 Gateways: -"
+	}
+	icons := make([]string, len(m.gatewayHealth))
+	problem := ""
+	for i, health := range m.gatewayHealth {
+		icons[i] = health.icon()
+		if problem == "" && !health.ok() {
+			problem = fmt.Sprintf(" (%s %s, :gateways)", TruncateAddress(health.address, 16), health.problem())
+		}
+	}
+	return "🩺 Gateways: " + strings.Join(icons, "") + problem
+}
+
+func (m model) updateGatewayHealth(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "r":
+		m.gatewayHealthAt = time.Time{}
+		return m, m.gatewayHealthRefreshCmd()
+	}
+	return m, nil
+}
+
+// renderGatewayHealth lists the on-chain record and health check of every
+// configured gateway.
+func (m model) renderGatewayHealth() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 6)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🩺 GATEWAYS • %s", m.currentNetwork)), ""}
+	if m.gatewayHealthNetwork != m.currentNetwork || len(m.gatewayHealth) == 0 {
+		content = append(content, textStyle.Render(m.spinner()+" Checking gateways..."))
+	} else {
+		unit := m.unitLabel()
+		for _, health := range m.gatewayHealth {
+			status := "staked " + m.formatAmount(health.stakeUpokt) + " " + unit
+			switch {
+			case health.queryErr != "":
+				status = "unknown"
+			case !health.staked:
+				status = "NOT STAKED"
+			case health.unstakingHeight > 0:
+				if left, ok := m.blocksUntil(health.unstakingHeight); ok {
+					status += fmt.Sprintf(", unstaking, %d blocks left", left)
+				} else {
+					status += fmt.Sprintf(", unstaking at %d", health.unstakingHeight)
+				}
+			}
+			check := "no health check"
+			if health.healthURL != "" {
+				check = "health ok"
+				if health.healthErr != "" {
+					check = "health FAILED"
+				}
+			}
+			current := "  "
+			if health.address == m.currentGateway {
+				current = "* "
+			}
+			content = append(content, textStyle.Render(fmt.Sprintf("%s %s%s  %s • %s", health.icon(), current, health.address, status, check)))
+			if health.queryErr != "" {
+				content = append(content, errorStyle.Render(truncateToWidth(health.queryErr, max(m.width-10, 20))))
+			}
+			if health.healthErr != "" {
+				content = append(content, errorStyle.Render(truncateToWidth(health.healthURL+": "+health.healthErr, max(m.width-10, 20))))
+			}
+		}
+		content = append(content, "")
+		status := "Checked " + m.gatewayHealthAt.Local().Format("15:04:05")
+		if m.gatewayHealthLoading {
+			status = m.spinner() + " Checking..."
+		}
+		content = append(content, textStyle.Render(status))
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("Checked every minute • * current gateway • r to check now • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// govPollInterval is how often active proposals are checked in the background.
+const govPollInterval = 10 * time.Minute
+
+// govStakingModules are the modules whose parameter changes affect how
+// applications must be staked.
+var govStakingModules = []string{"pocket.application.", "pocket.gateway.", "pocket.shared."}
+
+// govProposal is a governance proposal in its deposit or voting period.
+type govProposal struct {
+	ID           int64
+	Title        string
+	Status       string
+	DepositEnd   time.Time
+	VotingEnd    time.Time
+	MessageTypes []string
+}
+
+// affectsStaking reports whether the proposal changes parameters of a module
+// that governs application staking.
+func (p govProposal) affectsStaking() bool {
+	for _, msgType := range p.MessageTypes {
+		if !strings.Contains(msgType, "MsgUpdateParam") {
+			continue
+		}
+		for _, module := range govStakingModules {
+			if strings.Contains(msgType, module) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+type govLoadedMsg struct {
+	network   string
+	proposals []govProposal
+	err       error
+}
+
+type govTickMsg struct {
+	gen int
+}
+
+// QueryActiveProposals returns the proposals in their deposit or voting period.
+func QueryActiveProposals(rpcEndpoint, pocketdHome, networkName string) ([]govProposal, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"q", "gov", "proposals", "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID, "--limit", "1000"}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		// The query fails rather than returning an empty list when there are none
+		if strings.Contains(string(output), "no proposals found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Proposals []struct {
+			ID             flexInt                  `json:"id"`
+			Title          string                   `json:"title"`
+			Status         string                   `json:"status"`
+			DepositEndTime time.Time                `json:"deposit_end_time"`
+			VotingEndTime  time.Time                `json:"voting_end_time"`
+			Messages       []map[string]interface{} `json:"messages"`
+		} `json:"proposals"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	var proposals []govProposal
+	for _, p := range response.Proposals {
+		if p.Status != "PROPOSAL_STATUS_DEPOSIT_PERIOD" && p.Status != "PROPOSAL_STATUS_VOTING_PERIOD" {
+			continue
+		}
+		proposal := govProposal{
+			ID:         int64(p.ID),
+			Title:      p.Title,
+			Status:     strings.TrimPrefix(p.Status, "PROPOSAL_STATUS_"),
+			DepositEnd: p.DepositEndTime,
+			VotingEnd:  p.VotingEndTime,
+		}
+		for _, msg := range p.Messages {
+			// Proto JSON uses "@type", amino JSON uses "type"
+			msgType, _ := msg["@type"].(string)
+			if msgType == "" {
+				msgType, _ = msg["type"].(string)
+			}
+			proposal.MessageTypes = append(proposal.MessageTypes, msgType)
+		}
+		proposals = append(proposals, proposal)
+	}
+	sort.Slice(proposals, func(i, j int) bool { return proposals[i].ID > proposals[j].ID })
+	return proposals, nil
+}
+
+func loadGovCmd(network, rpcEndpoint, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		var proposals []govProposal
+		err := withFailover(network, rpcEndpoint, func(endpoint string) error {
+			var err error
+			proposals, err = QueryActiveProposals(endpoint, pocketdHome, network)
+			return err
+		})
+		return govLoadedMsg{network: network, proposals: proposals, err: err}
+	}
+}
+
+func govTickCmd(gen int) tea.Cmd {
+	return tea.Tick(govPollInterval, func(time.Time) tea.Msg {
+		return govTickMsg{gen: gen}
+	})
+}
+
+// refreshGov queries the active proposals of the current network.
+func (m *model) refreshGov() tea.Cmd {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil
+	}
+	m.govNetwork = m.currentNetwork
+	m.govLoading = true
+	return loadGovCmd(m.currentNetwork, network.RPCEndpoint, m.config.pocketdHome(m.currentNetwork))
+}
+
+// watchGov starts polling proposals of the current network, replacing the
+// poll loop of the previous network.
+func (m *model) watchGov() tea.Cmd {
+	m.govGen++
+	m.govProposals = nil
+	return tea.Batch(m.refreshGov(), govTickCmd(m.govGen))
+}
+
+// applyGov stores proposals loaded for the current network and notifies about
+// newly seen proposals that affect staking.
+func (m *model) applyGov(msg govLoadedMsg) tea.Cmd {
+	if msg.network != m.govNetwork {
+		return nil
+	}
+	m.govLoading = false
+	m.govErr = msg.err
+	if msg.err != nil {
+		logger.Warn("failed to load governance proposals", "network", msg.network, "error", msg.err)
+		return nil
+	}
+	m.govProposals = msg.proposals
+	m.govAt = time.Now()
+
+	if m.govSeen == nil {
+		m.govSeen = make(map[string]bool)
+	}
+	var cmds []tea.Cmd
+	for _, proposal := range msg.proposals {
+		key := fmt.Sprintf("%s/%d", msg.network, proposal.ID)
+		if !proposal.affectsStaking() || m.govSeen[key] {
+			continue
+		}
+		m.govSeen[key] = true
+		logger.Info("staking parameter proposal active", "network", msg.network, "proposal", proposal.ID, "title", proposal.Title)
+		cmds = append(cmds, m.notify(toastWarning, fmt.Sprintf("Proposal #%d changes staking params: %s (:gov)", proposal.ID, proposal.Title)))
+	}
+	return tea.Batch(cmds...)
+}
+
+// stakingProposalCount returns the number of active proposals that affect
+// staking on the current network.
+func (m model) stakingProposalCount() int {
+	if m.govNetwork != m.currentNetwork {
+		return 0
+	}
+	count := 0
+	for _, proposal := range m.govProposals {
+		if proposal.affectsStaking() {
+			count++
+		}
+	}
+	return count
+}
+
+func (m model) updateGov(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.govCursor > 0 {
+			m.govCursor--
+		}
+	case "down", "j":
+		if m.govCursor < len(m.govProposals)-1 {
+			m.govCursor++
+		}
+	case "r":
+		return m, tea.Batch(m.refreshGov(), m.startSpinner())
+	}
+	return m, nil
+}
+
+// renderGov lists active governance proposals, flagging the ones that change
+// staking parameters.
+func (m model) renderGov() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	flaggedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🗳️  GOVERNANCE • %s", m.currentNetwork)), ""}
+
+	switch {
+	case m.govLoading && m.govAt.IsZero():
+		content = append(content, textStyle.Render(m.spinner()+" Querying proposals..."))
+	case m.govErr != nil:
+		content = append(content, errorStyle.Render(fmt.Sprintf("Failed to query proposals: %v", m.govErr)))
+	case len(m.govProposals) == 0:
+		content = append(content, textStyle.Render("No proposals in deposit or voting period."))
+	}
+
+	for i, proposal := range m.govProposals {
+		ends := proposal.VotingEnd
+		if proposal.Status == "DEPOSIT_PERIOD" {
+			ends = proposal.DepositEnd
+		}
+		flag := "  "
+		if proposal.affectsStaking() {
+			flag = "⚠️"
+		}
+		line := fmt.Sprintf("%s #%-5d %-14s ends %s  %s", flag, proposal.ID, proposal.Status,
+			ends.Local().Format("2006-01-02 15:04"), proposal.Title)
+		switch {
+		case i == m.govCursor:
+			content = append(content, selectedStyle.Render(line))
+		case proposal.affectsStaking():
+			content = append(content, flaggedStyle.Render(line))
+		default:
+			content = append(content, textStyle.Render(line))
+		}
+		if i == m.govCursor {
+			for _, msgType := range proposal.MessageTypes {
+				content = append(content, textStyle.Render("      "+msgType))
+			}
+		}
+	}
+
+	content = append(content, "")
+	if !m.govAt.IsZero() {
+		content = append(content, textStyle.Render(fmt.Sprintf("⚠️ marks parameter changes to the application, gateway or shared modules • checked %s, every %s",
+			m.govAt.Local().Format("15:04:05"), govPollInterval)))
+	}
+	content = append(content, textStyle.Render("j/k to select • r to refresh • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Fund-all uses --gas=auto, so its fee is only known after simulation. These
+// bound it generously for the balance check.
+const (
+	multiSendBaseGas         = 100000
+	multiSendGasPerRecipient = 30000
+	multiSendGasAdjustment   = 2.5
+)
+
+// estimateMultiSendFee returns the expected fee of a multi-send to recipients
+// on network, in its fee denomination.
+func estimateMultiSendFee(network Network, recipients int) int64 {
+	gas := float64(multiSendBaseGas+multiSendGasPerRecipient*recipients) * multiSendGasAdjustment
+	return network.gasFee(int64(math.Ceil(gas)))
+}
+
+// chunkAddresses splits addresses into multi-sends of at most size
+// recipients.
+func chunkAddresses(addresses []string, size int) [][]string {
+	var chunks [][]string
+	for len(addresses) > size {
+		chunks = append(chunks, addresses[:size:size])
+		addresses = addresses[size:]
+	}
+	if len(addresses) > 0 {
+		chunks = append(chunks, addresses)
+	}
+	return chunks
+}
+
+// estimateChunkedFees returns the expected fees of funding recipients in
+// multi-sends of at most size recipients on network.
+func estimateChunkedFees(network Network, recipients, size int) int64 {
+	var fees int64
+	for ; recipients > size; recipients -= size {
+		fees += estimateMultiSendFee(network, size)
+	}
+	if recipients > 0 {
+		fees += estimateMultiSendFee(network, recipients)
+	}
+	return fees
+}
+
+// balancesReady reports whether balances reflect the chain closely enough to
+// guard a bulk operation.
+func (m model) balancesReady() error {
+	if m.loading || !m.staleSince.IsZero() {
+		return fmt.Errorf("balances are still refreshing, wait for the refresh to finish")
+	}
+	if len(m.pendingBalances) > 0 {
+		return fmt.Errorf("%d application balances are still loading", len(m.pendingBalances))
+	}
+	if unknown := m.unknownBalances(); len(unknown) > 0 {
+		return fmt.Errorf("%d application balances are unknown, retry them with :retry-balances", len(unknown))
+	}
+	return nil
+}
+
+// checkFundAll refuses a fund-all the bank cannot cover, including fees.
+func (m model) checkFundAll(amount int64, recipients int) error {
+	if err := m.balancesReady(); err != nil {
+		return err
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	fees := network.feeUpokt(estimateChunkedFees(network, recipients, m.config.multiSendChunk()))
+	required := amount*int64(recipients) + fees
+	available := int64(math.Round(m.bankBalance * upoktPerPOKT))
+	if available >= required {
+		return nil
+	}
+	return fmt.Errorf("insufficient bank balance: need %s %s (%d apps × %s + ~%s fees), have %s, short by %s",
+		m.formatAmount(required), m.unitLabel(), recipients, m.formatAmount(amount),
+		m.formatAmount(fees), m.formatAmount(available),
+		m.formatAmount(required-available))
+}
+
+// checkUpstakeAll refuses an upstake-all when any application cannot pay for
+// its own upstake. Upstakes are signed by each application, not the bank.
+func (m model) checkUpstakeAll(amount int64, addresses []string) error {
+	if err := m.balancesReady(); err != nil {
+		return err
+	}
+	configured := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		configured[address] = true
+	}
+
+	required := amount + m.config.Config.Networks[m.currentNetwork].appFeeUpokt()
+	var short, belowMin []string
+	var shortfall int64
+	for _, app := range m.applications {
+		if !configured[app.Address] {
+			continue
+		}
+		if m.checkMinStake(stakeUpokt(app), amount) != nil {
+			belowMin = append(belowMin, TruncateAddress(app.Address, 13))
+		}
+		if app.BalanceUpokt < required {
+			short = append(short, TruncateAddress(app.Address, 13))
+			shortfall += required - app.BalanceUpokt
+		}
+	}
+	if len(belowMin) > 0 {
+		return fmt.Errorf("%d apps would stay below the minimum stake of %s %s: %s",
+			len(belowMin), m.formatAmount(m.minStake()), m.unitLabel(), strings.Join(belowMin, ", "))
+	}
+	if len(short) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d apps cannot cover %s %s (amount + fee) each, short by %s in total: %s",
+		len(short), m.formatAmount(required), m.unitLabel(), m.formatAmount(shortfall), strings.Join(short, ", "))
+}
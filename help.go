@@ -0,0 +1,438 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpView is the help screen: the full reference, or with ? only the keys
+// of the view it was opened from.
+type helpView struct {
+	from       state // View to return to
+	contextual bool
+	scroll     int
+	searching  bool // Keys go to the search line
+	search     string
+	matches    []int // Lines containing the search
+	matchIndex int
+}
+
+// viewHelp is the contextual help of a view.
+type viewHelp struct {
+	title string
+	keys  [][2]string // Key and what it does, in help order
+}
+
+// viewKeys are the keys valid in each view that takes no text input, shown
+// by ? in that view.
+var viewKeys = map[state]viewHelp{
+	stateTable: {"APPLICATIONS", [][2]string{
+		{"↑/k, ↓/j", "Navigate up/down"},
+		{"g, G", "Go to top/bottom"},
+		{"enter", "Show application details"},
+		{"u", "Upstake selected application"},
+		{"f", "Fund selected application"},
+		{"e", "Type a target stake in the stake cell; Enter stages the upstake"},
+		{"F", "Fund all applications (opens :fa prompt)"},
+		{"U", "Upstake all applications (opens :ua prompt)"},
+		{"M", "Add the selected unmanaged application to config"},
+		{"A", "Show all applications delegated to the gateway"},
+		{"d", "Show drift from configured targets"},
+		{"p", "Pin or unpin the selected application on the watchlist"},
+		{"r", "Refresh application data"},
+		{"/", "Search applications (by address or service ID)"},
+		{":", "Enter a command"},
+		{"n", "Switch network"},
+		{"h", "Full help"},
+		{"esc", "Cancel an in-flight refresh or batch"},
+		{"q, ctrl+c", "Quit"},
+	}},
+	stateApplicationDetails: {"APPLICATION DETAILS", [][2]string{
+		{"[, ]", "Previous/next application in table order (also ←/→)"},
+		{"u", "Upstake this application (prefills :u)"},
+		{"f", "Fund this application (prefills :f)"},
+		{"d", "Delegate this application (prefills :delegate)"},
+		{"x", "Unstake this application (prefills :unstake)"},
+		{"r", "Reload details, balances, sessions and activity"},
+		{"s", "Refresh the current session"},
+		{"a", "Refresh the recent activity"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateNetworkSelect: {"NETWORKS", [][2]string{
+		{"↑/k, ↓/j", "Select a network"},
+		{"enter", "Switch to the selected network"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateGatewaySelect: {"GATEWAYS", [][2]string{
+		{"↑/k, ↓/j", "Select a gateway"},
+		{"enter", "Switch to the selected gateway"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateFilterPicker: {"FILTERS", [][2]string{
+		{"↑/k, ↓/j", "Select a saved filter"},
+		{"enter", "Search with the selected filter"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateDiff: {"DRIFT", [][2]string{
+		{"R", "Stage the reconciling transactions"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateDiscrepancies: {"DISCREPANCIES", [][2]string{
+		{"a", "Adopt the delegated applications into config"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateAudit: {"AUDIT LOG", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"g, G", "Go to top/bottom"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateSpend: {"SPEND", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"g", "Go to top"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateSnapshots: {"SNAPSHOTS", [][2]string{
+		{"↑/k, ↓/j", "Select a snapshot"},
+		{"enter", "Compare with the current state"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateCompare: {"COMPARE", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"R", "Restore the snapshot stake levels"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateRewards: {"REWARDS", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"r", "Refresh"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateParams: {"PARAMS", [][2]string{
+		{"r", "Refresh"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateGov: {"GOVERNANCE", [][2]string{
+		{"↑/k, ↓/j", "Select a proposal"},
+		{"r", "Refresh"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateFeeGrants: {"FEE GRANTS", [][2]string{
+		{"r", "Refresh"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateQueue: {"APPROVAL QUEUE", [][2]string{
+		{"↑/k, ↓/j", "Select a submission"},
+		{"a, y", "Approve and broadcast the selected submission"},
+		{"x, n", "Reject the selected submission"},
+		{"A", "Approve all"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateErrors: {"ERRORS", [][2]string{
+		{"↑/k, ↓/j", "Select an error"},
+		{"enter", "Show the error with its command and output"},
+		{"d", "Dismiss the selected error"},
+		{"D", "Dismiss all errors"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateRunbook: {"RUNBOOK", [][2]string{
+		{"↑/k, ↓/j", "Select a line"},
+		{"x", "Stop after the running command"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateGatewayHealth: {"GATEWAYS", [][2]string{
+		{"r", "Check now"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateJobs: {"JOBS", [][2]string{
+		{"↑/k, ↓/j", "Select a job"},
+		{"enter", "Open the selected job"},
+		{"r", "Retry a failed job"},
+		{"x", "Cancel the selected job"},
+		{"c", "Clear finished jobs"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateJob: {"JOB", [][2]string{
+		{"↑/k, ↓/j", "Select a step"},
+		{"enter, r", "Queue a staged job or retry a failed one"},
+		{"x", "Cancel the job"},
+		{"esc, q", "Return to the jobs"},
+	}},
+	statePassthrough: {"POCKETD OUTPUT", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"pgup, pgdown", "Scroll a page"},
+		{"g, G", "Go to top/bottom"},
+		{"r", "Run the command again"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateTxLookup: {"TRANSACTION", [][2]string{
+		{"↑/k, ↓/j", "Scroll"},
+		{"g", "Go to top"},
+		{"r", "Query again"},
+		{"esc, q", "Return to the table"},
+	}},
+	stateUpstakeAllReceipts: {"UPSTAKE ALL RECEIPTS", [][2]string{
+		{"esc, q", "Return to the table"},
+	}},
+	stateFundAllReceipt: {"FUND ALL RECEIPT", [][2]string{
+		{"esc, q", "Return to the table"},
+	}},
+	stateUpstakeAllConfirm: {"UPSTAKE ALL", [][2]string{
+		{"y", "Submit"},
+		{"tab, shift+tab", "Change the scope"},
+		{"n, esc", "Cancel"},
+	}},
+	stateGasEstimate: {"GAS ESTIMATE", [][2]string{
+		{"y", "Run the command"},
+		{"n, esc", "Cancel"},
+	}},
+	stateServiceChange: {"SERVICE CHANGE", [][2]string{
+		{"y", "Submit"},
+		{"n, esc", "Cancel"},
+	}},
+	statePlanReview: {"EDITED PLAN", [][2]string{
+		{"y", "Submit"},
+		{"n, esc", "Cancel"},
+	}},
+	stateTransfer: {"TRANSFER", [][2]string{
+		{"y", "Submit"},
+		{"n, esc", "Cancel"},
+	}},
+}
+
+// openHelp shows the help over the current view: the keys of that view when
+// contextual and it has any, the full help otherwise.
+func (m model) openHelp(contextual bool) model {
+	from := m.state
+	if from == stateHelp && m.help != nil {
+		from = m.help.from
+	}
+	_, hasKeys := viewKeys[from]
+	m.help = &helpView{from: from, contextual: contextual && hasKeys}
+	m.state = stateHelp
+	return m
+}
+
+// helpLines returns the lines of the help being shown, without the keys and
+// commands the current role cannot use.
+func (m model) helpLines() []string {
+	if !m.help.contextual {
+		return strings.Split(m.roleHelp(m.pluginHelp(helpContent)), "\n")
+	}
+	view := viewKeys[m.help.from]
+	lines := []string{"KEYS: " + view.title, ""}
+	for _, key := range view.keys {
+		lines = append(lines, fmt.Sprintf("  %-15s %s", key[0], key[1]))
+	}
+	lines = append(lines, "", "ANYWHERE:", "  ?               Keys of the current view", "  ctrl+l          Toggle debug pane with executed pocketd commands")
+	return strings.Split(m.roleHelp(strings.Join(lines, "\n")), "\n")
+}
+
+// findHelpMatches collects the help lines containing the search.
+func (m model) findHelpMatches() {
+	h := m.help
+	h.matches = nil
+	h.matchIndex = 0
+	if h.search == "" {
+		return
+	}
+	needle := strings.ToLower(h.search)
+	for i, line := range m.helpLines() {
+		if strings.Contains(strings.ToLower(line), needle) {
+			h.matches = append(h.matches, i)
+		}
+	}
+	if len(h.matches) > 0 {
+		h.scroll = max(h.matches[0]-2, 0)
+	}
+}
+
+// helpPage is the number of help lines shown at once.
+func (m model) helpPage() int {
+	return max(m.height-14, 1)
+}
+
+func (m model) updateHelp(msg tea.KeyMsg) (model, tea.Cmd) {
+	h := m.help
+	if h.searching {
+		switch msg.String() {
+		case "enter":
+			h.searching = false
+			m.findHelpMatches()
+			if h.search != "" && len(h.matches) == 0 {
+				m.err = fmt.Errorf("no match for %q", h.search)
+			}
+		case "esc":
+			h.searching = false
+		case "backspace":
+			if runes := []rune(h.search); len(runes) > 0 {
+				h.search = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				h.search += string(msg.Runes)
+			}
+		}
+		return m, nil
+	}
+
+	lines := len(m.helpLines())
+	switch msg.String() {
+	case "esc", "q", "enter":
+		m.state = h.from
+		m.help = nil
+	case "up", "k":
+		if h.scroll > 0 {
+			h.scroll--
+		}
+	case "down", "j":
+		if h.scroll < lines-m.helpPage() {
+			h.scroll++
+		}
+	case "pgup":
+		h.scroll = max(h.scroll-m.helpPage(), 0)
+	case "pgdown", " ":
+		h.scroll = max(min(h.scroll+m.helpPage(), lines-m.helpPage()), 0)
+	case "g":
+		h.scroll = 0
+	case "G":
+		h.scroll = max(lines-m.helpPage(), 0)
+	case "/":
+		h.searching = true
+		h.search = ""
+	case "n", "N":
+		if len(h.matches) == 0 {
+			return m, nil
+		}
+		if msg.String() == "n" {
+			h.matchIndex = (h.matchIndex + 1) % len(h.matches)
+		} else {
+			h.matchIndex = (h.matchIndex + len(h.matches) - 1) % len(h.matches)
+		}
+		h.scroll = max(h.matches[h.matchIndex]-2, 0)
+	case "?":
+		return m.openHelp(true), nil
+	case "h":
+		return m.openHelp(false), nil
+	}
+	return m, nil
+}
+
+// renderHelp shows the visible part of the help, with the lines matching the
+// search highlighted.
+func (m model) renderHelp() string {
+	helpStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Padding(1, 2).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")).
+		Width(m.width - 4)
+	matchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true)
+	currentStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Bold(true)
+	footerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+
+	h := m.help
+	if h == nil {
+		return ""
+	}
+	lines := m.helpLines()
+	matched := make(map[int]bool, len(h.matches))
+	for _, i := range h.matches {
+		matched[i] = true
+	}
+
+	// Scroll the help, keeping the status and search lines in place
+	visible := m.helpPage()
+	scroll := min(h.scroll, max(len(lines)-visible, 0))
+	end := min(scroll+visible, len(lines))
+	var shown []string
+	for i := scroll; i < end; i++ {
+		line := truncateToWidth(lines[i], m.width-10)
+		switch {
+		case len(h.matches) > 0 && i == h.matches[h.matchIndex]:
+			line = currentStyle.Render(line)
+		case matched[i]:
+			line = matchStyle.Render(line)
+		}
+		shown = append(shown, line)
+	}
+
+	status := fmt.Sprintf("Lines %d–%d of %d", scroll+1, end, len(lines))
+	if len(h.matches) > 0 {
+		status += fmt.Sprintf(" • match %d/%d for %q", h.matchIndex+1, len(h.matches), h.search)
+	}
+	switch {
+	case h.contextual:
+		status += " • h for the full help"
+	case viewKeys[h.from].title != "":
+		status += " • ? for the keys of this view"
+	}
+	content := []string{helpStyle.Render(strings.Join(shown, "\n")), footerStyle.Render(status)}
+	if h.searching {
+		content = append(content, inputStyle.Render("/"+h.search+"█"))
+	} else {
+		content = append(content, footerStyle.Render("j/k to scroll • pgup/pgdown a page • / to search, n/N • ESC, Enter or q to return"))
+	}
+	return strings.Join(content, "\n")
+}
+
+// cheatSheetEntry is an action of the header cheat-sheet, hidden along with
+// the help entry it belongs to ("" for none).
+type cheatSheetEntry struct {
+	helpEntry string
+	text      string
+}
+
+// cheatSheetNavigation are the navigation and sort columns of the header
+// cheat-sheet, one line each.
+var cheatSheetNavigation = []string{
+	"r: Refresh            :ss Status     :sv Service     ",
+	"n: Network            :sa Address                    ",
+	"g: Gateway            :sp Stake                      ",
+	"h: Help               :sb Balance                    ",
+}
+
+// cheatSheetActions are the actions of the header cheat-sheet, by line.
+var cheatSheetActions = [][]cheatSheetEntry{
+	{{"", ":: Command"}, {"", "/: Search"}},
+	{{"f  ", "f: Fund"}, {"F  ", "F: Fund All"}},
+	{{"u  ", "u: Upstake"}, {"U  ", "U: Upstake All"}},
+	{{"", "q: Quit"}},
+}
+
+// cheatSheetSpares fill the action lines the current role has nothing left
+// on.
+var cheatSheetSpares = []string{"d: Drift", "enter: Details"}
+
+// headerCheatSheet renders the keys of the header, without the actions the
+// current role cannot use.
+func (m model) headerCheatSheet() string {
+	var b strings.Builder
+	b.WriteString("Navigation:           Sort Columns:                  Actions:\n")
+	spares := cheatSheetSpares
+	for i, line := range cheatSheetNavigation {
+		var cells []string
+		for _, entry := range cheatSheetActions[i] {
+			if entry.helpEntry == "" || m.roleHelp("  "+entry.helpEntry) != "" {
+				cells = append(cells, fmt.Sprintf("%-14s", entry.text))
+			}
+		}
+		if len(cells) == 0 && len(spares) > 0 {
+			cells, spares = []string{spares[0]}, spares[1:]
+		}
+		b.WriteString(strings.TrimRight(line+strings.Join(cells, ""), " ") + "\n")
+	}
+	return b.String()
+}
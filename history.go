@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	_ "modernc.org/sqlite" // SQLite driver without cgo
+)
+
+const (
+	historyDir       = "history"
+	historyDBFile    = "history.db"
+	historyRetention = 90 * 24 * time.Hour // Samples older than this are deleted when history is loaded
+	sparklineWidth   = 48
+)
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// historySchema creates the time-series table of the history database.
+const historySchema = `
+CREATE TABLE IF NOT EXISTS samples (
+	network       TEXT    NOT NULL,
+	address       TEXT    NOT NULL,
+	time          INTEGER NOT NULL, -- Unix milliseconds
+	stake_upokt   INTEGER NOT NULL,
+	balance_upokt INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS samples_network_time ON samples (network, time);
+`
+
+// historyDB holds the open history database of the data directory in use;
+// it is reopened when a profile switch changes the directory.
+var historyDB struct {
+	sync.Mutex
+	path string
+	db   *sql.DB
+}
+
+// historySample is the stake and balance of one application at one refresh.
+type historySample struct {
+	Time         time.Time `json:"time"`
+	Address      string    `json:"address"`
+	StakeUpokt   int64     `json:"stake_upokt"`
+	BalanceUpokt int64     `json:"balance_upokt"`
+}
+
+// historyLoadedMsg delivers the recorded samples of a network, keyed by
+// address and oldest first.
+type historyLoadedMsg struct {
+	network string
+	samples map[string][]historySample
+	err     error
+}
+
+// openHistory returns the history database of the current data directory,
+// creating it if needed.
+func openHistory() (*sql.DB, error) {
+	path, err := dataPath(historyDir, historyDBFile)
+	if err != nil {
+		return nil, err
+	}
+	historyDB.Lock()
+	defer historyDB.Unlock()
+	if historyDB.db != nil && historyDB.path == path {
+		return historyDB.db, nil
+	}
+	// Wait for a daemon or another session writing to the same database
+	db, err := sql.Open("sqlite", "file:"+filepath.ToSlash(path)+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open history %s: %w", path, err)
+	}
+	os.Chmod(path, 0600)
+	if historyDB.db != nil {
+		historyDB.db.Close()
+	}
+	historyDB.path, historyDB.db = path, db
+	return db, nil
+}
+
+// importLegacyHistory moves the samples of network from the JSON lines file
+// written by earlier versions into db, then removes the file.
+func importLegacyHistory(db *sql.DB, network string) error {
+	path, err := dataPath(historyDir, safeFileName(network)+".jsonl")
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var samples []historySample
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample historySample
+		if json.Unmarshal(scanner.Bytes(), &sample) == nil {
+			samples = append(samples, sample)
+		}
+	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	if err := insertHistory(db, network, samples); err != nil {
+		return err
+	}
+	logger.Info("history imported", "network", network, "samples", len(samples), "from", path)
+	return os.Remove(path)
+}
+
+func loadHistoryCmd(network string) tea.Cmd {
+	return func() tea.Msg {
+		samples, err := loadHistory(network, time.Now().Add(-historyRetention))
+		return historyLoadedMsg{network: network, samples: samples, err: err}
+	}
+}
+
+// loadHistory reads the samples of network recorded since the given time,
+// oldest first, after deleting the samples of every network older than that
+// so the database does not grow without bound.
+func loadHistory(network string, since time.Time) (map[string][]historySample, error) {
+	samples := make(map[string][]historySample)
+	db, err := openHistory()
+	if err != nil {
+		return samples, err
+	}
+	if err := importLegacyHistory(db, network); err != nil {
+		logger.Warn("failed to import history", "network", network, "error", err)
+	}
+	if result, err := db.Exec(`DELETE FROM samples WHERE time < ?`, since.UnixMilli()); err != nil {
+		logger.Warn("failed to delete old history", "error", err)
+	} else if deleted, _ := result.RowsAffected(); deleted > 0 {
+		logger.Debug("old history deleted", "samples", deleted)
+	}
+
+	rows, err := db.Query(`SELECT address, time, stake_upokt, balance_upokt FROM samples
+		WHERE network = ? AND time >= ? ORDER BY time`, network, since.UnixMilli())
+	if err != nil {
+		return samples, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var sample historySample
+		var millis int64
+		if err := rows.Scan(&sample.Address, &millis, &sample.StakeUpokt, &sample.BalanceUpokt); err != nil {
+			return samples, err
+		}
+		sample.Time = time.UnixMilli(millis)
+		samples[sample.Address] = append(samples[sample.Address], sample)
+	}
+	return samples, rows.Err()
+}
+
+// insertHistory adds samples of network to db in one transaction.
+func insertHistory(db *sql.DB, network string, samples []historySample) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	insert, err := tx.Prepare(`INSERT INTO samples (network, address, time, stake_upokt, balance_upokt) VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer insert.Close()
+	for _, sample := range samples {
+		if _, err := insert.Exec(network, sample.Address, sample.Time.UnixMilli(), sample.StakeUpokt, sample.BalanceUpokt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// appendHistory records samples of network in the history database.
+func appendHistory(network string, samples []historySample) error {
+	db, err := openHistory()
+	if err != nil {
+		return err
+	}
+	return insertHistory(db, network, samples)
+}
+
+// recordHistory samples every loaded application once a refresh has fully
+// resolved.
+func (m *model) recordHistory() {
+	if m.history == nil || m.historyNetwork != m.currentNetwork || len(m.applications) == 0 {
+		return
+	}
+	now := time.Now()
+	var samples []historySample
+	for _, app := range m.applications {
+		if app.BalanceUnknown {
+			continue
+		}
+		sample := historySample{Time: now, Address: app.Address, StakeUpokt: stakeUpokt(app), BalanceUpokt: app.BalanceUpokt}
+		m.history[app.Address] = append(m.history[app.Address], sample)
+		samples = append(samples, sample)
+	}
+	if len(samples) == 0 {
+		return
+	}
+	if err := appendHistory(m.currentNetwork, samples); err != nil {
+		logger.Error("failed to record history", "network", m.currentNetwork, "error", err)
+	}
+}
+
+// sparkline renders values as a row of block characters scaled between their
+// minimum and maximum, keeping the most recent values that fit in width.
+func sparkline(values []int64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[0]
+	for _, v := range values {
+		low = min64(low, v)
+		high = max64(high, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int((v - low) * int64(len(sparkBlocks)-1) / (high - low))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// downsample picks at most n evenly spaced samples, always keeping the first
+// and the most recent one.
+func downsample(samples []historySample, n int) []historySample {
+	if len(samples) <= n || n < 2 {
+		return samples
+	}
+	picked := make([]historySample, n)
+	for i := range picked {
+		picked[i] = samples[i*(len(samples)-1)/(n-1)]
+	}
+	return picked
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// renderHistory renders stake and balance sparklines of an application.
+func (m model) renderHistory(address string) string {
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true)
+	sparkStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")) // Green for success
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")) // Soft grey-green
+
+	samples := m.history[address]
+	recorded := len(samples)
+	if recorded < 2 {
+		return textStyle.Render("  Not enough history yet; a sample is recorded on each refresh.")
+	}
+	samples = downsample(samples, sparklineWidth)
+
+	line := func(label string, value func(historySample) int64) string {
+		values := make([]int64, len(samples))
+		low, high := value(samples[0]), value(samples[0])
+		for i, sample := range samples {
+			values[i] = value(sample)
+			low = min64(low, values[i])
+			high = max64(high, values[i])
+		}
+		return fmt.Sprintf("  %s %s %s",
+			labelStyle.Render(fmt.Sprintf("%-8s", label)),
+			sparkStyle.Render(sparkline(values, sparklineWidth)),
+			textStyle.Render(fmt.Sprintf("%s → %s %s (min %s, max %s)",
+				m.formatAmount(values[0]), m.formatAmount(values[len(values)-1]), m.unitLabel(),
+				m.formatAmount(low), m.formatAmount(high))))
+	}
+
+	span := samples[len(samples)-1].Time.Sub(samples[0].Time)
+	return strings.Join([]string{
+		line("Stake", func(s historySample) int64 { return s.StakeUpokt }),
+		line("Balance", func(s historySample) int64 { return s.BalanceUpokt }),
+		textStyle.Render(fmt.Sprintf("  %d samples since %s (%s)", recorded,
+			samples[0].Time.Local().Format("2006-01-02 15:04"), formatDuration(span))),
+	}, "\n")
+}
+
+// formatDuration renders a span as days and hours.
+func formatDuration(d time.Duration) string {
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh", days, hours)
+	}
+	return fmt.Sprintf("%dh %dm", hours, int(d.Minutes())%60)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// importEntry is an application to add to the config.
+type importEntry struct {
+	Address string `json:"address"`
+	Label   string `json:"label,omitempty"`
+	Stake   int64  `json:"stake,omitempty"` // Target stake in upokt (0 = network target)
+}
+
+// readImportFile parses a JSON array of entries or a CSV file of
+// "address[,label[,stake]]" lines. Blank lines, # comments and an "address"
+// header are ignored.
+func readImportFile(path string) ([]importEntry, error) {
+	path = expandHome(path)
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var entries []importEntry
+		if err := readJSONFile(path, &entries); err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	var entries []importEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected address[,label[,stake]]", line)
+		}
+		entry := importEntry{Address: strings.TrimSpace(record[0])}
+		if len(entries) == 0 && strings.EqualFold(entry.Address, "address") {
+			continue // Header
+		}
+		if len(record) > 1 {
+			entry.Label = strings.TrimSpace(record[1])
+		}
+		if len(record) > 2 && strings.TrimSpace(record[2]) != "" {
+			entry.Stake, err = strconv.ParseInt(strings.TrimSpace(record[2]), 10, 64)
+			if err != nil || entry.Stake <= 0 {
+				return nil, fmt.Errorf("line %d: stake must be a positive integer: %s", line, record[2])
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// handleImportCommand appends the applications listed in a file to the
+// current network's config: "import <file>".
+func (m model) handleImportCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: import <file.csv|file.json>")
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
+		return m, nil
+	}
+	entries, err := readImportFile(parts[1])
+	if err != nil {
+		m.err = fmt.Errorf("failed to read %s: %v", parts[1], err)
+		return m, nil
+	}
+
+	configured := make(map[string]bool)
+	for _, address := range m.config.Config.Networks[m.currentNetwork].Applications {
+		configured[address] = true
+	}
+	var added []importEntry
+	existing := 0
+	for i, entry := range entries {
+		if err := validateAddress(entry.Address); err != nil {
+			m.err = fmt.Errorf("entry %d: %v", i+1, err)
+			return m, nil
+		}
+		if entry.Stake < 0 {
+			m.err = fmt.Errorf("entry %d: stake must be positive: %d", i+1, entry.Stake)
+			return m, nil
+		}
+		if configured[entry.Address] {
+			existing++
+			continue
+		}
+		configured[entry.Address] = true
+		added = append(added, entry)
+	}
+	if len(added) == 0 {
+		return m, m.notify(toastInfo, fmt.Sprintf("Nothing to import: all %d applications are already configured", existing))
+	}
+
+	summary := fmt.Sprintf("Imported %d applications into %s", len(added), m.currentNetwork)
+	if existing > 0 {
+		summary += fmt.Sprintf(" (%d already configured)", existing)
+	}
+	network, gateway := m.currentNetwork, m.currentGateway
+	return m, saveConfigCmd(summary, func(root *yaml.Node) error {
+		return importApplications(root, network, gateway, added)
+	})
+}
+
+// importApplications adds entries to the applications (of gateway, when the
+// network maps gateways to applications), labels and app_targets of network
+// in the config document.
+func importApplications(root *yaml.Node, network, gateway string, entries []importEntry) error {
+	node, err := networkNode(root, network)
+	if err != nil {
+		return err
+	}
+	applications, err := applicationList(node, network, gateway)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		applications.Content = append(applications.Content, scalarNode(entry.Address))
+		if entry.Label != "" {
+			labels := mappingValue(node, "labels", yaml.MappingNode, true)
+			mappingValue(labels, entry.Address, yaml.ScalarNode, true).Value = entry.Label
+		}
+		if entry.Stake > 0 {
+			targets := mappingValue(node, "app_targets", yaml.MappingNode, true)
+			override := mappingValue(targets, entry.Address, yaml.MappingNode, true)
+			mappingValue(override, "stake", yaml.ScalarNode, true).Value = strconv.FormatInt(entry.Stake, 10)
+		}
+	}
+	return nil
+}
+
+// appLabel returns the configured label of an application.
+func (m model) appLabel(address string) string {
+	if m.config == nil {
+		return ""
+	}
+	return m.config.Config.Networks[m.currentNetwork].Labels[address]
+}
@@ -0,0 +1,923 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// jobPollInterval is how often a running step checks whether its
+	// transaction has finished.
+	jobPollInterval = time.Second
+	// jobWaitInterval is how often a waiting step, such as the unbonding of
+	// a decommission, checks the chain again.
+	jobWaitInterval = 30 * time.Second
+)
+
+// Status of a job step
+const (
+	stepPending = "pending"
+	stepRunning = "running"
+	stepOK      = "ok"
+	stepFailed  = "failed"
+)
+
+// Status of a job
+const (
+	jobStaged    = "staged" // Waiting for Enter to be queued
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobWaiting   = "waiting" // Waiting on the chain; the next job may start
+	jobFailed    = "failed"
+	jobDone      = "done"
+	jobCancelled = "cancelled"
+)
+
+// Kinds of job
+const (
+	jobOnboard      = "onboard"
+	jobDecommission = "decommission"
+	jobUpstakeAll   = "upstake-all"
+)
+
+// job is a background operation made of steps, such as the onboarding or
+// decommission of an application or a batch upstake. Jobs run one at a time
+// in queue order. Every step queries the chain first and only submits what is
+// not done yet, so a failed job can be retried safely.
+type job struct {
+	id      int
+	kind    string
+	address string // Application of an onboarding or decommission
+	service string // Service staked for by an onboarding
+	stake   int64  // upokt staked by an onboarding
+	amount  int64  // upokt added to each application by a batch upstake
+	network string
+	gateway string
+	bank    string
+	steps   []jobStep
+	current int  // Index of the running or next step
+	queued  bool // The job was queued to run
+	batch   bool // The steps are being broadcast together
+	// cancelling stops the job once its running step finishes
+	cancelling bool
+	cancelled  bool
+	done       bool
+	createdAt  time.Time
+	finishedAt time.Time
+}
+
+// jobStep is a step of a job with its result and receipts.
+type jobStep struct {
+	name        string
+	description string
+	address     string // Application of the step, when not the job's
+	target      int64  // Stake the step brings the application to (upstakes)
+	status      string
+	detail      string
+	txID        int      // Tracked transaction in flight (0 if none)
+	saving      bool     // The config edit of the step is being written
+	waiting     bool     // The step checks the chain again after a wait
+	receipts    []string // Hashes of the transactions of the step
+}
+
+// status summarizes the state of the job.
+func (j *job) status() string {
+	switch {
+	case j.cancelled:
+		return jobCancelled
+	case j.done:
+		return jobDone
+	}
+	failed := false
+	for _, step := range j.steps {
+		if step.status == stepRunning {
+			if !j.batch && step.waiting {
+				return jobWaiting
+			}
+			return jobRunning
+		}
+		failed = failed || step.status == stepFailed
+	}
+	switch {
+	case failed:
+		return jobFailed
+	case j.queued:
+		return jobQueued
+	}
+	return jobStaged
+}
+
+// finished reports whether the job will not run again.
+func (j *job) finished() bool {
+	return j.done || j.cancelled
+}
+
+// target names what the job works on for the job list.
+func (j *job) target() string {
+	if j.address != "" {
+		return j.address
+	}
+	return fmt.Sprintf("%d applications", len(j.steps))
+}
+
+// title is the capitalized kind of the job for notifications.
+func (j *job) title() string {
+	return fmt.Sprintf("%s #%d", strings.ToUpper(j.kind[:1])+j.kind[1:], j.id)
+}
+
+// stepAddress returns the application the current step works on.
+func (j *job) stepAddress() string {
+	if address := j.steps[j.current].address; address != "" {
+		return address
+	}
+	return j.address
+}
+
+// progress counts the finished steps of the job.
+func (j *job) progress() (int, int) {
+	ok := 0
+	for _, step := range j.steps {
+		if step.status == stepOK {
+			ok++
+		}
+	}
+	return ok, len(j.steps)
+}
+
+// jobStateMsg is the on-chain state of the application of the running step,
+// queried at the start of every step.
+type jobStateMsg struct {
+	job     *job
+	balance int64
+	app     *Application // nil if not staked
+	err     error
+}
+
+type jobTickMsg struct {
+	job *job
+}
+
+// jobRecheckMsg starts a waiting step again.
+type jobRecheckMsg struct {
+	job *job
+}
+
+// jobSubmittedMsg reports a broadcast job transaction.
+type jobSubmittedMsg struct {
+	txID   int
+	kind   string
+	txHash string
+}
+
+func jobTickCmd(j *job) tea.Cmd {
+	return tea.Tick(jobPollInterval, func(time.Time) tea.Msg {
+		return jobTickMsg{job: j}
+	})
+}
+
+func jobRecheckCmd(j *job) tea.Cmd {
+	return tea.Tick(jobWaitInterval, func(time.Time) tea.Msg {
+		return jobRecheckMsg{job: j}
+	})
+}
+
+// newJob creates a job of kind on address with the named steps and their
+// descriptions, for the current network and gateway.
+func (m *model) newJob(kind, address string, steps ...string) *job {
+	m.nextJobID++
+	j := &job{
+		id:        m.nextJobID,
+		kind:      kind,
+		address:   address,
+		network:   m.currentNetwork,
+		gateway:   m.currentGateway,
+		bank:      m.config.Config.Networks[m.currentNetwork].Bank,
+		createdAt: time.Now(),
+	}
+	for i := 0; i+1 < len(steps); i += 2 {
+		j.steps = append(j.steps, jobStep{name: steps[i], description: steps[i+1], status: stepPending})
+	}
+	m.jobs = append(m.jobs, j)
+	return j
+}
+
+// hasJob reports whether j is still listed; messages of cleared jobs are
+// dropped.
+func (m model) hasJob(j *job) bool {
+	return j != nil && slices.Contains(m.jobs, j)
+}
+
+// jobFor returns the unfinished job working on address, or nil.
+func (m model) jobFor(address string) *job {
+	for _, j := range m.jobs {
+		if j.address == address && !j.finished() {
+			return j
+		}
+	}
+	return nil
+}
+
+// checkJobFree refuses a new job on an address another job is working on.
+func (m model) checkJobFree(address string) error {
+	if j := m.jobFor(address); j != nil {
+		return fmt.Errorf("%s already has %s job #%d (:jobs)", TruncateAddress(address, 20), j.status(), j.id)
+	}
+	return nil
+}
+
+// lastJob returns the most recent job of kind, or nil.
+func (m model) lastJob(kind string) *job {
+	for i := len(m.jobs) - 1; i >= 0; i-- {
+		if m.jobs[i].kind == kind {
+			return m.jobs[i]
+		}
+	}
+	return nil
+}
+
+// showJob opens the job view on j.
+func (m model) showJob(j *job) (model, tea.Cmd) {
+	m.viewedJob = j
+	m.jobStepCursor = 0
+	m.state = stateJob
+	return m, nil
+}
+
+// showLastJob opens the job view on the last job of kind.
+func (m model) showLastJob(kind, usage string) (model, tea.Cmd) {
+	j := m.lastJob(kind)
+	if j == nil {
+		m.err = fmt.Errorf("usage: %s", usage)
+		return m, nil
+	}
+	return m.showJob(j)
+}
+
+// queueJob queues j and starts it if no other job is running.
+func (m *model) queueJob(j *job) tea.Cmd {
+	j.queued = true
+	logger.Info("job queued", "job", j.id, "kind", j.kind, "network", j.network, "target", j.target())
+	return m.startQueuedJobs()
+}
+
+// startQueuedJobs starts the oldest queued job unless a job is running. A
+// job waiting on the chain lets the next one start.
+func (m *model) startQueuedJobs() tea.Cmd {
+	for _, j := range m.jobs {
+		if j.status() == jobRunning {
+			return nil
+		}
+	}
+	for _, j := range m.jobs {
+		if j.status() == jobQueued {
+			j.current = 0
+			for j.current < len(j.steps)-1 && j.steps[j.current].status == stepOK {
+				j.current++
+			}
+			logger.Info("job started", "job", j.id, "kind", j.kind, "step", j.steps[j.current].name)
+			return m.startJobStep(j)
+		}
+	}
+	return nil
+}
+
+// queryJobState reads the balance and application of the current step.
+func (m model) queryJobState(j *job) tea.Cmd {
+	config := m.config
+	address := j.stepAddress()
+	return func() tea.Msg {
+		network := config.Config.Networks[j.network]
+		msg := jobStateMsg{job: j}
+		msg.err = withFailover(j.network, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			msg.balance, err = QueryBankBalanceUpokt(address, endpoint, config.keyringBackend(j.network), config.pocketdHome(j.network))
+			if err != nil {
+				return err
+			}
+			msg.app, err = ShowApplication(address, endpoint, config.pocketdHome(j.network), j.network)
+			return err
+		})
+		return msg
+	}
+}
+
+// startJobStep runs the current step of j, starting with a query of the
+// chain.
+func (m *model) startJobStep(j *job) tea.Cmd {
+	step := &j.steps[j.current]
+	step.status = stepRunning
+	step.txID = 0
+	step.saving = false
+	step.waiting = false
+	if j.network != m.currentNetwork {
+		return m.failJobStep(j, "switch back to "+j.network+" to continue")
+	}
+	return tea.Batch(m.queryJobState(j), m.startSpinner())
+}
+
+// jobState hands the queried state to the running step, which finishes,
+// fails, waits, or submits its next transaction.
+func (m *model) jobState(msg jobStateMsg) tea.Cmd {
+	j := msg.job
+	if !m.hasJob(j) || j.finished() {
+		return nil
+	}
+	step := &j.steps[j.current]
+	if step.status != stepRunning || step.txID != 0 {
+		return nil
+	}
+	if j.cancelling {
+		return m.cancelJob(j)
+	}
+	if msg.err != nil {
+		return m.failJobStep(j, fmt.Sprintf("failed to query %s: %v", TruncateAddress(j.stepAddress(), 20), msg.err))
+	}
+	if j.network != m.currentNetwork {
+		return m.failJobStep(j, "switch back to "+j.network+" to continue")
+	}
+	switch j.kind {
+	case jobOnboard:
+		return m.onboardStep(j, msg)
+	case jobDecommission:
+		return m.decommissionStep(j, msg)
+	case jobUpstakeAll:
+		return m.upstakeStep(j, msg)
+	}
+	return nil
+}
+
+// submitJobTx tracks and submits the transaction of the running step, sent
+// by send once authorized, and follows it until it finishes.
+func (m *model) submitJobTx(j *job, kind, command string, amount int64, send func(config *Config, network string) (string, error)) tea.Cmd {
+	step := &j.steps[j.current]
+	address := j.stepAddress()
+	txID := m.trackTx(kind, command, []string{address}, amount)
+	step.txID = txID
+	config, network := m.config, j.network
+	run := func() tea.Msg {
+		txHash, err := send(config, network)
+		if err != nil {
+			return newTxFailedMsg(txID, kind, []string{address}, amount, err)
+		}
+		return jobSubmittedMsg{txID: txID, kind: strings.ToUpper(kind), txHash: txHash}
+	}
+	return tea.Batch(m.submitTracked(command, run, txID), jobTickCmd(j))
+}
+
+// jobTick follows the transactions of the running steps. Once included, a
+// step checks the chain again, so steps made of several transactions
+// continue with the next one.
+func (m *model) jobTick(msg jobTickMsg) tea.Cmd {
+	j := msg.job
+	if !m.hasJob(j) || j.finished() {
+		return nil
+	}
+	if j.batch {
+		return m.batchTick(j)
+	}
+	step := &j.steps[j.current]
+	if step.status != stepRunning || step.txID == 0 {
+		return nil
+	}
+	tx := m.findTx(step.txID)
+	if tx == nil {
+		return m.failJobStep(j, "transaction no longer tracked")
+	}
+	if !tx.finished() {
+		return jobTickCmd(j)
+	}
+	if tx.hash != "" {
+		step.receipts = append(step.receipts, tx.hash)
+	}
+	if tx.status != txIncluded {
+		return m.failJobStep(j, tx.failure())
+	}
+	if j.cancelling {
+		return m.cancelJob(j)
+	}
+	return m.startJobStep(j)
+}
+
+// batchTick follows the transactions of a job whose steps were broadcast
+// together, finishing the job once all of them have.
+func (m *model) batchTick(j *job) tea.Cmd {
+	running, failed := 0, 0
+	for i := range j.steps {
+		step := &j.steps[i]
+		if step.status == stepFailed {
+			failed++
+		}
+		if step.status != stepRunning {
+			continue
+		}
+		if step.txID == 0 {
+			running++ // Not broadcast yet
+			continue
+		}
+		tx := m.findTx(step.txID)
+		switch {
+		case tx == nil:
+			step.status, step.detail = stepFailed, "transaction no longer tracked"
+		case !tx.finished():
+			running++
+			continue
+		case tx.status == txIncluded:
+			step.status, step.detail = stepOK, "included"
+		default:
+			step.status, step.detail = stepFailed, tx.failure()
+		}
+		if tx != nil && tx.hash != "" {
+			step.receipts = append(step.receipts, tx.hash)
+		}
+		step.txID = 0
+		if step.status == stepFailed {
+			failed++
+		}
+	}
+	if running > 0 {
+		return jobTickCmd(j)
+	}
+
+	j.batch = false
+	switch {
+	case j.cancelling:
+		return m.cancelJob(j)
+	case failed > 0:
+		logger.Error("job failed", "job", j.id, "kind", j.kind, "failed", failed, "steps", len(j.steps))
+		return tea.Batch(
+			m.notify(toastError, fmt.Sprintf("%s: %d of %d failed; r in :jobs retries them", j.title(), failed, len(j.steps))),
+			m.startQueuedJobs(),
+		)
+	}
+	return m.finishJob(j)
+}
+
+// failure describes why t did not succeed.
+func (t trackedTx) failure() string {
+	reason := string(t.status)
+	if t.err != "" {
+		reason += ": " + t.err
+	}
+	return reason
+}
+
+// jobRecheck starts a waiting step again.
+func (m *model) jobRecheck(msg jobRecheckMsg) tea.Cmd {
+	j := msg.job
+	if !m.hasJob(j) || j.finished() || !j.steps[j.current].waiting {
+		return nil
+	}
+	if j.cancelling {
+		return m.cancelJob(j)
+	}
+	return m.startJobStep(j)
+}
+
+// waitJobStep keeps the running step waiting, checking the chain again after
+// the wait interval. The next queued job may start meanwhile.
+func (m *model) waitJobStep(j *job, detail string) tea.Cmd {
+	step := &j.steps[j.current]
+	step.waiting = true
+	step.detail = detail
+	return tea.Batch(jobRecheckCmd(j), m.startQueuedJobs())
+}
+
+// saveJobConfig runs the config edit of the running step; the step finishes
+// with its result.
+func (m *model) saveJobConfig(j *job, summary string, edit func(root *yaml.Node) error) tea.Cmd {
+	step := &j.steps[j.current]
+	step.saving = true
+	step.detail = "saving " + configFile
+	return saveConfigCmd(summary, edit)
+}
+
+// jobConfigSaved finishes the job step waiting on a config edit with its
+// result.
+func (m *model) jobConfigSaved(msg configSavedMsg) tea.Cmd {
+	for _, j := range m.jobs {
+		if j.finished() {
+			continue
+		}
+		step := j.steps[j.current]
+		if step.status != stepRunning || !step.saving {
+			continue
+		}
+		if msg.err != nil {
+			return m.failJobStep(j, fmt.Sprintf("config not saved: %v", msg.err))
+		}
+		return m.finishJobStep(j, msg.summary)
+	}
+	return nil
+}
+
+// finishJobStep marks the running step done and starts the next one that
+// is not done.
+func (m *model) finishJobStep(j *job, detail string) tea.Cmd {
+	step := &j.steps[j.current]
+	step.status = stepOK
+	step.detail = detail
+	step.txID = 0
+	logger.Info("job step done", "job", j.id, "kind", j.kind, "step", step.name, "detail", detail, "receipts", step.receipts)
+	if j.cancelling {
+		return m.cancelJob(j)
+	}
+	for i := range j.steps {
+		if j.steps[i].status != stepOK {
+			j.current = i
+			return m.startJobStep(j)
+		}
+	}
+	return m.finishJob(j)
+}
+
+// finishJob marks j done and starts the next queued job.
+func (m *model) finishJob(j *job) tea.Cmd {
+	j.done = true
+	j.finishedAt = time.Now()
+	logger.Info("job finished", "job", j.id, "kind", j.kind, "network", j.network, "target", j.target())
+	return tea.Batch(
+		m.notify(toastSuccess, fmt.Sprintf("%s of %s complete", j.title(), TruncateAddress(j.target(), 20))),
+		m.startQueuedJobs(),
+	)
+}
+
+// failJobStep stops j at the running step; it can be retried from the job
+// views.
+func (m *model) failJobStep(j *job, detail string) tea.Cmd {
+	step := &j.steps[j.current]
+	step.status = stepFailed
+	step.detail = detail
+	step.txID = 0
+	step.waiting = false
+	logger.Error("job step failed", "job", j.id, "kind", j.kind, "address", j.stepAddress(), "step", step.name, "error", detail)
+	if j.cancelling {
+		return m.cancelJob(j)
+	}
+	return tea.Batch(
+		m.notify(toastError, fmt.Sprintf("%s %s failed: %s", j.title(), step.name, detail)),
+		m.startQueuedJobs(),
+	)
+}
+
+// cancelJob marks j cancelled; a running step is left as it is.
+func (m *model) cancelJob(j *job) tea.Cmd {
+	j.cancelled = true
+	j.cancelling = false
+	j.finishedAt = time.Now()
+	for i := range j.steps {
+		if j.steps[i].status == stepRunning {
+			j.steps[i].status = stepFailed
+			j.steps[i].detail = "cancelled"
+		}
+	}
+	logger.Info("job cancelled", "job", j.id, "kind", j.kind, "target", j.target())
+	return tea.Batch(m.notify(toastInfo, j.title()+" cancelled"), m.startQueuedJobs())
+}
+
+// requestCancel cancels j now, or once its running step finishes when the
+// step has a transaction or query in flight.
+func (m *model) requestCancel(j *job) tea.Cmd {
+	switch j.status() {
+	case jobDone, jobCancelled:
+		return nil
+	case jobRunning:
+		j.cancelling = true
+		if j.batch {
+			return m.cancelInFlight()
+		}
+		return m.notify(toastInfo, j.title()+" stops once its running step finishes")
+	}
+	return m.cancelJob(j)
+}
+
+// retryJob queues the failed steps of j again.
+func (m *model) retryJob(j *job) tea.Cmd {
+	if j.status() != jobFailed {
+		return nil
+	}
+	for i := range j.steps {
+		if j.steps[i].status == stepFailed {
+			j.steps[i].status = stepPending
+		}
+	}
+	logger.Info("job retried", "job", j.id, "kind", j.kind)
+	return m.queueJob(j)
+}
+
+// clearFinishedJobs drops the done and cancelled jobs from the list.
+func (m *model) clearFinishedJobs() {
+	remaining := m.jobs[:0]
+	for _, j := range m.jobs {
+		if !j.finished() {
+			remaining = append(remaining, j)
+		}
+	}
+	m.jobs = remaining
+	if m.viewedJob != nil && !m.hasJob(m.viewedJob) {
+		m.viewedJob = nil
+	}
+	if m.jobCursor >= len(m.jobs) {
+		m.jobCursor = max(len(m.jobs)-1, 0)
+	}
+}
+
+// newUpstakeJob records a batch upstake of amount on addresses as a job,
+// with one running step per application. The steps are broadcast together;
+// a retry upstakes the failed ones one at a time to their target stake.
+func (m *model) newUpstakeJob(amount int64, addresses []string) *job {
+	stakes := make(map[string]int64, len(m.applications))
+	for _, app := range m.applications {
+		stakes[app.Address] = stakeUpokt(app)
+	}
+	j := m.newJob(jobUpstakeAll, "")
+	j.amount = amount
+	j.queued = true
+	j.batch = true
+	for _, address := range addresses {
+		j.steps = append(j.steps, jobStep{
+			name:        "upstake",
+			description: "Upstake " + TruncateAddress(address, 20),
+			address:     address,
+			target:      stakes[address] + amount,
+			status:      stepRunning,
+		})
+	}
+	logger.Info("job started", "job", j.id, "kind", j.kind, "steps", len(j.steps))
+	return j
+}
+
+// upstakeBatchSent attaches the tracked transactions of a broadcast batch
+// upstake to the steps of j and follows them.
+func (m *model) upstakeBatchSent(j *job, receipts []UpstakeReceipt, txIDs []int) tea.Cmd {
+	if !m.hasJob(j) {
+		return nil
+	}
+	for i, receipt := range receipts {
+		for s := range j.steps {
+			step := &j.steps[s]
+			if step.address != receipt.appAddress || step.status != stepRunning {
+				continue
+			}
+			step.txID = txIDs[i]
+			break
+		}
+	}
+	// Applications the batch skipped were not loaded or configured
+	for s := range j.steps {
+		if step := &j.steps[s]; step.status == stepRunning && step.txID == 0 {
+			step.status, step.detail = stepFailed, "not upstaked: not loaded or not configured"
+		}
+	}
+	return m.batchTick(j)
+}
+
+// upstakeStep brings the application of the running step of a batch upstake
+// to its target stake.
+func (m *model) upstakeStep(j *job, msg jobStateMsg) tea.Cmd {
+	step := j.steps[j.current]
+	if msg.app == nil {
+		return m.failJobStep(j, "the application is not staked")
+	}
+	stake := stakeUpokt(*msg.app)
+	if stake >= step.target {
+		return m.finishJobStep(j, fmt.Sprintf("staked %s %s", m.formatAmount(stake), m.unitLabel()))
+	}
+	amount := step.target - stake
+	address, serviceIDs := step.address, msg.app.ServiceIDs
+	command := fmt.Sprintf("upstake-all retry %s %d", address, amount)
+	return m.submitJobTx(j, "upstake-all", command, amount, func(config *Config, network string) (string, error) {
+		txHash, _, err := upstakeApplication(address, serviceIDs, amount, config, network)
+		return txHash, err
+	})
+}
+
+// handleJobsCommand opens the job list.
+func (m model) handleJobsCommand() (model, tea.Cmd) {
+	if m.jobCursor >= len(m.jobs) {
+		m.jobCursor = max(len(m.jobs)-1, 0)
+	}
+	m.state = stateJobs
+	return m, nil
+}
+
+func (m model) updateJobs(msg tea.KeyMsg) (model, tea.Cmd) {
+	var selected *job
+	if m.jobCursor < len(m.jobs) {
+		selected = m.jobs[m.jobCursor]
+	}
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.jobCursor > 0 {
+			m.jobCursor--
+		}
+	case "down", "j":
+		if m.jobCursor < len(m.jobs)-1 {
+			m.jobCursor++
+		}
+	case "enter":
+		if selected != nil {
+			return m.showJob(selected)
+		}
+	case "r":
+		if selected != nil {
+			return m, m.retryJob(selected)
+		}
+	case "x":
+		if selected != nil {
+			return m, m.requestCancel(selected)
+		}
+	case "c":
+		m.clearFinishedJobs()
+	}
+	return m, nil
+}
+
+func (m model) updateJob(msg tea.KeyMsg) (model, tea.Cmd) {
+	j := m.viewedJob
+	if j == nil {
+		m.state = stateJobs
+		return m, nil
+	}
+	switch msg.String() {
+	case "esc", "q":
+		// A running job keeps going; :jobs shows it again
+		m.state = stateJobs
+	case "up", "k":
+		if m.jobStepCursor > 0 {
+			m.jobStepCursor--
+		}
+	case "down", "j":
+		if m.jobStepCursor < len(j.steps)-1 {
+			m.jobStepCursor++
+		}
+	case "enter", "r":
+		// Queue a staged job, or retry a failed one
+		switch j.status() {
+		case jobStaged:
+			return m, m.queueJob(j)
+		case jobFailed:
+			return m, m.retryJob(j)
+		}
+	case "x":
+		return m, m.requestCancel(j)
+	}
+	return m, nil
+}
+
+// jobIcon shows the status of a job or step.
+func (m model) jobIcon(status string) string {
+	// Job and step statuses share the running and failed values
+	switch status {
+	case jobRunning, jobWaiting:
+		return m.spinner()
+	case jobDone, stepOK:
+		return "✅"
+	case jobFailed:
+		return "❌"
+	case jobCancelled:
+		return "🚫"
+	case jobQueued:
+		return "⏳"
+	}
+	return "·"
+}
+
+// renderJobs lists the jobs of the session, oldest first.
+func (m model) renderJobs() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	counts := make(map[string]int)
+	for _, j := range m.jobs {
+		counts[j.status()]++
+	}
+	title := fmt.Sprintf("⚙️ JOBS • %d running • %d queued • %d failed", counts[jobRunning]+counts[jobWaiting], counts[jobQueued], counts[jobFailed])
+	content := []string{headerStyle.Render(title), ""}
+	if len(m.jobs) == 0 {
+		content = append(content, textStyle.Render("No jobs yet. :onboard, :decommission and upstake-all run as jobs."))
+	}
+	for i, j := range m.jobs {
+		done, total := j.progress()
+		status := j.status()
+		if status == jobWaiting || status == jobFailed {
+			status += ": " + j.steps[j.current].detail
+		}
+		line := fmt.Sprintf("%s #%-3d %-13s %-44s %d/%d  %s", m.jobIcon(j.status()), j.id, j.kind, TruncateAddress(j.target(), 44), done, total, status)
+		line = truncateToWidth(line, max(m.width-6, 20))
+		if i == m.jobCursor {
+			content = append(content, selectedStyle.Render(line))
+		} else {
+			content = append(content, textStyle.Render(line))
+		}
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render("Enter to open • r to retry a failed job • x to cancel • c to clear finished • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
+
+// renderJob shows the steps of the viewed job with the result and receipts
+// of each.
+func (m model) renderJob() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+	detailStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")).
+		Padding(0, 8)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 8)
+
+	j := m.viewedJob
+	if j == nil {
+		return ""
+	}
+	var title, summary string
+	switch j.kind {
+	case jobOnboard:
+		title = fmt.Sprintf("🚀 ONBOARD #%d • %s", j.id, j.address)
+		summary = fmt.Sprintf("Service %s • stake %s %s • gateway %s • %s",
+			j.service, m.formatAmount(j.stake), m.unitLabel(), j.gateway, j.network)
+	case jobDecommission:
+		title = fmt.Sprintf("🪦 DECOMMISSION #%d • %s", j.id, j.address)
+		summary = fmt.Sprintf("Balance swept to the bank %s • %s", j.bank, j.network)
+	case jobUpstakeAll:
+		title = fmt.Sprintf("⬆️ UPSTAKE ALL #%d • %d applications", j.id, len(j.steps))
+		summary = fmt.Sprintf("+%s %s each • gateway %s • %s", m.formatAmount(j.amount), m.unitLabel(), j.gateway, j.network)
+	}
+	content := []string{headerStyle.Render(title), ""}
+	content = append(content, textStyle.Render(summary+" • "+j.status()))
+	content = append(content, "")
+
+	// Long batches scroll with the cursor
+	visible := max(m.height-16, 5)
+	start := 0
+	if len(j.steps) > visible {
+		start = min(max(m.jobStepCursor-visible/2, 0), len(j.steps)-visible)
+	}
+	for i := start; i < len(j.steps) && i < start+visible; i++ {
+		step := j.steps[i]
+		text := fmt.Sprintf("%s %d. %s", m.jobIcon(step.status), i+1, step.description)
+		if i == m.jobStepCursor {
+			content = append(content, selectedStyle.Render(text))
+		} else {
+			content = append(content, textStyle.Render(text))
+		}
+		for _, hash := range step.receipts {
+			content = append(content, detailStyle.Render("TXHASH: "+hash))
+		}
+		if step.detail != "" {
+			style := detailStyle
+			if step.status == stepFailed {
+				style = errorStyle
+			}
+			content = append(content, style.Render(truncateToWidth(step.detail, max(m.width-12, 20))))
+		}
+	}
+
+	content = append(content, "")
+	var help string
+	switch j.status() {
+	case jobDone, jobCancelled:
+		help = "j/k to move • ESC or Q to the job list"
+	case jobStaged:
+		help = "Enter to start • x to cancel • ESC or Q to the job list"
+	case jobFailed:
+		help = "r or Enter to retry the failed steps • x to cancel • ESC or Q to the job list"
+	default:
+		help = "j/k to move • x to cancel • ESC or Q to the job list (it keeps going)"
+	}
+	content = append(content, textStyle.Render(help))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,517 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const secretTimeout = 10 * time.Second
+
+// KeyringPassphrase is where the passphrase of a file or os keyring comes
+// from. It is piped to pocketd, which otherwise waits for it on a terminal
+// the TUI does not give it. Exactly one source is set.
+type KeyringPassphrase struct {
+	Env   string      `yaml:"env,omitempty"`   // Environment variable holding the passphrase
+	Vault VaultSecret `yaml:"vault,omitempty"` // Field of a HashiCorp Vault KV secret
+	AWS   AWSSecret   `yaml:"aws,omitempty"`   // AWS Secrets Manager secret, read with the aws CLI
+}
+
+// VaultSecret is a field of a Vault KV secret, read over the HTTP API.
+type VaultSecret struct {
+	Address  string `yaml:"address,omitempty"`   // Default $VAULT_ADDR
+	Path     string `yaml:"path,omitempty"`      // e.g. secret/data/gasms for KV v2
+	Field    string `yaml:"field,omitempty"`     // Default passphrase
+	TokenEnv string `yaml:"token_env,omitempty"` // Variable holding the token, default VAULT_TOKEN
+}
+
+// AWSSecret is a secret of AWS Secrets Manager, read with the aws CLI and
+// its usual credentials.
+type AWSSecret struct {
+	SecretID string `yaml:"secret_id,omitempty"`
+	Region   string `yaml:"region,omitempty"` // Default the CLI's region
+	Field    string `yaml:"field,omitempty"`  // Key of a JSON secret; the whole secret string without it
+}
+
+func (k KeyringPassphrase) enabled() bool {
+	return k.Env != "" || k.Vault.Path != "" || k.AWS.SecretID != ""
+}
+
+// source describes where the passphrase is read from, for errors and logs.
+func (k KeyringPassphrase) source() string {
+	switch {
+	case k.Env != "":
+		return "env " + k.Env
+	case k.Vault.Path != "":
+		return "vault " + k.Vault.Path + "#" + k.Vault.field()
+	case k.AWS.SecretID != "":
+		return "aws " + k.AWS.SecretID + "#" + k.AWS.Field
+	}
+	return ""
+}
+
+func (k KeyringPassphrase) validate() error {
+	sources := 0
+	for _, set := range []bool{k.Env != "", k.Vault.Path != "", k.AWS.SecretID != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return fmt.Errorf("set only one of env, vault and aws")
+	}
+	if (k.Vault != VaultSecret{}) && k.Vault.Path == "" {
+		return fmt.Errorf("vault needs a path")
+	}
+	if (k.AWS != AWSSecret{}) && k.AWS.SecretID == "" {
+		return fmt.Errorf("aws needs a secret_id")
+	}
+	return nil
+}
+
+func (v VaultSecret) field() string {
+	if v.Field != "" {
+		return v.Field
+	}
+	return "passphrase"
+}
+
+// validateKeyringPassphrases checks the global and per-network passphrase
+// sources.
+func validateKeyringPassphrases(config *Config) error {
+	if err := config.Config.Passphrase.validate(); err != nil {
+		return fmt.Errorf("keyring-passphrase: %w", err)
+	}
+	for name, network := range config.Config.Networks {
+		if err := network.Passphrase.validate(); err != nil {
+			return fmt.Errorf("network %s: keyring_passphrase: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// keyringPassphrase returns the passphrase source of network, falling back
+// to the global keyring-passphrase.
+func (c *Config) keyringPassphrase(network string) KeyringPassphrase {
+	if source := c.Config.Networks[network].Passphrase; source.enabled() {
+		return source
+	}
+	return c.Config.Passphrase
+}
+
+// fetch reads the passphrase from its source.
+func (k KeyringPassphrase) fetch() (string, error) {
+	switch {
+	case k.Env != "":
+		value, ok := os.LookupEnv(k.Env)
+		if !ok || value == "" {
+			return "", fmt.Errorf("environment variable %s is not set", k.Env)
+		}
+		return value, nil
+	case k.Vault.Path != "":
+		return k.Vault.fetch()
+	case k.AWS.SecretID != "":
+		return k.AWS.fetch()
+	}
+	return "", fmt.Errorf("no passphrase source configured")
+}
+
+func (v VaultSecret) fetch() (string, error) {
+	address := v.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return "", fmt.Errorf("vault address not set (address or VAULT_ADDR)")
+	}
+	tokenEnv := v.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = "VAULT_TOKEN"
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("vault token not set (%s)", tokenEnv)
+	}
+
+	url := strings.TrimRight(address, "/") + "/v1/" + strings.TrimLeft(v.Path, "/")
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	client := &http.Client{Timeout: secretTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned %s for %s", resp.Status, v.Path)
+	}
+
+	// KV v2 nests the fields in data.data, KV v1 in data
+	var response struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+	fields := response.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		fields = nested
+	}
+	value, ok := fields[v.field()].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("vault secret %s has no field %s", v.Path, v.field())
+	}
+	return value, nil
+}
+
+func (a AWSSecret) fetch() (string, error) {
+	args := []string{"secretsmanager", "get-secret-value", "--secret-id", a.SecretID, "--query", "SecretString", "--output", "text"}
+	if a.Region != "" {
+		args = append(args, "--region", a.Region)
+	}
+	ctx, cancel, timeout := operations.commandContext(args)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "aws", args...)
+	cmd.WaitDelay = time.Second
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	switch {
+	case err == nil:
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "", fmt.Errorf("aws secretsmanager timed out after %s", timeout)
+	case ctx.Err() != nil:
+		return "", errCancelled
+	default:
+		return "", fmt.Errorf("aws secretsmanager failed: %v, output: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	secret := strings.TrimRight(string(output), "\r\n")
+	if a.Field == "" {
+		return secret, nil
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(secret), &fields); err != nil {
+		return "", fmt.Errorf("secret %s is not JSON, so it has no field %s", a.SecretID, a.Field)
+	}
+	value, ok := fields[a.Field].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("secret %s has no field %s", a.SecretID, a.Field)
+	}
+	return value, nil
+}
+
+// keyringRegistry maps the pocketd homes of file and os keyrings to their
+// passphrase sources, and keeps the passphrases fetched until the config
+// changes. Keyrings without a source are asked for in the TUI.
+type keyringRegistry struct {
+	mu       sync.Mutex
+	sources  map[string]KeyringPassphrase  // By pocketd home
+	cache    map[string]string             // Passphrases by source
+	fetching map[string]*pendingPassphrase // Fetches in flight by source
+	prompts  chan passphrasePromptMsg      // Prompts for the TUI, nil outside of it
+	pending  map[string]*pendingPassphrase // Open prompts by pocketd home
+	prompted map[string]string             // Passphrases typed in the TUI by pocketd home
+}
+
+// passphrasePromptMsg asks for the passphrase of the keyring at home, which
+// no keyring-passphrase source covers.
+type passphrasePromptMsg struct {
+	backend string
+	home    string
+}
+
+// pendingPassphrase is the answer to a prompt, shared by every pocketd call
+// waiting on the same keyring.
+type pendingPassphrase struct {
+	done       chan struct{}
+	passphrase string
+	err        error
+}
+
+var keyrings = &keyringRegistry{}
+
+// needsPassphrase reports whether pocketd asks for a passphrase with backend.
+func needsPassphrase(backend string) bool {
+	return backend == "file" || backend == "os"
+}
+
+// isPassphraseRejected reports whether pocketd output shows that the keyring
+// refused the passphrase it was given.
+func isPassphraseRejected(output string) bool {
+	output = strings.ToLower(output)
+	return strings.Contains(output, "too many failed passphrase attempts") ||
+		strings.Contains(output, "integrity check failed") ||
+		strings.Contains(output, "incorrect passphrase")
+}
+
+// enablePrompts lets pocketd calls ask the TUI for passphrases with
+// waitForPassphrasePromptCmd, instead of failing.
+func (r *keyringRegistry) enablePrompts() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prompts = make(chan passphrasePromptMsg)
+}
+
+// configure registers the passphrase sources of config and forgets the
+// passphrases fetched before. Passphrases typed in the TUI are kept for the
+// session.
+func (r *keyringRegistry) configure(config *Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sources = make(map[string]KeyringPassphrase)
+	r.cache = make(map[string]string)
+	r.fetching = make(map[string]*pendingPassphrase)
+	for name := range config.Config.Networks {
+		source := config.keyringPassphrase(name)
+		if !source.enabled() || !needsPassphrase(config.keyringBackend(name)) {
+			continue
+		}
+		r.sources[config.txHome(name)] = source
+		if home := config.pocketdHome(name); home != "" {
+			r.sources[home] = source
+		}
+	}
+}
+
+// keyringFlags returns the keyring backend and home of a pocketd call.
+func keyringFlags(args []string) (string, string) {
+	backend, home := "", defaultPocketdHome()
+	for _, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "--keyring-backend="); ok {
+			backend = value
+		}
+		if value, ok := strings.CutPrefix(arg, "--home="); ok {
+			home = value
+		}
+	}
+	return backend, home
+}
+
+// stdin returns the input of a pocketd call: the passphrase of its keyring
+// when it uses a file or os keyring, or nil. Keyrings without a configured
+// source are prompted for, which blocks until the operator answers. Sources
+// are fetched outside the lock, so a slow one only holds up the calls that
+// need it.
+func (r *keyringRegistry) stdin(args []string) (io.Reader, error) {
+	backend, home := keyringFlags(args)
+	if !needsPassphrase(backend) {
+		return nil, nil
+	}
+
+	r.mu.Lock()
+	source, ok := r.sources[home]
+	if !ok {
+		r.mu.Unlock()
+		passphrase, err := r.prompt(backend, home)
+		if err != nil {
+			return nil, err
+		}
+		return passphraseInput(passphrase), nil
+	}
+	key := source.source()
+	if passphrase, ok := r.cache[key]; ok {
+		r.mu.Unlock()
+		return passphraseInput(passphrase), nil
+	}
+	// Calls needing the same source wait for the fetch already in flight
+	if fetch, ok := r.fetching[key]; ok {
+		r.mu.Unlock()
+		<-fetch.done
+		if fetch.err != nil {
+			return nil, fetch.err
+		}
+		return passphraseInput(fetch.passphrase), nil
+	}
+	fetch := &pendingPassphrase{done: make(chan struct{})}
+	r.fetching[key] = fetch
+	r.mu.Unlock()
+
+	fetch.passphrase, fetch.err = source.fetch()
+	if fetch.err != nil {
+		fetch.err = fmt.Errorf("keyring passphrase from %s: %w", key, fetch.err)
+	}
+	r.mu.Lock()
+	// A config reload while fetching started a fresh cache
+	if r.fetching[key] == fetch {
+		delete(r.fetching, key)
+		if fetch.err == nil {
+			r.cache[key] = fetch.passphrase
+		}
+	}
+	r.mu.Unlock()
+	close(fetch.done)
+	if fetch.err != nil {
+		return nil, fetch.err
+	}
+	logger.Info("keyring passphrase loaded", "source", key, "home", home)
+	return passphraseInput(fetch.passphrase), nil
+}
+
+// passphraseInput is the passphrase as pocketd reads it, repeated for its
+// confirmation prompt when it creates the keyring.
+func passphraseInput(passphrase string) io.Reader {
+	return strings.NewReader(passphrase + "\n" + passphrase + "\n")
+}
+
+// prompt returns the passphrase typed in the TUI for the keyring at home,
+// asking for it on first use. Concurrent calls on the same keyring share one
+// prompt.
+func (r *keyringRegistry) prompt(backend, home string) (string, error) {
+	r.mu.Lock()
+	if passphrase, ok := r.prompted[home]; ok {
+		r.mu.Unlock()
+		return passphrase, nil
+	}
+	if r.prompts == nil {
+		r.mu.Unlock()
+		return "", fmt.Errorf("the %s keyring at %s needs a passphrase: set keyring-passphrase in %s", backend, home, configFile)
+	}
+	if r.pending == nil {
+		r.pending = make(map[string]*pendingPassphrase)
+	}
+	pending, ok := r.pending[home]
+	if !ok {
+		pending = &pendingPassphrase{done: make(chan struct{})}
+		r.pending[home] = pending
+		prompts := r.prompts
+		go func() { prompts <- passphrasePromptMsg{backend: backend, home: home} }()
+		logger.Info("keyring passphrase requested", "backend", backend, "home", home)
+	}
+	r.mu.Unlock()
+	<-pending.done
+	return pending.passphrase, pending.err
+}
+
+// answer settles the prompt of the keyring at home with the passphrase typed,
+// or with err when the operator cancelled it.
+func (r *keyringRegistry) answer(home, passphrase string, err error) {
+	r.mu.Lock()
+	pending := r.pending[home]
+	delete(r.pending, home)
+	if err == nil {
+		if r.prompted == nil {
+			r.prompted = make(map[string]string)
+		}
+		r.prompted[home] = passphrase
+	}
+	r.mu.Unlock()
+	if pending != nil {
+		pending.passphrase, pending.err = passphrase, err
+		close(pending.done)
+	}
+}
+
+// forget drops the passphrase of the keyring of a pocketd call after it was
+// rejected, so that the next call fetches or asks for it again.
+func (r *keyringRegistry) forget(args []string) {
+	_, home := keyringFlags(args)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.prompted, home)
+	if source, ok := r.sources[home]; ok {
+		delete(r.cache, source.source())
+	}
+	logger.Warn("keyring passphrase rejected", "home", home)
+}
+
+// waitForPassphrasePromptCmd waits for the next pocketd call asking for a
+// passphrase.
+func waitForPassphrasePromptCmd() tea.Cmd {
+	return func() tea.Msg {
+		return <-keyrings.prompts
+	}
+}
+
+// showPassphrasePrompt opens the passphrase prompt, or queues the request
+// behind the one shown.
+func (m model) showPassphrasePrompt(msg passphrasePromptMsg) (model, tea.Cmd) {
+	m.passphrasePrompts = append(m.passphrasePrompts, msg)
+	if m.state != statePassphrase {
+		m.passphraseReturn = m.state
+		m.passphraseInput = ""
+		m.state = statePassphrase
+	}
+	return m, waitForPassphrasePromptCmd()
+}
+
+func (m model) updatePassphrase(msg tea.KeyMsg) (model, tea.Cmd) {
+	prompt := m.passphrasePrompts[0]
+	switch msg.String() {
+	case "enter":
+		if m.passphraseInput == "" {
+			return m, nil
+		}
+		keyrings.answer(prompt.home, m.passphraseInput, nil)
+		return m.nextPassphrasePrompt(), nil
+	case "esc", "ctrl+c":
+		keyrings.answer(prompt.home, "", errCancelled)
+		logger.Info("keyring passphrase prompt cancelled", "home", prompt.home)
+		m = m.nextPassphrasePrompt()
+		return m, m.notify(toastInfo, "Passphrase prompt cancelled; the pocketd command was not run")
+	case "backspace":
+		if runes := []rune(m.passphraseInput); len(runes) > 0 {
+			m.passphraseInput = string(runes[:len(runes)-1])
+		}
+	default:
+		if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+			m.passphraseInput += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// nextPassphrasePrompt shows the next queued prompt, or returns to the view
+// the first one interrupted.
+func (m model) nextPassphrasePrompt() model {
+	m.passphrasePrompts = m.passphrasePrompts[1:]
+	m.passphraseInput = ""
+	if len(m.passphrasePrompts) == 0 {
+		m.state = m.passphraseReturn
+	}
+	return m
+}
+
+// renderPassphrase asks for a keyring passphrase without echoing it.
+func (m model) renderPassphrase() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+
+	if len(m.passphrasePrompts) == 0 {
+		return ""
+	}
+	prompt := m.passphrasePrompts[0]
+	content := []string{headerStyle.Render("🔑 KEYRING PASSPHRASE"), ""}
+	content = append(content, textStyle.Render(fmt.Sprintf("pocketd needs the passphrase of the %s keyring at %s.", prompt.backend, prompt.home)))
+	if len(m.passphrasePrompts) > 1 {
+		content = append(content, textStyle.Render(fmt.Sprintf("%d more keyrings are waiting.", len(m.passphrasePrompts)-1)))
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render(fmt.Sprintf("Passphrase: %s█", strings.Repeat("•", len([]rune(m.passphraseInput))))))
+	content = append(content, "")
+	content = append(content, textStyle.Render("Kept in memory for this session. Set keyring-passphrase in "+configFile+" to skip this prompt."))
+	content = append(content, textStyle.Render("Enter to continue • ESC to cancel the command"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// layoutColumn describes a single column in a width-aware table layout.
+type layoutColumn struct {
+	title    string
+	width    int  // Preferred width in terminal cells
+	minWidth int  // Narrowest the column may shrink to before being hidden
+	flex     bool // Flexible columns absorb any spare width
+	priority int  // Lower priority columns shrink first and are hidden first
+	// Optional truncation strategy (defaults to truncateToWidth)
+	truncate func(s string, width int) string
+}
+
+// displayWidth returns the number of terminal cells needed to render s.
+// Emoji and CJK characters occupy two cells.
+func displayWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// truncateToWidth shortens s to fit within width cells, ending with an ellipsis
+// when anything had to be cut.
+func truncateToWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if displayWidth(s) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+// padToWidth truncates s to width cells and right-pads it with spaces so
+// every cell in a column lines up regardless of the characters it contains.
+func padToWidth(s string, width int) string {
+	s = truncateToWidth(s, width)
+	if pad := width - displayWidth(s); pad > 0 {
+		s += strings.Repeat(" ", pad)
+	}
+	return s
+}
+
+// layoutWidths assigns a width to every column so the row fits in total cells.
+// Columns are shrunk towards their minimum width starting with the lowest
+// priority; if that is still not enough the lowest priority columns are hidden
+// (width 0). Spare width is shared among flexible columns.
+func layoutWidths(cols []layoutColumn, total, gap int) []int {
+	hidden := make([]bool, len(cols))
+
+	for {
+		widths := make([]int, len(cols))
+		visible := []int{}
+		for i, col := range cols {
+			if !hidden[i] {
+				widths[i] = col.width
+				visible = append(visible, i)
+			}
+		}
+		if len(visible) == 0 {
+			return widths
+		}
+
+		used := gap * (len(visible) - 1)
+		for _, i := range visible {
+			used += widths[i]
+		}
+
+		// Spare room goes to the flexible columns
+		if used <= total {
+			spare := total - used
+			var flexCols []int
+			for _, i := range visible {
+				if cols[i].flex {
+					flexCols = append(flexCols, i)
+				}
+			}
+			for n, i := range flexCols {
+				share := spare / len(flexCols)
+				if n < spare%len(flexCols) {
+					share++
+				}
+				widths[i] += share
+			}
+			return widths
+		}
+
+		// Shrink columns towards their minimum, lowest priority first
+		overflow := used - total
+		for _, i := range byPriority(cols, visible) {
+			if overflow == 0 {
+				break
+			}
+			room := widths[i] - cols[i].minWidth
+			if room <= 0 {
+				continue
+			}
+			cut := min(room, overflow)
+			widths[i] -= cut
+			overflow -= cut
+		}
+		if overflow == 0 {
+			return widths
+		}
+
+		// Still too wide: hide the lowest priority column and try again
+		if len(visible) == 1 {
+			widths[visible[0]] = total
+			return widths
+		}
+		hidden[byPriority(cols, visible)[0]] = true
+	}
+}
+
+// byPriority returns the given column indexes ordered from lowest to highest priority.
+func byPriority(cols []layoutColumn, indexes []int) []int {
+	ordered := append([]int(nil), indexes...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return cols[ordered[i]].priority < cols[ordered[j]].priority
+	})
+	return ordered
+}
+
+// layoutRow fits each cell to its column width and joins the visible cells.
+func layoutRow(cols []layoutColumn, widths []int, cells []string, gap int) string {
+	var parts []string
+	for i, cell := range layoutCells(cols, widths, cells) {
+		if widths[i] > 0 {
+			parts = append(parts, cell)
+		}
+	}
+	return strings.Join(parts, strings.Repeat(" ", gap))
+}
+
+// layoutCells fits each cell to its column width, leaving hidden columns
+// empty, for rows whose cells are styled separately.
+func layoutCells(cols []layoutColumn, widths []int, cells []string) []string {
+	fitted := make([]string, len(cols))
+	for i, col := range cols {
+		if widths[i] <= 0 {
+			continue
+		}
+		cell := ""
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		if col.truncate != nil && displayWidth(cell) > widths[i] {
+			cell = col.truncate(cell, widths[i])
+		}
+		fitted[i] = padToWidth(cell, widths[i])
+	}
+	return fitted
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// balanceQueryWorkers bounds how many balance queries run at once.
+const balanceQueryWorkers = 8
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// balanceLoadedMsg delivers a single application balance as it resolves.
+type balanceLoadedMsg struct {
+	ch      <-chan balanceLoadedMsg // Stream the result belongs to
+	address string
+	upokt   int64
+	err     error
+}
+
+// balancesDoneMsg is sent once every balance in a stream has been delivered.
+type balancesDoneMsg struct {
+	ch <-chan balanceLoadedMsg
+}
+
+type spinnerTickMsg struct{}
+
+// streamBalances queries the balance of every address with a bounded worker
+// pool and returns a channel that yields each result as soon as it resolves.
+func streamBalances(addresses []string, networkName, rpcEndpoint, keyringBackend, pocketdHome string) <-chan balanceLoadedMsg {
+	ch := make(chan balanceLoadedMsg, len(addresses))
+	jobs := make(chan string)
+
+	var wg sync.WaitGroup
+	for i := 0; i < balanceQueryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for address := range jobs {
+				var upokt int64
+				err := withFailover(networkName, rpcEndpoint, func(endpoint string) error {
+					var err error
+					upokt, err = QueryBankBalanceUpokt(address, endpoint, keyringBackend, pocketdHome)
+					return err
+				})
+				ch <- balanceLoadedMsg{ch: ch, address: address, upokt: upokt, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, address := range addresses {
+			jobs <- address
+		}
+		close(jobs)
+		wg.Wait()
+		close(ch)
+	}()
+
+	return ch
+}
+
+// waitForBalanceCmd waits for the next balance from the stream.
+func waitForBalanceCmd(ch <-chan balanceLoadedMsg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-ch
+		if !ok {
+			return balancesDoneMsg{ch: ch}
+		}
+		return msg
+	}
+}
+
+func spinnerTickCmd() tea.Cmd {
+	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+		return spinnerTickMsg{}
+	})
+}
+
+// busy reports whether a background load or transaction is in progress and
+// the spinner should run.
+func (m model) busy() bool {
+	return m.loading || len(m.pendingBalances) > 0 || len(m.cooling) > 0 || m.pendingTxCount() > 0 || m.reconcileCh != nil || m.rewardsLoading || m.detailsLoading || m.balancesLoading || m.sessionsLoading || m.activityLoading || m.paramsLoading ||
+		(m.passthrough != nil && m.passthrough.running) || (m.queryConsole != nil && m.queryConsole.running) ||
+		(m.txLookup != nil && m.txLookup.loading)
+}
+
+func (m model) spinner() string {
+	return spinnerFrames[m.spinnerFrame%len(spinnerFrames)]
+}
+
+// startSpinner starts the spinner ticking unless it is already running.
+func (m *model) startSpinner() tea.Cmd {
+	if m.spinnerRunning {
+		return nil
+	}
+	m.spinnerRunning = true
+	return spinnerTickCmd()
+}
+
+// applyBalance stores a streamed balance on the matching application.
+func (m *model) applyBalance(msg balanceLoadedMsg) {
+	delete(m.pendingBalances, msg.address)
+	for i := range m.applications {
+		if m.applications[i].Address != msg.address {
+			continue
+		}
+		if msg.err != nil {
+			// Keep the last known balance, marked unknown until retried
+			logger.Warn("failed to load balance", "address", msg.address, "error", msg.err)
+			m.applications[i].BalanceUnknown = true
+			return
+		}
+		m.applications[i].BalanceUpokt = msg.upokt
+		m.applications[i].BalancePOKT = float64(msg.upokt) / upoktPerPOKT
+		m.applications[i].BalanceUnknown = false
+		return
+	}
+}
+
+// unknownBalances returns the addresses whose last balance query failed.
+func (m model) unknownBalances() []string {
+	var addresses []string
+	for _, app := range m.applications {
+		if app.BalanceUnknown {
+			addresses = append(addresses, app.Address)
+		}
+	}
+	return addresses
+}
+
+// retryBalances queries the unknown balances again, streaming them into the
+// table like a refresh.
+func (m model) retryBalances() (model, tea.Cmd) {
+	if m.loading || m.balanceStream != nil {
+		m.err = fmt.Errorf("balances are still loading")
+		return m, nil
+	}
+	addresses := m.unknownBalances()
+	if len(addresses) == 0 {
+		return m, m.notify(toastInfo, "No unknown balances to retry")
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	m.pendingBalances = make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		m.pendingBalances[address] = true
+	}
+	m.balanceStream = streamBalances(addresses, m.currentNetwork, network.RPCEndpoint, m.config.keyringBackend(m.currentNetwork), m.config.pocketdHome(m.currentNetwork))
+	return m, tea.Batch(waitForBalanceCmd(m.balanceStream), m.startSpinner())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// logger records queries, transactions, errors and state changes as JSON
+// lines. It discards everything unless --log-file is given, since the TUI
+// owns the terminal.
+var logger = slog.New(slog.DiscardHandler)
+
+// parseLogLevel converts a --log-level value into a slog level.
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", level)
+	}
+}
+
+// setupLogging points the logger at path, appending to any existing log. The
+// returned function closes the file.
+func setupLogging(level, path string) (func() error, error) {
+	lvl, err := parseLogLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return func() error { return nil }, nil
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log file: %w", err)
+	}
+	logger = slog.New(slog.NewJSONHandler(file, &slog.HandlerOptions{Level: lvl}))
+	return file.Close, nil
+}
@@ -2,12 +2,11 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -29,6 +28,39 @@ const (
 	stateHelp
 	stateApplicationDetails
 	stateUpstakeAllReceipts
+	stateAudit
+	stateSpend
+	stateDiff
+	stateDiscrepancies
+	stateSnapshots
+	stateCompare
+	stateRewards
+	stateParams
+	stateGov
+	stateServiceChange
+	stateTransfer
+	stateConfigEditor
+	stateFeeGrants
+	stateQueue
+	stateCoApproval
+	stateQuitConfirm
+	stateErrors
+	stateAmountForm
+	stateRunbook
+	stateCalc
+	stateGatewayHealth
+	stateJobs
+	stateJob
+	statePassphrase
+	statePassthrough
+	stateQueryConsole
+	stateTxLookup
+	stateUpstakeAllConfirm
+	stateFundAllReceipt
+	stateGasEstimate
+	stateStakeEdit
+	statePlanReview
+	stateFilterPicker
 )
 
 type model struct {
@@ -40,7 +72,8 @@ type model struct {
 	searchInput    string
 	searchResults  []int
 	searchIndex    int
-	err            error
+	err            error // Failure of the last command, moved to the error history
+	fatal          error // Config failure that leaves nothing to show
 	loading        bool
 	width          int
 	height         int
@@ -54,26 +87,198 @@ type model struct {
 	sortDesc       bool   // Sort direction (true = descending, false = ascending)
 	gatewayList    []string
 	gatewayCursor  int
-	txHash         string    // Current upstake transaction hash to display
-	txTimestamp    time.Time // When the upstake transaction was submitted
-	fundTxHash     string    // Current fund transaction hash to display
-	fundTimestamp  time.Time // When the fund transaction was submitted
-	txError        string    // Current transaction error to display
-	txErrorHash    string    // Hash of the failed transaction
+	txs            []trackedTx // Submitted transactions shown in the tracker panel
+	nextTxID       int
+	toasts         []toast     // Active notifications, oldest first
+	nextToastID    int
+	showDebug      bool        // Debug pane with executed pocketd commands (ctrl+l)
+	auditRecords   []auditRecord
+	auditCursor    int
+	spendReports   []spendReport
+	spendScroll    int
+	stagedPlan     *stakePlan // Reconcile transactions awaiting confirmation
+	reconcilePlan  *stakePlan // Reconcile plan being executed
+	reconcileCh    <-chan planReceipt
+	reconcileAudit string // Audit log command of the running plan
+	reconcileDone  int
+	reconcileLeft  []planItem // Items cancelled before being broadcast
+	reconcileTxs   []int      // Tracked transactions of the running plan
+	reconcileFails int
+	snapshots      []stakeSnapshot
+	snapshotCursor int
+	compareWith    *stakeSnapshot // Snapshot shown in the compare view
+	compareScroll  int
 	bankBalance    float64   // Current bank balance in POKT
 	// Application details view
 	selectedAppAddress string // Address of currently viewed application
 	applicationDetails string // Raw output from show-application command
 	bankBalances       string // Raw output from bank balances command
-	detailsLoading     bool   // Loading state for details view
+	detailsLoading     bool   // Loading state of show-application
+	detailsErr         error
+	balancesLoading    bool // Loading state of bank balances, queried concurrently
+	balancesErr        error
+	// Current sessions of the viewed application, one per staked service
+	sessions           []appSession
+	sessionsLoading    bool
+	sessionsErr        error
+	// Recent transactions of the viewed application, newest first
+	activity        []activityEntry
+	activityLoading bool
+	activityErr     error
 	// Upstake all receipts view
 	upstakeAllReceipts []UpstakeReceipt // List of transaction receipts from upstake all
 	processingUpstakeAll bool // Flag to indicate we're processing upstake all
+	cancelledAt          time.Time // Last esc/ctrl+c cancellation of in-flight operations
+	upstakeAllRunning    bool      // Upstake all is broadcasting
+	quitWhenIdle         bool      // Quit once the running batch finishes
+	pendingBatches       []pendingBatch // Batches exported once finished
+
+	// Table layout
+	visibleColumns   []string // Visible column ids in display order (nil = defaults)
+	displayUnit      string   // Display denomination (pokt or upokt)
+	displayPrecision int      // Decimal places shown for POKT amounts
+
+	// Fiat price feed
+	fiatPrice   float64   // Price of one POKT in the configured fiat currency
+	fiatPriceAt time.Time // When fiatPrice was fetched (zero = never)
+	fiatErr     error     // Last price fetch error
+
+	// Relay metrics of the PATH gateway
+	relayNetwork   string             // Network the relay metrics belong to
+	relayCounts    map[string]float64 // Relays by service or application
+	relayErrors    map[string]float64 // Failed relays by service or application
+	relayMetricsAt time.Time          // When the relay metrics were fetched (zero = never)
+
+	// Background loading
+	pendingBalances map[string]bool         // Addresses whose balance is still loading (true = no previous value to show)
+	balanceStream   <-chan balanceLoadedMsg // Stream of the most recent refresh
+	spinnerFrame    int
+	spinnerRunning  bool
+	staleSince      time.Time // When the displayed cached data was saved (zero = live data)
+	stakedApps      map[string]Application // Every staked application on the network (nil until queried)
+
+	// Stake and balance history
+	history        map[string][]historySample // Samples of historyNetwork by address (nil until loaded)
+	historyNetwork string
+
+	// Relay claims view
+	rewardsClaims  []relayClaim
+	rewardsParams  sharedParams
+	rewardsAt      time.Time
+	rewardsLoading bool
+	rewardsErr     error
+	rewardsCursor  int
+
+	// On-chain module params of paramsNetwork
+	params        *moduleParams
+	paramsNetwork string
+	paramsLoading bool
+	paramsErr     error
+
+	// Active governance proposals of govNetwork
+	govProposals []govProposal
+	govNetwork   string
+	govLoading   bool
+	govErr       error
+	govAt        time.Time
+	govCursor    int
+	govGen       int             // Identifies the current poll loop
+	govSeen      map[string]bool // Staking proposals already notified, by network/id
+
+	// Service change and stake transfer awaiting confirmation
+	svcChange *serviceChange
+	transfer  *stakeTransfer
+
+	// Applications below the auto-fund floor when last notified
+	autoFundKey string
+
+	// Config editor
+	configCursor  int
+	configEditing bool
+	configInput   string
+
+	// Fee grants of the bank of feeGrantsNetwork, by grantee
+	feeGrants        map[string]feeGrant
+	feeGrantsNetwork string
+	feeGrantsLoading bool
+	feeGrantsErr     error
+
+	// Submissions waiting for approval when approval-queue is enabled
+	txQueue     []queuedSubmission
+	nextQueueID int
+	queueCursor int
+
+	// Submission waiting for the second approver's code
+	coApproval      *queuedSubmission
+	coApprovalInput     string
+	coApprovalErr       error
+	coApprovalChallenge string // Signed by the second approver, new for each submission
+
+	// Websocket-driven refresh
+	watcher             *blockWatcher // Subscription on the current network (nil if disabled)
+	watchConnected      bool
+	watchRefreshPending bool // A refresh is scheduled after a relevant transaction
+
+	// Errors of this session, oldest first
+	errorLog      []errorEntry
+	errorSource   string // Command or operation of the message being handled
+	errorCursor   int    // Selected entry, counted from the newest
+	errorExpanded bool   // The selected entry shows its details
+
+	amountForm *amountForm // Amount being entered for u/f on a row
+
+	cooling []coolingSubmission // Submissions in their pre-submit cooldown, oldest first
+
+	runbook       *runbook // Last runbook started with :run (nil if none)
+	runbookCursor int
+
+	stakeCalc *stakeCalc // What-if calculator of :calc
+
+	// On-chain records and health checks of the configured gateways
+	gatewayHealth        []gatewayHealth // In config order
+	gatewayHealthNetwork string
+	gatewayHealthAt      time.Time
+	gatewayHealthLoading bool
+
+	jobs          []*job // Jobs of the session, oldest first
+	nextJobID     int
+	jobCursor     int
+	viewedJob     *job // Job shown in the job view
+	jobStepCursor int
+
+	restoreSelected string // Address to put the cursor on once loaded, from the saved UI state
+	ticking         bool   // The chain status and schedule tick loops are running
+	sessionUser     string // User of an SSH session of "gasms serve", "" for the local account
+
+	passphrasePrompts []passphrasePromptMsg // Keyrings waiting for a passphrase, the first one shown
+	passphraseInput   string
+	passphraseReturn  state // View to return to once answered
+
+	passthrough       *passthroughRun // Last command run with :! (nil if none)
+	passthroughScroll int
+
+	queryConsole *queryConsole      // Query line, history and last response of :query
+	txLookup     *txLookup          // Last transaction looked up with :tx
+	upstakeAll   *upstakeAllConfirm // Scoped upstake-all awaiting confirmation
+
+	fundAllReceipt *fundAllReceipt     // Last fund-all submitted (nil if none)
+	gasEstimate    *gasEstimate        // Simulated fees of a fund-all or upstake-all awaiting confirmation
+	help           *helpView           // Help being shown (nil outside the help)
+	stakeEdit      *stakeEdit          // Target stake being typed in the table (nil if none)
+	filterCursor   int                 // Selected saved filter in the :filter picker
+	watchlist      map[string][]string // Pinned application addresses by network
+
+	refreshedFor string           // Network and gateway of the shown applications
+	refreshedAt  time.Time        // When the shown applications were loaded
+	lastRefresh  *refreshBaseline // Applications before the last refresh (nil if not comparable)
+	showAllApps  bool             // The table shows every application staked on the network
 }
 
 type applicationsLoadedMsg struct {
 	apps        []Application
+	staked      map[string]Application // Every application staked on the network
 	bankBalance float64
+	balances    <-chan balanceLoadedMsg // Per-application balances as they resolve
 	err         error
 }
 
@@ -82,77 +287,114 @@ type configLoadedMsg struct {
 	err    error
 }
 
+// bootCompleteMsg ends the splash screen once the config is loaded.
+type bootCompleteMsg struct{}
+
+// showUpstakeAllReceiptsMsg switches from the upstake-all progress message to
+// its receipts.
+type showUpstakeAllReceiptsMsg struct{}
+
 type upstakeCompletedMsg struct {
+	txID   int
 	txHash string
 }
 
 type applicationDetailsLoadedMsg struct {
+	address    string
+	appDetails string
+	err        error
+}
+
+// detailsBalancesLoadedMsg carries the bank balances of the details view,
+// queried alongside the application.
+type detailsBalancesLoadedMsg struct {
 	address     string
-	appDetails  string
 	bankBalance string
 	err         error
 }
 
 type fundCompletedMsg struct {
+	txID   int
 	txHash string
 }
 
-type transactionErrorMsg struct {
-	txHash string
-	error  string
-}
-
 type UpstakeReceipt struct {
-	appAddress string
-	txHash     string
-	error      string
+	appAddress    string
+	txHash        string
+	error         string
+	amount        int64 // Added to the stake, in upokt
+	previousStake int64 // Stake before the upstake, in upokt
+	newStake      int64 // Stake once the upstake is included, in upokt
 }
 
 type upstakeAllCompletedMsg struct {
 	receipts []UpstakeReceipt
-}
-
-func loadSplashArt() string {
-	content, err := ioutil.ReadFile("art/splash.txt")
-	if err != nil {
-		return "GASMS\nLoading..."
-	}
-	return string(content)
-}
-
-func loadLogoLine() string {
-	content, err := ioutil.ReadFile("art/logo.txt")
-	if err != nil {
-		return "GASMS"
-	}
-	lines := strings.Split(string(content), "\n")
-	if len(lines) > 0 && strings.TrimSpace(lines[0]) != "" {
-		return strings.TrimSpace(lines[0])
-	}
-	return "GASMS"
+	amount   int64
+	network  string
+	unsent   []planItem // Cancelled before being broadcast
+	job      *job       // Job following the batch
 }
 
 func loadApplicationsCmd(rpcEndpoint, gateway, bankAddress, keyringBackend, pocketdHome, networkName string) tea.Cmd {
 	return func() tea.Msg {
-		apps, err := QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networkName)
+		var all []Application
+		err := withFailover(networkName, rpcEndpoint, func(endpoint string) error {
+			var err error
+			all, err = ListAllApplications(endpoint, pocketdHome, networkName)
+			return err
+		})
 		if err != nil {
-			return applicationsLoadedMsg{apps: apps, bankBalance: 0, err: err}
+			return applicationsLoadedMsg{bankBalance: 0, err: err}
+		}
+		apps := all
+		if gateway != "" {
+			apps = delegatedTo(all, gateway)
+		}
+		staked := make(map[string]Application, len(all))
+		for _, app := range all {
+			staked[app.Address] = app
 		}
 
 		// Query bank balance
-		bankBalance, bankErr := QueryBankBalance(bankAddress, rpcEndpoint, keyringBackend, pocketdHome)
+		var bankBalance float64
+		bankErr := withFailover(networkName, rpcEndpoint, func(endpoint string) error {
+			var err error
+			bankBalance, err = QueryBankBalance(bankAddress, endpoint, keyringBackend, pocketdHome)
+			return err
+		})
 		if bankErr != nil {
 			// If bank balance query fails, continue with apps but set balance to 0
 			bankBalance = 0
 		}
 
-		return applicationsLoadedMsg{apps: apps, bankBalance: bankBalance, err: err}
+		// Application balances stream in after the table is shown
+		addresses := make([]string, len(apps))
+		for i, app := range apps {
+			addresses[i] = app.Address
+		}
+		balances := streamBalances(addresses, networkName, rpcEndpoint, keyringBackend, pocketdHome)
+
+		return applicationsLoadedMsg{apps: apps, staked: staked, bankBalance: bankBalance, balances: balances, err: err}
 	}
 }
 
+// reloadApplications marks the table as loading and starts a fresh query of
+// the applications delegated to gateway on the given network, or of every
+// application staked on it when the table shows them all.
+func (m *model) reloadApplications(network Network, networkName, gateway string) tea.Cmd {
+	m.loading = true
+	if m.showAllApps {
+		gateway = ""
+	}
+	return tea.Batch(
+		loadApplicationsCmd(network.RPCEndpoint, gateway, network.Bank, m.config.keyringBackend(networkName), m.config.pocketdHome(networkName), networkName),
+		m.startSpinner(),
+	)
+}
+
 func loadConfigCmd() tea.Cmd {
 	return func() tea.Msg {
-		config, err := LoadConfig("config.yaml")
+		config, err := LoadConfig(configFile)
 		return configLoadedMsg{config: config, err: err}
 	}
 }
@@ -160,10 +402,13 @@ func loadConfigCmd() tea.Cmd {
 func initialModel() model {
 	return model{
 		state:     stateLoading,
-		splashArt: loadSplashArt(),
-		logoLine:  loadLogoLine(),
+		splashArt: splashArt(""),
+		logoLine:  logoLine(""),
 		loading:   true,
 		sortBy:    "service", // Default sort by service
+
+		displayUnit:      unitPOKT,
+		displayPrecision: defaultPrecision,
 	}
 }
 
@@ -171,12 +416,26 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		loadConfigCmd(),
 		tea.Tick(time.Second*2, func(t time.Time) tea.Msg {
-			return "boot_complete"
+			return bootCompleteMsg{}
 		}),
 	)
 }
 
+// Update records the error of a failed command or operation in the error
+// history, so that it does not replace the table.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m.errorSource = errorSource(m, msg)
+	next, cmd := m.update(msg)
+	updated := next.(model)
+	if updated.err != nil {
+		err := updated.err
+		updated.err = nil
+		cmd = tea.Batch(cmd, updated.notify(toastError, err.Error()+errorsHint))
+	}
+	return updated, cmd
+}
+
+func (m model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -184,10 +443,33 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case configLoadedMsg:
 		if msg.err != nil {
-			m.err = msg.err
+			logger.Error("failed to load config", "error", msg.err)
+			m.fatal = msg.err
 			return m, nil
 		}
 		m.config = msg.config
+		logger.Info("config loaded", "networks", len(m.config.Config.Networks))
+		m.applyArt()
+		if len(m.config.Config.Columns) > 0 {
+			if err := validateColumns(m.config.Config.Columns); err != nil {
+				m.fatal = fmt.Errorf("invalid columns in config: %w", err)
+				return m, nil
+			}
+			m.visibleColumns = m.config.Config.Columns
+		}
+		unit, err := parseDisplayUnit(m.config.Config.Denomination)
+		if err != nil {
+			m.fatal = fmt.Errorf("invalid denomination in config: %w", err)
+			return m, nil
+		}
+		m.displayUnit = unit
+		if m.config.Config.Precision != nil {
+			if err := validatePrecision(*m.config.Config.Precision); err != nil {
+				m.fatal = fmt.Errorf("invalid precision in config: %w", err)
+				return m, nil
+			}
+			m.displayPrecision = *m.config.Config.Precision
+		}
 
 		// Build network list and set defaults
 		m.networkList = []string{}
@@ -197,106 +479,507 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Default to first network found
 		if len(m.networkList) == 0 {
-			m.err = fmt.Errorf("no networks found in config")
+			m.fatal = fmt.Errorf("no networks found in config")
 			return m, nil
 		}
 
 		m.currentNetwork = m.networkList[0]
+		m.restoreUIState()
+		rpcPool.configure(m.config.Config.Networks)
+		pocketdLimiter.configure(m.config.Config.RateLimit)
+		keyrings.configure(m.config)
+		pocketdBinary.configure(m.config.Config.Pocketd)
+		operations.configure(m.config.Config.CommandTimeout)
 		if firstNetwork, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(firstNetwork.Gateways) > 0 {
-			m.currentGateway = firstNetwork.Gateways[0]
-			return m, loadApplicationsCmd(firstNetwork.RPCEndpoint, firstNetwork.Gateways[0], firstNetwork.Bank, m.config.Config.KeyringBackend, m.config.Config.PocketdHome, m.currentNetwork)
+			if !slices.Contains(firstNetwork.Gateways, m.currentGateway) {
+				m.currentGateway = firstNetwork.Gateways[0]
+			}
+			m.showCachedApplications(m.currentNetwork, m.currentGateway)
+			m.restoreSearch()
+			m.restoreCursor(false)
+			cmds := []tea.Cmd{
+				probeEndpointsCmd(m.currentNetwork),
+				m.reloadApplications(firstNetwork, m.currentNetwork, m.currentGateway),
+				m.priceRefreshCmd(),
+				m.restartWatcher(),
+			}
+			// The tick loops keep running across profile switches
+			if !m.ticking {
+				m.ticking = true
+				cmds = append(cmds, chainStatusTickCmd(m.statusInterval()), scheduleTickCmd(), waitForPassphrasePromptCmd())
+			}
+			return m, tea.Batch(cmds...)
 		}
-		m.err = fmt.Errorf("first network %s has no gateways configured", m.currentNetwork)
+		m.fatal = fmt.Errorf("first network %s has no gateways configured", m.currentNetwork)
 		return m, nil
 
 	case applicationsLoadedMsg:
+		if isCancelled(msg.err) {
+			// Keep showing the last known applications
+			logger.Info("application refresh cancelled", "network", m.currentNetwork, "gateway", m.currentGateway)
+			return m, nil
+		}
 		if msg.err != nil {
+			logger.Error("failed to load applications", "network", m.currentNetwork, "gateway", m.currentGateway, "error", msg.err)
 			m.err = msg.err
 			return m, nil
 		}
+		logger.Debug("applications loaded", "network", m.currentNetwork, "gateway", m.currentGateway, "count", len(msg.apps))
+		// Keep showing previously known balances until fresh ones arrive
+		previousBalances := make(map[string]int64)
+		for _, app := range m.applications {
+			previousBalances[app.Address] = app.BalanceUpokt
+		}
+
+		m.markRefreshed(m.currentNetwork, m.tableGateway(), time.Now())
 		m.applications = msg.apps
+		m.stakedApps = msg.staked
 		m.bankBalance = msg.bankBalance
+		m.staleSince = time.Time{}
 		m.sortApplications() // Sort applications after loading
-		m.loading = false    // clear loading state
+		m.restoreSearch()
+		m.restoreCursor(true)
+		m.loading = false // clear loading state
+
+		// Show the table right away and fill in balances as they resolve
+		m.pendingBalances = make(map[string]bool)
+		for i, app := range m.applications {
+			previous, known := previousBalances[app.Address]
+			if known {
+				m.applications[i].BalanceUpokt = previous
+				m.applications[i].BalancePOKT = float64(previous) / upoktPerPOKT
+			}
+			m.pendingBalances[app.Address] = !known
+		}
+		m.balanceStream = msg.balances
+		m.updateWatchedAddresses()
+		cmds := []tea.Cmd{waitForBalanceCmd(msg.balances), m.priceRefreshCmd(), m.relayMetricsRefreshCmd(), m.gatewayHealthRefreshCmd()}
+		if m.historyNetwork != m.currentNetwork {
+			m.historyNetwork = m.currentNetwork
+			m.history = nil
+			cmds = append(cmds, loadHistoryCmd(m.currentNetwork))
+		}
+		if m.paramsNetwork != m.currentNetwork {
+			m.params = nil
+			cmds = append(cmds, m.refreshParams())
+		}
+		if m.govNetwork != m.currentNetwork {
+			cmds = append(cmds, m.watchGov())
+		}
+		return m, tea.Batch(cmds...)
+
+	case govLoadedMsg:
+		return m, m.applyGov(msg)
+
+	case govTickMsg:
+		if msg.gen != m.govGen {
+			return m, nil // Poll loop of a previous network
+		}
+		return m, tea.Batch(m.refreshGov(), govTickCmd(m.govGen))
+
+	case paramsLoadedMsg:
+		m.applyParams(msg)
+		return m, nil
+
+	case rewardsLoadedMsg:
+		m.applyRewards(msg)
+		return m, nil
+
+	case historyLoadedMsg:
+		if msg.network != m.historyNetwork {
+			return m, nil
+		}
+		if msg.err != nil {
+			logger.Error("failed to load history", "network", msg.network, "error", msg.err)
+		}
+		m.history = msg.samples
+		return m, nil
+
+	case balanceLoadedMsg:
+		if msg.ch != m.balanceStream {
+			return m, nil // Result from a superseded refresh
+		}
+		m.applyBalance(msg)
+		return m, waitForBalanceCmd(msg.ch)
+
+	case balancesDoneMsg:
+		if msg.ch != m.balanceStream {
+			return m, nil
+		}
+		m.pendingBalances = nil
+		m.balanceStream = nil
+		m.recordHistory()
+		if m.sortBy == "balance" {
+			m.sortApplications()
+		}
+		cmds := []tea.Cmd{m.evaluateAutoFund()}
+		if !m.showAllApps {
+			cmds = append(cmds, saveApplicationCacheCmd(m.currentNetwork, m.currentGateway, m.applications, m.bankBalance))
+		}
+		if unknown := m.unknownBalances(); len(unknown) > 0 {
+			cmds = append(cmds, m.notify(toastWarning, fmt.Sprintf("%d application balances could not be loaded, retry them with :retry-balances", len(unknown))))
+		}
+		return m, tea.Batch(cmds...)
+
+	case endpointsProbedMsg:
+		// Endpoint health is read from rpcPool when rendering the header
+
+	case relevantTxMsg:
+		if msg.watcher != m.watcher {
+			return m, nil // Watcher was replaced
+		}
+		cmds := []tea.Cmd{waitForWatchEventCmd(m.watcher)}
+		if !m.watchRefreshPending {
+			// Collapse bursts of related transactions into a single refresh
+			m.watchRefreshPending = true
+			cmds = append(cmds, tea.Tick(watchRefreshDelay, func(t time.Time) tea.Msg {
+				return watchRefreshMsg{}
+			}))
+		}
+		return m, tea.Batch(cmds...)
+
+	case watchStatusMsg:
+		if msg.watcher != m.watcher {
+			return m, nil
+		}
+		m.watchConnected = msg.connected
+		return m, waitForWatchEventCmd(m.watcher)
+
+	case watchRefreshMsg:
+		m.watchRefreshPending = false
+		if m.config != nil && !m.loading {
+			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists {
+				return m, m.reloadApplications(network, m.currentNetwork, m.currentGateway)
+			}
+		}
+
+	case chainStatusTickMsg:
+		m.pruneTxs()
+		gatewayCmd := m.gatewayHealthRefreshCmd()
+		return m, tea.Batch(
+			refreshChainStatusCmd(m.currentNetwork),
+			chainStatusTickCmd(m.statusInterval()),
+			gatewayCmd,
+		)
+
+	case gatewayHealthMsg:
+		return m, m.applyGatewayHealth(msg)
+
+	case scheduleTickMsg:
+		return m.runDueSchedules(msg)
 
-	case string:
-		if msg == "boot_complete" && m.config != nil {
+	case scheduledPlanMsg:
+		return m.scheduledPlan(msg)
+
+	case cooldownDoneMsg:
+		return m.releaseCooling(msg.id)
+
+	case pluginDoneMsg:
+		return m, m.pluginDone(msg)
+
+	case faucetCompletedMsg:
+		return m, m.faucetCompleted(msg)
+
+	case runbookTickMsg:
+		if msg.runbook != m.runbook {
+			return m, nil // Runbook was replaced
+		}
+		return m.stepRunbook()
+
+	case spinnerTickMsg:
+		m.spinnerFrame++
+		if m.busy() {
+			return m, spinnerTickCmd()
+		}
+		m.spinnerRunning = false
+
+	case relayMetricsMsg:
+		m.applyRelayMetrics(msg)
+		if m.sortBy == "relays" {
+			m.sortApplications()
+		}
+
+	case priceLoadedMsg:
+		// Keep showing the last known price if the fetch failed
+		m.fiatErr = msg.err
+		if msg.err == nil {
+			m.fiatPrice = msg.price
+			m.fiatPriceAt = time.Now()
+		}
+
+	case bootCompleteMsg:
+		if m.config != nil {
 			m.state = stateTable
 			m.loading = false
-		} else if msg == "clear_tx_hash" {
-			m.txHash = ""
-		} else if msg == "clear_fund_hash" {
-			m.fundTxHash = ""
-		} else if msg == "clear_tx_error" {
-			m.txError = ""
-			m.txErrorHash = ""
-		} else if msg == "switch_to_receipts" {
-			m.state = stateUpstakeAllReceipts
-			m.loading = false
-			m.processingUpstakeAll = false
-		} else if strings.HasPrefix(msg, "Upstake failed:") {
-			m.err = fmt.Errorf("%s", msg)
-		} else if strings.HasPrefix(msg, "Fund failed:") {
-			m.err = fmt.Errorf("%s", msg)
 		}
 
+	case showUpstakeAllReceiptsMsg:
+		m.state = stateUpstakeAllReceipts
+		m.loading = false
+		m.processingUpstakeAll = false
+
+	case toastExpiredMsg:
+		m.expireToast(msg.id)
+
+	case txFailedMsg:
+		m.txFailedWith(msg.txID, msg.hash, msg.reason())
+		return m, tea.Batch(m.notify(toastError, msg.text()), m.exportFinishedBatches())
+
+	case txStatusMsg:
+		cmd := m.applyTxStatus(msg)
+		return m, tea.Batch(cmd, m.exportFinishedBatches())
+
+	case receiptsExportedMsg:
+		return m, m.receiptsExported(msg)
+
 	case upstakeCompletedMsg:
-		// Set transaction hash and timestamp for display
-		m.txHash = msg.txHash
-		m.txTimestamp = time.Now()
+		pollCmd := tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, "UPSTAKE TXHASH: "+msg.txHash),
+		)
 
 		// Refresh application data after successful upstake
 		if m.config != nil {
 			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
-				m.loading = true
 				return m, tea.Batch(
-					loadApplicationsCmd(network.RPCEndpoint, m.currentGateway, network.Bank, m.config.Config.KeyringBackend, m.config.Config.PocketdHome, m.currentNetwork),
-					tea.Tick(time.Second*10, func(t time.Time) tea.Msg {
-						return "clear_tx_hash"
-					}),
+					m.reloadApplications(network, m.currentNetwork, m.currentGateway),
+					pollCmd,
 				)
 			}
 		}
+		return m, pollCmd
 
-	case fundCompletedMsg:
-		// Set fund transaction hash and timestamp for display
-		m.fundTxHash = msg.txHash
-		m.fundTimestamp = time.Now()
+	case serviceChangedMsg:
+		pollCmd := tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, "SERVICE CHANGE TXHASH: "+msg.txHash),
+		)
+		if m.config != nil {
+			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+				return m, tea.Batch(
+					m.reloadApplications(network, m.currentNetwork, m.currentGateway),
+					pollCmd,
+				)
+			}
+		}
+		return m, pollCmd
 
-		// Set timer to clear fund hash after 10 seconds
-		return m, tea.Tick(time.Second*10, func(t time.Time) tea.Msg {
-			return "clear_fund_hash"
-		})
+	case transferSubmittedMsg:
+		pollCmd := tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, "TRANSFER TXHASH: "+msg.txHash),
+		)
+		if m.config != nil {
+			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+				return m, tea.Batch(
+					m.reloadApplications(network, m.currentNetwork, m.currentGateway),
+					pollCmd,
+				)
+			}
+		}
+		return m, pollCmd
 
-	case transactionErrorMsg:
-		// Set transaction error and hash for display
-		m.txError = msg.error
-		m.txErrorHash = msg.txHash
+	case drainCompletedMsg:
+		return m, m.drainCompleted(msg)
 
-		// Set timer to clear error after 15 seconds
-		return m, tea.Tick(time.Second*15, func(t time.Time) tea.Msg {
-			return "clear_tx_error"
-		})
+	case feeGrantsLoadedMsg:
+		m.applyFeeGrants(msg)
+		return m, nil
+
+	case feeGrantsSubmittedMsg:
+		return m, m.feeGrantsSubmitted(msg)
+
+	case configSavedMsg:
+		jobCmd := m.jobConfigSaved(msg)
+		return m, tea.Batch(m.applySavedConfig(msg), jobCmd)
+
+	case jobStateMsg:
+		return m, m.jobState(msg)
+
+	case jobTickMsg:
+		return m, m.jobTick(msg)
+
+	case jobRecheckMsg:
+		return m, m.jobRecheck(msg)
+
+	case appTxSubmittedMsg:
+		pollCmd := tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, strings.ToUpper(msg.kind)+" TXHASH: "+msg.txHash),
+		)
+		if m.config != nil {
+			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
+				return m, tea.Batch(
+					m.reloadApplications(network, m.currentNetwork, m.currentGateway),
+					pollCmd,
+				)
+			}
+		}
+		return m, pollCmd
+
+	case jobSubmittedMsg:
+		return m, tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, msg.kind+" TXHASH: "+msg.txHash),
+		)
+
+	case planEditedMsg:
+		return m.planEdited(msg)
+
+	case gasEstimatedMsg:
+		m.applyGasEstimate(msg)
+		return m, nil
+
+	case fundChunkMsg:
+		next, cmd := m.Update(msg.msg)
+		return next, tea.Batch(cmd, waitForFundChunkCmd(msg.ch))
+
+	case fundUnsentMsg:
+		return m, m.notifyResume(msg.network, m.config.Config.Networks[msg.network].Bank, msg.unsent)
+
+	case fundCompletedMsg:
+		// Show the breakdown of a fund-all once its first multi-send is broadcast
+		if m.fundAllReceipt != nil && m.fundAllReceipt.chunks[0].txID == msg.txID && m.state == stateTable {
+			m.state = stateFundAllReceipt
+		}
+		return m, tea.Batch(
+			m.txBroadcasted(msg.txID, msg.txHash),
+			m.notify(toastSuccess, "FUND TXHASH: "+msg.txHash),
+		)
 
 	case upstakeAllCompletedMsg:
 		// Store receipts and switch to receipts view
+		m.upstakeAllRunning = false
 		m.upstakeAllReceipts = msg.receipts
-		m.state = stateUpstakeAllReceipts
+		if m.state != stateQuitConfirm {
+			m.state = stateUpstakeAllReceipts
+		}
+
+		// Follow every broadcast transaction until it is included
+		var pollCmds []tea.Cmd
+		var txIDs []int
+		for _, receipt := range msg.receipts {
+			id := m.trackTx("upstake-all", fmt.Sprintf("upstake-all %d", msg.amount), []string{receipt.appAddress}, msg.amount)
+			txIDs = append(txIDs, id)
+			if receipt.error != "" {
+				m.txFailedWith(id, receipt.txHash, receipt.error)
+				continue
+			}
+			pollCmds = append(pollCmds, m.txBroadcasted(id, receipt.txHash))
+		}
+		pollCmds = append(pollCmds, m.notifyResume(msg.network, m.config.Config.Networks[msg.network].Bank, msg.unsent))
+		m.watchBatch("upstake-all", fmt.Sprintf("upstake-all %d", msg.amount), txIDs)
+		pollCmds = append(pollCmds, m.exportFinishedBatches(), m.upstakeBatchSent(msg.job, msg.receipts, txIDs))
+		return m, tea.Batch(append(pollCmds, m.startSpinner())...)
 
 	case applicationDetailsLoadedMsg:
+		if msg.address != m.selectedAppAddress {
+			return m, nil
+		}
 		m.detailsLoading = false
+		m.detailsErr = msg.err
 		if msg.err != nil {
-			m.err = msg.err
-			m.state = stateTable // Return to table on error
-		} else {
-			m.selectedAppAddress = msg.address
-			m.applicationDetails = msg.appDetails
-			m.bankBalances = msg.bankBalance
+			logger.Error("failed to load application details", "address", msg.address, "error", msg.err)
+			return m, nil
+		}
+		m.applicationDetails = msg.appDetails
+		// Sessions are queried for the services of the application
+		return m.refreshSessions()
+
+	case detailsBalancesLoadedMsg:
+		if msg.address != m.selectedAppAddress {
+			return m, nil
+		}
+		m.balancesLoading = false
+		m.bankBalances = msg.bankBalance
+		m.balancesErr = msg.err
+		if msg.err != nil {
+			logger.Error("failed to load bank balances", "address", msg.address, "error", msg.err)
+		}
+
+	case activityLoadedMsg:
+		if msg.address != m.selectedAppAddress {
+			return m, nil
+		}
+		m.activityLoading = false
+		m.activity = msg.entries
+		m.activityErr = msg.err
+		if msg.err != nil {
+			logger.Error("failed to load activity", "address", msg.address, "error", msg.err)
+		}
+
+	case sessionsLoadedMsg:
+		if msg.address != m.selectedAppAddress {
+			return m, nil
+		}
+		m.sessionsLoading = false
+		m.sessions = msg.sessions
+		m.sessionsErr = msg.err
+		if msg.err != nil {
+			logger.Error("failed to load session", "address", msg.address, "error", msg.err)
+		}
+
+	case reconcileProgressMsg:
+		if msg.ch != m.reconcileCh {
+			return m, nil
+		}
+		m.recordReconcileReceipt(msg.receipt)
+		return m, waitForReceiptCmd(msg.ch)
+
+	case reconcileDoneMsg:
+		if msg.ch != m.reconcileCh {
+			return m, nil
+		}
+		m.reconcileCh = nil
+		level := toastSuccess
+		if m.reconcileFails > 0 {
+			level = toastWarning
+		}
+		cmds := []tea.Cmd{m.notify(level, fmt.Sprintf("Finished %s: %d of %d transactions included",
+			m.reconcileAudit, m.reconcileDone-m.reconcileFails, m.reconcileDone))}
+		cmds = append(cmds, m.notifyResume(m.reconcilePlan.Network, m.reconcilePlan.Bank, m.reconcileLeft))
+		m.watchBatch("reconcile", m.reconcileAudit, m.reconcileTxs)
+		cmds = append(cmds, m.exportFinishedBatches())
+		if network, exists := m.config.Config.Networks[m.currentNetwork]; exists {
+			cmds = append(cmds, m.reloadApplications(network, m.currentNetwork, m.currentGateway))
+		}
+		return m, tea.Batch(cmds...)
+
+	case quitCheckMsg:
+		return m.quitWhenDone()
+
+	case passphrasePromptMsg:
+		return m.showPassphrasePrompt(msg)
+
+	case passthroughDoneMsg:
+		return m, m.applyPassthrough(msg)
+
+	case queryResultMsg:
+		m.applyQueryResult(msg)
+
+	case txLookupMsg:
+		m.applyTxLookup(msg)
+
+	case debugTickMsg:
+		if m.showDebug {
+			return m, debugTickCmd()
 		}
 
 	case tea.KeyMsg:
+		if m.fatal != nil && (msg.String() == "q" || msg.String() == "ctrl+c") {
+			return m, tea.Quit
+		}
+		if msg.String() == "ctrl+l" && m.state != stateLoading {
+			return m.toggleDebug()
+		}
+		if _, ok := viewKeys[m.state]; ok && msg.String() == "?" {
+			return m.openHelp(true), nil
+		}
 		switch m.state {
 		case stateLoading:
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
 			return m, nil
 
 		case stateTable:
@@ -321,6 +1004,72 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateApplicationDetails(msg)
 		case stateUpstakeAllReceipts:
 			return m.updateUpstakeAllReceipts(msg)
+		case stateAudit:
+			return m.updateAudit(msg)
+		case stateSpend:
+			return m.updateSpend(msg)
+		case stateDiff:
+			return m.updateDiff(msg)
+		case stateDiscrepancies:
+			return m.updateDiscrepancies(msg)
+		case stateSnapshots:
+			return m.updateSnapshots(msg)
+		case stateCompare:
+			return m.updateCompare(msg)
+		case stateRewards:
+			return m.updateRewards(msg)
+		case stateParams:
+			return m.updateParams(msg)
+		case stateGov:
+			return m.updateGov(msg)
+		case stateServiceChange:
+			return m.updateServiceChange(msg)
+		case stateTransfer:
+			return m.updateTransfer(msg)
+		case stateConfigEditor:
+			return m.updateConfigEditor(msg)
+		case stateFeeGrants:
+			return m.updateFeeGrants(msg)
+		case stateQueue:
+			return m.updateQueue(msg)
+		case stateCoApproval:
+			return m.updateCoApproval(msg)
+		case stateQuitConfirm:
+			return m.updateQuitConfirm(msg)
+		case stateErrors:
+			return m.updateErrors(msg)
+		case stateAmountForm:
+			return m.updateAmountForm(msg)
+		case stateRunbook:
+			return m.updateRunbook(msg)
+		case stateCalc:
+			return m.updateCalc(msg)
+		case stateGatewayHealth:
+			return m.updateGatewayHealth(msg)
+		case stateJobs:
+			return m.updateJobs(msg)
+		case stateJob:
+			return m.updateJob(msg)
+		case statePassphrase:
+			return m.updatePassphrase(msg)
+		case statePassthrough:
+			return m.updatePassthrough(msg)
+		case stateQueryConsole:
+			return m.updateQueryConsole(msg)
+		case stateTxLookup:
+			return m.updateTxLookup(msg)
+		case stateUpstakeAllConfirm:
+			return m.updateUpstakeAllConfirm(msg)
+		case stateFundAllReceipt:
+			return m.updateFundAllReceipt(msg)
+		case stateGasEstimate:
+			return m.updateGasEstimate(msg)
+		case stateStakeEdit:
+			return m.updateStakeEdit(msg)
+		case statePlanReview:
+			return m.updatePlanReview(msg)
+		case stateFilterPicker:
+			return m.updateFilterPicker(msg)
 		}
 	}
 
@@ -328,9 +1077,35 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) updateTable(msg tea.KeyMsg) (model, tea.Cmd) {
+	// Transaction keys do nothing for roles that cannot use them
 	switch msg.String() {
-	case "q", "ctrl+c":
-		return m, tea.Quit
+	case "u", "f", "e":
+		if m.role() == roleViewer {
+			return m, nil
+		}
+	case "F", "U", "M":
+		if m.role() != roleAdmin {
+			return m, nil
+		}
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		if m.inFlight() && time.Since(m.cancelledAt) > quitConfirmWindow {
+			return m, m.cancelInFlight()
+		}
+		return m.requestQuit()
+
+	case "q":
+		return m.requestQuit()
+
+	case "esc":
+		if len(m.cooling) > 0 {
+			return m, m.cancelCooling()
+		}
+		if m.inFlight() {
+			return m, m.cancelInFlight()
+		}
 
 	case ":":
 		m.state = stateCommand
@@ -347,8 +1122,7 @@ func (m model) updateTable(msg tea.KeyMsg) (model, tea.Cmd) {
 	case "r":
 		if m.config != nil {
 			if network, exists := m.config.Config.Networks[m.currentNetwork]; exists && len(network.Gateways) > 0 {
-				m.loading = true
-				return m, loadApplicationsCmd(network.RPCEndpoint, m.currentGateway, network.Bank, m.config.Config.KeyringBackend, m.config.Config.PocketdHome, m.currentNetwork)
+				return m, m.reloadApplications(network, m.currentNetwork, m.currentGateway)
 			}
 		}
 
@@ -369,11 +1143,7 @@ func (m model) updateTable(msg tea.KeyMsg) (model, tea.Cmd) {
 		m.cursor = len(m.applications) - 1
 
 	case "u":
-		if len(m.applications) > 0 && m.cursor < len(m.applications) {
-			currentApp := m.applications[m.cursor]
-			m.state = stateCommand
-			m.commandInput = "u " + currentApp.Address + " "
-		}
+		return m.openAmountForm("u"), nil
 
 	case "enter":
 		if len(m.applications) > 0 && m.cursor < len(m.applications) {
@@ -382,19 +1152,25 @@ func (m model) updateTable(msg tea.KeyMsg) (model, tea.Cmd) {
 		}
 
 	case "f":
-		if len(m.applications) > 0 && m.cursor < len(m.applications) {
-			currentApp := m.applications[m.cursor]
-			m.state = stateCommand
-			m.commandInput = "f " + currentApp.Address + " "
-		}
+		return m.openAmountForm("f"), nil
+	case "e":
+		return m.openStakeEdit(), nil
 	case "F":
 		m.state = stateCommand
 		m.commandInput = "fa "
 	case "U":
 		m.state = stateCommand
 		m.commandInput = "ua "
+	case "d":
+		m.state = stateDiff
 	case "h":
-		m.state = stateHelp
+		return m.openHelp(false), nil
+	case "A":
+		return m.toggleAllApplications()
+	case "M":
+		return m.adoptSelected()
+	case "p":
+		return m.togglePinned()
 	}
 
 	return m, nil
@@ -407,9 +1183,34 @@ func (m model) updateCommand(msg tea.KeyMsg) (model, tea.Cmd) {
 		m.commandInput = "" // Clear command input
 		m.state = stateTable
 
+		// Run the command with its transactions carrying the "--memo" text
+		if rest, memo := splitMemo(" " + cmd); memo != "" {
+			if m.config == nil {
+				m.err = fmt.Errorf("config not loaded")
+				return m, nil
+			}
+			if err := validMemo(memo); err != nil {
+				m.err = err
+				return m, nil
+			}
+			saved := m.config
+			m.config = withMemo(saved, memo)
+			m.commandInput = rest
+			next, run := m.updateCommand(msg)
+			if next.config == m.config {
+				next.config = saved
+			}
+			return next, run
+		}
+
+		if err := m.refuseCommand(cmd); err != nil {
+			m.err = err
+			return m, nil
+		}
+
 		switch cmd {
 		case "q", "quit":
-			return m, tea.Quit
+			return m.requestQuit()
 		case "n", "network":
 			m.state = stateNetworkSelect
 			m.networkCursor = 0
@@ -435,6 +1236,12 @@ func (m model) updateCommand(msg tea.KeyMsg) (model, tea.Cmd) {
 			m.setSortBy("balance")
 		case "sv", "sort service":
 			m.setSortBy("service")
+		case "sr", "sort burn":
+			m.setSortBy("burn")
+		case "sd", "sort danger":
+			m.setSortBy("danger")
+		case "sl", "sort relays":
+			m.setSortBy("relays")
 		// Sort direction commands
 		case "asc":
 			m.sortDesc = false
@@ -443,12 +1250,157 @@ func (m model) updateCommand(msg tea.KeyMsg) (model, tea.Cmd) {
 			m.sortDesc = true
 			m.sortApplications()
 		case "h", "help":
-			m.state = stateHelp
+			m = m.openHelp(false)
+		case "spend":
+			return m.handleSpendCommand()
+		case "edit-plan":
+			return m.handleEditPlanCommand()
+		case "filter":
+			return m.handleFilterCommand(cmd)
+		case "diff":
+			m.state = stateDiff
+		case "disc", "discrepancies":
+			m.state = stateDiscrepancies
+		case "snapshots":
+			return m.handleSnapshotCommand(cmd)
+		case "rewards", "claims":
+			return m.handleRewardsCommand()
+		case "params":
+			m.state = stateParams
+		case "gateways":
+			m.state = stateGatewayHealth
+			return m, m.gatewayHealthRefreshCmd()
+		case "jobs":
+			return m.handleJobsCommand()
+		case "profile":
+			return m.handleProfileCommand(cmd)
+		case "drain-all":
+			return m.handleDrainCommand(cmd)
+		case "autofund":
+			return m.handleAutoFundCommand()
+		case "config":
+			m.configCursor, m.configEditing = 0, false
+			m.state = stateConfigEditor
+		case "grants":
+			m.state = stateFeeGrants
+			return m, m.refreshFeeGrants()
+		case "grant-all":
+			return m.handleGrantCommand(cmd)
+		case "retry-balances":
+			return m.retryBalances()
+		case "errors":
+			m.state = stateErrors
+			m.errorCursor = 0
+			m.errorExpanded = false
+			return m, nil
+		case "queue":
+			m.queueCursor = 0
+			m.state = stateQueue
+		case "gov", "proposals":
+			m.govCursor = 0
+			m.state = stateGov
 		default:
 			// Handle upstake command: "u <address> <amount>"
 			if strings.HasPrefix(cmd, "u ") {
 				return m.handleUpstakeCommand(cmd)
 			}
+			// Handle service change command: "svc <address> <service_id>"
+			if strings.HasPrefix(cmd, "svc ") {
+				return m.handleServiceCommand(cmd)
+			}
+			// Handle import command: "import <file>"
+			if strings.HasPrefix(cmd, "import ") {
+				return m.handleImportCommand(cmd)
+			}
+			// Handle adopt command: "adopt [<address>]"
+			if cmd == "adopt" || strings.HasPrefix(cmd, "adopt ") {
+				return m.handleAdoptCommand(cmd)
+			}
+			// Handle fee grant commands: "grant <address> [spend_limit]" or "grant-all [spend_limit]"
+			if strings.HasPrefix(cmd, "grant ") || strings.HasPrefix(cmd, "grant-all ") {
+				return m.handleGrantCommand(cmd)
+			}
+			// Handle drain command: "drain <address>"
+			if strings.HasPrefix(cmd, "drain ") {
+				return m.handleDrainCommand(cmd)
+			}
+			// Handle runbook command: "run [<file> [--stop-on-error]]"
+			if cmd == "run" || strings.HasPrefix(cmd, "run ") {
+				return m.handleRunCommand(cmd)
+			}
+			// Handle onboarding command: "onboard [<address> <service_id> <stake>]"
+			if cmd == "onboard" || strings.HasPrefix(cmd, "onboard ") {
+				return m.handleOnboardCommand(cmd)
+			}
+
+			// Handle pocketd passthrough command: "! <pocketd args>"
+			if strings.HasPrefix(cmd, "!") {
+				return m.handlePassthroughCommand(cmd)
+			}
+
+			// Handle transaction lookup command: "tx <hash>"
+			if cmd == "tx" || strings.HasPrefix(cmd, "tx ") {
+				return m.handleTxCommand(cmd)
+			}
+
+			// Handle query console command: "query [<query>]"
+			if cmd == "query" || strings.HasPrefix(cmd, "query ") {
+				return m.handleQueryCommand(cmd)
+			}
+
+			// Handle profile command: "profile <name>"
+			if strings.HasPrefix(cmd, "profile ") {
+				return m.handleProfileCommand(cmd)
+			}
+
+			// Handle decommission command: "decommission [<address>]"
+			if cmd == "decommission" || strings.HasPrefix(cmd, "decommission ") {
+				return m.handleDecommissionCommand(cmd)
+			}
+			// Handle faucet command: "faucet <address>"
+			if cmd == "faucet" || strings.HasPrefix(cmd, "faucet ") {
+				return m.handleFaucetCommand(cmd)
+			}
+			// Handle calculator command: "calc [address|service]"
+			if cmd == "calc" || strings.HasPrefix(cmd, "calc ") {
+				return m.handleCalcCommand(cmd)
+			}
+			// Handle watchlist commands: "pin <address>" or "unpin <address>"
+			if strings.HasPrefix(cmd, "pin ") || strings.HasPrefix(cmd, "unpin ") {
+				return m.handlePinCommand(cmd)
+			}
+			// Handle saved filter command: "filter <name>" or "filter save <name>"
+			if strings.HasPrefix(cmd, "filter ") {
+				return m.handleFilterCommand(cmd)
+			}
+			// Handle delegate command: "delegate <address> [gateway]"
+			if strings.HasPrefix(cmd, "delegate ") {
+				return m.handleDelegateCommand(cmd)
+			}
+			// Handle unstake command: "unstake <address>"
+			if strings.HasPrefix(cmd, "unstake ") {
+				return m.handleUnstakeCommand(cmd)
+			}
+			// Handle transfer command: "transfer <address> <new_address>"
+			if strings.HasPrefix(cmd, "transfer ") {
+				return m.handleTransferCommand(cmd)
+			}
+			// Handle columns command: "columns <col,col,...>" or "columns +<col> -<col>"
+			if cmd == "columns" || strings.HasPrefix(cmd, "columns ") {
+				return m.handleColumnsCommand(cmd)
+			}
+			// Handle audit command: "audit" or "audit export <file>"
+			if cmd == "audit" || strings.HasPrefix(cmd, "audit ") {
+				return m.handleAuditCommand(cmd)
+			}
+			// Handle snapshot commands: "snapshot <name>" or "compare <name>"
+			if cmd == "snapshot" || strings.HasPrefix(cmd, "snapshot ") || cmd == "compare" || strings.HasPrefix(cmd, "compare ") {
+				return m.handleSnapshotCommand(cmd)
+			}
+			// Handle unit command: "unit <upokt|pokt> [precision]"
+			if strings.HasPrefix(cmd, "unit ") {
+				return m.handleUnitCommand(cmd)
+			}
 			// Handle show command: "show <address>"
 			if strings.HasPrefix(cmd, "show ") {
 				return m.handleShowCommand(cmd)
@@ -457,14 +1409,24 @@ func (m model) updateCommand(msg tea.KeyMsg) (model, tea.Cmd) {
 			if strings.HasPrefix(cmd, "f ") || strings.HasPrefix(cmd, "fund ") {
 				return m.handleFundCommand(cmd)
 			}
-			// Handle fund all command: "fa <amount>" or "fund-all <amount>"
-			if strings.HasPrefix(cmd, "fa ") || strings.HasPrefix(cmd, "fund-all ") {
+			// Handle fund all command: "fa[!] <amount>" or "fund-all[!] <amount>"
+			if strings.HasPrefix(cmd, "fa ") || strings.HasPrefix(cmd, "fa! ") ||
+				strings.HasPrefix(cmd, "fund-all ") || strings.HasPrefix(cmd, "fund-all! ") {
 				return m.handleFundAllCommand(cmd)
 			}
-			// Handle upstake all command: "ua <amount>" or "upstake-all <amount>"
-			if strings.HasPrefix(cmd, "ua ") || strings.HasPrefix(cmd, "upstake-all ") {
+			// Handle upstake all command: "ua[!] <amount>" or "upstake-all[!] <amount>"
+			if strings.HasPrefix(cmd, "ua ") || strings.HasPrefix(cmd, "ua! ") ||
+				strings.HasPrefix(cmd, "upstake-all ") || strings.HasPrefix(cmd, "upstake-all! ") {
 				return m.handleUpstakeAllCommand(cmd)
 			}
+			// Commands of configured plugins, unless built in
+			if plugin, ok := m.findPlugin(cmd); ok {
+				if err := m.refusePlugin(cmd); err != nil {
+					m.err = err
+					return m, nil
+				}
+				return m.runPlugin(plugin, cmd)
+			}
 		}
 
 	case "esc":
@@ -514,16 +1476,11 @@ func (m model) updateSearch(msg tea.KeyMsg) (model, tea.Cmd) {
 }
 
 func (m *model) performSearch() {
-	m.searchResults = []int{}
-	searchTerm := strings.ToLower(m.searchInput)
-
-	for i, app := range m.applications {
-		if strings.Contains(strings.ToLower(app.Address), searchTerm) ||
-			strings.Contains(strings.ToLower(app.ServiceID), searchTerm) {
-			m.searchResults = append(m.searchResults, i)
-		}
+	matches, err := m.searchMatches(m.searchInput)
+	if err != nil {
+		m.err = err
 	}
-
+	m.searchResults = matches
 	if len(m.searchResults) > 0 {
 		m.cursor = m.searchResults[0]
 		m.searchIndex = 0
@@ -538,9 +1495,15 @@ func (m model) updateNetworkSelect(msg tea.KeyMsg) (model, tea.Cmd) {
 			if network, exists := m.config.Config.Networks[selectedNetwork]; exists && len(network.Gateways) > 0 {
 				m.currentNetwork = selectedNetwork
 				m.currentGateway = network.Gateways[0]
+				m.showAllApps = false
 				m.state = stateTable
-				m.loading = true
-				return m, loadApplicationsCmd(network.RPCEndpoint, network.Gateways[0], network.Bank, m.config.Config.KeyringBackend, m.config.Config.PocketdHome, selectedNetwork)
+				logger.Info("network selected", "network", selectedNetwork, "gateway", m.currentGateway)
+				m.showCachedApplications(selectedNetwork, m.currentGateway)
+				return m, tea.Batch(
+					probeEndpointsCmd(selectedNetwork),
+					m.reloadApplications(network, selectedNetwork, m.currentGateway),
+					m.restartWatcher(),
+				)
 			}
 		}
 		m.state = stateTable
@@ -570,9 +1533,11 @@ func (m model) updateGatewaySelect(msg tea.KeyMsg) (model, tea.Cmd) {
 			if m.config != nil {
 				if network, exists := m.config.Config.Networks[m.currentNetwork]; exists {
 					m.currentGateway = selectedGateway
+					m.showAllApps = false
 					m.state = stateTable
-					m.loading = true
-					return m, loadApplicationsCmd(network.RPCEndpoint, selectedGateway, network.Bank, m.config.Config.KeyringBackend, m.config.Config.PocketdHome, m.currentNetwork)
+					logger.Info("gateway selected", "network", m.currentNetwork, "gateway", selectedGateway)
+					m.showCachedApplications(m.currentNetwork, selectedGateway)
+					return m, m.reloadApplications(network, m.currentNetwork, selectedGateway)
 				}
 			}
 		}
@@ -595,22 +1560,14 @@ func (m model) updateGatewaySelect(msg tea.KeyMsg) (model, tea.Cmd) {
 	return m, nil
 }
 
-func (m model) updateHelp(msg tea.KeyMsg) (model, tea.Cmd) {
-	switch msg.String() {
-	case "esc", "q", "enter":
-		m.state = stateTable
-	}
-	return m, nil
-}
-
 func (m model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("Error: %v\nPress q to quit.", m.err)
+	if m.fatal != nil {
+		return fmt.Sprintf("Error: %v\nPress q to quit.", m.fatal)
 	}
 
 	// Reserve space for command prompt at bottom (3 lines)
 	commandAreaHeight := 3
-	mainContentHeight := m.height - commandAreaHeight
+	mainContentHeight := m.height - commandAreaHeight - m.debugPaneHeight()
 
 	// Ensure mainContentHeight is never negative
 	if mainContentHeight < 1 {
@@ -622,7 +1579,7 @@ func (m model) View() string {
 	switch m.state {
 	case stateLoading:
 		mainContent = m.renderLoading()
-	case stateTable, stateCommand, stateSearch:
+	case stateTable, stateCommand, stateSearch, stateStakeEdit:
 		mainContent = m.renderTable()
 	case stateNetworkSelect:
 		mainContent = m.renderNetworkSelect()
@@ -634,6 +1591,70 @@ func (m model) View() string {
 		mainContent = m.renderApplicationDetails()
 	case stateUpstakeAllReceipts:
 		mainContent = m.renderUpstakeAllReceipts()
+	case stateAudit:
+		mainContent = m.renderAudit()
+	case stateSpend:
+		mainContent = m.renderSpend()
+	case stateDiff:
+		mainContent = m.renderDiff()
+	case stateDiscrepancies:
+		mainContent = m.renderDiscrepancies()
+	case stateSnapshots:
+		mainContent = m.renderSnapshots()
+	case stateCompare:
+		mainContent = m.renderCompare()
+	case stateRewards:
+		mainContent = m.renderRewards()
+	case stateParams:
+		mainContent = m.renderParams()
+	case stateGov:
+		mainContent = m.renderGov()
+	case stateServiceChange:
+		mainContent = m.renderServiceChange()
+	case stateTransfer:
+		mainContent = m.renderTransfer()
+	case stateConfigEditor:
+		mainContent = m.renderConfigEditor()
+	case stateFeeGrants:
+		mainContent = m.renderFeeGrants()
+	case stateQueue:
+		mainContent = m.renderQueue()
+	case stateCoApproval:
+		mainContent = m.renderCoApproval()
+	case stateQuitConfirm:
+		mainContent = m.renderQuitConfirm()
+	case stateErrors:
+		mainContent = m.renderErrors()
+	case stateAmountForm:
+		mainContent = m.renderAmountForm()
+	case stateRunbook:
+		mainContent = m.renderRunbook()
+	case stateCalc:
+		mainContent = m.renderCalc()
+	case stateGatewayHealth:
+		mainContent = m.renderGatewayHealth()
+	case stateJobs:
+		mainContent = m.renderJobs()
+	case stateJob:
+		mainContent = m.renderJob()
+	case statePassphrase:
+		mainContent = m.renderPassphrase()
+	case statePassthrough:
+		mainContent = m.renderPassthrough()
+	case stateQueryConsole:
+		mainContent = m.renderQueryConsole()
+	case stateTxLookup:
+		mainContent = m.renderTxLookup()
+	case stateUpstakeAllConfirm:
+		mainContent = m.renderUpstakeAllConfirm()
+	case stateFundAllReceipt:
+		mainContent = m.renderFundAllReceipt()
+	case stateGasEstimate:
+		mainContent = m.renderGasEstimate()
+	case statePlanReview:
+		mainContent = m.renderPlanReview()
+	case stateFilterPicker:
+		mainContent = m.renderFilterPicker()
 	default:
 		mainContent = ""
 	}
@@ -649,11 +1670,15 @@ func (m model) View() string {
 		mainContentLines = append(mainContentLines, "")
 	}
 
-	// Render command area (skip for application details view)
+	if m.showDebug {
+		mainContentLines = append(mainContentLines, m.renderDebugPane())
+	}
+
+	// Render command area (skip for application details view, which keeps
+	// only the status bar)
 	var result string
 	if m.state == stateApplicationDetails {
-		// No command area for details view
-		result = strings.Join(mainContentLines, "\n")
+		result = strings.Join(mainContentLines, "\n") + "\n" + m.renderStatusBar()
 	} else {
 		commandArea := m.renderCommandArea()
 		result = strings.Join(mainContentLines, "\n") + "\n" + commandArea
@@ -687,14 +1712,19 @@ func (m model) renderCommandArea() string {
 		commandContent = ":" + m.commandInput
 	case stateSearch:
 		commandContent = "/" + m.searchInput
+	case stateStakeEdit:
+		commandContent = m.stakeEditPrompt()
 	default:
-		commandContent = "Press : for commands, / for search, h for help"
+		commandContent = "Press : for commands, / for search, ? for keys, h for help"
+		if len(m.cooling) > 0 {
+			commandContent = m.cooldownStatus()
+		}
 	}
 
 	commandLine := commandStyle.Width(borderWidth).Render(commandContent)
 
-	// Return 3-line command area: border + command + empty
-	return border + "\n" + commandLine + "\n"
+	// Return 3-line command area: border + command + status bar
+	return border + "\n" + commandLine + "\n" + m.renderStatusBar()
 }
 
 func (m model) ensureFixedHeight(content string) string {
@@ -706,7 +1736,7 @@ func (m model) ensureFixedHeight(content string) string {
 		if m.state == stateCommand || m.state == stateSearch {
 			// Keep first few lines (header) and last few lines (command prompt)
 			// Trim from the table content in the middle
-			headerLines := 8  // Approximate header size
+			headerLines := lipgloss.Height(m.renderHeader())
 			commandLines := 3 // Approximate command prompt size
 
 			if len(lines) > headerLines+commandLines {
@@ -817,17 +1847,35 @@ func (m model) renderHeader() string {
 
 	// Column 1: App State
 	appCount := len(m.applications)
-	stateContent := fmt.Sprintf("🌐 Network: %s\n🧱 Gateway: %s\n📱 Applications: %d\n🏦 Bank Balance: %.2f POKT",
-		strings.ToUpper(m.currentNetwork), m.currentGateway, appCount, m.bankBalance)
+	networkLine := strings.ToUpper(m.currentNetwork)
+	if activeProfile != "" {
+		networkLine += " (🗂️ " + activeProfile + ")"
+	}
+	if count := m.stakingProposalCount(); count > 0 {
+		networkLine += fmt.Sprintf(" (🗳️ %d staking proposals, :gov)", count)
+	}
+	if len(m.txQueue) > 0 {
+		networkLine += fmt.Sprintf(" (⏸️ %d awaiting approval, :queue)", len(m.txQueue))
+	}
+	if m.readOnly() {
+		networkLine += " (👁️ read-only)"
+	} else if roles := configRoles(m.config); m.config != nil && (len(roles.Users) > 0 || roles.Default != "") {
+		networkLine += fmt.Sprintf(" (👤 %s: %s)", m.user(), m.role())
+	}
+	stateContent := fmt.Sprintf("🌐 Network: %s\n🧱 Gateway: %s\n%s\n📱 Applications: %d%s\n🏦 Bank Balance: %s %s",
+		networkLine, m.currentGateway+m.allAppsLabel(), m.gatewayHealthLine(), appCount, m.discrepancySummary()+m.delegationSummary(), m.formatPOKT(m.bankBalance), m.unitLabel())
+	if m.fiatEnabled() {
+		if m.fiatErr != nil && m.fiatPriceAt.IsZero() {
+			stateContent += " (price unavailable)"
+		} else {
+			stateContent += fmt.Sprintf(" (≈ %s %s)", m.formatFiat(m.bankBalance), m.config.Config.PriceFeed.currency())
+		}
+	}
+	stateContent += "\n" + m.rpcStatusLine() + "\n" + m.chainStatusLine()
 	stateColumn := stateStyle.Render(stateContent)
 
 	// Column 2: Commands (clean columns)
-	commandContent := "Navigation:           Sort Columns:                  Actions:\n"
-	commandContent += "r: Refresh            :ss Status     :sv Service     :: Command    /: Search\n"
-	commandContent += "n: Network            :sa Address                    f: Fund       F: Fund All\n"
-	commandContent += "g: Gateway            :sp Stake                      u: Upstake    U: Upstake All\n"
-	commandContent += "h: Help               :sb Balance                    q: Quit\n"
-	commandColumn := commandStyle.Render(commandContent)
+	commandColumn := commandStyle.Render(m.headerCheatSheet())
 
 	// Join 2 columns horizontally
 	headerContent := lipgloss.JoinHorizontal(lipgloss.Top, stateColumn, commandColumn)
@@ -849,37 +1897,44 @@ func (m model) renderTableContent() string {
 		Bold(true)
 
 	// Calculate available height for table content
-	// Account for command area (3 lines) and header (8-10 lines typically)
-	reservedLines := 13 // Conservative estimate
+	// Account for the header, command area (3 lines) and status lines below the table
+	reservedLines := lipgloss.Height(m.renderHeader()) + 6 + m.debugPaneHeight()
+	if panel := m.renderTxPanel(); panel != "" {
+		reservedLines += lipgloss.Height(panel)
+	}
+	if toasts := m.renderToasts(); toasts != "" {
+		reservedLines += lipgloss.Height(toasts)
+	}
+	if m.renderRefreshChanges() != "" {
+		reservedLines++
+	}
 	availableHeight := m.height - reservedLines
 	if availableHeight < 10 {
 		availableHeight = 10 // Minimum usable table height
 	}
 
-	// Improved column widths - better distribution across screen
-	statusWidth := 10
-	stakeWidth := 20   // Increased for better spacing
-	balanceWidth := 20 // Increased for better spacing
-	serviceWidth := 28 // Increased for better service ID readability
-	gatewayWidth := 20 // Increased for better spacing
-	// Calculate remaining width for address column with better spacing
-	usedWidth := statusWidth + stakeWidth + balanceWidth + serviceWidth + gatewayWidth
-	spacing := 20 // Account for column separators and padding
-	addressWidth := m.width - usedWidth - spacing
-	if addressWidth < 25 {
-		addressWidth = 25 // Minimum width for readability
-	}
-
-	tableHeader := fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s %-*s",
-		statusWidth, m.getColumnHeader("ℹ️  Status", "status"),
-		addressWidth, m.getColumnHeader("📫 App Address", "address"),
-		stakeWidth, m.getColumnHeader("🪙 Stake (POKT)", "stake"),
-		balanceWidth, m.getColumnHeader("💰 Balance (POKT)", "balance"),
-		serviceWidth, m.getColumnHeader("⚡ Service ID", "service"),
-		gatewayWidth, m.getColumnHeader("🧱 Gateway", "gateway"))
+	// Width-aware column layout: columns shrink (and low priority ones hide)
+	// on narrow terminals, and the address column absorbs any spare width
+	tableWidth := m.width
+	if tableWidth < 1 {
+		tableWidth = 80 // Fallback width
+	}
+	columns := m.tableColumns()
+	widths := layoutWidths(columns, tableWidth, 1)
+
+	headerCells := make([]string, len(columns))
+	for i, col := range columns {
+		headerCells[i] = col.title
+	}
+	tableHeader := layoutRow(columns, widths, headerCells, 1)
 
 	var rows []string
 	rows = append(rows, headerStyle.Render(tableHeader))
+
+	// Pinned applications stay above the table whatever its sort or search
+	watchlist := m.renderWatchlist(columns, widths, min(maxWatchlistRows, (availableHeight-2)/2))
+	rows = append(rows, watchlist...)
+
 	// Create separator with GASMS branding
 	gasmsText := " 🌿 G A S M S 🌿 "
 	availableWidth := m.width - 4 - len(gasmsText) // Account for border padding
@@ -892,39 +1947,50 @@ func (m model) renderTableContent() string {
 	rows = append(rows, headerStyle.Render(separatorText))
 
 	// Table rows (limit to available height)
-	displayRows := availableHeight - 2 // Reserve space for header and separator
+	displayRows := availableHeight - 2 - len(watchlist) // Reserve space for header, separator and watchlist
 	if displayRows < 1 {
 		displayRows = 1 // Always show at least one row
 	}
 
 	startRow := 0
-	if m.cursor >= displayRows {
+	if m.groupsByGateway() {
+		startRow = m.gatewayGroupStart(displayRows)
+	} else if m.cursor >= displayRows {
 		startRow = m.cursor - displayRows + 1
 	}
 
-	for i := startRow; i < len(m.applications) && i < startRow+displayRows; i++ {
+	tableStart := len(rows)
+	for i := startRow; i < len(m.applications) && len(rows)-tableStart < displayRows; i++ {
 		app := m.applications[i]
 
-		// Determine stake status and colors
-		status, rowStyle := m.getStakeStatus(app, selectedStyle, normalStyle, i == m.cursor)
+		// Group headers in the all-applications view sorted by gateway
+		if m.startsGatewayGroup(i, startRow) {
+			rows = append(rows, m.renderGatewayGroupHeader(i))
+			if len(rows)-tableStart >= displayRows {
+				break
+			}
+		}
 
-		// Use dynamic widths for consistent formatting
-		row := fmt.Sprintf("%-*s %-*s %-*s %-*s %-*s %-*s",
-			statusWidth, status,
-			addressWidth, TruncateAddress(app.Address, addressWidth-2),
-			stakeWidth, fmt.Sprintf("%.2f", app.StakePOKT),
-			balanceWidth, fmt.Sprintf("%.2f", app.BalancePOKT),
-			serviceWidth, app.ServiceID, // Never truncate service ID
-			gatewayWidth, TruncateAddress(m.currentGateway, gatewayWidth-2))
+		// Determine stake status colors
+		_, rowStyle := m.getStakeStatus(app, selectedStyle, normalStyle, i == m.cursor)
 
-		row = rowStyle.Render(row)
+		row := m.renderTableRow(columns, widths, app, rowStyle, i == m.cursor)
 		rows = append(rows, row)
 	}
 
+	if m.showsEmptyGateway() {
+		rows = append(rows, m.renderEmptyGateway())
+	}
+
 	tableContent := strings.Join(rows, "\n")
 
+	// Summarize what changed since the previous refresh
+	if changes := m.renderRefreshChanges(); changes != "" {
+		tableContent += "\n" + changes
+	}
+
 	// Add loading notification at bottom if loading
-	if m.loading {
+	if m.loading || len(m.pendingBalances) > 0 {
 		loadingStyle := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("220")). // Bold yellow
 			Bold(true).
@@ -932,45 +1998,26 @@ func (m model) renderTableContent() string {
 			Width(m.width)
 		var loadingText string
 		if m.processingUpstakeAll {
-			loadingText = "🔄 PROCESSING UPSTAKE TRANSACTIONS..."
+			loadingText = m.spinner() + " PROCESSING UPSTAKE TRANSACTIONS..."
+		} else if m.loading && !m.staleSince.IsZero() {
+			loadingText = fmt.Sprintf("%s STALE DATA FROM %s, REFRESHING...", m.spinner(), strings.ToUpper(formatAge(time.Since(m.staleSince))))
+		} else if m.loading {
+			loadingText = m.spinner() + " REFRESHING DATA..."
 		} else {
-			loadingText = "🔄 REFRESHING DATA..."
+			loadingText = fmt.Sprintf("%s LOADING BALANCES... (%d of %d pending)", m.spinner(), len(m.pendingBalances), len(m.applications))
 		}
 		loadingMsg := loadingStyle.Render(loadingText)
 		tableContent += "\n" + loadingMsg
 	}
 
-	// Add transaction hash display if available
-	if m.txHash != "" {
-		txStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("46")). // Bright green
-			Bold(true).
-			Align(lipgloss.Center).
-			Width(m.width)
-		txMsg := txStyle.Render("💸 UPSTAKE TXHASH: " + m.txHash)
-		tableContent += "\n" + txMsg
+	// Add submitted transactions below the table
+	if panel := m.renderTxPanel(); panel != "" {
+		tableContent += "\n" + panel
 	}
 
-	// Add fund transaction hash display if available
-	if m.fundTxHash != "" {
-		fundStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("46")). // Bright green
-			Bold(true).
-			Align(lipgloss.Center).
-			Width(m.width)
-		fundMsg := fundStyle.Render("💸 FUND TXHASH: " + m.fundTxHash)
-		tableContent += "\n" + fundMsg
-	}
-
-	// Add transaction error display if available
-	if m.txError != "" {
-		errorStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("196")). // Bright red
-			Bold(true).
-			Align(lipgloss.Center).
-			Width(m.width)
-		errorMsg := errorStyle.Render("❌ TXHASH: " + m.txErrorHash + ". ERROR: " + m.txError)
-		tableContent += "\n" + errorMsg
+	// Add notifications last so they are never pushed off screen
+	if toasts := m.renderToasts(); toasts != "" {
+		tableContent += "\n" + toasts
 	}
 
 	return tableContent
@@ -983,20 +2030,37 @@ func (m model) getStakeStatus(app Application, selectedStyle, normalStyle lipglo
 		stakeAmountInt = 0
 	}
 
-	// Default thresholds if config is not available
-	warningThreshold := int64(2000000000) // 2000 POKT
-	dangerThreshold := int64(1000000000)  // 1000 POKT
-
-	// Use config thresholds if available
-	if m.config != nil {
-		warningThreshold = m.config.Config.Thresholds.WarningThreshold
-		dangerThreshold = m.config.Config.Thresholds.DangerThreshold
-	}
+	warningThreshold, dangerThreshold := m.stakeThresholds()
 
 	var status string
 	var style lipgloss.Style
 
-	if stakeAmountInt >= warningThreshold {
+	if isUnstaking(app) {
+		// Eject badge and grey text for apps with a pending unstake
+		status = "⏏️"
+		if left, ok := m.unstakingBlocksLeft(app); ok {
+			status = fmt.Sprintf("⏏️ %d", left)
+		}
+		if isSelected {
+			style = lipgloss.NewStyle().
+				Background(lipgloss.Color("236")). // Dark grey background
+				Foreground(lipgloss.Color("244"))  // Grey text
+		} else {
+			style = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("244")) // Grey text
+		}
+	} else if isTransferring(app) {
+		// Transfer badge for apps whose stake moves to a new address
+		status = "🔀"
+		if left, ok := m.blocksUntil(app.TransferHeight); ok && left > 0 {
+			status = fmt.Sprintf("🔀 %d", left)
+		}
+		if isSelected {
+			style = selectedStyle
+		} else {
+			style = normalStyle
+		}
+	} else if stakeAmountInt >= warningThreshold {
 		// Green circle for good stakes
 		status = "🟢"
 		if isSelected {
@@ -1031,6 +2095,17 @@ func (m model) getStakeStatus(app Application, selectedStyle, normalStyle lipglo
 }
 
 func (m *model) sortApplications() {
+	// Estimates from history are computed once rather than per comparison
+	var estimates map[string]float64
+	switch m.sortBy {
+	case "burn":
+		estimates = m.burnSortKeys()
+	case "danger":
+		estimates = m.dangerSortKeys()
+	case "relays":
+		estimates = m.relaySortKeys()
+	}
+
 	sort.Slice(m.applications, func(i, j int) bool {
 		var result bool
 		switch m.sortBy {
@@ -1052,7 +2127,23 @@ func (m *model) sortApplications() {
 		case "service":
 			result = m.applications[i].ServiceID < m.applications[j].ServiceID
 		case "gateway":
-			result = m.currentGateway < m.currentGateway // All same gateway, so no change
+			// Applications delegated to no gateway last, then by address so
+			// rows keep their place within a gateway
+			gatewayI, gatewayJ := m.rowGateway(m.applications[i]), m.rowGateway(m.applications[j])
+			switch {
+			case gatewayI == gatewayJ:
+				result = m.applications[i].Address < m.applications[j].Address
+			case gatewayI == "" || gatewayJ == "":
+				result = gatewayJ == ""
+			default:
+				result = gatewayI < gatewayJ
+			}
+		case "burn":
+			result = estimates[m.applications[i].Address] > estimates[m.applications[j].Address] // Default: fastest burn first
+		case "danger":
+			result = estimates[m.applications[i].Address] < estimates[m.applications[j].Address] // Default: soonest first
+		case "relays":
+			result = estimates[m.applications[i].Address] > estimates[m.applications[j].Address] // Default: most relays first
 		default:
 			result = m.applications[i].ServiceID < m.applications[j].ServiceID
 		}
@@ -1235,24 +2326,27 @@ func (m model) renderGatewaySelect() string {
 	return header + "\n" + content
 }
 
-func (m model) renderHelp() string {
-	helpStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("150")). // Light grey-green
-		Padding(1, 2).
-		Border(lipgloss.DoubleBorder()).
-		BorderForeground(lipgloss.Color("65")).
-		Width(m.width - 4)
-
-	helpContent := `GASMS - Grove🌿 AppStakes Management System
+// helpContent is the full help, shown by h and :help.
+const helpContent = `GASMS - Grove🌿 AppStakes Management System
 
 NAVIGATION:
   ↑/k, ↓/j        Navigate up/down
   g, G            Go to top/bottom
   u               Upstake selected application (add to current stake)
   f               Fund selected application
+                  u/f open a form with the current stake and balance, a
+                  suggested amount (Tab), the fee and the resulting balances
+  e               Type a target stake in the stake cell of the selected
+                  application; Enter stages the upstake that reaches it
   F               Fund all applications (opens :fa prompt)
   U               Upstake all applications (opens :ua prompt)
+  d               Show drift from configured targets (R to reconcile)
+  p               Pin or unpin the selected application on the watchlist
+                  shown above the table
   enter           Show application details
+  ?               Keys of the current view (in any view); in the help,
+                  / searches and j/k, pgup/pgdown scroll
+  esc, ctrl+c     Cancel an in-flight refresh or batch (ctrl+c again quits)
   
 COMMANDS (prefix with :):
   q, quit         Quit application
@@ -1263,7 +2357,102 @@ COMMANDS (prefix with :):
   f <addr> <amt>  Fund application (send tokens)
   fa <amount>     Fund all applications (each app receives <amount> tokens)
   ua <amount>     Upstake all applications (each app gets <amount> added to stake)
+                  Amounts are upokt, or take a unit: 1500pokt, 2.5kpokt,
+                  250000000upokt
+                  fa/ua refuse to start if balances cannot cover amounts + fees;
+                  fa!/ua! skip the check; ua skips unstaking apps
+                  unless --include-unstaking is given
+                  ua --scope=configured|displayed|selected shows the
+                  targets of that scope for confirmation first
+                  fa/ua --estimate simulates a representative transaction
+                  and shows the gas and fees of the whole batch first
+  fa @<file>, ua @<file>
+                  Fund/upstake each app by its own amount from a CSV file
+                  of address,amount lines (upokt unless a unit is given)
+  ... --memo <text>
+                  Attach a memo to the transactions of any command,
+                  overriding the configured memo (e.g. fa 100 --memo OPS-123)
+  svc <addr> <id> Re-stake application for service IDs (comma-separated),
+                  keeping its stake; "svc <addr> +<id>" adds a service
+  delegate <addr> [gw]
+                  Delegate an application to a gateway (default the
+                  current one)
+  unstake <addr>  Unstake an application; its stake returns to its balance
+                  after the unbonding period
+  transfer <addr> <new>
+                  Transfer application stake to a new owner address
+  config          Edit thresholds, bank, gateways and applications of the
+                  current network (written back to config.yaml)
+  import <file>   Add applications (address[,label[,stake]] CSV or JSON)
+                  to the current network's config
+  adopt [<addr>]  Add the applications delegated to the gateway but not
+                  configured (marked ❔, M for the selected one) to config
+  edit-plan       Edit the stakes and funds of the applications as a table
+                  in $EDITOR; on save the changed rows are staged for review
+  drain <addr>    Send application balance back to the bank, keeping
+                  drain-keep (default 0.2 POKT) plus the fee
+  drain-all       Drain every application of the current gateway
+  autofund        Top up applications below the auto_fund balance floor
+  grants          Fee grants from the bank to the configured applications
+  queue           Transactions waiting for approval (approval-queue: true);
+                  a approves, x rejects, A approves all
+  grant <addr> [limit]
+                  Grant an application a fee allowance from the bank
+                  (spend limit in upokt); "grant-all [limit]" grants every
+                  application of the current gateway without one
   show <addr>     Show application details
+  columns <list>  Set visible columns in order (e.g. columns status,address,stake)
+  columns +c -c   Show (+) or hide (-) individual columns, "columns reset" for defaults
+                  Columns: status, address, label, stake, balance, service, gateway,
+                           unstaking, transfer, delegations, stake_fiat, balance_fiat,
+                           burn, danger_days, relays, relay_errors
+  unit <u> [prec] Display amounts in upokt or pokt, optionally with decimal precision
+  audit           Show the audit log of fund/upstake operations
+  audit export <f> Export the audit log to a JSON file
+  run <file> [--stop-on-error]
+                  Run the commands of a file one at a time, each waiting for
+                  the transactions of the previous one; "run" shows results
+  onboard <addr> <service> <stake>
+                  Onboard a new application step by step: fund it, stake it,
+                  delegate it to the gateway, verify and add it to config
+  decommission <addr>
+                  Retire an application step by step: undelegate, unstake,
+                  wait for unbonding, sweep its balance to the bank and
+                  remove it from config
+  profile [name]  List the profiles, or switch to one ("default" for
+                  config.yaml); the session restarts on its config and state
+  ! <pocketd args>
+                  Run any pocketd command with the node, chain ID, home and
+                  keyring of the current network appended (tx get --yes);
+                  the output scrolls in a pane, "!" shows it again
+  tx <hash>       Look up a transaction on the current network: messages,
+                  fee, gas used and events
+  query [<query>] Query console: run "pocketd q" queries (or "grpc
+                  <service/method> [json]" with grpcurl) and browse the JSON
+                  response as a tree with folding and / search
+  jobs            Running, queued and finished jobs (onboarding, decommission,
+                  upstake-all) with their receipts; retry or cancel them
+  faucet <addr>   Request test network tokens for an address from the faucet
+                  of the network and track the transaction it sends
+  calc [addr|svc] What-if stake calculator for the selected application, an
+                  address or a service group: upstake, thresholds, relay
+                  capacity, fees and the resulting bank balance
+  retry-balances  Query the balances that failed in the last refresh again
+  errors          Errors of this session with their command and output;
+                  enter for details, d/D to dismiss
+  spend           Daily/weekly POKT outflow per bank (fund, upstake, fees)
+  diff            Drift between configured targets and chain state (also: d);
+                  press R there to stage the reconciling transactions
+  disc            Configured applications missing on chain, and delegated
+                  applications missing from config
+  snapshot <name> Record current stakes and balances
+  snapshots       List snapshots (enter to compare)
+  compare <name>  Stake/balance deltas since a snapshot; R restores its stakes
+  rewards         Pending relay claims per application vs. stake burned
+  gateways        On-chain stake and unstaking state of the configured gateways
+                  and their health checks (r to check now)
+  params          On-chain application/shared module params (min stake, etc.)
+  gov             Active governance proposals; staking param changes flagged
   
 SORTING:
   ss, sort status    Sort by stake status (high to low)
@@ -1271,23 +2460,34 @@ SORTING:
   sp, sort stake     Sort by stake amount (high to low)
   sb, sort balance   Sort by balance amount (high to low)
   sv, sort service   Sort by service ID (A-Z)
-  sg, sort gateway   Sort by gateway
+  sg, sort gateway   Sort by gateway (A-Z), grouped per gateway after A
+  sr, sort burn      Sort by daily stake burn rate (fastest first)
+  sd, sort danger    Sort by days until danger threshold (soonest first)
+  sl, sort relays    Sort by relays from relay_metrics (most first)
   
 SEARCH:
-  /               Search applications (by address or service ID)
+  /               Search applications (by address or service ID); addr:, svc:,
+                  gw: match one column, stake: and balance: compare amounts
+                  (stake:<1000, balance:>=5), status: is green, yellow,
+                  red, unstaking or transferring; words must all match
+  pin <addr>      Pin an application on the watchlist (unpin <addr> to
+                  remove it)
+  filter [name]   Search with a saved filter, or pick one from a list
+  filter save <name>
+                  Save the current search as a filter in config.yaml
   
 REFRESH:
   r               Refresh application data
 
+DEBUG:
+  ctrl+l          Toggle debug pane with executed pocketd commands
+
 STAKE STATUS INDICATORS:
   🟢              Healthy stake (≥ warning threshold)
   🟡              Warning stake (between thresholds)  
   🔴              Danger stake (< danger threshold)
-
-Press ESC, Enter, or q to return to main view.`
-
-	return helpStyle.Render(helpContent)
-}
+  ⏏️ <blocks>      Unstaking, with blocks left until the unstake completes
+  🔀 <blocks>      Transferring to a new address, with blocks left in the session`
 
 func max(a, b int) int {
 	if a > b {
@@ -1305,52 +2505,98 @@ func (m model) handleUpstakeCommand(cmd string) (model, tea.Cmd) {
 
 	address := parts[1]
 	amountStr := parts[2]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
 
 	// Validate amount is numeric
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
-	if err != nil || amount <= 0 {
-		m.err = fmt.Errorf("amount must be a positive integer: %s", amountStr)
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		m.err = err
 		return m, nil
 	}
 
-	// Find the application to get its service ID
-	var serviceID string
+	// Find the application to get its service IDs
+	var serviceIDs []string
+	var currentStake int64
+	found := false
 	for _, app := range m.applications {
 		if app.Address == address {
-			serviceID = app.ServiceID
+			serviceIDs = app.ServiceIDs
+			currentStake = stakeUpokt(app)
+			found = true
 			break
 		}
 	}
 
-	if serviceID == "" {
+	if !found {
 		m.err = fmt.Errorf("application not found: %s", address)
 		return m, nil
 	}
 
+	// The chain rejects stakes below the minimum after charging the fee
+	if err := m.checkMinStake(currentStake, amount); err != nil {
+		return m, m.notify(toastWarning, fmt.Sprintf("Upstake refused: %v", err))
+	}
+
 	// Execute upstake in background
-	return m, m.executeUpstake(address, serviceID, amount)
+	txID := m.trackTx("upstake", cmd, []string{address}, amount)
+	return m, m.submitTracked(cmd, m.executeUpstake(txID, address, serviceIDs, amount), txID)
 }
 
-func (m model) executeUpstake(address, serviceID string, amount int64) tea.Cmd {
+func (m model) executeUpstake(txID int, address string, serviceIDs []string, amount int64) tea.Cmd {
 	return func() tea.Msg {
-		txHash, err := upstakeApplication(address, serviceID, amount, m.config, m.currentNetwork)
+		txHash, _, err := upstakeApplication(address, serviceIDs, amount, m.config, m.currentNetwork)
 		if err != nil {
-			// Check if this is a transaction error with hash
-			if strings.Contains(err.Error(), "transaction failed with hash") {
-				parts := strings.Split(err.Error(), ": ")
-				if len(parts) >= 2 {
-					hashPart := strings.TrimPrefix(parts[0], "transaction failed with hash ")
-					errorPart := strings.Join(parts[1:], ": ")
-					return transactionErrorMsg{txHash: hashPart, error: errorPart}
-				}
-			}
-			return fmt.Sprintf("Upstake failed: %v", err)
+			return newTxFailedMsg(txID, "upstake", []string{address}, amount, err)
 		}
-		return upstakeCompletedMsg{txHash: txHash}
+		return upstakeCompletedMsg{txID: txID, txHash: txHash}
+	}
+}
+
+// upstakeApplication adds amount to the stake of address. A staked
+// application is re-staked for every service it is staked for on chain, since
+// services missing from the stake config are unstaked; serviceIDs are only
+// used to stake a new application. It returns the stake read from chain
+// that amount was added to.
+func upstakeApplication(address string, serviceIDs []string, amount int64, config *Config, networkName string) (string, int64, error) {
+	if config == nil {
+		return "", 0, fmt.Errorf("config not loaded")
+	}
+
+	network, exists := config.Config.Networks[networkName]
+	if !exists {
+		return "", 0, fmt.Errorf("network not found: %s", networkName)
+	}
+
+	// Get current stake and services
+	var current *Application
+	err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+		var err error
+		current, err = ShowApplication(address, endpoint, config.pocketdHome(networkName), networkName)
+		return err
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to get current stake: %v", err)
 	}
+
+	// New application unless already staked, in which case increment
+	var previousStake int64
+	var gateways []string
+	if current != nil {
+		previousStake = stakeUpokt(*current)
+		serviceIDs = current.ServiceIDs
+		gateways = current.DelegateeGateways
+	}
+	txHash, err := stakeApplication(address, serviceIDs, previousStake+amount, gateways, config, networkName)
+	return txHash, previousStake, err
 }
 
-func upstakeApplication(address, serviceID string, amount int64, config *Config, networkName string) (string, error) {
+// stakeApplication stakes address with stake upokt for exactly serviceIDs.
+// gateways are its current delegatee gateways, for stake templates that keep
+// them.
+func stakeApplication(address string, serviceIDs []string, stake int64, gateways []string, config *Config, networkName string) (string, error) {
 	if config == nil {
 		return "", fmt.Errorf("config not loaded")
 	}
@@ -1359,96 +2605,75 @@ func upstakeApplication(address, serviceID string, amount int64, config *Config,
 	if !exists {
 		return "", fmt.Errorf("network not found: %s", networkName)
 	}
+	if len(serviceIDs) == 0 {
+		return "", fmt.Errorf("no service IDs to stake %s for", address)
+	}
 
 	// Note: Bank address field is available in config but not currently used for --from
 	// The --from parameter uses the application address instead
 
-	// Get current stake amount
-	currentStake, err := getCurrentStake(address, network.RPCEndpoint, networkName, config.Config.KeyringBackend, config.Config.PocketdHome)
-	if err != nil {
-		return "", fmt.Errorf("failed to get current stake: %v", err)
-	}
-
-	var newStake int64
-	if currentStake == -1 {
-		// New application
-		newStake = amount
-	} else {
-		// Existing application, increment
-		newStake = currentStake + amount
-	}
-
 	// Create temporary config file
-	tempDir := "/tmp"
-	configFile := filepath.Join(tempDir, fmt.Sprintf("gasms_upstake_%s_%d.yaml", address, time.Now().Unix()))
-
-	configContent := fmt.Sprintf(`stake_amount: %dupokt
-service_ids:
-  - "%s"
-address: %s
-`, newStake, serviceID, address)
-
-	if err := os.WriteFile(configFile, []byte(configContent), 0600); err != nil {
-		return "", fmt.Errorf("failed to create config file: %v", err)
+	configFile, err := writeStakeConfig(network, address, serviceIDs, stake, gateways)
+	if err != nil {
+		return "", err
 	}
 
 	// Clean up temp file when done
 	defer os.Remove(configFile)
 
-	// Determine chain ID and node based on network
-	var chainID, node string
+	// Determine chain ID based on network
+	var chainID string
 	switch networkName {
 	case "pocket":
 		chainID = "pocket"
-		node = "https://shannon-grove-rpc.mainnet.poktroll.com"
 	case "pocket-beta":
 		chainID = "pocket-beta"
-		node = "https://shannon-testnet-grove-rpc.beta.poktroll.com"
 	default:
 		return "", fmt.Errorf("unsupported network: %s", networkName)
 	}
 
-	// Execute pocketd command using application address for --from
-	args := []string{"tx", "application", "stake-application",
-		"--config=" + configFile,
-		"--from=" + address,
-		"--node=" + node,
-		"--chain-id=" + chainID,
-		"--fees=20000upokt"}
+	var output []byte
+	err = withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		// Execute pocketd command using application address for --from
+		args := []string{"tx", "application", "stake-application",
+			"--config=" + configFile,
+			"--from=" + address,
+			"--node=" + node,
+			"--chain-id=" + chainID,
+			network.feesArg()}
+		args = append(args, feeGranterArgs(network)...)
+		args = append(args, memoArgs(config)...)
 
-	// Add optional pocketd home flag (only if specified in config)
-	if config.Config.PocketdHome != "" {
-		args = append(args, "--home="+config.Config.PocketdHome)
-	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
-	}
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
 
-	// Add keyring-backend if specified
-	if config.Config.KeyringBackend != "" {
-		args = append(args, "--keyring-backend="+config.Config.KeyringBackend)
+		args = append(args, "-y")
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
-	args = append(args, "-y")
-	cmd := exec.Command("pocketd", args...)
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
+}
 
-	output, err := cmd.CombinedOutput()
+// writeStakeConfig writes the stake-application config staking address with
+// stake upokt for serviceIDs to a private temporary file and returns its path.
+func writeStakeConfig(network Network, address string, serviceIDs []string, stake int64, gateways []string) (string, error) {
+	configContent, err := stakeConfig(network, address, serviceIDs, stake, gateways)
 	if err != nil {
-		return "", fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		return "", err
 	}
-
-	// Parse transaction hash and check for errors
-	outputStr := string(output)
-	txHash, rawLog, err := parsePocketdOutput(outputStr)
+	configFile, err := writeTempFile("stake-*.yaml", configContent)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse pocketd output: %v", err)
-	}
-
-	// Check if there's an error in raw_log
-	if rawLog != "" && (strings.Contains(rawLog, "failed") || strings.Contains(rawLog, "error") || strings.Contains(rawLog, "insufficient") || strings.Contains(rawLog, "out of gas")) {
-		return "", fmt.Errorf("transaction failed with hash %s: %s", txHash, rawLog)
+		return "", fmt.Errorf("failed to create config file: %v", err)
 	}
-
-	return txHash, nil
+	return configFile, nil
 }
 
 func isHexString(s string) bool {
@@ -1460,42 +2685,6 @@ func isHexString(s string) bool {
 	return true
 }
 
-func parsePocketdOutput(output string) (txHash string, rawLog string, err error) {
-	// Try to parse as JSON first
-	var jsonResp map[string]interface{}
-	if err := json.Unmarshal([]byte(output), &jsonResp); err == nil {
-		// Extract txhash
-		if hash, ok := jsonResp["txhash"].(string); ok {
-			txHash = hash
-		}
-
-		// Extract raw_log for error checking
-		if log, ok := jsonResp["raw_log"].(string); ok {
-			rawLog = log
-		}
-
-		return txHash, rawLog, nil
-	}
-
-	// Fallback to text parsing
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// Handle formats: "txhash: ABC123", "txhash:ABC123", or just "ABC123" on its own
-		if strings.HasPrefix(strings.ToLower(line), "txhash:") {
-			txHash = strings.TrimSpace(strings.TrimPrefix(line, "txhash:"))
-			txHash = strings.TrimSpace(strings.TrimPrefix(txHash, " "))
-			break
-		} else if len(line) == 64 && isHexString(line) {
-			// Likely a 64-character hex hash
-			txHash = line
-			break
-		}
-	}
-
-	return txHash, "", nil
-}
-
 func createClickableLink(url, displayText string) string {
 	// OSC 8 hyperlink format: \x1b]8;;URL\x1b\\DISPLAYTEXT\x1b]8;;\x1b\\
 	// This creates a clickable link in terminals that support OSC 8
@@ -1503,77 +2692,42 @@ func createClickableLink(url, displayText string) string {
 	return fmt.Sprintf("\x1b]8;;%s\x1b\\%s\x1b]8;;\x1b\\", url, displayText)
 }
 
-func getCurrentStake(address, rpcEndpoint, networkName, keyringBackend, pocketdHome string) (int64, error) {
-	var chainID string
-	switch networkName {
-	case "pocket":
-		chainID = "pocket"
-	case "pocket-beta":
-		chainID = "pocket-beta"
-	default:
-		return 0, fmt.Errorf("unsupported network: %s", networkName)
-	}
-
-	args := []string{"query", "application", "show-application", address,
-		"--node=" + rpcEndpoint,
-		"--chain-id=" + chainID,
-		"--output=json"}
-
-	// Add optional home flag (keyring-backend not needed for query commands)
-	if pocketdHome != "" {
-		args = append(args, "--home="+pocketdHome)
-	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
-	}
-
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		// Check if application not found
-		if strings.Contains(string(output), "application not found") || strings.Contains(string(output), "key not found") {
-			return -1, nil // Indicates new application
-		}
-		return 0, fmt.Errorf("query failed: %v, output: %s", err, string(output))
-	}
-
-	// Parse JSON to extract stake amount
-	var appData map[string]interface{}
-	if err := json.Unmarshal(output, &appData); err != nil {
-		return 0, fmt.Errorf("failed to parse JSON output: %v", err)
-	}
-
-	// Navigate to application.stake.amount
-	app, ok := appData["application"].(map[string]interface{})
-	if !ok {
-		return 0, fmt.Errorf("application field not found in response")
-	}
-
-	stake, ok := app["stake"].(map[string]interface{})
-	if !ok {
-		return 0, fmt.Errorf("stake field not found in application")
-	}
-
-	amountStr, ok := stake["amount"].(string)
-	if !ok {
-		return 0, fmt.Errorf("amount field not found in stake or not a string")
-	}
-
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid stake amount: %v", err)
-	}
-
-	return amount, nil
-}
-
 func (m model) showApplicationDetails(address string) (model, tea.Cmd) {
 	m.selectedAppAddress = address
 	m.state = stateApplicationDetails
+	return m.reloadApplicationDetails()
+}
+
+// reloadApplicationDetails queries the application, its bank balances and
+// its activity concurrently; each section of the details view renders as
+// soon as its query returns, and the sessions once the application has.
+func (m model) reloadApplicationDetails() (model, tea.Cmd) {
 	m.detailsLoading = true
+	m.detailsErr = nil
 	m.applicationDetails = ""
+	m.balancesLoading = true
+	m.balancesErr = nil
 	m.bankBalances = ""
-	return m, m.loadApplicationDetailsCmd(address)
+	m.sessions = nil
+	m.sessionsErr = nil
+	m.sessionsLoading = false
+	activity := m.refreshActivity()
+	return m, tea.Batch(m.loadApplicationDetailsCmd(m.selectedAppAddress), m.loadDetailsBalancesCmd(m.selectedAppAddress), activity, m.startSpinner())
+}
+
+// stepApplicationDetails shows the application delta rows away from the
+// viewed one in table order, moving the table cursor along with it.
+func (m model) stepApplicationDetails(delta int) (model, tea.Cmd) {
+	i := slices.IndexFunc(m.applications, func(app Application) bool { return app.Address == m.selectedAppAddress })
+	if i < 0 {
+		i = m.cursor
+	}
+	next := i + delta
+	if next < 0 || next >= len(m.applications) {
+		return m, nil
+	}
+	m.cursor = next
+	return m.showApplicationDetails(m.applications[next].Address)
 }
 
 func (m model) handleShowCommand(cmd string) (model, tea.Cmd) {
@@ -1584,47 +2738,72 @@ func (m model) handleShowCommand(cmd string) (model, tea.Cmd) {
 	}
 
 	address := parts[1]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
 	return m.showApplicationDetails(address)
 }
 
+// detailsNetwork returns the network the details view queries.
+func (m model) detailsNetwork() (Network, error) {
+	if m.config == nil {
+		return Network{}, fmt.Errorf("config not loaded")
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return Network{}, fmt.Errorf("network not found: %s", m.currentNetwork)
+	}
+	return network, nil
+}
+
 func (m model) loadApplicationDetailsCmd(address string) tea.Cmd {
 	return func() tea.Msg {
-		if m.config == nil {
-			return applicationDetailsLoadedMsg{
-				address: address,
-				err:     fmt.Errorf("config not loaded"),
-			}
+		network, err := m.detailsNetwork()
+		if err != nil {
+			return applicationDetailsLoadedMsg{address: address, err: err}
 		}
 
-		network, exists := m.config.Config.Networks[m.currentNetwork]
-		if !exists {
+		var appDetails string
+		err = withFailover(m.currentNetwork, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			appDetails, err = queryApplicationDetails(address, endpoint, m.currentNetwork, m.config.keyringBackend(m.currentNetwork), m.config.pocketdHome(m.currentNetwork))
+			return err
+		})
+		if err != nil {
 			return applicationDetailsLoadedMsg{
 				address: address,
-				err:     fmt.Errorf("network not found: %s", m.currentNetwork),
+				err:     fmt.Errorf("failed to query application details: %v", err),
 			}
 		}
+		return applicationDetailsLoadedMsg{
+			address:    address,
+			appDetails: appDetails,
+		}
+	}
+}
 
-		// Query application details
-		appDetails, err := queryApplicationDetails(address, network.RPCEndpoint, m.currentNetwork, m.config.Config.KeyringBackend, m.config.Config.PocketdHome)
+func (m model) loadDetailsBalancesCmd(address string) tea.Cmd {
+	return func() tea.Msg {
+		network, err := m.detailsNetwork()
 		if err != nil {
-			return applicationDetailsLoadedMsg{
-				address: address,
-				err:     fmt.Errorf("failed to query application details: %v", err),
-			}
+			return detailsBalancesLoadedMsg{address: address, err: err}
 		}
 
-		// Query bank balances
-		bankBalance, err := queryBankBalances(address, network.RPCEndpoint, m.currentNetwork, m.config.Config.KeyringBackend, m.config.Config.PocketdHome)
+		var bankBalance string
+		err = withFailover(m.currentNetwork, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			bankBalance, err = queryBankBalances(address, endpoint, m.currentNetwork, m.config.keyringBackend(m.currentNetwork), m.config.pocketdHome(m.currentNetwork))
+			return err
+		})
 		if err != nil {
-			return applicationDetailsLoadedMsg{
+			return detailsBalancesLoadedMsg{
 				address: address,
 				err:     fmt.Errorf("failed to query bank balances: %v", err),
 			}
 		}
-
-		return applicationDetailsLoadedMsg{
+		return detailsBalancesLoadedMsg{
 			address:     address,
-			appDetails:  appDetails,
 			bankBalance: bankBalance,
 		}
 	}
@@ -1650,12 +2829,10 @@ func queryApplicationDetails(address, rpcEndpoint, networkName, keyringBackend,
 	if pocketdHome != "" {
 		args = append(args, "--home="+pocketdHome)
 	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
+		args = append(args, "--home="+defaultPocketdHome())
 	}
 
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
+	output, err := runPocketd(args)
 	if err != nil {
 		return "", fmt.Errorf("query failed: %v, output: %s", err, string(output))
 	}
@@ -1683,12 +2860,10 @@ func queryBankBalances(address, rpcEndpoint, networkName, keyringBackend, pocket
 	if pocketdHome != "" {
 		args = append(args, "--home="+pocketdHome)
 	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
+		args = append(args, "--home="+defaultPocketdHome())
 	}
 
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
+	output, err := runPocketd(args)
 	if err != nil {
 		return "", fmt.Errorf("query failed: %v, output: %s", err, string(output))
 	}
@@ -1700,6 +2875,30 @@ func (m model) updateApplicationDetails(msg tea.KeyMsg) (model, tea.Cmd) {
 	switch msg.String() {
 	case "esc", "q":
 		m.state = stateTable
+	case "s":
+		if !m.detailsLoading && !m.sessionsLoading {
+			return m.refreshSessions()
+		}
+	case "a":
+		if !m.activityLoading {
+			return m, m.refreshActivity()
+		}
+	case "r":
+		if !m.detailsLoading && !m.balancesLoading {
+			return m.reloadApplicationDetails()
+		}
+	case "u":
+		return m.prefillAppCommand("u"), nil
+	case "f":
+		return m.prefillAppCommand("f"), nil
+	case "d":
+		return m.prefillAppCommand("delegate"), nil
+	case "x":
+		return m.prefillAppCommand("unstake"), nil
+	case "[", "left":
+		return m.stepApplicationDetails(-1)
+	case "]", "right":
+		return m.stepApplicationDetails(1)
 	}
 	return m, nil
 }
@@ -1718,28 +2917,61 @@ func (m model) renderApplicationDetails() string {
 		Padding(1, 2).
 		Width(m.width - 4)
 
-	if m.detailsLoading {
-		loadingStyle := lipgloss.NewStyle().
-			Foreground(lipgloss.Color("220")). // Bold yellow
-			Bold(true).
-			Align(lipgloss.Center).
-			Width(m.width)
-		return loadingStyle.Render("🔄 Loading application details...")
-	}
+	loadingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(1, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(1, 2).
+		Width(m.width - 4)
 
 	// Header with address
 	headerText := fmt.Sprintf("📮 APPLICATION DETAILS - %s", m.selectedAppAddress)
+	if i := slices.IndexFunc(m.applications, func(app Application) bool { return app.Address == m.selectedAppAddress }); i >= 0 {
+		headerText += fmt.Sprintf(" (%d of %d)", i+1, len(m.applications))
+	}
 	header := headerStyle.Render(headerText)
 
+	historyHeader := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")).
+		Bold(true).
+		Render("📈 HISTORY")
+
+	sessionHeader := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")).
+		Bold(true).
+		Render("🛰️ CURRENT SESSION")
+
+	activityHeader := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")).
+		Bold(true).
+		Render("🕘 RECENT ACTIVITY")
+
+	delegationsHeader := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")).
+		Bold(true).
+		Render("🔗 DELEGATIONS")
+
 	// Application details section
 	appDetailsHeader := lipgloss.NewStyle().
 		Foreground(lipgloss.Color("150")).
 		Bold(true).
 		Render("ℹ️ Application Information:")
 
-	// Pretty print the JSON for application details
-	prettyAppDetails := m.prettyPrintJSON(m.applicationDetails)
-	appDetailsContent := contentStyle.Render(prettyAppDetails)
+	// Each query fills its own section as it returns
+	var appDetailsContent string
+	sessionsContent := m.renderSessions()
+	switch {
+	case m.detailsLoading:
+		appDetailsContent = loadingStyle.Render(m.spinner() + " Querying the application...")
+		sessionsContent = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("108")).
+			Render("  " + m.spinner() + " Waiting for the services of the application...")
+	case m.detailsErr != nil:
+		appDetailsContent = errorStyle.Render(m.detailsErr.Error())
+	default:
+		appDetailsContent = contentStyle.Render(m.prettyPrintJSON(m.applicationDetails))
+	}
 
 	// Bank balances section
 	bankHeader := lipgloss.NewStyle().
@@ -1747,7 +2979,15 @@ func (m model) renderApplicationDetails() string {
 		Bold(true).
 		Render("💰 BANK BALANCES")
 
-	bankContent := contentStyle.Render(m.bankBalances)
+	var bankContent string
+	switch {
+	case m.balancesLoading:
+		bankContent = loadingStyle.Render(m.spinner() + " Querying bank balances...")
+	case m.balancesErr != nil:
+		bankContent = errorStyle.Render(m.balancesErr.Error())
+	default:
+		bankContent = contentStyle.Render(m.bankBalances)
+	}
 
 	// Instructions
 	instructions := lipgloss.NewStyle().
@@ -1755,9 +2995,13 @@ func (m model) renderApplicationDetails() string {
 		Italic(true).
 		Align(lipgloss.Center).
 		Width(m.width).
-		Render("Press ESC to return to main view")
+		Render("Press [/] for the previous/next application • U/F/D/X to upstake, fund, delegate or unstake • R to reload everything • S to refresh the session • A to refresh the activity • ESC to return to main view")
 
 	content := header + "\n\n" +
+		historyHeader + "\n" + m.renderHistory(m.selectedAppAddress) + "\n\n" +
+		sessionHeader + "\n" + sessionsContent + "\n\n" +
+		activityHeader + "\n" + m.renderActivity() + "\n\n" +
+		delegationsHeader + "\n" + m.renderDelegations(m.selectedAppAddress) + "\n\n" +
 		appDetailsHeader + "\n" + appDetailsContent + "\n\n" +
 		bankHeader + "\n" + bankContent + "\n\n" +
 		instructions
@@ -1796,34 +3040,30 @@ func (m model) handleFundCommand(cmd string) (model, tea.Cmd) {
 
 	address := parts[1]
 	amountStr := parts[2]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
 
 	// Validate amount is numeric
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
-	if err != nil || amount <= 0 {
-		m.err = fmt.Errorf("amount must be a positive integer: %s", amountStr)
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		m.err = err
 		return m, nil
 	}
 
 	// Execute fund in background
-	return m, m.executeFund(address, amount)
+	txID := m.trackTx("fund", cmd, []string{address}, amount)
+	return m, m.submitTracked(cmd, m.executeFund(txID, address, amount), txID)
 }
 
-func (m model) executeFund(address string, amount int64) tea.Cmd {
+func (m model) executeFund(txID int, address string, amount int64) tea.Cmd {
 	return func() tea.Msg {
 		txHash, err := fundApplication(address, amount, m.config, m.currentNetwork)
 		if err != nil {
-			// Check if this is a transaction error with hash
-			if strings.Contains(err.Error(), "transaction failed with hash") {
-				parts := strings.Split(err.Error(), ": ")
-				if len(parts) >= 2 {
-					hashPart := strings.TrimPrefix(parts[0], "transaction failed with hash ")
-					errorPart := strings.Join(parts[1:], ": ")
-					return transactionErrorMsg{txHash: hashPart, error: errorPart}
-				}
-			}
-			return fmt.Sprintf("Fund failed: %v", err)
+			return newTxFailedMsg(txID, "fund", []string{address}, amount, err)
 		}
-		return fundCompletedMsg{txHash: txHash}
+		return fundCompletedMsg{txID: txID, txHash: txHash}
 	}
 }
 
@@ -1869,22 +3109,33 @@ func (m model) renderUpstakeAllReceipts() string {
 		content = append(content, loadingStyle.Render("🔄 PROCESSING UPSTAKE TRANSACTIONS..."))
 		content = append(content, receiptStyle.Render("Please wait while we upstake all applications."))
 	} else {
+		var added int64
+		sent := 0
 		for i, receipt := range m.upstakeAllReceipts {
 			var line string
 			if receipt.error != "" {
-				line = fmt.Sprintf("%d. %s - ERROR: %s",
+				line = fmt.Sprintf("%d. %s - stake %s %s unchanged - ERROR: %s",
 					i+1,
 					TruncateAddress(receipt.appAddress, 42),
+					m.formatAmount(receipt.previousStake), m.unitLabel(),
 					receipt.error)
 				content = append(content, errorStyle.Render(line))
 			} else {
-				line = fmt.Sprintf("%d. %s - TX: %s",
+				line = fmt.Sprintf("%d. %s - +%s: %s → %s %s - TX: %s",
 					i+1,
 					TruncateAddress(receipt.appAddress, 42),
+					m.formatAmount(receipt.amount),
+					m.formatAmount(receipt.previousStake),
+					m.formatAmount(receipt.newStake), m.unitLabel(),
 					receipt.txHash)
 				content = append(content, successStyle.Render(line))
+				added += receipt.amount
+				sent++
 			}
 		}
+		content = append(content, "")
+		content = append(content, receiptStyle.Render(fmt.Sprintf("%d of %d upstakes sent, %s %s added in total",
+			sent, len(m.upstakeAllReceipts), m.formatAmount(added), m.unitLabel())))
 	}
 
 	content = append(content, "")
@@ -1894,70 +3145,182 @@ func (m model) renderUpstakeAllReceipts() string {
 }
 
 func (m model) handleUpstakeAllCommand(cmd string) (model, tea.Cmd) {
+	cmd, estimate := cutEstimateFlag(cmd)
 	parts := strings.Fields(cmd)
 	if len(parts) < 2 {
-		m.err = fmt.Errorf("usage: ua <amount> or upstake-all <amount> (each app gets <amount> added to current stake)")
+		m.err = fmt.Errorf("usage: ua[!] <amount|@file> [--scope=configured|displayed|selected] [--include-unstaking] [--estimate] or upstake-all[!] ... (each app gets <amount> added to current stake, ! skips the balance check)")
 		return m, nil
 	}
 
 	amountStr := parts[1]
+	includeUnstaking := false
+	scope := ""
+	for _, flag := range parts[2:] {
+		switch {
+		case flag == "--include-unstaking":
+			includeUnstaking = true
+		case strings.HasPrefix(flag, "--scope="):
+			scope = strings.TrimPrefix(flag, "--scope=")
+			if !slices.Contains(upstakeScopes, scope) {
+				m.err = fmt.Errorf("unknown scope: %s (use %s)", scope, strings.Join(upstakeScopes, ", "))
+				return m, nil
+			}
+		default:
+			m.err = fmt.Errorf("unknown flag: %s", flag)
+			return m, nil
+		}
+	}
+	if strings.HasPrefix(amountStr, "@") {
+		if scope != "" || estimate {
+			m.err = fmt.Errorf("--scope and --estimate do not apply to amounts files")
+			return m, nil
+		}
+		return m.handleAmountsFileCommand(cmd, planUpstake, includeUnstaking)
+	}
 
 	// Validate amount is numeric
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
-	if err != nil || amount <= 0 {
-		m.err = fmt.Errorf("amount must be a positive integer: %s", amountStr)
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		m.err = err
 		return m, nil
 	}
 
-	// Show processing message first, then execute upstake all
-	m.loading = true // This will show the processing message in main view
-	m.processingUpstakeAll = true // Flag to show upstake processing message
-	m.upstakeAllReceipts = []UpstakeReceipt{} // Clear previous receipts
-	return m, tea.Batch(
-		tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
-			return "switch_to_receipts"
-		}),
-		m.executeUpstakeAll(amount),
-	)
+	// Simulate one upstake before running the batch
+	if estimate {
+		confirm := upstakeAllConfirm{scope: scope, includeUnstaking: includeUnstaking}
+		if scope == "" {
+			confirm.scope = upstakeScopeConfigured
+		}
+		targets, _, err := m.upstakeAllTargets(confirm)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		return m.startGasEstimate(cmd, "upstake-all", amount, targets)
+	}
+
+	// An explicit scope shows its targets for confirmation first
+	if scope != "" {
+		m.upstakeAll = &upstakeAllConfirm{
+			verb:             parts[0],
+			amountArg:        amountStr,
+			amount:           amount,
+			scope:            scope,
+			includeUnstaking: includeUnstaking,
+		}
+		m.state = stateUpstakeAllConfirm
+		return m, nil
+	}
+	return m.startUpstakeAll(cmd, amount, upstakeScopeConfigured, includeUnstaking, strings.HasSuffix(parts[0], "!"))
+}
+
+// startUpstakeAll submits the upstake of every application in scope by
+// amount. force skips the balance and minimum stake checks.
+func (m model) startUpstakeAll(cmd string, amount int64, scope string, includeUnstaking, force bool) (model, tea.Cmd) {
+	addresses := m.upstakeScopeAddresses(scope)
+	var notices []tea.Cmd
+	// Apps with a pending unstake are left alone unless explicitly included
+	if !includeUnstaking {
+		var skipped []string
+		addresses, skipped = m.withoutUnstaking(addresses)
+		if len(skipped) > 0 {
+			notices = append(notices, m.notify(toastInfo, fmt.Sprintf("Skipping %d unstaking apps (--include-unstaking to upstake them): %s", len(skipped), describeSkipped(skipped))))
+		}
+		if len(addresses) == 0 {
+			m.err = fmt.Errorf("every %s app is unstaking (use --include-unstaking to upstake them)", scope)
+			return m, nil
+		}
+	}
+	if unmanaged := m.unmanagedApplications(); scope == upstakeScopeConfigured && len(unmanaged) > 0 {
+		notices = append(notices, m.notify(toastInfo, fmt.Sprintf("Skipping %d unmanaged apps not in config (:adopt to add them, or --scope=displayed): %s", len(unmanaged), describeSkipped(unmanaged))))
+	}
+
+	// Refuse batches that would fail midway unless overridden with "!"
+	if !force && m.config != nil {
+		if err := m.checkUpstakeAll(amount, addresses); err != nil {
+			verb := strings.Fields(cmd)[0]
+			return m, m.notify(toastError, fmt.Sprintf("Upstake all refused: %v (use %s! to override)", err, verb))
+		}
+	}
+
+	rows := make([]queuedRow, len(addresses))
+	for i, address := range addresses {
+		rows[i] = queuedRow{kind: "upstake-all", target: address, amount: amount, fee: m.txFeeUpokt()}
+	}
+	run := m.submit(cmd, rows, nil, false, func(m model) (model, tea.Cmd) {
+		// Show processing message first, then execute upstake all
+		m.loading = true // This will show the processing message in main view
+		m.processingUpstakeAll = true // Flag to show upstake processing message
+		m.upstakeAllRunning = true
+		m.upstakeAllReceipts = []UpstakeReceipt{} // Clear previous receipts
+		j := m.newUpstakeJob(amount, addresses)
+		return m, tea.Batch(
+			tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
+				return showUpstakeAllReceiptsMsg{}
+			}),
+			m.executeUpstakeAll(amount, addresses, j),
+			m.startSpinner(),
+		)
+	})
+	return m, tea.Batch(append(notices, run)...)
 }
 
-func (m model) executeUpstakeAll(amount int64) tea.Cmd {
+func (m model) executeUpstakeAll(amount int64, addresses []string, j *job) tea.Cmd {
+	selected := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		selected[address] = true
+	}
+	var applications []Application
+	for _, app := range m.applications {
+		if selected[app.Address] {
+			applications = append(applications, app)
+		}
+	}
+	config, networkName := m.config, m.currentNetwork
 	return func() tea.Msg {
-		receipts := upstakeAllApplications(amount, m.config, m.currentNetwork, m.applications)
-		return upstakeAllCompletedMsg{receipts: receipts}
+		receipts := upstakeAllApplications(amount, config, networkName, applications)
+		return upstakeAllCompletedMsg{
+			receipts: receipts,
+			amount:   amount,
+			network:  networkName,
+			unsent:   upstakeResumeItems(applications, receipts, amount),
+			job:      j,
+		}
 	}
 }
 
+// upstakeAllApplications upstakes each of applications, already narrowed to
+// the scope of the command, one at a time.
 func upstakeAllApplications(amount int64, config *Config, networkName string, applications []Application) []UpstakeReceipt {
 	var receipts []UpstakeReceipt
 	
-	// Get the configured applications list for the current network
-	network, exists := config.Config.Networks[networkName]
-	if !exists {
+	if _, exists := config.Config.Networks[networkName]; !exists {
 		return receipts // Return empty if network not found
 	}
 	
-	// Create a map of configured application addresses for fast lookup
-	configuredApps := make(map[string]bool)
-	for _, addr := range network.Applications {
-		configuredApps[addr] = true
-	}
-	
-	// Only process applications that are in the config
+	ctx := operations.context()
 	for _, app := range applications {
-		if !configuredApps[app.Address] {
-			continue // Skip applications not in config
+		stake := stakeUpokt(app)
+		if ctx.Err() != nil {
+			receipts = append(receipts, UpstakeReceipt{appAddress: app.Address, error: errCancelled.Error(), previousStake: stake, newStake: stake})
+			continue
 		}
 		
-		txHash, err := upstakeApplication(app.Address, app.ServiceID, amount, config, networkName)
+		txHash, previousStake, err := upstakeApplication(app.Address, app.ServiceIDs, amount, config, networkName)
 		receipt := UpstakeReceipt{
-			appAddress: app.Address,
+			appAddress:    app.Address,
+			amount:        amount,
+			previousStake: stake,
+			newStake:      stake,
 		}
 		
 		if err != nil {
 			receipt.error = err.Error()
 		} else {
+			// The stake read from chain, which the cached row may lag
 			receipt.txHash = txHash
+			receipt.previousStake = previousStake
+			receipt.newStake = previousStake + amount
 		}
 		
 		receipts = append(receipts, receipt)
@@ -1981,104 +3344,133 @@ func fundApplication(address string, amount int64, config *Config, networkName s
 		return "", fmt.Errorf("bank address not configured for network: %s", networkName)
 	}
 
-	// Determine chain ID and node based on network
-	var chainID, node string
+	return bankSend(network.Bank, address, amount, config, networkName)
+}
+
+// bankSend sends amount upokt from one address to another, signed by from.
+func bankSend(from, to string, amount int64, config *Config, networkName string) (string, error) {
+	network := config.Config.Networks[networkName]
+
+	// Determine chain ID based on network
+	var chainID string
 	switch networkName {
 	case "pocket":
 		chainID = "pocket"
-		node = "https://shannon-grove-rpc.mainnet.poktroll.com"
 	case "pocket-beta":
 		chainID = "pocket-beta"
-		node = "https://shannon-testnet-grove-rpc.beta.poktroll.com"
 	default:
 		return "", fmt.Errorf("unsupported network: %s", networkName)
 	}
 
-	// Execute pocketd bank send command
-	amountWithDenom := fmt.Sprintf("%dupokt", amount)
-	args := []string{"tx", "bank", "send",
-		network.Bank,
-		address,
-		amountWithDenom,
-		"--node=" + node,
-		"--chain-id=" + chainID,
-		"--fees=20000upokt"}
-
-	// Add optional pocketd home flag (only if specified in config)
-	if config.Config.PocketdHome != "" {
-		args = append(args, "--home="+config.Config.PocketdHome)
-	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
-	}
-
-	// Add keyring-backend if specified
-	if config.Config.KeyringBackend != "" {
-		args = append(args, "--keyring-backend="+config.Config.KeyringBackend)
-	}
-
-	args = append(args, "-y")
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return "", fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
-	}
-
-	// Parse transaction hash and check for errors
-	outputStr := string(output)
-	txHash, rawLog, err := parsePocketdOutput(outputStr)
+	var output []byte
+	err := withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		// Execute pocketd bank send command
+		amountWithDenom := fmt.Sprintf("%dupokt", amount)
+		args := []string{"tx", "bank", "send",
+			from,
+			to,
+			amountWithDenom,
+			"--node=" + node,
+			"--chain-id=" + chainID,
+			network.feesArg()}
+		args = append(args, memoArgs(config)...)
+
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
+
+		args = append(args, "-y")
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		}
+		return nil
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to parse pocketd output: %v", err)
-	}
-
-	// Check if there's an error in raw_log
-	if rawLog != "" && (strings.Contains(rawLog, "failed") || strings.Contains(rawLog, "error") || strings.Contains(rawLog, "insufficient") || strings.Contains(rawLog, "out of gas")) {
-		return "", fmt.Errorf("transaction failed with hash %s: %s", txHash, rawLog)
+		return "", err
 	}
 
-	return txHash, nil
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
 }
 
 func (m model) handleFundAllCommand(cmd string) (model, tea.Cmd) {
+	cmd, estimate := cutEstimateFlag(cmd)
 	parts := strings.Fields(cmd)
 	if len(parts) < 2 {
-		m.err = fmt.Errorf("usage: fa <amount> or fund-all <amount> (each app receives <amount> tokens)")
+		m.err = fmt.Errorf("usage: fa[!] <amount|@file> [--estimate] or fund-all[!] <amount|@file> [--estimate] (each app receives <amount> tokens, ! skips the balance check)")
 		return m, nil
 	}
 
 	amountStr := parts[1]
+	if strings.HasPrefix(amountStr, "@") {
+		if estimate {
+			m.err = fmt.Errorf("--estimate does not apply to amounts files")
+			return m, nil
+		}
+		return m.handleAmountsFileCommand(cmd, planFund, false)
+	}
 
 	// Validate amount is numeric
-	amount, err := strconv.ParseInt(amountStr, 10, 64)
-	if err != nil || amount <= 0 {
-		m.err = fmt.Errorf("amount must be a positive integer: %s", amountStr)
+	amount, err := parseAmount(amountStr)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if m.config == nil {
+		m.err = fmt.Errorf("config not loaded")
 		return m, nil
 	}
+	var addresses []string
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists {
+		addresses = network.applicationsFor(m.currentGateway)
+	}
+	if len(addresses) == 0 {
+		return m, m.notify(toastError, "Fund all failed: no applications configured for network: "+m.currentNetwork)
+	}
+
+	// Simulate the multi-sends before running them
+	if estimate {
+		return m.startGasEstimate(cmd, "fund-all", amount, addresses)
+	}
+
+	// Refuse batches the bank cannot cover unless overridden with "!"
+	if !strings.HasSuffix(parts[0], "!") {
+		if err := m.checkFundAll(amount, len(addresses)); err != nil {
+			return m, m.notify(toastError, fmt.Sprintf("Fund all refused: %v (use %s! to override)", err, parts[0]))
+		}
+	}
 
-	// Execute fund all in background
-	return m, m.executeFundAll(amount)
+	// Execute fund all in background, in multi-sends of at most
+	// multisend-chunk recipients
+	chunks := chunkAddresses(addresses, m.config.multiSendChunk())
+	txIDs := make([]int, len(chunks))
+	for i, recipients := range chunks {
+		txIDs[i] = m.trackTx("fund-all", cmd, recipients, amount)
+	}
+	m.fundAllReceipt = m.newFundAllReceipt(txIDs, amount, chunks)
+	m.watchBatch("fund-all", cmd, txIDs)
+	if len(chunks) == 1 {
+		return m, m.submitTracked(cmd, m.executeFundAll(txIDs[0], amount, addresses), txIDs...)
+	}
+	logger.Info("fund-all split into chunks", "network", m.currentNetwork, "recipients", len(addresses), "chunks", len(chunks))
+	run := func() tea.Msg {
+		return waitForFundChunkCmd(m.runFundAllChunks(txIDs, amount, chunks))()
+	}
+	return m, m.submitTracked(cmd, run, txIDs...)
 }
 
-func (m model) executeFundAll(amount int64) tea.Cmd {
+func (m model) executeFundAll(txID int, amount int64, addresses []string) tea.Cmd {
 	return func() tea.Msg {
-		txHash, err := fundAllApplications(amount, m.config, m.currentNetwork)
+		txHash, err := fundAllApplications(amount, addresses, m.config, m.currentNetwork)
 		if err != nil {
-			// Check if this is a transaction error with hash
-			if strings.Contains(err.Error(), "transaction failed with hash") {
-				parts := strings.Split(err.Error(), ": ")
-				if len(parts) >= 2 {
-					hashPart := strings.TrimPrefix(parts[0], "transaction failed with hash ")
-					errorPart := strings.Join(parts[1:], ": ")
-					return transactionErrorMsg{txHash: hashPart, error: errorPart}
-				}
-			}
-			return fmt.Sprintf("Fund failed: %v", err)
+			return newTxFailedMsg(txID, "fund-all", addresses, amount*int64(len(addresses)), err)
 		}
-		return fundCompletedMsg{txHash: txHash}
+		return fundCompletedMsg{txID: txID, txHash: txHash}
 	}
 }
 
-func fundAllApplications(amount int64, config *Config, networkName string) (string, error) {
+func fundAllApplications(amount int64, addresses []string, config *Config, networkName string) (string, error) {
 	if config == nil {
 		return "", fmt.Errorf("config not loaded")
 	}
@@ -2094,86 +3486,134 @@ func fundAllApplications(amount int64, config *Config, networkName string) (stri
 	}
 
 	// Check if there are any applications to fund
-	if len(network.Applications) == 0 {
+	if len(addresses) == 0 {
 		return "", fmt.Errorf("no applications configured for network: %s", networkName)
 	}
 
-	// Determine chain ID and node based on network
-	var chainID, node string
+	// Determine chain ID based on network
+	var chainID string
 	switch networkName {
 	case "pocket":
 		chainID = "pocket"
-		node = "https://shannon-grove-rpc.mainnet.poktroll.com"
 	case "pocket-beta":
 		chainID = "pocket-beta"
-		node = "https://shannon-testnet-grove-rpc.beta.poktroll.com"
 	default:
 		return "", fmt.Errorf("unsupported network: %s", networkName)
 	}
 
-	// Build the multi-send command arguments
-	// Format: pocketd tx bank multi-send [from_key_or_address] [to_address_1 to_address_2 ...] [amount] [flags]
-	args := []string{"tx", "bank", "multi-send", network.Bank}
-
-	// Add all application addresses from config as recipients
-	for _, appAddress := range network.Applications {
-		args = append(args, appAddress)
-	}
-
-	// Calculate total amount: amount per app * number of apps
-	// This ensures each app receives the specified amount when using --split
-	totalAmount := amount * int64(len(network.Applications))
-	amountWithDenom := fmt.Sprintf("%dupokt", totalAmount)
-	args = append(args, amountWithDenom)
+	var output []byte
+	err := withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		// Build the multi-send command arguments
+		// Format: pocketd tx bank multi-send [from_key_or_address] [to_address_1 to_address_2 ...] [amount] [flags]
+		args := []string{"tx", "bank", "multi-send", network.Bank}
 
-	// Add remaining flags
-	args = append(args,
-		"--node="+node,
-		"--chain-id="+chainID,
-		"--split",
-		"--yes",
-		"--gas=auto",
-		"--gas-prices=1upokt",
-		"--gas-adjustment=2.5")
-
-	// Add optional pocketd home flag (only if specified in config)
-	if config.Config.PocketdHome != "" {
-		args = append(args, "--home="+config.Config.PocketdHome)
-	} else {
-		args = append(args, "--home="+os.Getenv("HOME")+"/.pocket")
-	}
+		// Add the applications of the current gateway as recipients
+		for _, appAddress := range addresses {
+			args = append(args, appAddress)
+		}
 
-	// Add keyring-backend if specified
-	if config.Config.KeyringBackend != "" {
-		args = append(args, "--keyring-backend="+config.Config.KeyringBackend)
+		// Calculate total amount: amount per app * number of apps
+		// This ensures each app receives the specified amount when using --split
+		totalAmount := amount * int64(len(addresses))
+		amountWithDenom := fmt.Sprintf("%dupokt", totalAmount)
+		args = append(args, amountWithDenom)
+
+		// Add remaining flags
+		args = append(args,
+			"--node="+node,
+			"--chain-id="+chainID,
+			"--split",
+			"--yes",
+			"--gas=auto",
+			network.gasPricesArg(),
+			"--gas-adjustment=2.5")
+		args = append(args, memoArgs(config)...)
+
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
+
+		// Execute pocketd multi-send command
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s, command: %s", err, string(output), "pocketd "+strings.Join(args, " "))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
 	}
 
-	// Execute pocketd multi-send command
-	cmd := exec.Command("pocketd", args...)
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
+}
 
-	output, err := cmd.CombinedOutput()
+func main() {
+	logLevel := flag.String("log-level", "info", "log level: debug, info, warn or error")
+	logFile := flag.String("log-file", "", "write structured JSON logs to this file")
+	flag.BoolVar(&readOnlyFlag, "read-only", false, "disable every command that submits transactions")
+	profile := flag.String("profile", "", "use the config and local state of a profile in ~/.gasms/profiles/<name>")
+	flag.BoolVar(&freshFlag, "fresh", false, "start with the default network, gateway, sort and columns instead of the ones of the last session")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: gasms [flags] [plan | apply <plan.json> | resume [network] | scheduler | daemon | serve | approver-init [key-file] | approver-sign <challenge>]\n\nFlags:\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	closeLog, err := setupLogging(*logLevel, *logFile)
 	if err != nil {
-		return "", fmt.Errorf("pocketd command failed: %v, output: %s, command: %s", err, string(output), strings.Join(cmd.Args, " "))
+		log.Fatal(err)
 	}
+	defer closeLog()
 
-	// Parse transaction hash and check for errors
-	outputStr := string(output)
-	txHash, rawLog, err := parsePocketdOutput(outputStr)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse pocketd output: %v", err)
+	if err := selectProfile(*profile); err != nil {
+		log.Fatal(err)
 	}
-
-	// Check if there's an error in raw_log
-	if rawLog != "" && (strings.Contains(rawLog, "failed") || strings.Contains(rawLog, "error") || strings.Contains(rawLog, "insufficient") || strings.Contains(rawLog, "out of gas")) {
-		return "", fmt.Errorf("transaction failed with hash %s: %s", txHash, rawLog)
+	cleanTempFiles()
+
+	// Non-interactive subcommands
+	if flag.NArg() > 0 {
+		var err error
+		switch flag.Arg(0) {
+		case "plan":
+			err = runPlan(flag.Args()[1:])
+		case "apply":
+			err = runApply(flag.Args()[1:])
+		case "resume":
+			err = runResume(flag.Args()[1:])
+		case "scheduler":
+			err = runScheduler(flag.Args()[1:])
+		case "daemon":
+			err = runDaemon(flag.Args()[1:])
+		case "serve":
+			err = runServe(flag.Args()[1:])
+		case "approver-init":
+			err = runApproverInit(flag.Args()[1:])
+		case "approver-sign":
+			err = runApproverSign(flag.Args()[1:])
+		default:
+			flag.Usage()
+			os.Exit(2)
+		}
+		if err != nil {
+			logger.Error("command failed", "command", flag.Arg(0), "error", err)
+			closeLog()
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	return txHash, nil
-}
+	logger.Info("gasms started")
 
-func main() {
+	keyrings.enablePrompts()
 	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if err != nil {
+		logger.Error("gasms exited with error", "error", err)
 		log.Fatal(err)
 	}
+	if m, ok := final.(model); ok {
+		saveUIState(m)
+	}
+	logger.Info("gasms stopped")
 }
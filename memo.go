@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxMemoLength is the memo length accepted by the chain (the auth module's
+// max_memo_characters default).
+const maxMemoLength = 256
+
+// memoFlag introduces a per-command memo: everything after it is the memo.
+const memoFlag = " --memo "
+
+// memoArgs returns the flag attaching the memo of config to a transaction.
+func memoArgs(config *Config) []string {
+	if config.Config.Memo == "" {
+		return nil
+	}
+	return []string{"--note=" + config.Config.Memo}
+}
+
+// splitMemo separates a trailing "--memo <text>" from a command, e.g. "fa 100
+// --memo weekly top-up, ticket OPS-123".
+func splitMemo(cmd string) (rest, memo string) {
+	i := strings.Index(cmd, memoFlag)
+	if i < 0 {
+		return cmd, ""
+	}
+	return strings.TrimSpace(cmd[:i]), strings.TrimSpace(cmd[i+len(memoFlag):])
+}
+
+// validMemo checks that the chain will accept memo.
+func validMemo(memo string) error {
+	if n := utf8.RuneCountInString(memo); n > maxMemoLength {
+		return fmt.Errorf("memo is %d characters, the chain accepts at most %d", n, maxMemoLength)
+	}
+	return nil
+}
+
+// withMemo returns a copy of config whose transactions carry memo instead of
+// the configured one.
+func withMemo(config *Config, memo string) *Config {
+	override := *config
+	override.Config.Memo = memo
+	return &override
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxToasts bounds how many notifications are stacked at once; the oldest
+// is dropped first.
+const maxToasts = 5
+
+type toastLevel int
+
+const (
+	toastInfo toastLevel = iota
+	toastSuccess
+	toastWarning
+	toastError
+)
+
+// toast is a notification shown below the table until it expires.
+type toast struct {
+	id    int
+	level toastLevel
+	text  string
+}
+
+type toastExpiredMsg struct {
+	id int
+}
+
+// duration returns how long a toast of this level stays visible.
+func (l toastLevel) duration() time.Duration {
+	switch l {
+	case toastWarning, toastError:
+		return 15 * time.Second
+	default:
+		return 10 * time.Second
+	}
+}
+
+func (l toastLevel) icon() string {
+	switch l {
+	case toastSuccess:
+		return "✅"
+	case toastWarning:
+		return "⚠️"
+	case toastError:
+		return "❌"
+	default:
+		return "ℹ️"
+	}
+}
+
+func (l toastLevel) style() lipgloss.Style {
+	style := lipgloss.NewStyle().Bold(true)
+	switch l {
+	case toastSuccess:
+		return style.Foreground(lipgloss.Color("46")) // Bright green
+	case toastWarning:
+		return style.Foreground(lipgloss.Color("220")) // Yellow
+	case toastError:
+		return style.Foreground(lipgloss.Color("196")) // Bright red
+	default:
+		return style.Foreground(lipgloss.Color("117")) // Light blue
+	}
+}
+
+// notify queues a toast and returns the command that expires it. Errors are
+// also kept in the error history.
+func (m *model) notify(level toastLevel, text string) tea.Cmd {
+	if level == toastError {
+		m.recordError(text)
+	}
+	m.nextToastID++
+	id := m.nextToastID
+	m.toasts = append(m.toasts, toast{id: id, level: level, text: text})
+	if len(m.toasts) > maxToasts {
+		m.toasts = m.toasts[len(m.toasts)-maxToasts:]
+	}
+	return tea.Tick(level.duration(), func(t time.Time) tea.Msg {
+		return toastExpiredMsg{id: id}
+	})
+}
+
+func (m *model) expireToast(id int) {
+	for i, t := range m.toasts {
+		if t.id == id {
+			m.toasts = append(m.toasts[:i:i], m.toasts[i+1:]...)
+			return
+		}
+	}
+}
+
+// renderToasts renders the active notifications, oldest first.
+func (m model) renderToasts() string {
+	if len(m.toasts) == 0 {
+		return ""
+	}
+	lines := make([]string, 0, len(m.toasts))
+	for _, t := range m.toasts {
+		line := truncateToWidth(t.level.icon()+" "+t.text, max(m.width, 20))
+		lines = append(lines, t.level.style().
+			Align(lipgloss.Center).
+			Width(m.width).
+			Render(line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// Steps of an onboarding, in order
+const (
+	onboardFund     = "fund"
+	onboardStake    = "stake"
+	onboardDelegate = "delegate"
+	onboardVerify   = "verify"
+	onboardConfig   = "config"
+)
+
+const onboardUsage = "onboard <address> <service_id> <stake>"
+
+// applicationTx signs and broadcasts "tx application <args>" from the
+// application address and returns the transaction hash.
+func applicationTx(address string, txArgs []string, config *Config, networkName string) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("config not loaded")
+	}
+	network, exists := config.Config.Networks[networkName]
+	if !exists {
+		return "", fmt.Errorf("network not found: %s", networkName)
+	}
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return "", err
+	}
+
+	var output []byte
+	err = withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		args := append([]string{"tx", "application"}, txArgs...)
+		args = append(args,
+			"--from="+address,
+			"--node="+node,
+			"--chain-id="+chainID,
+			network.feesArg())
+		args = append(args, feeGranterArgs(network)...)
+		args = append(args, memoArgs(config)...)
+
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
+
+		args = append(args, "-y")
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
+}
+
+// delegateToGateway delegates the application address to gateway, signed by
+// the application.
+func delegateToGateway(address, gateway string, config *Config, networkName string) (string, error) {
+	return applicationTx(address, []string{"delegate-to-gateway", gateway}, config, networkName)
+}
+
+// handleOnboardCommand stages "onboard <address> <service_id> <stake>", or
+// shows the last onboarding when no arguments are given.
+func (m model) handleOnboardCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) == 1 {
+		return m.showLastJob(jobOnboard, onboardUsage)
+	}
+	if len(parts) != 4 {
+		m.err = fmt.Errorf("usage: %s", onboardUsage)
+		return m, nil
+	}
+	address, service := parts[1], parts[2]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+	stake, err := parseAmount(parts[3])
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := m.checkMinStake(0, stake); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.config == nil || m.currentGateway == "" {
+		m.err = fmt.Errorf("no gateway selected")
+		return m, nil
+	}
+	if err := m.checkJobFree(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	j := m.newJob(jobOnboard, address,
+		onboardFund, "Fund the stake and fees from the bank",
+		onboardStake, "Stake for "+service,
+		onboardDelegate, "Delegate to "+TruncateAddress(m.currentGateway, 20),
+		onboardVerify, "Verify the on-chain state",
+		onboardConfig, "Add to "+configFile,
+	)
+	j.service = service
+	j.stake = stake
+	return m.showJob(j)
+}
+
+// onboardStep runs the current onboarding step against the queried state.
+func (m *model) onboardStep(j *job, msg jobStateMsg) tea.Cmd {
+	appFee := m.config.Config.Networks[j.network].appFeeUpokt()
+	delegated := msg.app != nil && slices.Contains(msg.app.DelegateeGateways, j.gateway)
+
+	switch j.steps[j.current].name {
+	case onboardFund:
+		// The application pays the stake and the fees of its own transactions
+		need := int64(0)
+		if msg.app == nil {
+			need += j.stake + appFee
+		}
+		if !delegated {
+			need += appFee
+		}
+		if msg.balance >= need {
+			return m.finishJobStep(j, fmt.Sprintf("balance %s %s covers the stake and fees", m.formatAmount(msg.balance), m.unitLabel()))
+		}
+		amount := need - msg.balance
+		command := fmt.Sprintf("fund %s %d", j.address, amount)
+		return m.submitJobTx(j, "fund", command, amount, func(config *Config, network string) (string, error) {
+			return fundApplication(j.address, amount, config, network)
+		})
+
+	case onboardStake:
+		if msg.app != nil {
+			if !slices.Contains(msg.app.ServiceIDs, j.service) {
+				return m.failJobStep(j, fmt.Sprintf("staked for %s; add %s with :svc %s +%s",
+					joinServiceIDs(msg.app.ServiceIDs), j.service, j.address, j.service))
+			}
+			return m.finishJobStep(j, fmt.Sprintf("staked %s %s for %s", m.formatAmount(stakeUpokt(*msg.app)), m.unitLabel(), j.service))
+		}
+		command := fmt.Sprintf("onboard stake %s %s %d", j.address, j.service, j.stake)
+		return m.submitJobTx(j, "stake", command, j.stake, func(config *Config, network string) (string, error) {
+			return stakeApplication(j.address, []string{j.service}, j.stake, nil, config, network)
+		})
+
+	case onboardDelegate:
+		if msg.app == nil {
+			return m.failJobStep(j, "the application is not staked yet")
+		}
+		if delegated {
+			return m.finishJobStep(j, "delegated to "+TruncateAddress(j.gateway, 20))
+		}
+		if limit := m.maxDelegations(); limit > 0 && int64(len(msg.app.DelegateeGateways)) >= limit {
+			return m.failJobStep(j, fmt.Sprintf("already delegates to %d gateways, the max_delegated_gateways", limit))
+		}
+		command := fmt.Sprintf("onboard delegate %s %s", j.address, j.gateway)
+		return m.submitJobTx(j, "delegate", command, 0, func(config *Config, network string) (string, error) {
+			return delegateToGateway(j.address, j.gateway, config, network)
+		})
+
+	case onboardVerify:
+		switch {
+		case msg.app == nil:
+			return m.failJobStep(j, "the application is not staked")
+		case !slices.Contains(msg.app.ServiceIDs, j.service):
+			return m.failJobStep(j, "the application is not staked for "+j.service)
+		case !delegated:
+			return m.failJobStep(j, "the application is not delegated to "+TruncateAddress(j.gateway, 20))
+		}
+		return m.finishJobStep(j, fmt.Sprintf("staked %s %s for %s, delegated to %s",
+			m.formatAmount(stakeUpokt(*msg.app)), m.unitLabel(), j.service, TruncateAddress(j.gateway, 20)))
+
+	case onboardConfig:
+		if slices.Contains(m.config.Config.Networks[j.network].Applications, j.address) {
+			return m.finishJobStep(j, "already in "+configFile)
+		}
+		network, gateway := j.network, j.gateway
+		entries := []importEntry{{Address: j.address}}
+		return m.saveJobConfig(j, fmt.Sprintf("Added %s to %s", TruncateAddress(j.address, 20), network), func(root *yaml.Node) error {
+			return importApplications(root, network, gateway, entries)
+		})
+	}
+	return nil
+}
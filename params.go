@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// applicationParams are the application module parameters.
+type applicationParams struct {
+	MinStakeUpokt        int64
+	MaxDelegatedGateways int64
+}
+
+// moduleParams are the on-chain parameters GASMS validates against.
+type moduleParams struct {
+	application applicationParams
+	shared      sharedParams
+	loadedAt    time.Time
+}
+
+type paramsLoadedMsg struct {
+	network string
+	params  moduleParams
+	err     error
+}
+
+// QueryApplicationParams returns the application module parameters.
+func QueryApplicationParams(rpcEndpoint, pocketdHome, networkName string) (applicationParams, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return applicationParams{}, err
+	}
+	args := []string{"q", "application", "params", "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return applicationParams{}, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Params struct {
+			MaxDelegatedGateways flexInt `json:"max_delegated_gateways"`
+			MinStake             struct {
+				Amount flexInt `json:"amount"`
+			} `json:"min_stake"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return applicationParams{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return applicationParams{
+		MinStakeUpokt:        int64(response.Params.MinStake.Amount),
+		MaxDelegatedGateways: int64(response.Params.MaxDelegatedGateways),
+	}, nil
+}
+
+// queryModuleParams fetches the application and shared module parameters.
+func queryModuleParams(networkName, rpcEndpoint, pocketdHome string) (moduleParams, error) {
+	params := moduleParams{loadedAt: time.Now()}
+	err := withFailover(networkName, rpcEndpoint, func(endpoint string) error {
+		var err error
+		params.application, err = QueryApplicationParams(endpoint, pocketdHome, networkName)
+		return err
+	})
+	if err != nil {
+		return params, err
+	}
+	err = withFailover(networkName, rpcEndpoint, func(endpoint string) error {
+		var err error
+		params.shared, err = QuerySharedParams(endpoint, pocketdHome, networkName)
+		return err
+	})
+	return params, err
+}
+
+func loadParamsCmd(network, rpcEndpoint, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		params, err := queryModuleParams(network, rpcEndpoint, pocketdHome)
+		return paramsLoadedMsg{network: network, params: params, err: err}
+	}
+}
+
+// refreshParams queries the parameters of the current network.
+func (m *model) refreshParams() tea.Cmd {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil
+	}
+	m.paramsNetwork = m.currentNetwork
+	m.paramsLoading = true
+	return tea.Batch(loadParamsCmd(m.currentNetwork, network.RPCEndpoint, m.config.pocketdHome(m.currentNetwork)), m.startSpinner())
+}
+
+// applyParams stores parameters loaded for the current network.
+func (m *model) applyParams(msg paramsLoadedMsg) {
+	if msg.network != m.paramsNetwork {
+		return
+	}
+	m.paramsLoading = false
+	m.paramsErr = msg.err
+	if msg.err != nil {
+		logger.Error("failed to load module params", "network", msg.network, "error", msg.err)
+		return
+	}
+	params := msg.params
+	m.params = &params
+	logger.Debug("module params loaded", "network", msg.network, "min_stake_upokt", params.application.MinStakeUpokt)
+}
+
+// minStake returns the on-chain minimum application stake, or 0 while the
+// parameters of the current network are unknown.
+func (m model) minStake() int64 {
+	if m.params == nil || m.paramsNetwork != m.currentNetwork {
+		return 0
+	}
+	return m.params.application.MinStakeUpokt
+}
+
+// checkMinStake refuses an upstake that would leave the stake below the
+// on-chain minimum, which the chain rejects after charging the fee.
+func (m model) checkMinStake(current, amount int64) error {
+	if minStake := m.minStake(); current+amount < minStake {
+		return fmt.Errorf("resulting stake %s %s is below the minimum stake of %s %s",
+			m.formatAmount(current+amount), m.unitLabel(), m.formatAmount(minStake), m.unitLabel())
+	}
+	return nil
+}
+
+func (m model) updateParams(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "r":
+		return m, m.refreshParams()
+	}
+	return m, nil
+}
+
+// renderParams shows the on-chain parameters that affect stake management.
+func (m model) renderParams() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("⚙️  MODULE PARAMS • %s", m.currentNetwork)), ""}
+
+	switch {
+	case m.paramsLoading && m.params == nil:
+		content = append(content, textStyle.Render(m.spinner()+" Querying module params..."))
+	case m.params == nil:
+		if m.paramsErr != nil {
+			content = append(content, errorStyle.Render(fmt.Sprintf("Failed to query module params: %v", m.paramsErr)))
+		} else {
+			content = append(content, textStyle.Render("Module params are loaded with the applications."))
+		}
+	default:
+		p := m.params
+		unit := m.unitLabel()
+		row := func(label, value string) string {
+			return textStyle.Render(fmt.Sprintf("%-36s %s", label, value))
+		}
+		content = append(content, sectionStyle.Render("Application module"))
+		content = append(content, row("Minimum stake", m.formatAmount(p.application.MinStakeUpokt)+" "+unit))
+		content = append(content, row("Max delegated gateways", fmt.Sprintf("%d", p.application.MaxDelegatedGateways)))
+		content = append(content, "")
+		content = append(content, sectionStyle.Render("Shared module"))
+		content = append(content, row("Blocks per session", fmt.Sprintf("%d", p.shared.NumBlocksPerSession)))
+		unbonding := fmt.Sprintf("%d sessions", p.shared.ApplicationUnbondingPeriodSessions)
+		if p.shared.NumBlocksPerSession > 0 {
+			unbonding += fmt.Sprintf(" (%d blocks)", p.shared.ApplicationUnbondingPeriodSessions*p.shared.NumBlocksPerSession)
+		}
+		content = append(content, row("Application unbonding period", unbonding))
+		content = append(content, row("Compute units to tokens multiplier", fmt.Sprintf("%d", p.shared.ComputeUnitsToTokensMultiplier)))
+		content = append(content, row("Compute unit cost granularity", fmt.Sprintf("%d", p.shared.ComputeUnitCostGranularity)))
+		content = append(content, "")
+		status := "Loaded " + p.loadedAt.Local().Format("15:04:05")
+		if m.paramsLoading {
+			status = m.spinner() + " Refreshing..."
+		}
+		content = append(content, textStyle.Render(status))
+		if m.paramsErr != nil {
+			content = append(content, errorStyle.Render(fmt.Sprintf("Last refresh failed: %v", m.paramsErr)))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render(strings.Join([]string{"Upstakes below the minimum stake are refused before submission", "r to refresh", "ESC or Q to return"}, " • ")))
+	return strings.Join(content, "\n")
+}
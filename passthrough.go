@@ -0,0 +1,328 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// passthroughRun is a pocketd command run with ":!", the escape hatch for
+// operations GASMS has no command for.
+type passthroughRun struct {
+	command  string   // As typed, without the "!"
+	args     []string // With the network flags appended
+	network  string
+	output   []string
+	err      error
+	running  bool
+	started  time.Time
+	duration time.Duration
+}
+
+// cancellable reports whether run is a query still running, which esc kills.
+func (run *passthroughRun) cancellable() bool {
+	return run != nil && run.running && run.args[0] != "tx"
+}
+
+type passthroughDoneMsg struct {
+	run    *passthroughRun
+	output []byte
+	err    error
+}
+
+// splitCommandLine splits a command line into arguments, keeping text in
+// single or double quotes together.
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	var quote rune
+	inArg := false
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
+
+// hasFlag reports whether args set the flag name, as "--name value" or
+// "--name=value".
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// passthroughArgs appends the flags of the current network that args do not
+// set: node and chain ID for queries and transactions, keyring for
+// transactions and keys, home for every command. Transactions are confirmed
+// with --yes, there being no terminal to confirm them on.
+func passthroughArgs(args []string, config *Config, network, endpoint string) ([]string, error) {
+	args = append([]string(nil), args...)
+	add := func(name, value string) {
+		if value != "" && !hasFlag(args, name) {
+			args = append(args, name+"="+value)
+		}
+	}
+	command := args[0]
+	chain := command == "q" || command == "query" || command == "tx"
+	if chain || command == "status" {
+		add("--node", endpoint)
+	}
+	if chain {
+		chainID, err := chainIDFor(network)
+		if err != nil {
+			return nil, err
+		}
+		add("--chain-id", chainID)
+	}
+	if command == "tx" || command == "keys" {
+		add("--keyring-backend", config.keyringBackend(network))
+	}
+	add("--home", config.pocketdHome(network))
+	if command == "tx" {
+		if memo := memoArgs(config); memo != nil && !hasFlag(args, "--note") {
+			args = append(args, memo...)
+		}
+		if !hasFlag(args, "--yes") && !hasFlag(args, "-y") {
+			args = append(args, "--yes")
+		}
+	}
+	return args, nil
+}
+
+// handlePassthroughCommand runs "! <pocketd args>" on the current network,
+// or shows the output of the last one with a bare "!".
+func (m model) handlePassthroughCommand(cmd string) (model, tea.Cmd) {
+	line := strings.TrimSpace(strings.TrimPrefix(cmd, "!"))
+	if line == "" {
+		if m.passthrough == nil {
+			m.err = fmt.Errorf("usage: ! <pocketd args>, e.g. ! q bank balances <address>")
+			return m, nil
+		}
+		m.state = statePassthrough
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("no config loaded")
+		return m, nil
+	}
+	if m.passthrough != nil && m.passthrough.running {
+		m.err = fmt.Errorf("pocketd %s is still running", m.passthrough.command)
+		return m, nil
+	}
+	args, err := splitCommandLine(line)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if args[0] == "pocketd" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		m.err = fmt.Errorf("usage: ! <pocketd args>")
+		return m, nil
+	}
+	network := m.config.Config.Networks[m.currentNetwork]
+	endpoint := rpcPool.active(m.currentNetwork, network.RPCEndpoint)
+	args, err = passthroughArgs(args, m.config, m.currentNetwork, endpoint)
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	run := &passthroughRun{
+		command: line,
+		args:    args,
+		network: m.currentNetwork,
+		running: true,
+		started: time.Now(),
+	}
+	m.passthrough = run
+	m.passthroughScroll = 0
+	m.state = statePassthrough
+	logger.Info("pocketd passthrough", "network", run.network, "args", args)
+	return m, tea.Batch(func() tea.Msg {
+		output, err := runPocketd(args)
+		return passthroughDoneMsg{run: run, output: output, err: err}
+	}, m.startSpinner())
+}
+
+// applyPassthrough stores the output of a finished ":!" command. Broadcast
+// transactions are recorded in the audit trail, since they bypass the queue
+// and the transaction tracker.
+func (m *model) applyPassthrough(msg passthroughDoneMsg) tea.Cmd {
+	run := msg.run
+	run.running = false
+	run.duration = time.Since(run.started)
+	run.err = msg.err
+	run.output = passthroughLines(msg.output)
+
+	if run.args[0] == "tx" && !isCancelled(msg.err) {
+		record := auditRecord{
+			Time:     time.Now(),
+			Operator: m.operator(),
+			Network:  run.network,
+			Command:  "! " + run.command,
+			Kind:     "pocketd",
+			Result:   "broadcast",
+		}
+		hash, err := broadcastHash(msg.output)
+		record.TxHash = hash
+		if msg.err != nil {
+			err = msg.err
+		}
+		if err != nil {
+			record.Result = "failed"
+			record.Error = err.Error()
+		}
+		if err := appendAudit(record); err != nil {
+			logger.Error("failed to write audit record", "command", record.Command, "error", err)
+		}
+	}
+	if run != m.passthrough || m.state == statePassthrough {
+		return nil
+	}
+	if msg.err != nil {
+		return m.notify(toastError, fmt.Sprintf("pocketd %s failed • :! to show the output", run.command))
+	}
+	return m.notify(toastSuccess, fmt.Sprintf("pocketd %s finished • :! to show the output", run.command))
+}
+
+// passthroughLines splits pocketd output into lines, indenting JSON output.
+func passthroughLines(output []byte) []string {
+	output = bytes.TrimRight(output, "\n")
+	var indented bytes.Buffer
+	if json.Valid(output) && json.Indent(&indented, output, "", "  ") == nil {
+		output = indented.Bytes()
+	}
+	if len(output) == 0 {
+		return nil
+	}
+	return strings.Split(string(output), "\n")
+}
+
+func (m model) updatePassthrough(msg tea.KeyMsg) (model, tea.Cmd) {
+	lines := 0
+	if m.passthrough != nil {
+		lines = len(m.passthrough.output)
+	}
+	page := max(m.height-12, 1)
+	switch msg.String() {
+	case "esc", "q":
+		// Queries are killed; a broadcast cannot be taken back
+		if m.passthrough.cancellable() && msg.String() == "esc" {
+			return m, m.cancelInFlight()
+		}
+		m.state = stateTable
+	case "up", "k":
+		if m.passthroughScroll > 0 {
+			m.passthroughScroll--
+		}
+	case "down", "j":
+		if m.passthroughScroll < lines-1 {
+			m.passthroughScroll++
+		}
+	case "pgup":
+		m.passthroughScroll = max(m.passthroughScroll-page, 0)
+	case "pgdown", " ":
+		m.passthroughScroll = min(m.passthroughScroll+page, max(lines-1, 0))
+	case "g":
+		m.passthroughScroll = 0
+	case "G":
+		m.passthroughScroll = max(lines-page, 0)
+	case "r":
+		if m.passthrough != nil && !m.passthrough.running {
+			return m.handlePassthroughCommand("! " + m.passthrough.command)
+		}
+	}
+	return m, nil
+}
+
+// renderPassthrough shows the scrollable output of the last ":!" command.
+func (m model) renderPassthrough() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	commandStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+
+	run := m.passthrough
+	if run == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render("🧰 POCKETD • " + run.network), ""}
+	content = append(content, commandStyle.Render(truncateToWidth("$ pocketd "+strings.Join(run.args, " "), m.width-4)))
+	switch {
+	case run.running:
+		status := fmt.Sprintf("%s Running for %s...", m.spinner(), time.Since(run.started).Round(time.Second))
+		if run.cancellable() {
+			status += " • ESC to cancel"
+		}
+		content = append(content, textStyle.Render(status))
+	case run.err != nil:
+		content = append(content, errorStyle.Render(fmt.Sprintf("Failed after %s: %v", run.duration.Round(time.Millisecond), run.err)))
+	default:
+		content = append(content, textStyle.Render(fmt.Sprintf("Finished in %s at %s", run.duration.Round(time.Millisecond), run.started.Add(run.duration).Local().Format("15:04:05"))))
+	}
+	content = append(content, "")
+
+	// Scroll the output, keeping the command and footer in place
+	visible := max(m.height-12, 1)
+	scroll := min(m.passthroughScroll, max(len(run.output)-visible, 0))
+	end := min(scroll+visible, len(run.output))
+	for _, line := range run.output[scroll:end] {
+		content = append(content, textStyle.Render(truncateToWidth(line, m.width-6)))
+	}
+	if !run.running && len(run.output) == 0 {
+		content = append(content, textStyle.Render("(no output)"))
+	}
+	content = append(content, "")
+	footer := "j/k to scroll • g/G for top/bottom • r to run again • ESC or Q to return"
+	if len(run.output) > visible {
+		footer = fmt.Sprintf("Lines %d-%d of %d • ", scroll+1, end, len(run.output)) + footer
+	}
+	content = append(content, textStyle.Render(footer))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const planVersion = 1
+
+const (
+	planFund    = "fund"
+	planUpstake = "upstake"
+)
+
+// planItem is a single transaction of a plan. Current is the on-chain value
+// (stake or balance) observed when planning; apply refuses the item if it
+// changed since.
+type planItem struct {
+	Action       string `json:"action"`
+	Address      string `json:"address"`
+	ServiceID    string `json:"service_id,omitempty"`
+	AmountUpokt  int64  `json:"amount_upokt"`
+	CurrentUpokt int64  `json:"current_upokt"`
+	TargetUpokt  int64  `json:"target_upokt"`
+}
+
+// planNote records an application the plan could not bring to its targets.
+type planNote struct {
+	Address string `json:"address"`
+	Reason  string `json:"reason"`
+}
+
+// stakePlan is the reviewed set of transactions written by "gasms plan" and
+// executed by "gasms apply".
+type stakePlan struct {
+	Version           int        `json:"version"`
+	CreatedAt         time.Time  `json:"created_at"`
+	Operator          string     `json:"operator"`
+	Network           string     `json:"network"`
+	Bank              string     `json:"bank"`
+	Targets           Targets    `json:"targets"`
+	BankBalanceUpokt  int64      `json:"bank_balance_upokt"`
+	BankRequiredUpokt int64      `json:"bank_required_upokt"`
+	Items             []planItem `json:"items"`
+	Skipped           []planNote `json:"skipped,omitempty"`
+}
+
+// planReceipt is the outcome of one applied plan item.
+type planReceipt struct {
+	planItem
+	Time   time.Time `json:"time"`
+	TxHash string    `json:"tx_hash,omitempty"`
+	Height int64     `json:"height,omitempty"`
+	Fee    int64     `json:"fee_upokt,omitempty"`
+	Result string    `json:"result"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// formatPlanAmount renders a upokt amount as POKT for plan output.
+func formatPlanAmount(upokt int64) string {
+	return strconv.FormatFloat(float64(upokt)/upoktPerPOKT, 'f', 6, 64) + " POKT"
+}
+
+// observedApplication is the on-chain state of a configured application.
+type observedApplication struct {
+	app     *Application // nil if not staked
+	balance int64
+}
+
+// observeApplication queries the stake and balance of an application.
+func observeApplication(config *Config, networkName, address string) (observedApplication, error) {
+	network := config.Config.Networks[networkName]
+	var observed observedApplication
+	err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+		var err error
+		observed.app, err = ShowApplication(address, endpoint, config.pocketdHome(networkName), networkName)
+		return err
+	})
+	if err != nil {
+		return observed, err
+	}
+	err = withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+		var err error
+		observed.balance, err = QueryBankBalanceUpokt(address, endpoint, config.keyringBackend(networkName), config.pocketdHome(networkName))
+		return err
+	})
+	return observed, err
+}
+
+// planApplication returns the fund and upstake (either may be nil) that bring
+// one application to its targets. app is nil if the application is not staked.
+// Upstakes to a target below minStake (0 if unknown) would be rejected by the
+// chain and are noted instead. appFee is the fee the application pays for its
+// upstake.
+func planApplication(address string, app *Application, balance int64, targets Targets, minStake, appFee int64) (fund, upstake *planItem, note *planNote) {
+	if targets.Stake > 0 {
+		if app == nil {
+			note = &planNote{Address: address, Reason: "not staked; stake it before planning upstakes"}
+		} else if stake := stakeUpokt(*app); stake < targets.Stake && targets.Stake < minStake {
+			note = &planNote{Address: address, Reason: fmt.Sprintf("target stake %s is below the minimum stake of %s",
+				formatPlanAmount(targets.Stake), formatPlanAmount(minStake))}
+		} else if stake := stakeUpokt(*app); stake < targets.Stake {
+			upstake = &planItem{
+				Action:       planUpstake,
+				Address:      address,
+				ServiceID:    app.ServiceID,
+				AmountUpokt:  targets.Stake - stake,
+				CurrentUpokt: stake,
+				TargetUpokt:  targets.Stake,
+			}
+		}
+	}
+
+	// The application pays for its upstake and fee from its balance
+	required := targets.MinBalance
+	if upstake != nil {
+		required += upstake.AmountUpokt + appFee
+	}
+	if balance < required {
+		fund = &planItem{
+			Action:       planFund,
+			Address:      address,
+			AmountUpokt:  required - balance,
+			CurrentUpokt: balance,
+			TargetUpokt:  required,
+		}
+	}
+	return fund, upstake, note
+}
+
+// computePlan compares the targets of a network with on-chain state and
+// returns the transactions needed to reach them. Funds come first so that
+// applications can pay for their own upstakes.
+func computePlan(config *Config, networkName string) (*stakePlan, error) {
+	network, exists := config.Config.Networks[networkName]
+	if !exists {
+		return nil, fmt.Errorf("network not found: %s", networkName)
+	}
+	if !network.hasTargets() {
+		return nil, fmt.Errorf("no targets configured for network %s", networkName)
+	}
+
+	plan := &stakePlan{
+		Version:   planVersion,
+		CreatedAt: time.Now(),
+		Operator:  auditOperator(),
+		Network:   networkName,
+		Bank:      network.Bank,
+		Targets:   network.Targets,
+	}
+
+	// Without the minimum stake, upstakes are planned unchecked
+	var minStake int64
+	if params, err := queryModuleParams(networkName, network.RPCEndpoint, config.pocketdHome(networkName)); err != nil {
+		logger.Warn("failed to query module params; minimum stake not enforced", "network", networkName, "error", err)
+	} else {
+		minStake = params.application.MinStakeUpokt
+	}
+
+	var funds, upstakes []planItem
+	for _, address := range network.Applications {
+		observed, err := observeApplication(config, networkName, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s: %w", address, err)
+		}
+
+		fund, upstake, note := planApplication(address, observed.app, observed.balance, network.targetsFor(address), minStake, network.appFeeUpokt())
+		if fund != nil {
+			funds = append(funds, *fund)
+		}
+		if upstake != nil {
+			upstakes = append(upstakes, *upstake)
+		}
+		if note != nil {
+			plan.Skipped = append(plan.Skipped, *note)
+		}
+	}
+
+	plan.Items = append(funds, upstakes...)
+	for _, item := range funds {
+		plan.BankRequiredUpokt += item.AmountUpokt + network.txFeeUpokt()
+	}
+	if network.Bank != "" && len(funds) > 0 {
+		err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			plan.BankBalanceUpokt, err = QueryBankBalanceUpokt(network.Bank, endpoint, config.keyringBackend(networkName), config.pocketdHome(networkName))
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query bank balance: %w", err)
+		}
+	}
+	return plan, nil
+}
+
+// printPlan writes a human readable summary of plan.
+func printPlan(plan *stakePlan) {
+	fmt.Printf("GASMS plan for network %s (bank %s)\n\n", plan.Network, plan.Bank)
+	for _, item := range plan.Items {
+		switch item.Action {
+		case planFund:
+			fmt.Printf("  + fund     %s  %s  (balance %s -> %s)\n", item.Address,
+				formatPlanAmount(item.AmountUpokt), formatPlanAmount(item.CurrentUpokt), formatPlanAmount(item.TargetUpokt))
+		case planUpstake:
+			fmt.Printf("  ~ upstake  %s  %s  (stake %s -> %s, service %s)\n", item.Address,
+				formatPlanAmount(item.AmountUpokt), formatPlanAmount(item.CurrentUpokt), formatPlanAmount(item.TargetUpokt), item.ServiceID)
+		}
+	}
+	for _, note := range plan.Skipped {
+		fmt.Printf("  ! skipped  %s  %s\n", note.Address, note.Reason)
+	}
+	if len(plan.Items) == 0 {
+		fmt.Println("  No changes. On-chain state matches the configured targets.")
+	}
+
+	var funds, upstakes int
+	for _, item := range plan.Items {
+		if item.Action == planFund {
+			funds++
+		} else {
+			upstakes++
+		}
+	}
+	fmt.Printf("\nPlan: %d to fund, %d to upstake.\n", funds, upstakes)
+	if funds > 0 {
+		fmt.Printf("Bank needs %s including fees, has %s.\n", formatPlanAmount(plan.BankRequiredUpokt), formatPlanAmount(plan.BankBalanceUpokt))
+		if plan.BankBalanceUpokt < plan.BankRequiredUpokt {
+			fmt.Printf("WARNING: bank is short by %s; apply will fail partway.\n", formatPlanAmount(plan.BankRequiredUpokt-plan.BankBalanceUpokt))
+		}
+	}
+}
+
+// loadCLIConfig loads the config and registers RPC endpoints for failover.
+func loadCLIConfig(path string) (*Config, error) {
+	config, err := LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	rpcPool.configure(config.Config.Networks)
+	pocketdLimiter.configure(config.Config.RateLimit)
+	keyrings.configure(config)
+	pocketdBinary.configure(config.Config.Pocketd)
+	operations.configure(config.Config.CommandTimeout)
+	return config, nil
+}
+
+// defaultNetwork returns the only configured network, which may be omitted
+// on the command line.
+func defaultNetwork(config *Config) (string, error) {
+	switch len(config.Config.Networks) {
+	case 0:
+		return "", fmt.Errorf("no networks found in config")
+	case 1:
+		for name := range config.Config.Networks {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("several networks configured, choose one with -network")
+}
+
+// runPlan implements "gasms plan".
+func runPlan(args []string) error {
+	flags := flag.NewFlagSet("plan", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	networkName := flags.String("network", "", "network to plan (required if several are configured)")
+	out := flags.String("out", "plan.json", "file to write the plan to")
+	flags.Parse(args)
+
+	config, err := loadCLIConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *networkName == "" {
+		if *networkName, err = defaultNetwork(config); err != nil {
+			return err
+		}
+	}
+
+	plan, err := computePlan(config, *networkName)
+	if err != nil {
+		return err
+	}
+	printPlan(plan)
+	if len(plan.Items) == 0 {
+		return nil
+	}
+
+	if err := writeJSONFile(*out, plan); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
+	logger.Info("plan written", "network", plan.Network, "items", len(plan.Items), "file", *out)
+	fmt.Printf("\nSaved plan to %s. Run \"gasms apply %s\" to execute it.\n", *out, *out)
+	return nil
+}
+
+// waitForInclusion polls a transaction until it is included or the
+// confirmation timeout expires.
+func waitForInclusion(config *Config, networkName, hash string) (txStatusMsg, error) {
+	network := config.Config.Networks[networkName]
+	ctx := operations.context()
+	deadline := time.Now().Add(txConfirmTimeout)
+	for time.Now().Before(deadline) {
+		if !sleepContext(ctx, txPollInterval) {
+			return txStatusMsg{}, errCancelled
+		}
+		var status txStatusMsg
+		err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			status, err = queryTxStatus(hash, endpoint, networkName, config.pocketdHome(networkName))
+			return err
+		})
+		if err == nil && status.found {
+			return status, nil
+		}
+	}
+	return txStatusMsg{}, fmt.Errorf("not found after %s", txConfirmTimeout)
+}
+
+// applyItem executes one plan item after checking that the state it was
+// planned against has not changed.
+func applyItem(config *Config, plan *stakePlan, item planItem) planReceipt {
+	receipt := planReceipt{planItem: item, Time: time.Now(), Result: string(txFailed)}
+
+	observed, err := observeApplication(config, plan.Network, item.Address)
+	if err != nil {
+		receipt.Error = err.Error()
+		return receipt
+	}
+	current := observed.balance
+	if item.Action == planUpstake {
+		if observed.app == nil {
+			receipt.Error = "application is no longer staked"
+			return receipt
+		}
+		current = stakeUpokt(*observed.app)
+	}
+	if current != item.CurrentUpokt {
+		receipt.Result = "skipped"
+		receipt.Error = fmt.Sprintf("state changed since plan (was %s, now %s); run plan again",
+			formatPlanAmount(item.CurrentUpokt), formatPlanAmount(current))
+		return receipt
+	}
+
+	var txHash string
+	switch item.Action {
+	case planFund:
+		txHash, err = fundApplication(item.Address, item.AmountUpokt, config, plan.Network)
+	case planUpstake:
+		txHash, _, err = upstakeApplication(item.Address, splitServiceIDs(item.ServiceID), item.AmountUpokt, config, plan.Network)
+	default:
+		err = fmt.Errorf("unknown action: %s", item.Action)
+	}
+	receipt.TxHash = txHash
+	if err != nil {
+		receipt.Error = err.Error()
+		return receipt
+	}
+
+	// Wait for inclusion so the next transaction sees the new balance
+	status, err := waitForInclusion(config, plan.Network, txHash)
+	switch {
+	case err != nil:
+		receipt.Result = string(txUnconfirmed)
+		receipt.Error = err.Error()
+	case status.code != 0:
+		receipt.Height = status.height
+		receipt.Fee = status.fee
+		receipt.Error = status.failure()
+	default:
+		receipt.Height = status.height
+		receipt.Fee = status.fee
+		receipt.Result = string(txIncluded)
+	}
+	return receipt
+}
+
+// auditPlanReceipt logs an applied plan item and appends it to the audit log.
+func auditPlanReceipt(plan *stakePlan, command string, receipt planReceipt) {
+	logger.Info("plan item applied", "action", receipt.Action, "address", receipt.Address, "amount_upokt", receipt.AmountUpokt, "hash", receipt.TxHash, "result", receipt.Result)
+	if err := appendAudit(auditRecord{
+		Time:        receipt.Time,
+		Operator:    auditOperator(),
+		Network:     plan.Network,
+		Bank:        plan.Bank,
+		Command:     command,
+		Kind:        receipt.Action,
+		Addresses:   []string{receipt.Address},
+		AmountUpokt: receipt.AmountUpokt,
+		FeeUpokt:    receipt.Fee,
+		TxHash:      receipt.TxHash,
+		Height:      receipt.Height,
+		Result:      receipt.Result,
+		Error:       receipt.Error,
+	}); err != nil {
+		logger.Error("failed to write audit record", "error", err)
+	}
+}
+
+// confirmApply asks the operator to approve the plan on stdin.
+func confirmApply() bool {
+	fmt.Print("\nDo you want to perform these actions? Only 'yes' will be accepted: ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// applyRefusal explains why the current user cannot apply plans from the
+// command line under config, or returns nil. Plans are bulk transactions,
+// which need the admin role as in the TUI.
+func applyRefusal(config *Config) error {
+	switch {
+	case configReadOnly(config):
+		return errors.New("read-only is enabled")
+	case config.Config.ApprovalQueue:
+		return errors.New("approval-queue is enabled; transactions must be approved in a GASMS session")
+	}
+	if role := configRole(config); role != roleAdmin {
+		return fmt.Errorf("applying a plan needs the admin role (%s is %s)", currentUser(), role)
+	}
+	return nil
+}
+
+// runApply implements "gasms apply <plan.json>".
+func runApply(args []string) error {
+	flags := flag.NewFlagSet("apply", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	autoApprove := flags.Bool("auto-approve", false, "skip interactive approval")
+	approvalSignature := flags.String("approval-signature", "", "second approver's signature of the plan, when it is above the two-person threshold (default <plan>.sig)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: gasms apply [-config file] [-auto-approve] [-approval-signature signature] <plan.json>")
+	}
+	planPath := flags.Arg(0)
+
+	// The second approver signs the file as it is
+	data, err := os.ReadFile(planPath)
+	if err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan stakePlan
+	if err := json.Unmarshal(data, &plan); err != nil {
+		return fmt.Errorf("failed to read plan: %w", err)
+	}
+	if plan.Version != planVersion {
+		return fmt.Errorf("unsupported plan version %d", plan.Version)
+	}
+
+	config, err := loadCLIConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if _, exists := config.Config.Networks[plan.Network]; !exists {
+		return fmt.Errorf("network not found: %s", plan.Network)
+	}
+	if err := applyRefusal(config); err != nil {
+		return fmt.Errorf("apply refused: %w", err)
+	}
+
+	printPlan(&plan)
+	fmt.Printf("\nPlan created %s by %s.\n", plan.CreatedAt.Local().Format("2006-01-02 15:04:05"), plan.Operator)
+	if len(plan.Items) == 0 {
+		return nil
+	}
+	if !*autoApprove && !confirmApply() {
+		return fmt.Errorf("apply cancelled")
+	}
+	var total int64
+	for _, item := range plan.Items {
+		total += item.AmountUpokt
+	}
+	if err := promptApproval(config, planPath, data, total, *approvalSignature); err != nil {
+		return err
+	}
+
+	command := "apply " + planPath
+	var receipts []planReceipt
+	failed := 0
+	for i, item := range plan.Items {
+		fmt.Printf("[%d/%d] %s %s %s ... ", i+1, len(plan.Items), item.Action, item.Address, formatPlanAmount(item.AmountUpokt))
+		receipt := applyItem(config, &plan, item)
+		receipts = append(receipts, receipt)
+
+		if receipt.Result == string(txIncluded) {
+			fmt.Printf("included at height %d (%s)\n", receipt.Height, receipt.TxHash)
+		} else {
+			failed++
+			fmt.Printf("%s: %s\n", receipt.Result, receipt.Error)
+		}
+
+		auditPlanReceipt(&plan, command, receipt)
+	}
+
+	receiptsPath := strings.TrimSuffix(planPath, ".json") + ".receipts.json"
+	if err := writeJSONFile(receiptsPath, receipts); err != nil {
+		return fmt.Errorf("failed to write receipts: %w", err)
+	}
+	fmt.Printf("\nApply complete: %d succeeded, %d failed. Receipts written to %s.\n", len(receipts)-failed, failed, receiptsPath)
+	if failed > 0 {
+		return fmt.Errorf("%d plan items failed", failed)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// defaultPluginTimeout bounds a plugin run unless the plugin sets its own.
+const defaultPluginTimeout = 30 * time.Second
+
+// pluginNamePattern restricts plugin names to plain command words.
+var pluginNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Plugin is a custom command run as an external program. The command
+// receives the current network, gateway and selection as JSON on stdin.
+type Plugin struct {
+	Name        string   `yaml:"name"`                  // Command typed after ":"
+	Command     string   `yaml:"command"`               // Program to run
+	Args        []string `yaml:"args,omitempty"`        // Arguments before the ones typed
+	Description string   `yaml:"description,omitempty"` // Shown in help
+	Timeout     string   `yaml:"timeout,omitempty"`     // Bound on a run (default 30s)
+}
+
+// timeout returns how long a run of the plugin may take.
+func (p Plugin) timeout() time.Duration {
+	if timeout, err := time.ParseDuration(p.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return defaultPluginTimeout
+}
+
+// validatePlugins checks that every plugin has a usable, unique name and a
+// command.
+func validatePlugins(config *Config) error {
+	seen := make(map[string]bool)
+	for i, plugin := range config.Config.Plugins {
+		switch {
+		case !pluginNamePattern.MatchString(plugin.Name):
+			return fmt.Errorf("plugin %d: name %q must be lowercase letters, digits and dashes", i+1, plugin.Name)
+		case seen[plugin.Name]:
+			return fmt.Errorf("plugin %s is defined twice", plugin.Name)
+		case plugin.Command == "":
+			return fmt.Errorf("plugin %s has no command", plugin.Name)
+		}
+		if plugin.Timeout != "" {
+			if _, err := time.ParseDuration(plugin.Timeout); err != nil {
+				return fmt.Errorf("plugin %s: invalid timeout %q", plugin.Name, plugin.Timeout)
+			}
+		}
+		seen[plugin.Name] = true
+	}
+	return nil
+}
+
+// pluginApplication is an application as passed to plugins.
+type pluginApplication struct {
+	Address      string   `json:"address"`
+	Label        string   `json:"label,omitempty"`
+	StakeUpokt   int64    `json:"stake_upokt"`
+	BalanceUpokt int64    `json:"balance_upokt"`
+	Services     []string `json:"services"`
+	Gateways     []string `json:"gateways"`
+	Unstaking    bool     `json:"unstaking"`
+	TransferTo   string   `json:"transfer_to,omitempty"`
+}
+
+// pluginContext is the JSON a plugin receives on stdin.
+type pluginContext struct {
+	Plugin           string              `json:"plugin"`
+	Args             []string            `json:"args"`
+	Operator         string              `json:"operator"`
+	Time             time.Time           `json:"time"`
+	Network          string              `json:"network"`
+	Gateway          string              `json:"gateway"`
+	Bank             string              `json:"bank"`
+	BankBalanceUpokt int64               `json:"bank_balance_upokt"`
+	Selected         *pluginApplication  `json:"selected"`
+	Applications     []pluginApplication `json:"applications"`
+}
+
+type pluginDoneMsg struct {
+	name   string
+	output string
+	err    error
+}
+
+// findPlugin returns the plugin named by the first word of cmd.
+func (m model) findPlugin(cmd string) (Plugin, bool) {
+	if m.config == nil {
+		return Plugin{}, false
+	}
+	name := strings.Fields(cmd + " ")[0]
+	for _, plugin := range m.config.Config.Plugins {
+		if plugin.Name == name {
+			return plugin, true
+		}
+	}
+	return Plugin{}, false
+}
+
+// pluginContext describes the current view for a run of plugin with args.
+func (m model) pluginContext(plugin Plugin, args []string) pluginContext {
+	info := pluginContext{
+		Plugin:           plugin.Name,
+		Args:             args,
+		Operator:         m.operator(),
+		Time:             time.Now().UTC(),
+		Network:          m.currentNetwork,
+		Gateway:          m.currentGateway,
+		Bank:             m.config.Config.Networks[m.currentNetwork].Bank,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+		Applications:     []pluginApplication{},
+	}
+	for i, app := range m.applications {
+		entry := pluginApplication{
+			Address:      app.Address,
+			Label:        m.appLabel(app.Address),
+			StakeUpokt:   stakeUpokt(app),
+			BalanceUpokt: app.BalanceUpokt,
+			Services:     app.ServiceIDs,
+			Gateways:     app.DelegateeGateways,
+			Unstaking:    isUnstaking(app),
+			TransferTo:   app.TransferTo,
+		}
+		info.Applications = append(info.Applications, entry)
+		if i == m.cursor {
+			info.Selected = &entry
+		}
+	}
+	return info
+}
+
+// runPlugin runs the plugin named by cmd in the background with the typed
+// arguments.
+func (m model) runPlugin(plugin Plugin, cmd string) (model, tea.Cmd) {
+	args := strings.Fields(cmd)[1:]
+	input, err := json.Marshal(m.pluginContext(plugin, args))
+	if err != nil {
+		m.err = fmt.Errorf("failed to encode plugin context: %v", err)
+		return m, nil
+	}
+	logger.Info("plugin started", "plugin", plugin.Name, "command", plugin.Command, "args", args)
+	return m, func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), plugin.timeout())
+		defer cancel()
+		command := exec.CommandContext(ctx, plugin.Command, append(append([]string{}, plugin.Args...), args...)...)
+		command.Stdin = bytes.NewReader(input)
+		output, err := command.CombinedOutput()
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %s", plugin.timeout())
+		}
+		return pluginDoneMsg{name: plugin.Name, output: strings.TrimSpace(string(output)), err: err}
+	}
+}
+
+// pluginDone reports the result of a plugin run.
+func (m *model) pluginDone(msg pluginDoneMsg) tea.Cmd {
+	if msg.err != nil {
+		logger.Error("plugin failed", "plugin", msg.name, "error", msg.err, "output", msg.output)
+		text := fmt.Sprintf("%s failed: %v", msg.name, msg.err)
+		if msg.output != "" {
+			text += ", output: " + msg.output
+		}
+		return m.notify(toastError, text)
+	}
+	logger.Info("plugin finished", "plugin", msg.name)
+	first, _, _ := strings.Cut(msg.output, "\n")
+	if first == "" {
+		first = "done"
+	}
+	return m.notify(toastSuccess, fmt.Sprintf("%s: %s", msg.name, first))
+}
+
+// pluginHelp lists the configured plugins in the help text.
+func (m model) pluginHelp(help string) string {
+	if m.config == nil || len(m.config.Config.Plugins) == 0 || m.refusePlugin("") != nil {
+		return help
+	}
+	lines := []string{"PLUGINS:"}
+	for _, plugin := range m.config.Config.Plugins {
+		description := plugin.Description
+		if description == "" {
+			description = "Run " + plugin.Command
+		}
+		lines = append(lines, fmt.Sprintf("  %-15s %s", plugin.Name, description))
+	}
+	return strings.Replace(help, "SORTING:", strings.Join(lines, "\n")+"\n  \nSORTING:", 1)
+}
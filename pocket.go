@@ -3,19 +3,118 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strconv"
+	"strings"
 )
 
 type Application struct {
-	Address     string  `json:"address"`
-	StakeAmount string  `json:"stake_amount"`
-	ServiceID   string  `json:"service_id"`
-	StakePOKT   float64 // Calculated field for display
-	BalancePOKT float64 // Bank balance in POKT
+	Address           string   `json:"address"`
+	StakeAmount       string   `json:"stake_amount"`
+	ServiceID         string   `json:"service_id"` // Service IDs joined for display ("-" if none)
+	ServiceIDs        []string // Every service the application is staked for
+	StakePOKT         float64  // Calculated field for display
+	BalancePOKT       float64  // Bank balance in POKT
+	BalanceUpokt      int64    // Bank balance in uPOKT (exact)
+	BalanceUnknown    bool     // The balance query failed; the balance is the last known one or 0
+	UnstakingHeight   int64    // Session end height of a pending unstake (0 if not unstaking)
+	DelegateeGateways []string // Gateways this application delegates to
+	TransferTo        string   // Destination of a pending stake transfer ("" if none)
+	TransferHeight    int64    // Session end height at which the pending transfer was started
+}
+
+// pendingTransfer is the pending_transfer field of an on-chain application.
+type pendingTransfer struct {
+	DestinationAddress string  `json:"destination_address"`
+	SessionEndHeight   flexInt `json:"session_end_height"`
+}
+
+// applyTransfer copies a pending transfer into app.
+func (app *Application) applyTransfer(transfer *pendingTransfer) {
+	if transfer == nil {
+		return
+	}
+	app.TransferTo = transfer.DestinationAddress
+	app.TransferHeight = int64(transfer.SessionEndHeight)
+}
+
+// flexInt decodes integers that pocketd may render either as JSON numbers or
+// as quoted strings (uint64 fields are quoted in proto JSON output).
+type flexInt int64
+
+func (f *flexInt) UnmarshalJSON(data []byte) error {
+	str := strings.Trim(string(data), `"`)
+	if str == "" || str == "null" {
+		*f = 0
+		return nil
+	}
+	value, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return err
+	}
+	*f = flexInt(value)
+	return nil
 }
 
 func QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networkName string) ([]Application, error) {
+	applications, err := ListApplications(rpcEndpoint, gateway, pocketdHome, networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range applications {
+		// Query bank balance for this application
+		balanceUpokt, err := QueryBankBalanceUpokt(applications[i].Address, rpcEndpoint, keyringBackend, pocketdHome)
+		if err != nil {
+			// Keep the other applications; this one is marked unknown
+			applications[i].BalanceUnknown = true
+			continue
+		}
+		applications[i].BalanceUpokt = balanceUpokt
+		applications[i].BalancePOKT = float64(balanceUpokt) / 1_000_000
+	}
+
+	return applications, nil
+}
+
+// chainIDFor returns the chain ID of a supported network.
+func chainIDFor(networkName string) (string, error) {
+	switch networkName {
+	case "pocket":
+		return "pocket", nil
+	case "pocket-beta":
+		return "pocket-beta", nil
+	default:
+		return "", fmt.Errorf("unsupported network: %s", networkName)
+	}
+}
+
+// ListApplications returns the applications delegated to gateway without
+// their bank balances, which require one query per application.
+func ListApplications(rpcEndpoint, gateway, pocketdHome, networkName string) ([]Application, error) {
+	applications, err := ListAllApplications(rpcEndpoint, pocketdHome, networkName)
+	if err != nil {
+		return nil, err
+	}
+	return delegatedTo(applications, gateway), nil
+}
+
+// delegatedTo returns the applications that delegate to gateway.
+func delegatedTo(applications []Application, gateway string) []Application {
+	var delegated []Application
+	for _, app := range applications {
+		for _, gw := range app.DelegateeGateways {
+			if gw == gateway {
+				delegated = append(delegated, app)
+				break
+			}
+		}
+	}
+	return delegated
+}
+
+// ListAllApplications returns every application staked on the network,
+// without bank balances.
+func ListAllApplications(rpcEndpoint, pocketdHome, networkName string) ([]Application, error) {
 	// Build the command equivalent to:
 	// pocketd q application list-application -o json $MAINNODE | jq '.applications[] | select(.delegatee_gateway_addresses[] == "gateway") | {address, stake_amount: .stake.amount, service_id: .service_configs[].service_id}'
 	// Use --limit 10000 to ensure we get all applications (pagination workaround)
@@ -36,9 +135,7 @@ func QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networ
 	if pocketdHome != "" {
 		args = append(args, "--home="+pocketdHome)
 	}
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
+	output, err := runPocketd(args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
 	}
@@ -53,7 +150,9 @@ func QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networ
 			ServiceConfigs []struct {
 				ServiceID string `json:"service_id"`
 			} `json:"service_configs"`
-			DelegateeGatewayAddresses []string `json:"delegatee_gateway_addresses"`
+			DelegateeGatewayAddresses []string         `json:"delegatee_gateway_addresses"`
+			UnstakeSessionEndHeight   flexInt          `json:"unstake_session_end_height"`
+			PendingTransfer           *pendingTransfer `json:"pending_transfer"`
 		} `json:"applications"`
 	}
 
@@ -65,23 +164,9 @@ func QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networ
 	var applications []Application
 
 	for _, app := range response.Applications {
-		// Check if this app has our gateway
-		hasGateway := false
-		for _, gw := range app.DelegateeGatewayAddresses {
-			if gw == gateway {
-				hasGateway = true
-				break
-			}
-		}
-
-		if !hasGateway {
-			continue
-		}
-
-		// Get service ID (use first one if multiple)
-		serviceID := "-"
-		if len(app.ServiceConfigs) > 0 {
-			serviceID = app.ServiceConfigs[0].ServiceID
+		serviceIDs := make([]string, len(app.ServiceConfigs))
+		for i, config := range app.ServiceConfigs {
+			serviceIDs[i] = config.ServiceID
 		}
 
 		// Convert stake amount to POKT (divide by 1,000,000)
@@ -91,34 +176,115 @@ func QueryApplications(rpcEndpoint, gateway, keyringBackend, pocketdHome, networ
 		}
 		stakePOKT := stakeAmount / 1_000_000
 
-		// Query bank balance for this application
-		balancePOKT, err := QueryBankBalance(app.Address, rpcEndpoint, keyringBackend, pocketdHome)
-		if err != nil {
-			// If balance query fails, set to 0 and continue
-			balancePOKT = 0
+		application := Application{
+			Address:           app.Address,
+			StakeAmount:       app.Stake.Amount,
+			ServiceID:         joinServiceIDs(serviceIDs),
+			ServiceIDs:        serviceIDs,
+			StakePOKT:         stakePOKT,
+			UnstakingHeight:   int64(app.UnstakeSessionEndHeight),
+			DelegateeGateways: app.DelegateeGatewayAddresses,
 		}
-
-		applications = append(applications, Application{
-			Address:     app.Address,
-			StakeAmount: app.Stake.Amount,
-			ServiceID:   serviceID,
-			StakePOKT:   stakePOKT,
-			BalancePOKT: balancePOKT,
-		})
+		application.applyTransfer(app.PendingTransfer)
+		applications = append(applications, application)
 	}
 
 	return applications, nil
 }
 
+// joinServiceIDs formats the service IDs of an application for display.
+func joinServiceIDs(serviceIDs []string) string {
+	if len(serviceIDs) == 0 {
+		return "-"
+	}
+	return strings.Join(serviceIDs, ",")
+}
+
+// splitServiceIDs parses service IDs formatted by joinServiceIDs.
+func splitServiceIDs(serviceID string) []string {
+	if serviceID == "" || serviceID == "-" {
+		return nil
+	}
+	return strings.Split(serviceID, ",")
+}
+
+// ShowApplication returns the on-chain application at address, or nil if it
+// is not staked.
+func ShowApplication(address, rpcEndpoint, pocketdHome, networkName string) (*Application, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"q", "application", "show-application", address, "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		if strings.Contains(string(output), "application not found") || strings.Contains(string(output), "key not found") {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Application struct {
+			Address string `json:"address"`
+			Stake   struct {
+				Amount string `json:"amount"`
+			} `json:"stake"`
+			ServiceConfigs []struct {
+				ServiceID string `json:"service_id"`
+			} `json:"service_configs"`
+			DelegateeGatewayAddresses []string         `json:"delegatee_gateway_addresses"`
+			UnstakeSessionEndHeight   flexInt          `json:"unstake_session_end_height"`
+			PendingTransfer           *pendingTransfer `json:"pending_transfer"`
+		} `json:"application"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	app := response.Application
+	serviceIDs := make([]string, len(app.ServiceConfigs))
+	for i, config := range app.ServiceConfigs {
+		serviceIDs[i] = config.ServiceID
+	}
+	stakeAmount, err := strconv.ParseFloat(app.Stake.Amount, 64)
+	if err != nil {
+		stakeAmount = 0
+	}
+	application := &Application{
+		Address:           app.Address,
+		StakeAmount:       app.Stake.Amount,
+		ServiceID:         joinServiceIDs(serviceIDs),
+		ServiceIDs:        serviceIDs,
+		StakePOKT:         stakeAmount / 1_000_000,
+		UnstakingHeight:   int64(app.UnstakeSessionEndHeight),
+		DelegateeGateways: app.DelegateeGatewayAddresses,
+	}
+	application.applyTransfer(app.PendingTransfer)
+	return application, nil
+}
+
 func QueryBankBalance(address, rpcEndpoint, keyringBackend, pocketdHome string) (float64, error) {
+	amount, err := QueryBankBalanceUpokt(address, rpcEndpoint, keyringBackend, pocketdHome)
+	if err != nil {
+		return 0, err
+	}
+	// Convert from upokt to POKT (divide by 1,000,000)
+	return float64(amount) / 1_000_000, nil
+}
+
+// QueryBankBalanceUpokt returns the exact upokt balance of address.
+func QueryBankBalanceUpokt(address, rpcEndpoint, keyringBackend, pocketdHome string) (int64, error) {
 	args := []string{"q", "bank", "balances", address, "--node", rpcEndpoint, "--output", "json"}
 	// Only add --home flag for query commands (keyring-backend not needed for queries)
 	if pocketdHome != "" {
 		args = append(args, "--home="+pocketdHome)
 	}
-	cmd := exec.Command("pocketd", args...)
-
-	output, err := cmd.CombinedOutput()
+	output, err := runPocketd(args)
 	if err != nil {
 		return 0, fmt.Errorf("failed to execute pocketd balance query: %w, output: %s", err, string(output))
 	}
@@ -139,12 +305,11 @@ func QueryBankBalance(address, rpcEndpoint, keyringBackend, pocketdHome string)
 	// Find upokt balance
 	for _, balance := range response.Balances {
 		if balance.Denom == "upokt" {
-			amount, err := strconv.ParseFloat(balance.Amount, 64)
+			amount, err := strconv.ParseInt(balance.Amount, 10, 64)
 			if err != nil {
 				return 0, fmt.Errorf("failed to parse balance amount: %w", err)
 			}
-			// Convert from upokt to POKT (divide by 1,000,000)
-			return amount / 1_000_000, nil
+			return amount, nil
 		}
 	}
 
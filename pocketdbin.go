@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// pocketdBinary is the pocketd executable every pocketd call runs.
+var pocketdBinary = &pocketdExecutable{}
+
+// pocketdExecutable holds the configured path of pocketd.
+type pocketdExecutable struct {
+	mu   sync.Mutex
+	path string
+}
+
+// configure sets the pocketd executable, "" for the one on PATH.
+func (p *pocketdExecutable) configure(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.path = expandHome(path)
+}
+
+// name returns the executable to run: the configured one, or pocketd looked
+// up on PATH (pocketd.exe on Windows).
+func (p *pocketdExecutable) name() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.path != "" {
+		return p.path
+	}
+	if runtime.GOOS == "windows" {
+		return "pocketd.exe"
+	}
+	return "pocketd"
+}
+
+// defaultPocketdHome returns ~/.pocket, the home pocketd uses without --home.
+func defaultPocketdHome() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".pocket"
+	}
+	return filepath.Join(home, ".pocket")
+}
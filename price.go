@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultPriceURL  = "https://api.coingecko.com/api/v3/simple/price?ids=pocket-network&vs_currencies=usd"
+	defaultPricePath = "pocket-network.usd"
+	defaultPriceTTL  = 5 * time.Minute
+)
+
+type priceLoadedMsg struct {
+	price float64
+	err   error
+}
+
+// priceURL returns the configured price API URL or the CoinGecko default.
+func (p PriceFeed) priceURL() string {
+	if p.URL != "" {
+		return p.URL
+	}
+	return defaultPriceURL
+}
+
+// pricePath returns the dot-separated path of the price in the JSON response.
+func (p PriceFeed) pricePath() string {
+	if p.Path != "" {
+		return p.Path
+	}
+	return defaultPricePath
+}
+
+// currency returns the display label of the fiat currency.
+func (p PriceFeed) currency() string {
+	if p.Currency != "" {
+		return strings.ToUpper(p.Currency)
+	}
+	return "USD"
+}
+
+// ttl returns how long a fetched price is reused before refetching.
+func (p PriceFeed) ttl() time.Duration {
+	if p.TTL == "" {
+		return defaultPriceTTL
+	}
+	ttl, err := time.ParseDuration(p.TTL)
+	if err != nil || ttl <= 0 {
+		return defaultPriceTTL
+	}
+	return ttl
+}
+
+// FetchPOKTPrice queries the price API and extracts the POKT price.
+func FetchPOKTPrice(feed PriceFeed) (float64, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(feed.priceURL())
+	if err != nil {
+		return 0, fmt.Errorf("price request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read price response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("price API returned %s", resp.Status)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return 0, fmt.Errorf("failed to parse price response: %w", err)
+	}
+
+	// Walk the dot-separated path down to the price value
+	for _, key := range strings.Split(feed.pricePath(), ".") {
+		obj, ok := data.(map[string]interface{})
+		if !ok {
+			return 0, fmt.Errorf("price path %s not found in response", feed.pricePath())
+		}
+		data, ok = obj[key]
+		if !ok {
+			return 0, fmt.Errorf("price path %s not found in response", feed.pricePath())
+		}
+	}
+
+	switch price := data.(type) {
+	case float64:
+		return price, nil
+	case string:
+		return strconv.ParseFloat(price, 64)
+	default:
+		return 0, fmt.Errorf("price at %s is not a number", feed.pricePath())
+	}
+}
+
+func loadPriceCmd(feed PriceFeed) tea.Cmd {
+	return func() tea.Msg {
+		price, err := FetchPOKTPrice(feed)
+		return priceLoadedMsg{price: price, err: err}
+	}
+}
+
+// fiatEnabled reports whether the price feed integration is turned on.
+func (m model) fiatEnabled() bool {
+	return m.config != nil && m.config.Config.PriceFeed.Enabled
+}
+
+// priceRefreshCmd fetches a new price when the cached one has expired.
+func (m model) priceRefreshCmd() tea.Cmd {
+	if !m.fiatEnabled() {
+		return nil
+	}
+	if !m.fiatPriceAt.IsZero() && time.Since(m.fiatPriceAt) < m.config.Config.PriceFeed.ttl() {
+		return nil
+	}
+	return loadPriceCmd(m.config.Config.PriceFeed)
+}
+
+// formatFiat renders a POKT amount in the configured fiat currency.
+func (m model) formatFiat(pokt float64) string {
+	if m.fiatPriceAt.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f", pokt*m.fiatPrice)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const profilesDirName = "profiles"
+
+// activeProfile is the profile selected with --profile or :profile, "" for
+// config.yaml in the working directory and the state in ~/.gasms.
+var activeProfile string
+
+// profileDir returns the directory of a profile, ~/.gasms/profiles/<name>,
+// holding its config.yaml and its own cache, history, audit log and other
+// local state.
+func profileDir(name string) (string, error) {
+	base, err := baseDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, profilesDirName, name), nil
+}
+
+// validateProfileName refuses names that are not usable as a directory.
+func validateProfileName(name string) error {
+	if name == "" || name == "." || name == ".." || safeFileName(name) != name {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-', '_' and '.'", name)
+	}
+	return nil
+}
+
+// selectProfile makes name the active profile, reading its config from then
+// on. "default" or "" selects config.yaml in the working directory.
+func selectProfile(name string) error {
+	if name == "" || name == "default" {
+		activeProfile = ""
+		configFile = defaultConfigFile
+		return nil
+	}
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	dir, err := profileDir(name)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, defaultConfigFile)
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("profile %s not found: create %s", name, path)
+	}
+	activeProfile = name
+	configFile = path
+	return nil
+}
+
+// listProfiles returns the names of the profiles that have a config.
+func listProfiles() ([]string, error) {
+	base, err := baseDataDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(base, profilesDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(base, profilesDirName, entry.Name(), defaultConfigFile)); err == nil {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// profileBusy returns why the session cannot switch profiles yet: work of
+// the current profile that would be lost.
+func (m model) profileBusy() error {
+	if m.inFlight() || m.upstakeAllRunning {
+		return fmt.Errorf("operations are in flight; wait or cancel them with esc first")
+	}
+	if len(m.txQueue) > 0 {
+		return fmt.Errorf("%d submissions await approval (:queue)", len(m.txQueue))
+	}
+	for _, tx := range m.txs {
+		if !tx.finished() {
+			return fmt.Errorf("transactions are still being followed")
+		}
+	}
+	for _, j := range m.jobs {
+		if !j.finished() && j.status() != jobStaged {
+			return fmt.Errorf("job #%d is %s (:jobs)", j.id, j.status())
+		}
+	}
+	return nil
+}
+
+// handleProfileCommand lists the profiles with "profile", or switches to one
+// with "profile <name>" ("default" for config.yaml). The session restarts on
+// the config and local state of the profile.
+func (m model) handleProfileCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) > 2 {
+		m.err = fmt.Errorf("usage: profile [name|default]")
+		return m, nil
+	}
+	// The profile is shared by every session of gasms serve
+	if len(parts) == 2 && m.sessionUser != "" {
+		m.err = fmt.Errorf("profile cannot be switched over SSH")
+		return m, nil
+	}
+	if len(parts) == 1 {
+		names, err := listProfiles()
+		if err != nil {
+			m.err = fmt.Errorf("failed to list profiles: %w", err)
+			return m, nil
+		}
+		current := activeProfile
+		if current == "" {
+			current = "default"
+		}
+		list := []string{"default"}
+		list = append(list, names...)
+		for i, name := range list {
+			if name == current {
+				list[i] = "*" + name
+			}
+		}
+		return m, m.notify(toastInfo, "Profiles: "+strings.Join(list, ", ")+" • :profile <name> to switch")
+	}
+
+	name := parts[1]
+	if name == activeProfile || (name == "default" && activeProfile == "") {
+		return m, m.notify(toastInfo, "Already on profile "+name)
+	}
+	if err := m.profileBusy(); err != nil {
+		m.err = fmt.Errorf("cannot switch profiles: %w", err)
+		return m, nil
+	}
+	previous := activeProfile
+	saveUIState(m)
+	if err := selectProfile(name); err != nil {
+		m.err = err
+		return m, nil
+	}
+	// A broken config would leave nothing to show; stay on the current one
+	if _, err := LoadConfig(configFile); err != nil {
+		selectProfile(previous)
+		m.err = fmt.Errorf("profile %s: %w", name, err)
+		return m, nil
+	}
+	if m.watcher != nil {
+		m.watcher.stop()
+	}
+	logger.Info("profile switched", "from", previous, "to", activeProfile, "config", configFile)
+
+	// Keep what belongs to the terminal and the running loops, not the profile
+	next := initialModel()
+	next.width, next.height = m.width, m.height
+	next.spinnerFrame, next.spinnerRunning = m.spinnerFrame, m.spinnerRunning
+	next.toasts, next.nextToastID = m.toasts, m.nextToastID
+	next.govGen = m.govGen + 1 // Stops the proposal poll of the previous profile
+	next.ticking = m.ticking
+	return next, next.Init()
+}
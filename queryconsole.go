@@ -0,0 +1,624 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Kinds of JSON tree nodes
+const (
+	jsonValue = iota
+	jsonObject
+	jsonArray
+)
+
+// jsonNode is a node of a parsed JSON document, keeping the key order of the
+// response.
+type jsonNode struct {
+	key      string // Object key, or [index] in an array
+	kind     int
+	value    string // JSON literal of a value
+	children []*jsonNode
+	parent   *jsonNode
+}
+
+// jsonRow is a visible line of the tree.
+type jsonRow struct {
+	node  *jsonNode
+	depth int
+}
+
+// queryConsole is the state of :query: the query being typed, the history of
+// the session and the last response, browsed as a foldable tree.
+type queryConsole struct {
+	input     string
+	editing   bool // Keys go to the query line rather than the tree
+	history   []string
+	historyAt int // Position while browsing the history with up/down
+
+	command  string // Query of the response shown
+	network  string
+	args     []string // Command run, pocketd or grpcurl
+	running  bool
+	started  time.Time
+	duration time.Duration
+	err      error
+	raw      []string  // Output lines, shown when it is not JSON
+	root     *jsonNode // Parsed response, nil when it is not JSON
+	expanded map[*jsonNode]bool
+	cursor   int
+
+	searching  bool // Keys go to the search line
+	search     string
+	matches    []*jsonNode
+	matchIndex int
+}
+
+type queryResultMsg struct {
+	console *queryConsole
+	command string
+	output  []byte
+	err     error
+}
+
+// parseJSONTree parses a JSON document into a tree.
+func parseJSONTree(data []byte) (*jsonNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeJSONNode(dec, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("trailing data after the JSON document")
+	}
+	return root, nil
+}
+
+func decodeJSONNode(dec *json.Decoder, key string, parent *jsonNode) (*jsonNode, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	node := &jsonNode{key: key, parent: parent}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		literal, err := json.Marshal(token)
+		if err != nil {
+			return nil, err
+		}
+		node.value = string(literal)
+		return node, nil
+	}
+	switch delim {
+	case '{':
+		node.kind = jsonObject
+		for dec.More() {
+			keyToken, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			child, err := decodeJSONNode(dec, fmt.Sprint(keyToken), node)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+	case '[':
+		node.kind = jsonArray
+		for i := 0; dec.More(); i++ {
+			child, err := decodeJSONNode(dec, fmt.Sprintf("[%d]", i), node)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected %v", delim)
+	}
+	// The closing delimiter
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// walk calls fn on node and its descendants in document order.
+func (n *jsonNode) walk(fn func(*jsonNode, int), depth int) {
+	fn(n, depth)
+	for _, child := range n.children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// summary describes a folded container, e.g. "{3}" or "[12]".
+func (n *jsonNode) summary() string {
+	if n.kind == jsonArray {
+		return fmt.Sprintf("[%d]", len(n.children))
+	}
+	return fmt.Sprintf("{%d}", len(n.children))
+}
+
+// rows returns the visible lines of the tree. The root container is not a
+// line of its own.
+func (c *queryConsole) rows() []jsonRow {
+	if c.root == nil {
+		return nil
+	}
+	if c.root.kind == jsonValue {
+		return []jsonRow{{node: c.root}}
+	}
+	var rows []jsonRow
+	var add func(*jsonNode, int)
+	add = func(n *jsonNode, depth int) {
+		rows = append(rows, jsonRow{node: n, depth: depth})
+		if n.kind != jsonValue && c.expanded[n] {
+			for _, child := range n.children {
+				add(child, depth+1)
+			}
+		}
+	}
+	for _, child := range c.root.children {
+		add(child, 0)
+	}
+	return rows
+}
+
+// setExpanded expands or folds every container of the tree.
+func (c *queryConsole) setExpanded(open bool) {
+	c.expanded = map[*jsonNode]bool{c.root: true}
+	if !open {
+		return
+	}
+	c.root.walk(func(n *jsonNode, _ int) {
+		if n.kind != jsonValue {
+			c.expanded[n] = true
+		}
+	}, 0)
+}
+
+// reveal expands the ancestors of node and moves the cursor to it.
+func (c *queryConsole) reveal(node *jsonNode) {
+	for parent := node.parent; parent != nil; parent = parent.parent {
+		c.expanded[parent] = true
+	}
+	for i, row := range c.rows() {
+		if row.node == node {
+			c.cursor = i
+			return
+		}
+	}
+}
+
+// findMatches collects the nodes whose key or value contains the search
+// text, ignoring case, and jumps to the first one.
+func (c *queryConsole) findMatches() {
+	c.matches = nil
+	c.matchIndex = 0
+	if c.root == nil || c.search == "" {
+		return
+	}
+	needle := strings.ToLower(c.search)
+	c.root.walk(func(n *jsonNode, _ int) {
+		if n != c.root && (strings.Contains(strings.ToLower(n.key), needle) || strings.Contains(strings.ToLower(n.value), needle)) {
+			c.matches = append(c.matches, n)
+		}
+	}, 0)
+	if len(c.matches) > 0 {
+		c.reveal(c.matches[0])
+	}
+}
+
+// queryArgs turns a console query into the command to run: "grpc
+// <service/method> [json]" for grpcurl, anything else for "pocketd q" with
+// JSON output.
+func queryArgs(line string, config *Config, network string) (string, []string, error) {
+	args, err := splitCommandLine(line)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(args) > 0 && args[0] == "pocketd" {
+		args = args[1:]
+	}
+	if len(args) > 0 && (args[0] == "q" || args[0] == "query") {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("type a query, e.g. bank balances <address>")
+	}
+
+	if args[0] == "grpc" {
+		if len(args) < 2 || len(args) > 3 {
+			return "", nil, fmt.Errorf("usage: grpc <service/method> [json request]")
+		}
+		endpoint := config.Config.Networks[network].GRPCEndpoint
+		if endpoint == "" {
+			return "", nil, fmt.Errorf("no grpc_endpoint configured for %s", network)
+		}
+		var grpcArgs []string
+		host, tls := strings.CutPrefix(endpoint, "https://")
+		if !tls {
+			grpcArgs = append(grpcArgs, "-plaintext")
+			host = strings.TrimPrefix(host, "http://")
+		}
+		if len(args) == 3 {
+			grpcArgs = append(grpcArgs, "-d", args[2])
+		}
+		return "grpcurl", append(grpcArgs, host, args[1]), nil
+	}
+
+	args = append([]string{"q"}, args...)
+	if !hasFlag(args, "-o") && !hasFlag(args, "--output") {
+		args = append(args, "-o", "json")
+	}
+	endpoint := rpcPool.active(network, config.Config.Networks[network].RPCEndpoint)
+	args, err = passthroughArgs(args, config, network, endpoint)
+	return "pocketd", args, err
+}
+
+// runGrpcurl runs a gRPC query with grpcurl under the operations context, so
+// that esc cancels it like a pocketd query.
+func runGrpcurl(args []string) ([]byte, error) {
+	ctx, cancel, timeout := operations.commandContext(args)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "grpcurl", args...)
+	cmd.WaitDelay = time.Second
+	output, err := cmd.CombinedOutput()
+	switch {
+	case err == nil:
+	case errors.Is(err, exec.ErrNotFound):
+		err = fmt.Errorf("grpcurl is not installed: see https://github.com/fullstorydev/grpcurl")
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("grpcurl timed out after %s: %w", timeout, ctx.Err())
+	case ctx.Err() != nil:
+		err = context.Cause(ctx)
+	}
+	if err != nil {
+		logger.Warn("grpcurl failed", "args", args, "error", err)
+	}
+	return output, err
+}
+
+// handleQueryCommand opens the query console, running "query <args>" right
+// away when a query is given.
+func (m model) handleQueryCommand(cmd string) (model, tea.Cmd) {
+	if m.config == nil {
+		m.err = fmt.Errorf("no config loaded")
+		return m, nil
+	}
+	if m.queryConsole == nil {
+		m.queryConsole = &queryConsole{expanded: make(map[*jsonNode]bool)}
+	}
+	c := m.queryConsole
+	m.state = stateQueryConsole
+	line := strings.TrimSpace(strings.TrimPrefix(cmd, "query"))
+	if line == "" {
+		c.editing = c.root == nil && len(c.raw) == 0
+		return m, nil
+	}
+	c.input = line
+	return m, m.runQuery()
+}
+
+// runQuery runs the query typed in the console on the current network.
+func (m *model) runQuery() tea.Cmd {
+	c := m.queryConsole
+	if c.running {
+		m.err = fmt.Errorf("the previous query is still running")
+		return nil
+	}
+	line := strings.TrimSpace(c.input)
+	program, args, err := queryArgs(line, m.config, m.currentNetwork)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	if len(c.history) == 0 || c.history[len(c.history)-1] != line {
+		c.history = append(c.history, line)
+	}
+	c.historyAt = len(c.history)
+	c.command, c.network, c.args = line, m.currentNetwork, append([]string{program}, args...)
+	c.running, c.started, c.err = true, time.Now(), nil
+	c.editing = false
+	logger.Info("console query", "network", c.network, "command", program, "args", args)
+	return tea.Batch(func() tea.Msg {
+		var output []byte
+		var err error
+		if program == "grpcurl" {
+			output, err = runGrpcurl(args)
+		} else {
+			output, err = runPocketd(args)
+		}
+		return queryResultMsg{console: c, command: line, output: output, err: err}
+	}, m.startSpinner())
+}
+
+// applyQueryResult shows the response of a console query.
+func (m *model) applyQueryResult(msg queryResultMsg) {
+	c := msg.console
+	if c != m.queryConsole || msg.command != c.command {
+		return
+	}
+	c.running = false
+	c.duration = time.Since(c.started)
+	c.err = msg.err
+	c.cursor = 0
+	c.root, c.raw = nil, nil
+	output := bytes.TrimSpace(msg.output)
+	if root, err := parseJSONTree(output); err == nil && len(output) > 0 {
+		c.root = root
+		// Two levels open; deeper containers start folded
+		c.expanded = map[*jsonNode]bool{root: true}
+		for _, child := range root.children {
+			c.expanded[child] = true
+		}
+	} else if len(output) > 0 {
+		c.raw = strings.Split(string(output), "\n")
+	}
+	if c.search != "" {
+		c.findMatches()
+	}
+}
+
+func (m model) updateQueryConsole(msg tea.KeyMsg) (model, tea.Cmd) {
+	c := m.queryConsole
+	if c.searching {
+		switch msg.String() {
+		case "enter":
+			c.searching = false
+			c.findMatches()
+			if c.search != "" && len(c.matches) == 0 {
+				m.err = fmt.Errorf("no match for %q", c.search)
+			}
+		case "esc":
+			c.searching = false
+		case "backspace":
+			if runes := []rune(c.search); len(runes) > 0 {
+				c.search = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				c.search += string(msg.Runes)
+			}
+		}
+		return m, nil
+	}
+
+	if c.editing {
+		switch msg.String() {
+		case "enter":
+			return m, m.runQuery()
+		case "esc":
+			if c.root == nil && len(c.raw) == 0 && !c.running {
+				m.state = stateTable
+			}
+			c.editing = false
+		case "up":
+			if c.historyAt > 0 {
+				c.historyAt--
+				c.input = c.history[c.historyAt]
+			}
+		case "down":
+			if c.historyAt < len(c.history)-1 {
+				c.historyAt++
+				c.input = c.history[c.historyAt]
+			} else {
+				c.historyAt = len(c.history)
+				c.input = ""
+			}
+		case "ctrl+u":
+			c.input = ""
+		case "backspace":
+			if runes := []rune(c.input); len(runes) > 0 {
+				c.input = string(runes[:len(runes)-1])
+			}
+		default:
+			if msg.Type == tea.KeyRunes || msg.Type == tea.KeySpace {
+				c.input += string(msg.Runes)
+			}
+		}
+		return m, nil
+	}
+
+	rows := c.rows()
+	lines := len(rows)
+	if c.root == nil {
+		lines = len(c.raw)
+	}
+	page := max(m.height-14, 1)
+	switch msg.String() {
+	case "esc", "q":
+		if c.running && msg.String() == "esc" {
+			return m, m.cancelInFlight()
+		}
+		m.state = stateTable
+	case "i", "e", ":":
+		c.editing = true
+		c.historyAt = len(c.history)
+	case "r":
+		if c.command != "" {
+			c.input = c.command
+			return m, m.runQuery()
+		}
+	case "/":
+		c.searching = true
+		c.search = ""
+	case "n", "N":
+		if len(c.matches) == 0 {
+			return m, nil
+		}
+		if msg.String() == "n" {
+			c.matchIndex = (c.matchIndex + 1) % len(c.matches)
+		} else {
+			c.matchIndex = (c.matchIndex + len(c.matches) - 1) % len(c.matches)
+		}
+		c.reveal(c.matches[c.matchIndex])
+	case "up", "k":
+		if c.cursor > 0 {
+			c.cursor--
+		}
+	case "down", "j":
+		if c.cursor < lines-1 {
+			c.cursor++
+		}
+	case "pgup":
+		c.cursor = max(c.cursor-page, 0)
+	case "pgdown":
+		c.cursor = max(min(c.cursor+page, lines-1), 0)
+	case "g":
+		c.cursor = 0
+	case "G":
+		c.cursor = max(lines-1, 0)
+	case "enter", " ":
+		if c.cursor < len(rows) && rows[c.cursor].node.kind != jsonValue {
+			node := rows[c.cursor].node
+			c.expanded[node] = !c.expanded[node]
+		}
+	case "right", "l":
+		if c.cursor < len(rows) && rows[c.cursor].node.kind != jsonValue {
+			c.expanded[rows[c.cursor].node] = true
+		}
+	case "left", "h":
+		// Fold the container, or move to the one holding the value
+		if c.cursor >= len(rows) {
+			return m, nil
+		}
+		node := rows[c.cursor].node
+		if node.kind != jsonValue && c.expanded[node] {
+			c.expanded[node] = false
+		} else if node.parent != nil && node.parent != c.root {
+			c.reveal(node.parent)
+		}
+	case "E":
+		if c.root != nil {
+			c.setExpanded(true)
+		}
+	case "C":
+		if c.root != nil {
+			c.setExpanded(false)
+			c.cursor = 0
+		}
+	}
+	return m, nil
+}
+
+// renderQueryConsole shows the query line and the response as a foldable
+// JSON tree.
+func (m model) renderQueryConsole() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	inputStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	keyStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")) // Light grey-green
+	matchStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")) // Yellow
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230"))
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+
+	c := m.queryConsole
+	if c == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render("🔎 QUERY CONSOLE • " + m.currentNetwork), ""}
+	prompt := "query> " + c.input
+	if c.editing {
+		prompt += "█"
+	}
+	content = append(content, inputStyle.Render(truncateToWidth(prompt, m.width-6)))
+
+	switch {
+	case c.command == "":
+		content = append(content, textStyle.Render("pocketd queries: bank balances <address>, application show-application <address>, ..."))
+		content = append(content, textStyle.Render("gRPC queries (grpcurl): grpc cosmos.bank.v1beta1.Query/Balance '{\"address\":\"...\",\"denom\":\"upokt\"}'"))
+	case c.running:
+		content = append(content, textStyle.Render(fmt.Sprintf("%s Running %s for %s... • ESC to cancel", m.spinner(), c.args[0], time.Since(c.started).Round(time.Second))))
+	case c.err != nil:
+		content = append(content, errorStyle.Render(truncateToWidth(fmt.Sprintf("Failed after %s: %v", c.duration.Round(time.Millisecond), c.err), m.width-6)))
+	default:
+		status := fmt.Sprintf("%s on %s in %s", c.command, c.network, c.duration.Round(time.Millisecond))
+		if len(c.matches) > 0 {
+			status += fmt.Sprintf(" • match %d/%d for %q", c.matchIndex+1, len(c.matches), c.search)
+		}
+		content = append(content, textStyle.Render(truncateToWidth(status, m.width-6)))
+	}
+	if c.searching {
+		content = append(content, inputStyle.Render("/"+c.search+"█"))
+	} else {
+		content = append(content, "")
+	}
+
+	// Keep the cursor in the middle of the visible lines where possible
+	visible := max(m.height-14, 1)
+	var lines []string
+	total := len(c.raw)
+	if c.root != nil {
+		rows := c.rows()
+		total = len(rows)
+		matched := make(map[*jsonNode]bool, len(c.matches))
+		for _, node := range c.matches {
+			matched[node] = true
+		}
+		start := max(min(c.cursor-visible/2, total-visible), 0)
+		for i := start; i < min(start+visible, total); i++ {
+			row := rows[i]
+			line := strings.Repeat("  ", row.depth)
+			label := row.node.key
+			switch {
+			case row.node.kind == jsonValue && label == "":
+				line += row.node.value
+			case row.node.kind == jsonValue:
+				line += "  " + keyStyle.Render(label+":") + " " + row.node.value
+			case c.expanded[row.node]:
+				line += "▾ " + keyStyle.Render(label)
+			default:
+				line += "▸ " + keyStyle.Render(label) + " " + row.node.summary()
+			}
+			line = truncateToWidth(line, m.width-6)
+			switch {
+			case i == c.cursor && !c.editing:
+				line = selectedStyle.Render(line)
+			case matched[row.node]:
+				line = matchStyle.Render(line)
+			}
+			lines = append(lines, "  "+line)
+		}
+	} else {
+		start := max(min(c.cursor-visible/2, total-visible), 0)
+		for i := start; i < min(start+visible, total); i++ {
+			lines = append(lines, textStyle.Render(truncateToWidth(c.raw[i], m.width-6)))
+		}
+	}
+	content = append(content, lines...)
+	content = append(content, "")
+
+	footer := "Enter to run • ↑/↓ history • ctrl+u to clear • ESC to browse the response"
+	if !c.editing {
+		footer = "j/k to move • enter/space fold • h/l fold/unfold • E/C all • / search, n/N • i to edit • r to rerun • ESC or Q to return"
+	}
+	content = append(content, textStyle.Render(footer))
+	return strings.Join(content, "\n")
+}
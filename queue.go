@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// queuedRow is one transaction of a queued submission, as reviewed by the
+// operator.
+type queuedRow struct {
+	kind   string
+	target string // Address or description of the recipients
+	amount int64  // upokt (0 if not applicable)
+	fee    int64  // Expected fee in upokt
+}
+
+// queuedSubmission holds the transactions of one command until an operator
+// approves or rejects them in the queue view.
+type queuedSubmission struct {
+	id       int
+	command  string
+	network  string
+	queuedAt time.Time
+	rows     []queuedRow
+	txIDs    []int                          // Tracked transactions held in the queue
+	plan     bool                           // Runs as a reconcile, one at a time
+	approve  func(m model) (model, tea.Cmd) // Broadcasts the transactions
+}
+
+// approvalRequired reports whether transactions wait in the queue for
+// approval instead of being broadcast right away.
+func (m model) approvalRequired() bool {
+	return m.config != nil && m.config.Config.ApprovalQueue
+}
+
+// submit broadcasts the transactions of command with approve, or queues them
+// for approval when the approval queue is enabled. plan marks submissions
+// that run as a reconcile.
+func (m *model) submit(command string, rows []queuedRow, txIDs []int, plan bool, approve func(m model) (model, tea.Cmd)) tea.Cmd {
+	m.nextQueueID++
+	entry := queuedSubmission{
+		id:       m.nextQueueID,
+		command:  command,
+		network:  m.currentNetwork,
+		queuedAt: time.Now(),
+		rows:     rows,
+		txIDs:    txIDs,
+		plan:     plan,
+		approve:  approve,
+	}
+	for _, id := range txIDs {
+		if tx := m.findTx(id); tx != nil {
+			tx.status = txQueued
+			tx.updatedAt = time.Now()
+		}
+	}
+	if reason := m.refuseSubmission(entry); reason != "" {
+		return m.rejectSubmission(entry, reason)
+	}
+	if !m.approvalRequired() {
+		if cooldown := m.cooldown(); cooldown > 0 {
+			return m.holdSubmission(entry, cooldown)
+		}
+		var cmd tea.Cmd
+		*m, cmd = m.authorize(entry)
+		return cmd
+	}
+	m.txQueue = append(m.txQueue, entry)
+	logger.Info("transactions queued for approval", "command", command, "network", m.currentNetwork, "transactions", len(rows))
+	return m.notify(toastInfo, fmt.Sprintf("Queued %d transactions for approval (:queue)", len(rows)))
+}
+
+// submitTracked broadcasts the tracked transactions txIDs with run, or queues
+// them for approval.
+func (m *model) submitTracked(command string, run tea.Cmd, txIDs ...int) tea.Cmd {
+	var rows []queuedRow
+	for _, id := range txIDs {
+		tx := m.findTx(id)
+		if tx == nil {
+			continue
+		}
+		row := queuedRow{kind: tx.kind, target: tx.target, amount: tx.amount, fee: m.txFeeUpokt()}
+		switch tx.kind {
+		case "fund-all":
+			// Every recipient receives the amount
+			row.amount *= int64(len(tx.addresses))
+			network := m.config.Config.Networks[m.currentNetwork]
+			row.fee = network.feeUpokt(estimateMultiSendFee(network, len(tx.addresses)))
+		case "feegrant":
+			row.amount = 0 // The spend limit is not transferred
+		}
+		rows = append(rows, row)
+	}
+	return m.submit(command, rows, txIDs, false, func(m model) (model, tea.Cmd) {
+		for _, id := range txIDs {
+			if tx := m.findTx(id); tx != nil && tx.status == txQueued {
+				tx.status = txSubmitting
+				tx.updatedAt = time.Now()
+			}
+		}
+		return m, tea.Batch(run, m.startSpinner())
+	})
+}
+
+// approveQueued broadcasts the queued submission at index i.
+func (m model) approveQueued(i int) (model, tea.Cmd) {
+	entry := m.txQueue[i]
+	if entry.network != m.currentNetwork {
+		return m, m.notify(toastWarning, fmt.Sprintf("Queued on %s; switch back to that network to approve it", entry.network))
+	}
+	if entry.plan && m.reconcileCh != nil {
+		return m, m.notify(toastWarning, "Another reconcile is still running; approve this one when it finishes")
+	}
+	if m.coApproval != nil && m.requiresCoApproval(entry) {
+		return m, m.notify(toastWarning, "Another submission is waiting for its second approval")
+	}
+	m.txQueue = append(m.txQueue[:i:i], m.txQueue[i+1:]...)
+	logger.Info("queued transactions approved", "command", entry.command, "network", entry.network, "operator", m.operator())
+	return m.authorize(entry)
+}
+
+// rejectQueued drops the queued submission at index i.
+func (m *model) rejectQueued(i int) tea.Cmd {
+	entry := m.txQueue[i]
+	m.txQueue = append(m.txQueue[:i:i], m.txQueue[i+1:]...)
+	return m.rejectSubmission(entry, "rejected in the approval queue")
+}
+
+// rejectSubmission drops entry without broadcasting it, recording its
+// transactions as rejected.
+func (m *model) rejectSubmission(entry queuedSubmission, reason string) tea.Cmd {
+	for _, id := range entry.txIDs {
+		if tx := m.findTx(id); tx != nil {
+			tx.status = txRejected
+			tx.err = reason
+			tx.updatedAt = time.Now()
+			auditTx(*tx)
+		}
+	}
+	logger.Info("transactions rejected", "command", entry.command, "network", entry.network, "operator", m.operator(), "reason", reason)
+	return m.notify(toastInfo, fmt.Sprintf("Rejected (%s): %s", reason, entry.command))
+}
+
+func (m model) updateQueue(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.queueCursor > 0 {
+			m.queueCursor--
+		}
+	case "down", "j":
+		if m.queueCursor < len(m.txQueue)-1 {
+			m.queueCursor++
+		}
+	case "a", "y", "x", "n", "A":
+		if m.role() != roleAdmin {
+			return m, m.notify(toastWarning, "Approving and rejecting queued transactions needs the admin role")
+		}
+	}
+
+	switch msg.String() {
+	case "a", "y":
+		if m.queueCursor < len(m.txQueue) {
+			next, cmd := m.approveQueued(m.queueCursor)
+			next.queueCursor = min(next.queueCursor, max(len(next.txQueue)-1, 0))
+			return next, cmd
+		}
+	case "x", "n":
+		if m.queueCursor < len(m.txQueue) {
+			cmd := m.rejectQueued(m.queueCursor)
+			m.queueCursor = min(m.queueCursor, max(len(m.txQueue)-1, 0))
+			return m, cmd
+		}
+	case "A":
+		// Approve everything queued on the current network, oldest first
+		var cmds []tea.Cmd
+		for i := 0; i < len(m.txQueue); {
+			queued := len(m.txQueue)
+			var cmd tea.Cmd
+			m, cmd = m.approveQueued(i)
+			cmds = append(cmds, cmd)
+			if len(m.txQueue) == queued {
+				i++ // Left in the queue
+			}
+		}
+		m.queueCursor = 0
+		return m, tea.Batch(cmds...)
+	}
+	return m, nil
+}
+
+// renderQueue lists the submissions waiting for approval.
+func (m model) renderQueue() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("⏸️  APPROVAL QUEUE • %d waiting", len(m.txQueue))), ""}
+	if !m.approvalRequired() {
+		content = append(content, warningStyle.Render("approval-queue is off: new transactions are broadcast without approval."), "")
+	}
+	if len(m.txQueue) == 0 {
+		content = append(content, textStyle.Render("No transactions waiting for approval."))
+	}
+
+	for i, entry := range m.txQueue {
+		var total, fees int64
+		for _, row := range entry.rows {
+			total += row.amount
+			fees += row.fee
+		}
+		title := fmt.Sprintf("%s  %s  %d txs", entry.queuedAt.Format("15:04:05"), entry.command, len(entry.rows))
+		if total > 0 {
+			title += fmt.Sprintf("  %s %s", m.formatAmount(total), m.unitLabel())
+		}
+		title += fmt.Sprintf("  fees ~%s", m.formatAmount(fees))
+		if entry.network != m.currentNetwork {
+			title += "  [" + entry.network + "]"
+		}
+		if i == m.queueCursor {
+			content = append(content, selectedStyle.Render(title))
+		} else {
+			content = append(content, textStyle.Render(title))
+		}
+		for _, row := range entry.rows {
+			amount := "-"
+			if row.amount > 0 {
+				amount = m.formatAmount(row.amount)
+			}
+			content = append(content, textStyle.Render(fmt.Sprintf("    %-11s %-45s %14s  fee %s", row.kind, row.target, amount, m.formatAmount(row.fee))))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("j/k to select • a to approve • x to reject • A to approve all • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
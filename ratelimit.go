@@ -0,0 +1,112 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 4
+	defaultBackoff    = time.Second
+	maxBackoff        = 30 * time.Second
+)
+
+// RateLimit throttles every pocketd call and sets how rate-limited or timed
+// out requests are retried.
+type RateLimit struct {
+	RequestsPerSecond float64 `yaml:"requests-per-second,omitempty"` // 0 = unlimited
+	Burst             int     `yaml:"burst,omitempty"`               // Calls allowed at once after idling (default 1)
+	MaxRetries        *int    `yaml:"max-retries,omitempty"`         // Retries after a 429 or timeout (default 4)
+	Backoff           string  `yaml:"backoff,omitempty"`             // First retry delay, doubled per retry (default 1s)
+}
+
+// rateLimiter is a token bucket shared by every pocketd call, with the retry
+// policy used on rate limits and timeouts.
+type rateLimiter struct {
+	mu         sync.Mutex
+	rate       float64 // Tokens per second, 0 = unlimited
+	burst      float64
+	tokens     float64
+	last       time.Time
+	maxRetries int
+	backoff    time.Duration
+}
+
+var pocketdLimiter = &rateLimiter{maxRetries: defaultMaxRetries, backoff: defaultBackoff}
+
+// configure applies the rate-limit settings of the config.
+func (l *rateLimiter) configure(settings RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = settings.RequestsPerSecond
+	l.burst = float64(max(settings.Burst, 1))
+	l.tokens = l.burst
+	l.last = time.Now()
+	l.maxRetries = defaultMaxRetries
+	if settings.MaxRetries != nil {
+		l.maxRetries = max(*settings.MaxRetries, 0)
+	}
+	l.backoff = defaultBackoff
+	if d, err := time.ParseDuration(settings.Backoff); err == nil && d > 0 {
+		l.backoff = d
+	}
+}
+
+// wait blocks until the bucket allows another call.
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	// Take the token now; callers that find the bucket empty sleep off the debt
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+	time.Sleep(delay)
+}
+
+// retryDelay returns the backoff before retry attempt (0-based), with jitter
+// so that parallel queries do not retry in lockstep, or false when the
+// retries are used up.
+func (l *rateLimiter) retryDelay(attempt int) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if attempt >= l.maxRetries {
+		return 0, false
+	}
+	delay := l.backoff << attempt
+	if delay > maxBackoff || delay <= 0 {
+		delay = maxBackoff
+	}
+	delay += time.Duration(rand.Int63n(int64(delay)/4 + 1))
+	return delay, true
+}
+
+// isRateLimitError reports whether the endpoint refused a request because
+// too many were sent. Such requests were not processed and are safe to retry,
+// even broadcasts.
+func isRateLimitError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{
+		"429 too many requests",
+		"too many requests",
+		"rate limit",
+		"resourceexhausted",
+		"resource exhausted",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"strings"
+)
+
+// readOnlyFlag is set by --read-only.
+var readOnlyFlag bool
+
+// txCommandPrefixes are the commands that submit transactions.
+var txCommandPrefixes = []string{
+	"u ", "f ", "fund ", "fa ", "fa! ", "fund-all ", "fund-all! ",
+	"ua ", "ua! ", "upstake-all ", "upstake-all! ",
+	"svc ", "transfer ", "drain ", "grant ", "grant-all ", "faucet ", "onboard ", "decommission ",
+	"delegate ", "unstake ",
+}
+
+// txCommands are the exact commands that submit transactions.
+var txCommands = map[string]bool{"drain-all": true, "grant-all": true, "autofund": true, "edit-plan": true}
+
+// txHelpEntries are the help entries of keys and commands that submit
+// transactions, hidden in read-only mode.
+var txHelpEntries = []string{
+	"u  ", "f  ", "e  ", "F  ", "U  ",
+	"u <addr>", "f <addr>", "fa <amount>", "ua <amount>", "fa @<file>", "... --memo",
+	"svc ", "transfer ", "drain ", "drain-all ", "autofund ", "queue ", "grant ", "faucet ", "onboard ", "decommission ",
+	"delegate ", "unstake ", "edit-plan ",
+}
+
+// readOnly reports whether transactions are disabled, with --read-only or
+// read-only in the config.
+func (m model) readOnly() bool {
+	return configReadOnly(m.config)
+}
+
+// configReadOnly reports whether transactions are disabled under config:
+// with --read-only, or read-only in config or in the base config roles are
+// taken from.
+func configReadOnly(config *Config) bool {
+	if readOnlyFlag || (config != nil && config.Config.ReadOnly) {
+		return true
+	}
+	source, err := roleConfig(config)
+	return err == nil && source != nil && source.Config.ReadOnly
+}
+
+// isTxCommand reports whether cmd submits transactions.
+func isTxCommand(cmd string) bool {
+	if txCommands[cmd] {
+		return true
+	}
+	for _, prefix := range txCommandPrefixes {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hideHelp removes the help entries starting with any of entries, including
+// their continuation lines.
+func hideHelp(help string, entries []string) string {
+	var kept []string
+	hiding := false
+	for _, line := range strings.Split(help, "\n") {
+		if hiding && strings.HasPrefix(line, strings.Repeat(" ", 18)) {
+			continue
+		}
+		hiding = false
+		for _, entry := range entries {
+			if strings.HasPrefix(line, "  "+entry) {
+				hiding = true
+				break
+			}
+		}
+		if !hiding {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// ReceiptExport publishes the receipts of finished upstake-all, fund-all and
+// reconcile batches.
+type ReceiptExport struct {
+	Dir     string `yaml:"dir,omitempty"`     // Write each batch to <network>-<kind>-<time>.json here
+	Webhook string `yaml:"webhook,omitempty"` // POST each batch as JSON here
+}
+
+func (r ReceiptExport) enabled() bool {
+	return r.Dir != "" || r.Webhook != ""
+}
+
+// pendingBatch is a batch whose receipts are exported once every one of its
+// transactions has finished.
+type pendingBatch struct {
+	kind      string
+	command   string
+	network   string
+	startedAt time.Time
+	txIDs     []int
+}
+
+// batchReceipt is the outcome of one transaction of an exported batch.
+type batchReceipt struct {
+	Kind        string   `json:"kind"`
+	Addresses   []string `json:"addresses"`
+	AmountUpokt int64    `json:"amount_upokt,omitempty"`
+	TxHash      string   `json:"tx_hash,omitempty"`
+	Height      int64    `json:"height,omitempty"`
+	FeeUpokt    int64    `json:"fee_upokt,omitempty"`
+	Result      string   `json:"result"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// batchReport is the exported receipts of a batch. Text summarizes it for
+// chat webhooks such as Slack, which display that field.
+type batchReport struct {
+	Text       string         `json:"text"`
+	Kind       string         `json:"kind"`
+	Command    string         `json:"command"`
+	Network    string         `json:"network"`
+	Bank       string         `json:"bank"`
+	Operator   string         `json:"operator"`
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	Included   int            `json:"included"`
+	Failed     int            `json:"failed"`
+	Error      string         `json:"error,omitempty"` // Why the batch did not run
+	Receipts   []batchReceipt `json:"receipts"`
+}
+
+type receiptsExportedMsg struct {
+	report batchReport
+	path   string
+	err    error
+}
+
+// watchBatch exports the receipts of txIDs once they have all finished, when
+// receipt export is configured.
+func (m *model) watchBatch(kind, command string, txIDs []int) {
+	if m.config == nil || !m.config.Config.Receipts.enabled() || len(txIDs) == 0 {
+		return
+	}
+	m.pendingBatches = append(m.pendingBatches, pendingBatch{
+		kind:      kind,
+		command:   command,
+		network:   m.currentNetwork,
+		startedAt: time.Now(),
+		txIDs:     txIDs,
+	})
+}
+
+// exportFinishedBatches exports the batches whose transactions have all
+// finished. Batches rejected in the approval queue are dropped.
+func (m *model) exportFinishedBatches() tea.Cmd {
+	var cmds []tea.Cmd
+	remaining := m.pendingBatches[:0]
+	for _, batch := range m.pendingBatches {
+		report, done := m.batchReport(batch)
+		switch {
+		case !done:
+			remaining = append(remaining, batch)
+		case len(report.Receipts) > 0:
+			cmds = append(cmds, exportReceiptsCmd(m.config.Config.Receipts, report))
+		}
+	}
+	m.pendingBatches = remaining
+	return tea.Batch(cmds...)
+}
+
+// batchReport builds the report of batch, or returns false while some of its
+// transactions have not finished.
+func (m model) batchReport(batch pendingBatch) (batchReport, bool) {
+	report := batchReport{
+		Kind:       batch.kind,
+		Command:    batch.command,
+		Network:    batch.network,
+		Operator:   m.operator(),
+		StartedAt:  batch.startedAt,
+		FinishedAt: time.Now(),
+	}
+	for _, id := range batch.txIDs {
+		tx := m.findTx(id)
+		if tx == nil {
+			continue
+		}
+		if !tx.finished() {
+			return batchReport{}, false
+		}
+		if tx.status == txRejected {
+			continue
+		}
+		report.Bank = tx.bank
+		if tx.status == txIncluded {
+			report.Included++
+		} else {
+			report.Failed++
+		}
+		report.Receipts = append(report.Receipts, batchReceipt{
+			Kind:        tx.kind,
+			Addresses:   tx.addresses,
+			AmountUpokt: tx.amount,
+			TxHash:      tx.hash,
+			Height:      tx.height,
+			FeeUpokt:    tx.fee,
+			Result:      string(tx.status),
+			Error:       tx.err,
+		})
+	}
+	report.Text = fmt.Sprintf("GASMS %s on %s (%s): %d included, %d failed",
+		report.Kind, report.Network, report.Command, report.Included, report.Failed)
+	return report, true
+}
+
+// exportReceiptsCmd writes report to the receipts directory and posts it to
+// the webhook.
+func exportReceiptsCmd(export ReceiptExport, report batchReport) tea.Cmd {
+	return func() tea.Msg {
+		path, err := exportReport(export, report)
+		return receiptsExportedMsg{report: report, path: path, err: err}
+	}
+}
+
+// exportReport writes report to the receipts directory and posts it to the
+// webhook, returning the file written.
+func exportReport(export ReceiptExport, report batchReport) (string, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	// A failed write does not hold back the webhook, nor the reverse
+	var path string
+	var errs []error
+	if export.Dir != "" {
+		if path, err = writeReceipts(export.Dir, report, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if export.Webhook != "" {
+		if err := postReceipts(export.Webhook, data); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return path, errors.Join(errs...)
+}
+
+// planReport builds the report of a plan applied outside the UI.
+func planReport(kind, command string, plan *stakePlan, receipts []planReceipt, startedAt time.Time) batchReport {
+	report := batchReport{
+		Kind:       kind,
+		Command:    command,
+		Network:    plan.Network,
+		Bank:       plan.Bank,
+		Operator:   auditOperator(),
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+	}
+	for _, receipt := range receipts {
+		if receipt.Result == string(txIncluded) {
+			report.Included++
+		} else {
+			report.Failed++
+		}
+		report.Receipts = append(report.Receipts, batchReceipt{
+			Kind:        receipt.Action,
+			Addresses:   []string{receipt.Address},
+			AmountUpokt: receipt.AmountUpokt,
+			TxHash:      receipt.TxHash,
+			Height:      receipt.Height,
+			FeeUpokt:    receipt.Fee,
+			Result:      receipt.Result,
+			Error:       receipt.Error,
+		})
+	}
+	report.Text = fmt.Sprintf("GASMS %s on %s (%s): %d included, %d failed",
+		report.Kind, report.Network, report.Command, report.Included, report.Failed)
+	return report
+}
+
+// writeReceipts writes the receipts of report to a timestamped file in dir.
+func writeReceipts(dir string, report batchReport, data []byte) (string, error) {
+	dir = expandHome(dir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("%s-%s-%s.json", safeFileName(report.Network), report.Kind, report.FinishedAt.Format("20060102T150405"))
+	path := filepath.Join(dir, name)
+	return path, writeFileAtomic(path, data, 0600)
+}
+
+// postReceipts sends the receipts JSON to the webhook.
+func postReceipts(url string, data []byte) error {
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// receiptsExported reports the outcome of a receipt export.
+func (m *model) receiptsExported(msg receiptsExportedMsg) tea.Cmd {
+	if msg.err != nil {
+		logger.Error("failed to export receipts", "kind", msg.report.Kind, "command", msg.report.Command, "error", msg.err)
+		return m.notify(toastError, fmt.Sprintf("Receipts of %s not exported: %v", msg.report.Command, msg.err))
+	}
+	logger.Info("receipts exported", "kind", msg.report.Kind, "command", msg.report.Command, "path", msg.path)
+	if msg.path != "" {
+		return m.notify(toastInfo, "Receipts written to "+msg.path)
+	}
+	return nil
+}
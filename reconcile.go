@@ -0,0 +1,324 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// driftRow compares one configured application with its targets.
+type driftRow struct {
+	address string
+	app     *Application // nil if not shown under the current gateway
+	targets Targets
+	fund    *planItem
+	upstake *planItem
+	note    string
+}
+
+// reconcileProgressMsg delivers the receipt of one reconciled item.
+type reconcileProgressMsg struct {
+	ch      <-chan planReceipt
+	receipt planReceipt
+}
+
+type reconcileDoneMsg struct {
+	ch <-chan planReceipt
+}
+
+// driftPlan compares the loaded applications with the targets of the current
+// network and returns the rows of the diff view and the plan that reconciles
+// them.
+func (m model) driftPlan() ([]driftRow, *stakePlan) {
+	if m.config == nil {
+		return nil, nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil, nil
+	}
+
+	loaded := make(map[string]*Application, len(m.applications))
+	for i := range m.applications {
+		loaded[m.applications[i].Address] = &m.applications[i]
+	}
+
+	plan := &stakePlan{
+		Version:   planVersion,
+		CreatedAt: time.Now(),
+		Operator:  m.operator(),
+		Network:   m.currentNetwork,
+		Bank:      network.Bank,
+		Targets:   network.Targets,
+	}
+	var rows []driftRow
+	var funds, upstakes []planItem
+	for _, address := range network.Applications {
+		row := driftRow{address: address, app: loaded[address], targets: network.targetsFor(address)}
+		if row.app == nil {
+			row.note = "not delegated to " + TruncateAddress(m.currentGateway, 20)
+			rows = append(rows, row)
+			continue
+		}
+		fund, upstake, note := planApplication(address, row.app, row.app.BalanceUpokt, row.targets, m.minStake(), network.appFeeUpokt())
+		row.fund, row.upstake = fund, upstake
+		if note != nil {
+			row.note = note.Reason
+		}
+		if fund != nil {
+			funds = append(funds, *fund)
+			plan.BankRequiredUpokt += fund.AmountUpokt + network.txFeeUpokt()
+		}
+		if upstake != nil {
+			upstakes = append(upstakes, *upstake)
+		}
+		rows = append(rows, row)
+	}
+	plan.Items = append(funds, upstakes...)
+	plan.BankBalanceUpokt = int64(math.Round(m.bankBalance * upoktPerPOKT))
+	return rows, plan
+}
+
+// stageReconcile prepares the transactions that bring every application to
+// its targets; they run once the operator confirms.
+func (m model) stageReconcile() (model, tea.Cmd) {
+	_, plan := m.driftPlan()
+	return m.stagePlan(plan, "Nothing to reconcile: all applications meet their targets")
+}
+
+// stagePlan holds plan for confirmation, or explains why it cannot run.
+func (m model) stagePlan(plan *stakePlan, nothingToDo string) (model, tea.Cmd) {
+	if m.role() != roleAdmin {
+		return m, m.notify(toastWarning, fmt.Sprintf("Bulk transactions need the admin role (you are %s)", m.role()))
+	}
+	if m.reconcileCh != nil {
+		return m, m.notify(toastWarning, "Another reconcile is still running")
+	}
+	if err := m.balancesReady(); err != nil {
+		return m, m.notify(toastWarning, fmt.Sprintf("Cannot stage transactions yet: %v", err))
+	}
+	if plan == nil || len(plan.Items) == 0 {
+		return m, m.notify(toastInfo, nothingToDo)
+	}
+	m.stagedPlan = plan
+	return m, nil
+}
+
+// confirmStagedPlan handles the y/n prompt of a staged plan and, once
+// confirmed, runs it with command recorded in the audit log.
+func (m model) confirmStagedPlan(msg tea.KeyMsg, command string) (model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		plan := m.stagedPlan
+		m.stagedPlan = nil
+		return m.startPlan(plan, command)
+	case "n", "esc":
+		m.stagedPlan = nil
+	}
+	return m, nil
+}
+
+// startPlan runs plan in the background with command recorded in the audit
+// log, or queues it for approval.
+func (m model) startPlan(plan *stakePlan, command string) (model, tea.Cmd) {
+	rows := make([]queuedRow, len(plan.Items))
+	for i, item := range plan.Items {
+		rows[i] = queuedRow{kind: item.Action, target: item.Address, amount: item.AmountUpokt, fee: m.txFeeUpokt()}
+	}
+	cmd := m.submit(command, rows, nil, true, func(m model) (model, tea.Cmd) {
+		return m.launchPlan(plan, command)
+	})
+	return m, cmd
+}
+
+// launchPlan runs plan in the background with command recorded in the audit
+// log.
+func (m model) launchPlan(plan *stakePlan, command string) (model, tea.Cmd) {
+	m.reconcilePlan = plan
+	m.reconcileAudit = command
+	m.reconcileDone, m.reconcileFails = 0, 0
+	m.reconcileLeft = nil
+	m.reconcileTxs = nil
+	m.reconcileCh = runReconcile(m.config, m.reconcilePlan)
+	logger.Info("reconcile started", "command", command, "network", m.reconcilePlan.Network, "items", len(m.reconcilePlan.Items))
+	return m, tea.Batch(waitForReceiptCmd(m.reconcileCh), m.startSpinner())
+}
+
+// runReconcile executes the staged plan one item at a time, waiting for each
+// transaction to be included, and streams the receipts.
+func runReconcile(config *Config, plan *stakePlan) <-chan planReceipt {
+	ch := make(chan planReceipt)
+	ctx := operations.context()
+	go func() {
+		defer close(ch)
+		for _, item := range plan.Items {
+			if ctx.Err() != nil {
+				ch <- planReceipt{planItem: item, Time: time.Now(), Result: "skipped", Error: errCancelled.Error()}
+				continue
+			}
+			ch <- applyItem(config, plan, item)
+		}
+	}()
+	return ch
+}
+
+func waitForReceiptCmd(ch <-chan planReceipt) tea.Cmd {
+	return func() tea.Msg {
+		receipt, ok := <-ch
+		if !ok {
+			return reconcileDoneMsg{ch: ch}
+		}
+		return reconcileProgressMsg{ch: ch, receipt: receipt}
+	}
+}
+
+// recordReconcileReceipt adds a finished reconcile item to the transaction
+// panel and the audit log.
+func (m *model) recordReconcileReceipt(receipt planReceipt) {
+	m.reconcileDone++
+	auditPlanReceipt(m.reconcilePlan, m.reconcileAudit, receipt)
+	if receipt.Error == errCancelled.Error() && receipt.TxHash == "" {
+		m.reconcileLeft = append(m.reconcileLeft, receipt.planItem)
+	}
+
+	id := m.trackTx(receipt.Action, m.reconcileAudit, []string{receipt.Address}, receipt.AmountUpokt)
+	m.reconcileTxs = append(m.reconcileTxs, id)
+	tx := m.findTx(id)
+	tx.hash = receipt.TxHash
+	tx.height = receipt.Height
+	tx.fee = receipt.Fee
+	tx.err = receipt.Error
+	tx.updatedAt = time.Now()
+	switch receipt.Result {
+	case string(txIncluded):
+		tx.status = txIncluded
+	case string(txUnconfirmed):
+		tx.status = txUnconfirmed
+	default:
+		tx.status = txFailed
+	}
+	if tx.status != txIncluded {
+		m.reconcileFails++
+	}
+}
+
+func (m model) updateDiff(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.stagedPlan != nil {
+		return m.confirmStagedPlan(msg, "reconcile")
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "R":
+		return m.stageReconcile()
+	}
+	return m, nil
+}
+
+// renderDiff shows the drift between the configured targets and chain state.
+func (m model) renderDiff() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	columnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("65")). // Muted green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	syncedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	driftStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	promptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("🎯 DESIRED STATE DIFF • %s", m.currentNetwork)), ""}
+
+	rows, plan := m.driftPlan()
+	if plan == nil || !m.config.Config.Networks[m.currentNetwork].hasTargets() {
+		content = append(content, textStyle.Render("No targets configured for this network. Add targets (and optionally app_targets) to config.yaml."))
+	} else {
+		unit := m.unitLabel()
+		content = append(content, columnStyle.Render(fmt.Sprintf("%-15s %14s %14s %14s %14s  %s",
+			"Address", "Stake", "Target", "Balance", "Min balance", "Needed ("+unit+")")))
+		for _, row := range rows {
+			if row.app == nil {
+				content = append(content, errorStyle.Render(fmt.Sprintf("%-15s %s", TruncateAddress(row.address, 15), row.note)))
+				continue
+			}
+			var actions []string
+			if row.fund != nil {
+				actions = append(actions, "fund "+m.formatAmount(row.fund.AmountUpokt))
+			}
+			if row.upstake != nil {
+				actions = append(actions, "upstake "+m.formatAmount(row.upstake.AmountUpokt))
+			}
+			if row.note != "" {
+				actions = append(actions, row.note)
+			}
+			line := fmt.Sprintf("%-15s %14s %14s %14s %14s  ",
+				TruncateAddress(row.address, 15),
+				m.formatAmount(stakeUpokt(*row.app)), m.formatTarget(row.targets.Stake),
+				m.formatAmount(row.app.BalanceUpokt), m.formatTarget(row.targets.MinBalance))
+			switch {
+			case row.note != "":
+				content = append(content, errorStyle.Render(line+strings.Join(actions, ", ")))
+			case len(actions) > 0:
+				content = append(content, driftStyle.Render(line+strings.Join(actions, ", ")))
+			default:
+				content = append(content, syncedStyle.Render(line+"✓ in sync"))
+			}
+		}
+	}
+
+	content = append(content, "")
+	switch {
+	case m.reconcileCh != nil || m.stagedPlan != nil:
+		content = append(content, promptStyle.Render(m.reconcileStatus()))
+	case plan != nil && len(plan.Items) > 0:
+		content = append(content, textStyle.Render(fmt.Sprintf("Press R to reconcile (%d transactions) • ESC or Q to return", len(plan.Items))))
+	default:
+		content = append(content, textStyle.Render("Press ESC or Q to return"))
+	}
+
+	return strings.Join(content, "\n")
+}
+
+// reconcileStatus describes the running or staged plan.
+func (m model) reconcileStatus() string {
+	if m.reconcileCh != nil {
+		return fmt.Sprintf("%s RUNNING %s... (%d of %d done)",
+			m.spinner(), strings.ToUpper(m.reconcileAudit), m.reconcileDone, len(m.reconcilePlan.Items))
+	}
+	if m.stagedPlan != nil {
+		return fmt.Sprintf("Staged %d transactions (bank needs %s %s). Press y to submit, n to cancel.",
+			len(m.stagedPlan.Items), m.formatAmount(m.stagedPlan.BankRequiredUpokt), m.unitLabel())
+	}
+	return ""
+}
+
+// formatTarget renders a target amount, or "-" when it is not enforced.
+func (m model) formatTarget(upokt int64) string {
+	if upokt <= 0 {
+		return "-"
+	}
+	return m.formatAmount(upokt)
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// refreshBaseline is the table as of the previous refresh, which the current
+// one is compared with to highlight what changed.
+type refreshBaseline struct {
+	at           time.Time
+	cached       bool // The baseline is the cached table shown at startup
+	apps         map[string]snapshotApp
+	balanceKnown map[string]bool // Balances that had loaded, the others are not compared
+}
+
+// refreshChanges counts what changed since the previous refresh.
+type refreshChanges struct {
+	stakesUp, stakesDown     int
+	balancesUp, balancesDown int
+	added, removed           int
+	regressions              []string // Applications whose stake fell to a worse status
+}
+
+// refreshKey identifies the network and gateway the table shows, since only
+// refreshes of the same table are compared.
+func refreshKey(network, gateway string) string {
+	return network + "/" + gateway
+}
+
+// markRefreshed records that the table now shows network and gateway as of
+// at, keeping the previous table as the baseline when it showed the same.
+func (m *model) markRefreshed(network, gateway string, at time.Time) {
+	key := refreshKey(network, gateway)
+	m.lastRefresh = nil
+	if key == m.refreshedFor && !m.refreshedAt.IsZero() {
+		m.lastRefresh = m.takeRefreshBaseline()
+	}
+	m.refreshedFor = key
+	m.refreshedAt = at
+}
+
+// takeRefreshBaseline captures the applications shown before a refresh.
+func (m model) takeRefreshBaseline() *refreshBaseline {
+	baseline := &refreshBaseline{
+		at:           m.refreshedAt,
+		cached:       !m.staleSince.IsZero(),
+		apps:         make(map[string]snapshotApp, len(m.applications)),
+		balanceKnown: make(map[string]bool, len(m.applications)),
+	}
+	for _, app := range m.takeSnapshot("").Applications {
+		baseline.apps[app.Address] = app
+	}
+	for _, app := range m.applications {
+		baseline.balanceKnown[app.Address] = !m.pendingBalances[app.Address] && !app.BalanceUnknown
+	}
+	return baseline
+}
+
+// stakeChange returns how much the stake of app changed since the previous
+// refresh (0 for applications that were not in it).
+func (m model) stakeChange(app Application) int64 {
+	if m.lastRefresh == nil {
+		return 0
+	}
+	then, ok := m.lastRefresh.apps[app.Address]
+	if !ok {
+		return 0
+	}
+	return stakeUpokt(app) - then.StakeUpokt
+}
+
+// balanceChange returns how much the balance of app changed since the
+// previous refresh, once both balances are known.
+func (m model) balanceChange(app Application) int64 {
+	if m.lastRefresh == nil || !m.lastRefresh.balanceKnown[app.Address] {
+		return 0
+	}
+	if m.pendingBalances[app.Address] || app.BalanceUnknown {
+		return 0
+	}
+	return app.BalanceUpokt - m.lastRefresh.apps[app.Address].BalanceUpokt
+}
+
+// stakeLevel ranks a stake against the thresholds: 2 above warning, 1 above
+// danger, 0 below.
+func (m model) stakeLevel(upokt int64) int {
+	warning, danger := m.stakeThresholds()
+	switch {
+	case upokt >= warning:
+		return 2
+	case upokt >= danger:
+		return 1
+	}
+	return 0
+}
+
+// regressed reports whether the stake of app fell to a worse status since the
+// previous refresh.
+func (m model) regressed(app Application) bool {
+	if m.lastRefresh == nil {
+		return false
+	}
+	then, ok := m.lastRefresh.apps[app.Address]
+	return ok && m.stakeLevel(stakeUpokt(app)) < m.stakeLevel(then.StakeUpokt)
+}
+
+// refreshChanges compares the table with the previous refresh.
+func (m model) refreshChanges() refreshChanges {
+	var changes refreshChanges
+	loaded := make(map[string]bool, len(m.applications))
+	for _, app := range m.applications {
+		loaded[app.Address] = true
+		if _, ok := m.lastRefresh.apps[app.Address]; !ok {
+			changes.added++
+			continue
+		}
+		switch stake := m.stakeChange(app); {
+		case stake > 0:
+			changes.stakesUp++
+		case stake < 0:
+			changes.stakesDown++
+		}
+		switch balance := m.balanceChange(app); {
+		case balance > 0:
+			changes.balancesUp++
+		case balance < 0:
+			changes.balancesDown++
+		}
+		if m.regressed(app) {
+			name := m.appLabel(app.Address)
+			if name == "" {
+				name = TruncateAddress(app.Address, 20)
+			}
+			changes.regressions = append(changes.regressions, name)
+		}
+	}
+	for address := range m.lastRefresh.apps {
+		if !loaded[address] {
+			changes.removed++
+		}
+	}
+	return changes
+}
+
+// renderRefreshChanges renders the line summarizing what changed since the
+// previous refresh ("" when there is nothing to compare with).
+func (m model) renderRefreshChanges() string {
+	if m.lastRefresh == nil || m.loading {
+		return ""
+	}
+	changes := m.refreshChanges()
+	since := "the refresh at " + m.lastRefresh.at.Local().Format("15:04:05")
+	if m.lastRefresh.cached {
+		since = "the cached data from " + formatAge(time.Since(m.lastRefresh.at))
+	}
+
+	var parts []string
+	if changes.stakesUp > 0 || changes.stakesDown > 0 {
+		parts = append(parts, fmt.Sprintf("stakes ▲%d ▼%d", changes.stakesUp, changes.stakesDown))
+	}
+	if changes.balancesUp > 0 || changes.balancesDown > 0 {
+		parts = append(parts, fmt.Sprintf("balances ▲%d ▼%d", changes.balancesUp, changes.balancesDown))
+	}
+	if changes.added > 0 {
+		parts = append(parts, fmt.Sprintf("%d added", changes.added))
+	}
+	if changes.removed > 0 {
+		parts = append(parts, fmt.Sprintf("%d gone", changes.removed))
+	}
+
+	style := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 1)
+	line := "Δ No changes since " + since
+	if len(parts) > 0 {
+		line = "Δ Since " + since + ": " + strings.Join(parts, " • ")
+	}
+	if len(changes.regressions) > 0 {
+		line += fmt.Sprintf(" • ⚠️ %d fell below a threshold: %s", len(changes.regressions), strings.Join(changes.regressions, ", "))
+		style = style.Foreground(lipgloss.Color("196")).Bold(true) // Red for errors
+	} else if changes.stakesDown > 0 || changes.balancesDown > 0 {
+		style = style.Foreground(lipgloss.Color("220")) // Yellow for drops
+	}
+	return style.Render(truncateToWidth(line, m.width-2))
+}
+
+// changeStyle returns the style of a table cell that changed since the
+// previous refresh: green when it grew, yellow when it shrank, red when the
+// stake fell to a worse status.
+func changeStyle(delta int64, regressed, selected bool) (lipgloss.Style, bool) {
+	style := lipgloss.NewStyle().Bold(true)
+	if selected {
+		style = style.Background(lipgloss.Color("236")) // Dark grey background
+	}
+	switch {
+	case regressed:
+		return style.Foreground(lipgloss.Color("196")), true // Red for errors
+	case delta > 0:
+		return style.Foreground(lipgloss.Color("120")), true // Green for success
+	case delta < 0:
+		return style.Foreground(lipgloss.Color("220")), true // Yellow for drops
+	}
+	return style, false
+}
+
+// renderTableRow renders the cells of app, highlighting the stake, balance
+// and status cells that changed since the previous refresh.
+func (m model) renderTableRow(columns []layoutColumn, widths []int, app Application, rowStyle lipgloss.Style, selected bool) string {
+	cells := layoutCells(columns, widths, m.tableCells(app))
+	regressed := m.regressed(app)
+	var parts []string
+	for i, def := range m.activeColumns() {
+		if widths[i] <= 0 {
+			continue
+		}
+		if def.id == "stake" && m.state == stateStakeEdit && m.stakeEdit != nil && m.stakeEdit.address == app.Address {
+			parts = append(parts, m.stakeEditCell(widths[i]))
+			continue
+		}
+		var style lipgloss.Style
+		var changed bool
+		switch def.id {
+		case "status":
+			style, changed = changeStyle(0, regressed, selected)
+		case "stake", "stake_fiat":
+			style, changed = changeStyle(m.stakeChange(app), regressed, selected)
+		case "balance", "balance_fiat":
+			style, changed = changeStyle(m.balanceChange(app), false, selected)
+		}
+		if !changed {
+			style = rowStyle
+		}
+		parts = append(parts, style.Render(cells[i]))
+	}
+	return strings.Join(parts, rowStyle.Render(" "))
+}
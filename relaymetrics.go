@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultRelayWindow = "24h"
+	defaultRelayLabel  = "service_id"
+	defaultRelayTTL    = 5 * time.Minute
+)
+
+// RelayMetrics pulls relay counts and errors of a network's PATH gateway
+// from the Prometheus that scrapes it, keyed by service or application.
+type RelayMetrics struct {
+	PrometheusURL string `yaml:"prometheus_url"`
+	RelaysQuery   string `yaml:"relays_query,omitempty"` // PromQL returning relays per Label
+	ErrorsQuery   string `yaml:"errors_query,omitempty"` // PromQL returning failed relays per Label
+	Label         string `yaml:"label,omitempty"`        // service_id (default) or app_address
+	Window        string `yaml:"window,omitempty"`       // Range of the default queries (default 24h)
+	TTL           string `yaml:"ttl,omitempty"`          // How long fetched metrics are reused (default 5m)
+}
+
+// enabled reports whether relay metrics are configured.
+func (r RelayMetrics) enabled() bool {
+	return r.PrometheusURL != ""
+}
+
+func (r RelayMetrics) label() string {
+	if r.Label != "" {
+		return r.Label
+	}
+	return defaultRelayLabel
+}
+
+func (r RelayMetrics) window() string {
+	if r.Window != "" {
+		return r.Window
+	}
+	return defaultRelayWindow
+}
+
+// relaysQuery returns the PromQL of the relay counts, by default the PATH
+// relay counter summed over the window.
+func (r RelayMetrics) relaysQuery() string {
+	if r.RelaysQuery != "" {
+		return r.RelaysQuery
+	}
+	return fmt.Sprintf("sum by (%s) (increase(path_relays_total[%s]))", r.label(), r.window())
+}
+
+// errorsQuery returns the PromQL of the failed relay counts.
+func (r RelayMetrics) errorsQuery() string {
+	if r.ErrorsQuery != "" {
+		return r.ErrorsQuery
+	}
+	return fmt.Sprintf(`sum by (%s) (increase(path_relays_total{success="false"}[%s]))`, r.label(), r.window())
+}
+
+func (r RelayMetrics) ttl() time.Duration {
+	ttl, err := time.ParseDuration(r.TTL)
+	if err != nil || ttl <= 0 {
+		return defaultRelayTTL
+	}
+	return ttl
+}
+
+// relayMetricsMsg delivers the relay counts of network, keyed by the
+// configured label.
+type relayMetricsMsg struct {
+	network string
+	relays  map[string]float64
+	errors  map[string]float64
+	err     error
+}
+
+// queryPrometheus runs an instant PromQL query and returns the value of each
+// series by its label.
+func queryPrometheus(baseURL, query, label string) (map[string]float64, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(strings.TrimRight(baseURL, "/") + "/api/v1/query?query=" + url.QueryEscape(query))
+	if err != nil {
+		return nil, fmt.Errorf("relay metrics request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relay metrics: %w", err)
+	}
+	var result struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			Result []struct {
+				Metric map[string]string `json:"metric"`
+				Value  []interface{}     `json:"value"` // [timestamp, "value"]
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse relay metrics (%s): %w", resp.Status, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("relay metrics query failed: %s", result.Error)
+	}
+
+	values := make(map[string]float64)
+	for _, series := range result.Data.Result {
+		key, ok := series.Metric[label]
+		if !ok || len(series.Value) != 2 {
+			continue
+		}
+		text, _ := series.Value[1].(string)
+		value, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			continue
+		}
+		values[key] += value
+	}
+	return values, nil
+}
+
+func loadRelayMetricsCmd(network string, metrics RelayMetrics) tea.Cmd {
+	return func() tea.Msg {
+		relays, err := queryPrometheus(metrics.PrometheusURL, metrics.relaysQuery(), metrics.label())
+		if err != nil {
+			return relayMetricsMsg{network: network, err: err}
+		}
+		errors, err := queryPrometheus(metrics.PrometheusURL, metrics.errorsQuery(), metrics.label())
+		return relayMetricsMsg{network: network, relays: relays, errors: errors, err: err}
+	}
+}
+
+// relayMetricsEnabled reports whether the current network has relay metrics.
+func (m model) relayMetricsEnabled() bool {
+	return m.config != nil && m.config.Config.Networks[m.currentNetwork].RelayMetrics.enabled()
+}
+
+// relayMetricsRefreshCmd fetches relay metrics when the cached ones have
+// expired or belong to another network.
+func (m model) relayMetricsRefreshCmd() tea.Cmd {
+	if !m.relayMetricsEnabled() {
+		return nil
+	}
+	metrics := m.config.Config.Networks[m.currentNetwork].RelayMetrics
+	if m.relayNetwork == m.currentNetwork && !m.relayMetricsAt.IsZero() && time.Since(m.relayMetricsAt) < metrics.ttl() {
+		return nil
+	}
+	return loadRelayMetricsCmd(m.currentNetwork, metrics)
+}
+
+// applyRelayMetrics stores fetched relay metrics, keeping the last known
+// ones of the network if the fetch failed.
+func (m *model) applyRelayMetrics(msg relayMetricsMsg) {
+	if msg.network != m.currentNetwork {
+		return
+	}
+	if msg.err != nil {
+		logger.Warn("failed to load relay metrics", "network", msg.network, "error", msg.err)
+		return
+	}
+	m.relayNetwork = msg.network
+	m.relayCounts = msg.relays
+	m.relayErrors = msg.errors
+	m.relayMetricsAt = time.Now()
+}
+
+// appRelays returns the relays and failed relays of app: its own, or the sum
+// over its services when metrics are keyed by service.
+func (m model) appRelays(app Application) (relays, errors float64, ok bool) {
+	if m.relayNetwork != m.currentNetwork || m.relayMetricsAt.IsZero() {
+		return 0, 0, false
+	}
+	keys := []string{app.Address}
+	if m.config.Config.Networks[m.currentNetwork].RelayMetrics.label() == "service_id" {
+		keys = app.ServiceIDs
+	}
+	for _, key := range keys {
+		relays += m.relayCounts[key]
+		errors += m.relayErrors[key]
+	}
+	return relays, errors, true
+}
+
+// formatRelays renders the relay count of app for the table.
+func (m model) formatRelays(app Application) string {
+	relays, _, ok := m.appRelays(app)
+	if !ok {
+		return "-"
+	}
+	return formatCount(relays)
+}
+
+// formatRelayErrorRate renders the share of failed relays of app.
+func (m model) formatRelayErrorRate(app Application) string {
+	relays, errors, ok := m.appRelays(app)
+	if !ok || relays == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2f%%", errors/relays*100)
+}
+
+// relaySortKeys returns the relay count of each application for sorting.
+func (m model) relaySortKeys() map[string]float64 {
+	keys := make(map[string]float64, len(m.applications))
+	for _, app := range m.applications {
+		relays, _, _ := m.appRelays(app)
+		keys[app.Address] = relays
+	}
+	return keys
+}
+
+// formatCount renders a count compactly, e.g. 1.2M.
+func formatCount(count float64) string {
+	switch {
+	case count >= 1e9:
+		return fmt.Sprintf("%.1fB", count/1e9)
+	case count >= 1e6:
+		return fmt.Sprintf("%.1fM", count/1e6)
+	case count >= 1e3:
+		return fmt.Sprintf("%.1fk", count/1e3)
+	}
+	return fmt.Sprintf("%.0f", count)
+}
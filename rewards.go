@@ -0,0 +1,352 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// A claim's root hash is a sparse merkle sum tree root: the digest followed
+// by the sum (compute units) and the count (relays) as big-endian uint64s.
+const (
+	claimRootCountSize = 8
+	claimRootSumSize   = 8
+)
+
+// relayClaim is a supplier's claim for the relays of one application session.
+type relayClaim struct {
+	Supplier         string
+	Application      string
+	ServiceID        string
+	SessionEndHeight int64
+	Relays           uint64
+	ComputeUnits     uint64
+}
+
+// sharedParams are the shared module parameters used to price compute units
+// and time sessions.
+type sharedParams struct {
+	ComputeUnitsToTokensMultiplier     uint64
+	ComputeUnitCostGranularity         uint64
+	NumBlocksPerSession                int64
+	ApplicationUnbondingPeriodSessions int64
+}
+
+// claimedUpokt converts compute units to the upokt they settle for.
+func (p sharedParams) claimedUpokt(computeUnits uint64) int64 {
+	granularity := p.ComputeUnitCostGranularity
+	if granularity == 0 {
+		granularity = 1
+	}
+	return int64(float64(computeUnits) * float64(p.ComputeUnitsToTokensMultiplier) / float64(granularity))
+}
+
+// appRewards sums the pending claims of one application.
+type appRewards struct {
+	address      string
+	serviceID    string
+	claims       int
+	suppliers    int
+	relays       uint64
+	computeUnits uint64
+	claimedUpokt int64
+	burned7d     int64 // Stake consumed over the last 7 days of history
+	burnedKnown  bool
+}
+
+type rewardsLoadedMsg struct {
+	network string
+	claims  []relayClaim
+	params  sharedParams
+	err     error
+}
+
+// parseClaimRoot extracts the relay count and compute units of a claim root.
+func parseClaimRoot(encoded string) (relays, computeUnits uint64, err error) {
+	root, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(root) < claimRootSumSize+claimRootCountSize {
+		return 0, 0, fmt.Errorf("claim root too short: %d bytes", len(root))
+	}
+	countStart := len(root) - claimRootCountSize
+	sumStart := countStart - claimRootSumSize
+	return binary.BigEndian.Uint64(root[countStart:]), binary.BigEndian.Uint64(root[sumStart:countStart]), nil
+}
+
+// ListClaims returns every claim awaiting settlement on the network.
+func ListClaims(rpcEndpoint, pocketdHome, networkName string) ([]relayClaim, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"q", "proof", "list-claims", "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID, "--limit", "10000"}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Claims []struct {
+			SupplierOperatorAddress string `json:"supplier_operator_address"`
+			SessionHeader           struct {
+				ApplicationAddress    string  `json:"application_address"`
+				ServiceID             string  `json:"service_id"`
+				SessionEndBlockHeight flexInt `json:"session_end_block_height"`
+			} `json:"session_header"`
+			RootHash string `json:"root_hash"`
+		} `json:"claims"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	claims := make([]relayClaim, 0, len(response.Claims))
+	for _, c := range response.Claims {
+		relays, computeUnits, err := parseClaimRoot(c.RootHash)
+		if err != nil {
+			logger.Warn("skipping claim with unreadable root", "application", c.SessionHeader.ApplicationAddress, "error", err)
+			continue
+		}
+		claims = append(claims, relayClaim{
+			Supplier:         c.SupplierOperatorAddress,
+			Application:      c.SessionHeader.ApplicationAddress,
+			ServiceID:        c.SessionHeader.ServiceID,
+			SessionEndHeight: int64(c.SessionHeader.SessionEndBlockHeight),
+			Relays:           relays,
+			ComputeUnits:     computeUnits,
+		})
+	}
+	return claims, nil
+}
+
+// QuerySharedParams returns the shared module parameters.
+func QuerySharedParams(rpcEndpoint, pocketdHome, networkName string) (sharedParams, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return sharedParams{}, err
+	}
+	args := []string{"q", "shared", "params", "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return sharedParams{}, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Params struct {
+			ComputeUnitsToTokensMultiplier     flexInt `json:"compute_units_to_tokens_multiplier"`
+			ComputeUnitCostGranularity         flexInt `json:"compute_unit_cost_granularity"`
+			NumBlocksPerSession                flexInt `json:"num_blocks_per_session"`
+			ApplicationUnbondingPeriodSessions flexInt `json:"application_unbonding_period_sessions"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return sharedParams{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+	return sharedParams{
+		ComputeUnitsToTokensMultiplier:     uint64(response.Params.ComputeUnitsToTokensMultiplier),
+		ComputeUnitCostGranularity:         uint64(response.Params.ComputeUnitCostGranularity),
+		NumBlocksPerSession:                int64(response.Params.NumBlocksPerSession),
+		ApplicationUnbondingPeriodSessions: int64(response.Params.ApplicationUnbondingPeriodSessions),
+	}, nil
+}
+
+func loadRewardsCmd(network, rpcEndpoint, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		var claims []relayClaim
+		err := withFailover(network, rpcEndpoint, func(endpoint string) error {
+			var err error
+			claims, err = ListClaims(endpoint, pocketdHome, network)
+			return err
+		})
+		if err != nil {
+			return rewardsLoadedMsg{network: network, err: err}
+		}
+		var params sharedParams
+		err = withFailover(network, rpcEndpoint, func(endpoint string) error {
+			var err error
+			params, err = QuerySharedParams(endpoint, pocketdHome, network)
+			return err
+		})
+		return rewardsLoadedMsg{network: network, claims: claims, params: params, err: err}
+	}
+}
+
+// handleRewardsCommand opens the rewards view and queries pending claims.
+func (m model) handleRewardsCommand() (model, tea.Cmd) {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		m.err = fmt.Errorf("network %s not found in config", m.currentNetwork)
+		return m, nil
+	}
+	m.state = stateRewards
+	m.rewardsLoading = true
+	m.rewardsErr = nil
+	m.rewardsCursor = 0
+	return m, tea.Batch(
+		loadRewardsCmd(m.currentNetwork, network.RPCEndpoint, m.config.pocketdHome(m.currentNetwork)),
+		m.startSpinner(),
+	)
+}
+
+// rewardsRows sums the loaded claims of every application in the table,
+// largest claimed amount first.
+func (m model) rewardsRows() []appRewards {
+	byApp := make(map[string]*appRewards, len(m.applications))
+	suppliers := make(map[string]map[string]bool)
+	rows := make([]appRewards, len(m.applications))
+	for i, app := range m.applications {
+		rows[i] = appRewards{address: app.Address, serviceID: app.ServiceID}
+		if rate, ok := m.appBurnRate(app.Address); ok {
+			rows[i].burned7d = int64(math.Round(rate * 7))
+			rows[i].burnedKnown = true
+		}
+		byApp[app.Address] = &rows[i]
+		suppliers[app.Address] = make(map[string]bool)
+	}
+	for _, claim := range m.rewardsClaims {
+		row, ok := byApp[claim.Application]
+		if !ok {
+			continue
+		}
+		row.claims++
+		row.relays += claim.Relays
+		row.computeUnits += claim.ComputeUnits
+		row.claimedUpokt += m.rewardsParams.claimedUpokt(claim.ComputeUnits)
+		suppliers[claim.Application][claim.Supplier] = true
+	}
+	for i := range rows {
+		rows[i].suppliers = len(suppliers[rows[i].address])
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].claimedUpokt > rows[j].claimedUpokt
+	})
+	return rows
+}
+
+func (m model) updateRewards(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.rewardsCursor > 0 {
+			m.rewardsCursor--
+		}
+	case "down", "j":
+		if m.rewardsCursor < len(m.applications)-1 {
+			m.rewardsCursor++
+		}
+	case "r":
+		return m.handleRewardsCommand()
+	}
+	return m, nil
+}
+
+// renderRewards shows pending relay claims against each application next to
+// the stake it consumed.
+func (m model) renderRewards() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	columnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("65")). // Muted green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("💸 RELAY CLAIMS • %s", m.currentNetwork)), ""}
+
+	switch {
+	case m.rewardsLoading:
+		content = append(content, textStyle.Render(m.spinner()+" Querying pending claims..."))
+	case m.rewardsErr != nil:
+		content = append(content, errorStyle.Render(fmt.Sprintf("Failed to query claims: %v", m.rewardsErr)))
+	default:
+		unit := m.unitLabel()
+		content = append(content, columnStyle.Render(fmt.Sprintf("%-15s %-20s %7s %9s %12s %14s %16s %16s",
+			"Address", "Service", "Claims", "Suppliers", "Relays", "Compute units", "Claimed ("+unit+")", "Burned 7d ("+unit+")")))
+
+		rows := m.rewardsRows()
+		var totalRelays uint64
+		var totalClaimed, totalBurned int64
+		visible := max(m.height-14, 1)
+		start := 0
+		if m.rewardsCursor >= visible {
+			start = m.rewardsCursor - visible + 1
+		}
+		for i, row := range rows {
+			totalRelays += row.relays
+			totalClaimed += row.claimedUpokt
+			totalBurned += row.burned7d
+			if i < start || i >= start+visible {
+				continue
+			}
+			burned := "-"
+			if row.burnedKnown {
+				burned = m.formatAmount(row.burned7d)
+			}
+			line := fmt.Sprintf("%-15s %-20s %7d %9d %12d %14d %16s %16s",
+				TruncateAddress(row.address, 15), truncateToWidth(row.serviceID, 20), row.claims, row.suppliers,
+				row.relays, row.computeUnits, m.formatAmount(row.claimedUpokt), burned)
+			if i == m.rewardsCursor {
+				content = append(content, selectedStyle.Render(line))
+			} else {
+				content = append(content, textStyle.Render(line))
+			}
+		}
+		content = append(content, "")
+		content = append(content, columnStyle.Render(fmt.Sprintf("Total: %d relays claimed for %s %s pending settlement • %s %s stake burned in the last 7 days",
+			totalRelays, m.formatAmount(totalClaimed), unit, m.formatAmount(totalBurned), unit)))
+		content = append(content, textStyle.Render(fmt.Sprintf("Claimed amounts use compute_units_to_tokens_multiplier %d (granularity %d); settled claims show up as burned stake. Loaded %s.",
+			m.rewardsParams.ComputeUnitsToTokensMultiplier, m.rewardsParams.ComputeUnitCostGranularity, m.rewardsAt.Local().Format("15:04:05"))))
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render(strings.Join([]string{"j/k to scroll", "r to refresh", "ESC or Q to return"}, " • ")))
+	return strings.Join(content, "\n")
+}
+
+// applyRewards stores a finished claims query for the current network.
+func (m *model) applyRewards(msg rewardsLoadedMsg) {
+	if msg.network != m.currentNetwork {
+		return
+	}
+	m.rewardsLoading = false
+	m.rewardsErr = msg.err
+	if msg.err != nil {
+		logger.Error("failed to load claims", "network", msg.network, "error", msg.err)
+		return
+	}
+	m.rewardsClaims = msg.claims
+	m.rewardsParams = msg.params
+	m.rewardsAt = time.Now()
+	logger.Debug("claims loaded", "network", msg.network, "count", len(msg.claims))
+}
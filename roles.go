@@ -0,0 +1,253 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"sync"
+	"time"
+)
+
+type role int
+
+const (
+	roleViewer   role = iota // Queries only
+	roleOperator             // Single-application transactions up to the operator limit
+	roleAdmin                // Everything, including bulk operations and approvals
+)
+
+var roleNames = map[string]role{"viewer": roleViewer, "operator": roleOperator, "admin": roleAdmin}
+
+func (r role) String() string {
+	switch r {
+	case roleViewer:
+		return "viewer"
+	case roleOperator:
+		return "operator"
+	default:
+		return "admin"
+	}
+}
+
+// Roles gates the commands each user can run. Users are matched by their
+// account name, or by their ssh.users name in SSH sessions of gasms serve.
+// Only the roles of the base config apply; see roleConfig.
+type Roles struct {
+	Default       string            `yaml:"default,omitempty"`        // Role of users not listed (default admin)
+	Users         map[string]string `yaml:"users,omitempty"`          // User -> viewer, operator or admin
+	OperatorLimit int64             `yaml:"operator-limit,omitempty"` // Largest amount per operator transaction, upokt (0 = no limit)
+}
+
+func (r Roles) validate() error {
+	if _, ok := roleNames[r.Default]; r.Default != "" && !ok {
+		return fmt.Errorf("roles: unknown default role %q (viewer, operator or admin)", r.Default)
+	}
+	for name, value := range r.Users {
+		if _, ok := roleNames[value]; !ok {
+			return fmt.Errorf("roles: unknown role %q for %s (viewer, operator or admin)", value, name)
+		}
+	}
+	return nil
+}
+
+// bulkCommandPrefixes are the commands that submit transactions for many
+// applications at once.
+var bulkCommandPrefixes = []string{
+	"fa ", "fa! ", "fund-all ", "fund-all! ", "ua ", "ua! ", "upstake-all ", "upstake-all! ", "grant-all ",
+}
+
+// adminCommands are the exact commands reserved to admins.
+var adminCommands = map[string]bool{"drain-all": true, "grant-all": true, "autofund": true, "config": true, "edit-plan": true}
+
+// adminHelpEntries are the help entries of keys and commands reserved to
+// admins, hidden from operators.
+var adminHelpEntries = []string{
+	"F  ", "U  ", "fa <amount>", "ua <amount>", "fa @<file>", "drain-all ", "edit-plan ", "autofund ", "queue ", "config ", "import ", "adopt ", "onboard ", "decommission ", "! ",
+}
+
+// currentUser returns the name roles are looked up by: the account running
+// GASMS, which the user cannot pick themselves.
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// baseConfig caches the config roles are read from, reloaded when the file
+// changes.
+var baseConfig struct {
+	sync.Mutex
+	loaded  bool
+	modTime time.Time
+	size    int64
+	config  *Config
+	err     error
+}
+
+// roleConfig returns the config roles are taken from: config.yaml in the
+// working directory, whatever profile or -config file is loaded, since users
+// can write those themselves and would otherwise grant themselves a role.
+// Without a base config it is config.
+func roleConfig(config *Config) (*Config, error) {
+	info, err := os.Stat(defaultConfigFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	baseConfig.Lock()
+	defer baseConfig.Unlock()
+	if !baseConfig.loaded || !info.ModTime().Equal(baseConfig.modTime) || info.Size() != baseConfig.size {
+		baseConfig.loaded, baseConfig.modTime, baseConfig.size = true, info.ModTime(), info.Size()
+		baseConfig.config, baseConfig.err = LoadConfig(defaultConfigFile)
+		if baseConfig.err != nil {
+			logger.Error("failed to read roles from the base config; everyone is a viewer", "path", defaultConfigFile, "error", baseConfig.err)
+		}
+	}
+	return baseConfig.config, baseConfig.err
+}
+
+// configRoles returns the roles that apply under config.
+func configRoles(config *Config) Roles {
+	if source, err := roleConfig(config); err == nil && source != nil {
+		return source.Config.Roles
+	}
+	return Roles{}
+}
+
+// user returns who runs the session: the user an SSH session of "gasms
+// serve" authenticated as, or the account running GASMS.
+func (m model) user() string {
+	if m.sessionUser != "" {
+		return m.sessionUser
+	}
+	return currentUser()
+}
+
+// operator identifies who runs the session in audit records and logs, as
+// user@host.
+func (m model) operator() string {
+	if m.sessionUser != "" {
+		return operatorAt(m.sessionUser)
+	}
+	return auditOperator()
+}
+
+// role returns the role of the user of the session. Read-only mode makes
+// everyone a viewer.
+func (m model) role() role {
+	return userRole(m.config, m.user())
+}
+
+// configRole returns the role config gives the account running GASMS, for
+// the subcommands that submit transactions.
+func configRole(config *Config) role {
+	return userRole(config, currentUser())
+}
+
+// userRole returns the role config gives user.
+func userRole(config *Config, user string) role {
+	if configReadOnly(config) {
+		return roleViewer
+	}
+	source, err := roleConfig(config)
+	if err != nil {
+		return roleViewer
+	}
+	if source == nil {
+		return roleAdmin
+	}
+	roles := source.Config.Roles
+	name, listed := roles.Users[user]
+	if !listed {
+		name = roles.Default
+	}
+	if name == "" {
+		return roleAdmin
+	}
+	return roleNames[name]
+}
+
+// isAdminCommand reports whether cmd is reserved to admins: bulk
+// transactions and config changes.
+func isAdminCommand(cmd string) bool {
+	if adminCommands[cmd] || strings.HasPrefix(cmd, "!") || strings.HasPrefix(cmd, "import ") || cmd == "adopt" || strings.HasPrefix(cmd, "adopt ") || strings.HasPrefix(cmd, "onboard ") || strings.HasPrefix(cmd, "decommission ") {
+		return true
+	}
+	for _, prefix := range bulkCommandPrefixes {
+		if strings.HasPrefix(cmd, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// refuseCommand returns the error shown when the current role may not run
+// cmd, or nil if it may.
+func (m model) refuseCommand(cmd string) error {
+	name := strings.Fields(cmd + " ")[0]
+	switch r := m.role(); {
+	case r == roleViewer && (isTxCommand(cmd) || isAdminCommand(cmd)):
+		if m.readOnly() {
+			return fmt.Errorf("read-only mode: %s is disabled", name)
+		}
+		return fmt.Errorf("%s is not allowed for the viewer role", name)
+	case r == roleOperator && isAdminCommand(cmd):
+		return fmt.Errorf("%s needs the admin role", name)
+	}
+	return nil
+}
+
+// refusePlugin returns the error shown when the current role may not run the
+// plugin command cmd, or nil if it may. Plugins run external programs that
+// may submit transactions, so like "!" they need the admin role and are
+// disabled in read-only mode.
+func (m model) refusePlugin(cmd string) error {
+	name := strings.Fields(cmd + " ")[0]
+	switch {
+	case m.readOnly():
+		return fmt.Errorf("read-only mode: %s is disabled", name)
+	case m.role() != roleAdmin:
+		return fmt.Errorf("%s needs the admin role", name)
+	}
+	return nil
+}
+
+// refuseSubmission returns why the current role may not broadcast entry, or
+// "" if it may.
+func (m model) refuseSubmission(entry queuedSubmission) string {
+	switch m.role() {
+	case roleViewer:
+		if m.readOnly() {
+			return "read-only mode"
+		}
+		return "viewer role"
+	case roleOperator:
+		if entry.plan {
+			return "bulk transactions need the admin role"
+		}
+		limit := configRoles(m.config).OperatorLimit
+		for _, row := range entry.rows {
+			if limit > 0 && row.amount > limit {
+				return fmt.Sprintf("%s %s is above the operator limit of %s %s",
+					m.formatAmount(row.amount), m.unitLabel(), m.formatAmount(limit), m.unitLabel())
+			}
+		}
+	}
+	return ""
+}
+
+// roleHelp removes the help entries the current role cannot use.
+func (m model) roleHelp(help string) string {
+	switch m.role() {
+	case roleViewer:
+		return hideHelp(hideHelp(help, txHelpEntries), adminHelpEntries)
+	case roleOperator:
+		return hideHelp(help, adminHelpEntries)
+	}
+	return help
+}
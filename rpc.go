@@ -0,0 +1,455 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const probeTimeout = 5 * time.Second
+
+// endpointHealth is the result of the most recent probe of an RPC endpoint.
+type endpointHealth struct {
+	URL       string
+	Healthy   bool
+	Probed    bool
+	Latency   time.Duration
+	CheckedAt time.Time
+	Err       string
+
+	// Chain status reported by the endpoint
+	Height     int64
+	CatchingUp bool
+	BlockTime  time.Time
+}
+
+type networkEndpoints struct {
+	endpoints []endpointHealth
+	active    int
+}
+
+// endpointPool tracks the RPC endpoints of every network and which one is
+// currently used. It is shared by queries and transactions.
+type endpointPool struct {
+	mu       sync.Mutex
+	networks map[string]*networkEndpoints
+}
+
+var rpcPool = &endpointPool{networks: make(map[string]*networkEndpoints)}
+
+type endpointsProbedMsg struct {
+	network string
+}
+
+// endpoints returns the configured RPC endpoints in priority order.
+func (n Network) endpoints() []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, endpoint := range append([]string{n.RPCEndpoint}, n.RPCEndpoints...) {
+		endpoint = strings.TrimSpace(endpoint)
+		if endpoint == "" || seen[endpoint] {
+			continue
+		}
+		seen[endpoint] = true
+		urls = append(urls, endpoint)
+	}
+	return urls
+}
+
+// configure registers the endpoints of every network, keeping health data for
+// endpoints that are still configured.
+func (p *endpointPool) configure(networks map[string]Network) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for name, network := range networks {
+		previous := make(map[string]endpointHealth)
+		if existing, ok := p.networks[name]; ok {
+			for _, health := range existing.endpoints {
+				previous[health.URL] = health
+			}
+		}
+		entry := &networkEndpoints{}
+		for _, endpoint := range network.endpoints() {
+			health, ok := previous[endpoint]
+			if !ok {
+				health = endpointHealth{URL: endpoint, Healthy: true}
+			}
+			entry.endpoints = append(entry.endpoints, health)
+		}
+		p.networks[name] = entry
+	}
+}
+
+// active returns the endpoint currently used for a network, or fallback if the
+// network has no registered endpoints.
+func (p *endpointPool) active(network, fallback string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.networks[network]
+	if !ok || len(entry.endpoints) == 0 {
+		return fallback
+	}
+	return entry.endpoints[entry.active].URL
+}
+
+// status returns the health of the active endpoint of a network.
+func (p *endpointPool) status(network string) (endpointHealth, int, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.networks[network]
+	if !ok || len(entry.endpoints) == 0 {
+		return endpointHealth{}, 0, false
+	}
+	return entry.endpoints[entry.active], len(entry.endpoints), true
+}
+
+func (p *endpointPool) record(network string, health endpointHealth) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.networks[network]
+	if !ok {
+		return
+	}
+	for i := range entry.endpoints {
+		if entry.endpoints[i].URL == health.URL {
+			entry.endpoints[i] = health
+		}
+	}
+}
+
+// selectActive makes the first healthy endpoint (in priority order) active.
+func (p *endpointPool) selectActive(network string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.networks[network]
+	if !ok {
+		return
+	}
+	for i, health := range entry.endpoints {
+		if health.Healthy {
+			entry.active = i
+			return
+		}
+	}
+}
+
+func (p *endpointPool) urls(network string) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entry, ok := p.networks[network]
+	if !ok {
+		return nil
+	}
+	urls := make([]string, len(entry.endpoints))
+	for i, health := range entry.endpoints {
+		urls[i] = health.URL
+	}
+	return urls
+}
+
+// probeAll checks every endpoint of a network concurrently and activates the
+// first healthy one.
+func (p *endpointPool) probeAll(network string) {
+	var wg sync.WaitGroup
+	for _, endpoint := range p.urls(network) {
+		wg.Add(1)
+		go func(endpoint string) {
+			defer wg.Done()
+			p.record(network, probeEndpoint(endpoint))
+		}(endpoint)
+	}
+	wg.Wait()
+	p.selectActive(network)
+}
+
+// failover marks an endpoint as unhealthy, probes the endpoints not yet tried
+// and activates the first healthy one. It returns "" if none is left.
+func (p *endpointPool) failover(network, failed string, cause error, tried map[string]bool) string {
+	p.record(network, endpointHealth{URL: failed, Probed: true, CheckedAt: time.Now(), Err: cause.Error()})
+	logger.Warn("rpc endpoint failed", "network", network, "endpoint", failed, "error", cause)
+
+	for _, endpoint := range p.urls(network) {
+		if tried[endpoint] {
+			continue
+		}
+		health := probeEndpoint(endpoint)
+		p.record(network, health)
+		if health.Healthy {
+			p.selectActive(network)
+			logger.Info("rpc failover", "network", network, "endpoint", endpoint)
+			return endpoint
+		}
+	}
+	return ""
+}
+
+// probeEndpoint queries the CometBFT /status route of an RPC endpoint.
+func probeEndpoint(endpoint string) endpointHealth {
+	health := endpointHealth{URL: endpoint, Probed: true, CheckedAt: time.Now()}
+
+	client := &http.Client{Timeout: probeTimeout}
+	start := time.Now()
+	resp, err := client.Get(statusURL(endpoint))
+	health.Latency = time.Since(start)
+	if err != nil {
+		health.Err = err.Error()
+		return health
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		health.Err = err.Error()
+		return health
+	}
+	if resp.StatusCode != http.StatusOK {
+		health.Err = resp.Status
+		return health
+	}
+
+	var status struct {
+		Result struct {
+			SyncInfo *struct {
+				LatestBlockHeight flexInt   `json:"latest_block_height"`
+				LatestBlockTime   time.Time `json:"latest_block_time"`
+				CatchingUp        bool      `json:"catching_up"`
+			} `json:"sync_info"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(body, &status); err != nil || status.Result.SyncInfo == nil {
+		health.Err = "unexpected /status response"
+		return health
+	}
+
+	health.Healthy = true
+	health.Height = int64(status.Result.SyncInfo.LatestBlockHeight)
+	health.BlockTime = status.Result.SyncInfo.LatestBlockTime
+	health.CatchingUp = status.Result.SyncInfo.CatchingUp
+	return health
+}
+
+// statusURL converts a pocketd --node value into the HTTP URL of its /status route.
+func statusURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return strings.TrimSuffix(endpoint, "/") + "/status"
+	}
+	if u.Scheme == "tcp" {
+		u.Scheme = "http"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/status"
+	return u.String()
+}
+
+// isEndpointError reports whether err indicates the RPC endpoint itself is
+// unreachable or unhealthy rather than a problem with the request.
+func isEndpointError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{
+		"connection refused",
+		"no such host",
+		"connection reset",
+		"network is unreachable",
+		"i/o timeout",
+		"context deadline exceeded",
+		"502 bad gateway",
+		"503 service unavailable",
+		"504 gateway timeout",
+		"post failed",
+	} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConnectError reports whether err happened before a connection to the
+// endpoint was established, so the request cannot have reached the node.
+func isConnectError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	for _, marker := range []string{"connection refused", "no such host", "network is unreachable"} {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// withFailover runs a query against the active endpoint of a network and
+// retries on the next healthy endpoint when the endpoint itself fails.
+func withFailover(network, fallback string, fn func(endpoint string) error) error {
+	return runWithFailover(network, fallback, isEndpointError, fn)
+}
+
+// withBroadcastFailover is withFailover for transactions: it only fails over
+// when the endpoint could not be reached at all, since a broadcast that timed
+// out may still have been included and must not be sent twice.
+func withBroadcastFailover(network, fallback string, fn func(endpoint string) error) error {
+	return runWithFailover(network, fallback, isConnectError, fn)
+}
+
+// runWithFailover retries rate-limited requests on the same endpoint and
+// fails over on retryable errors. Once every endpoint has failed, the whole
+// round is retried with exponential backoff.
+func runWithFailover(network, fallback string, retryable func(error) bool, fn func(endpoint string) error) error {
+	ctx := operations.context()
+	tried := make(map[string]bool)
+	endpoint := rpcPool.active(network, fallback)
+	attempt := 0
+	for {
+		err := fn(endpoint)
+		if err == nil {
+			return nil
+		}
+		if isCancelled(err) {
+			return err
+		}
+		if isRateLimitError(err) {
+			delay, ok := pocketdLimiter.retryDelay(attempt)
+			if !ok {
+				return fmt.Errorf("rate limited after %d retries: %w", attempt, err)
+			}
+			logger.Warn("rate limited, backing off", "network", network, "endpoint", endpoint, "attempt", attempt+1, "delay", delay.String())
+			attempt++
+			if !sleepContext(ctx, delay) {
+				return errCancelled
+			}
+			continue
+		}
+		if !retryable(err) {
+			return err
+		}
+		tried[endpoint] = true
+		next := rpcPool.failover(network, endpoint, err, tried)
+		if next == "" {
+			delay, ok := pocketdLimiter.retryDelay(attempt)
+			if !ok {
+				return fmt.Errorf("all RPC endpoints failed: %w", err)
+			}
+			logger.Warn("all RPC endpoints failed, backing off", "network", network, "attempt", attempt+1, "delay", delay.String(), "error", err)
+			attempt++
+			if !sleepContext(ctx, delay) {
+				return errCancelled
+			}
+			tried = make(map[string]bool)
+			endpoint = rpcPool.active(network, fallback)
+			continue
+		}
+		endpoint = next
+	}
+}
+
+// refreshActive re-probes the active endpoint of a network and fails over to
+// another endpoint if it stopped responding.
+func (p *endpointPool) refreshActive(network string) {
+	health, _, ok := p.status(network)
+	if !ok {
+		return
+	}
+	health = probeEndpoint(health.URL)
+	p.record(network, health)
+	if !health.Healthy {
+		p.probeAll(network)
+	}
+}
+
+func probeEndpointsCmd(network string) tea.Cmd {
+	return func() tea.Msg {
+		rpcPool.probeAll(network)
+		return endpointsProbedMsg{network: network}
+	}
+}
+
+type chainStatusTickMsg struct{}
+
+const defaultStatusInterval = 15 * time.Second
+
+// statusInterval returns how often the chain status in the header is refreshed.
+func (m model) statusInterval() time.Duration {
+	if m.config == nil || m.config.Config.StatusInterval == "" {
+		return defaultStatusInterval
+	}
+	interval, err := time.ParseDuration(m.config.Config.StatusInterval)
+	if err != nil || interval < time.Second {
+		return defaultStatusInterval
+	}
+	return interval
+}
+
+func chainStatusTickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
+		return chainStatusTickMsg{}
+	})
+}
+
+func refreshChainStatusCmd(network string) tea.Cmd {
+	return func() tea.Msg {
+		rpcPool.refreshActive(network)
+		return endpointsProbedMsg{network: network}
+	}
+}
+
+// chainStatusLine renders block height and sync state for the header.
+func (m model) chainStatusLine() string {
+	health, _, ok := rpcPool.status(m.currentNetwork)
+	if !ok || !health.Probed || health.Height == 0 {
+		return "⛓️  Block: -"
+	}
+	line := fmt.Sprintf("⛓️  Block: %d", health.Height)
+	switch {
+	case health.CatchingUp:
+		line += " ⚠️ CATCHING UP"
+	case !health.BlockTime.IsZero() && time.Since(health.BlockTime) > 2*time.Minute:
+		line += fmt.Sprintf(" ⚠️ STALLED (last block %s)", formatAge(time.Since(health.BlockTime)))
+	default:
+		line += " (synced)"
+	}
+	if m.watchConnected {
+		line += " 🔌 live"
+	}
+	return line
+}
+
+// endpointHost returns the host of an endpoint URL for compact display.
+func endpointHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+	return u.Host
+}
+
+// rpcStatusLine renders the active endpoint and its latency for the header.
+func (m model) rpcStatusLine() string {
+	health, count, ok := rpcPool.status(m.currentNetwork)
+	if !ok {
+		return "📡 RPC: -"
+	}
+	line := "📡 RPC: " + endpointHost(health.URL)
+	switch {
+	case !health.Probed:
+		line += " (probing...)"
+	case health.Healthy:
+		line += fmt.Sprintf(" (%dms)", health.Latency.Milliseconds())
+	default:
+		line += " (unreachable)"
+	}
+	if count > 1 {
+		line += fmt.Sprintf(" [%d endpoints]", count)
+	}
+	return line
+}
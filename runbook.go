@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// runbookPollInterval is how often a running runbook checks whether the
+// transactions of its current line have finished.
+const runbookPollInterval = 500 * time.Millisecond
+
+// Status of a runbook line
+const (
+	runbookPending = "pending"
+	runbookRunning = "running"
+	runbookOK      = "ok"
+	runbookFailed  = "failed"
+	runbookSkipped = "skipped"
+)
+
+// runbook is a file of GASMS commands run one after the other by :run.
+// Each line waits for the transactions and refreshes of the previous one.
+type runbook struct {
+	path        string
+	stopOnError bool
+	lines       []runbookLine
+	next        int  // Index of the line to start next
+	done        bool // Every line ran, or the runbook stopped
+}
+
+// runbookLine is a command of a runbook and its result.
+type runbookLine struct {
+	command string
+	status  string
+	detail  string // Error of a failed line
+	firstTx int    // Transactions tracked after this id belong to the line
+}
+
+type runbookTickMsg struct {
+	runbook *runbook
+}
+
+func runbookTickCmd(rb *runbook) tea.Cmd {
+	return tea.Tick(runbookPollInterval, func(time.Time) tea.Msg {
+		return runbookTickMsg{runbook: rb}
+	})
+}
+
+// readRunbook reads the commands of a runbook file. Blank lines and #
+// comments are skipped, and a leading ":" is optional.
+func readRunbook(path string) ([]runbookLine, error) {
+	path = expandHome(path)
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []runbookLine
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		command := strings.TrimPrefix(strings.TrimSpace(scanner.Text()), ":")
+		if command == "" || strings.HasPrefix(command, "#") {
+			continue
+		}
+		if name := strings.Fields(command)[0]; name == "run" {
+			return nil, fmt.Errorf("line %d: runbooks cannot run other runbooks", number)
+		}
+		lines = append(lines, runbookLine{command: command, status: runbookPending})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no commands in %s", path)
+	}
+	return lines, nil
+}
+
+// handleRunCommand starts "run <file> [--stop-on-error]", or shows the last
+// runbook when no file is given.
+func (m model) handleRunCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) == 1 {
+		if m.runbook == nil {
+			m.err = fmt.Errorf("usage: run <file> [--stop-on-error]")
+			return m, nil
+		}
+		m.state = stateRunbook
+		return m, nil
+	}
+	stopOnError := false
+	for _, flag := range parts[2:] {
+		if flag != "--stop-on-error" {
+			m.err = fmt.Errorf("unknown flag: %s", flag)
+			return m, nil
+		}
+		stopOnError = true
+	}
+	if m.runbook != nil && !m.runbook.done {
+		m.err = fmt.Errorf("runbook %s is still running", m.runbook.path)
+		return m, nil
+	}
+	lines, err := readRunbook(parts[1])
+	if err != nil {
+		m.err = fmt.Errorf("failed to read runbook: %v", err)
+		return m, nil
+	}
+
+	m.runbook = &runbook{path: parts[1], stopOnError: stopOnError, lines: lines}
+	m.runbookCursor = 0
+	m.state = stateRunbook
+	logger.Info("runbook started", "path", parts[1], "commands", len(lines), "stop_on_error", stopOnError)
+	return m.stepRunbook()
+}
+
+// runbookBusy reports whether the current line of the runbook is still
+// running: its transactions are not broadcast and included yet, or a
+// refresh or reconcile is in progress.
+func (m model) runbookBusy(line runbookLine) bool {
+	if m.loading || len(m.pendingBalances) > 0 || m.reconcileCh != nil || len(m.cooling) > 0 || m.coApproval != nil {
+		return true
+	}
+	// Views opened by the line, such as a confirmation, wait for the operator
+	if m.state != stateTable && m.state != stateRunbook {
+		return true
+	}
+	for _, tx := range m.txs {
+		if tx.id > line.firstTx && !tx.finished() && tx.status != txQueued {
+			return true
+		}
+	}
+	return false
+}
+
+// stepRunbook finishes the running line of the runbook once it is idle and
+// starts the next one.
+func (m model) stepRunbook() (model, tea.Cmd) {
+	rb := m.runbook
+	if rb == nil || rb.done {
+		return m, nil
+	}
+
+	if rb.next > 0 {
+		line := &rb.lines[rb.next-1]
+		if line.status == runbookRunning {
+			if m.runbookBusy(*line) {
+				return m, runbookTickCmd(rb)
+			}
+			line.status = runbookOK
+			for _, tx := range m.txs {
+				if tx.id > line.firstTx && (tx.status == txFailed || tx.status == txRejected) {
+					line.status = runbookFailed
+					line.detail = fmt.Sprintf("%s %s: %s", tx.kind, tx.status, tx.err)
+					break
+				}
+			}
+		}
+		if line.status == runbookFailed && rb.stopOnError {
+			for i := rb.next; i < len(rb.lines); i++ {
+				rb.lines[i].status = runbookSkipped
+			}
+			rb.next = len(rb.lines)
+		}
+	}
+
+	if rb.next >= len(rb.lines) {
+		rb.done = true
+		return m, m.runbookFinished()
+	}
+
+	// Run the next line as if it was typed in command mode
+	line := &rb.lines[rb.next]
+	rb.next++
+	line.status = runbookRunning
+	line.firstTx = m.nextTxID
+	returnTo := m.state
+	m.state = stateCommand
+	m.commandInput = line.command
+	m, cmd := m.updateCommand(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.err != nil {
+		line.status = runbookFailed
+		line.detail = m.err.Error()
+	}
+	if m.state == stateTable && returnTo == stateRunbook {
+		m.state = stateRunbook
+	}
+	return m, tea.Batch(cmd, runbookTickCmd(rb))
+}
+
+// runbookFinished reports the result of the finished runbook.
+func (m *model) runbookFinished() tea.Cmd {
+	var ok, failed, skipped int
+	for _, line := range m.runbook.lines {
+		switch line.status {
+		case runbookOK:
+			ok++
+		case runbookFailed:
+			failed++
+		case runbookSkipped:
+			skipped++
+		}
+	}
+	logger.Info("runbook finished", "path", m.runbook.path, "ok", ok, "failed", failed, "skipped", skipped)
+	summary := fmt.Sprintf("Runbook %s finished: %d ok, %d failed, %d skipped", m.runbook.path, ok, failed, skipped)
+	if failed > 0 {
+		return m.notify(toastWarning, summary)
+	}
+	return m.notify(toastSuccess, summary)
+}
+
+func (m model) updateRunbook(msg tea.KeyMsg) (model, tea.Cmd) {
+	rb := m.runbook
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.runbookCursor > 0 {
+			m.runbookCursor--
+		}
+	case "down", "j":
+		if rb != nil && m.runbookCursor < len(rb.lines)-1 {
+			m.runbookCursor++
+		}
+	case "x":
+		// Stop after the running line; it cannot be taken back
+		if rb == nil || rb.done {
+			return m, nil
+		}
+		for i := rb.next; i < len(rb.lines); i++ {
+			rb.lines[i].status = runbookSkipped
+		}
+		rb.next = len(rb.lines)
+		return m, m.notify(toastInfo, "Runbook stopped; the running command finishes")
+	}
+	return m, nil
+}
+
+// renderRunbook lists the commands of the runbook with their results.
+func (m model) renderRunbook() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 6)
+
+	rb := m.runbook
+	if rb == nil {
+		return ""
+	}
+	title := fmt.Sprintf("📜 RUNBOOK • %s", rb.path)
+	if rb.stopOnError {
+		title += " • stop on error"
+	}
+	content := []string{headerStyle.Render(title), ""}
+	for i, line := range rb.lines {
+		var icon string
+		switch line.status {
+		case runbookPending:
+			icon = "·"
+		case runbookRunning:
+			icon = m.spinner()
+		case runbookOK:
+			icon = "✅"
+		case runbookFailed:
+			icon = "❌"
+		case runbookSkipped:
+			icon = "⏭️"
+		}
+		text := truncateToWidth(fmt.Sprintf("%s %3d  %s", icon, i+1, line.command), max(m.width-6, 20))
+		if i == m.runbookCursor {
+			content = append(content, selectedStyle.Render(text))
+		} else {
+			content = append(content, textStyle.Render(text))
+		}
+		if line.detail != "" {
+			content = append(content, errorStyle.Render(truncateToWidth(line.detail, max(m.width-10, 20))))
+		}
+	}
+
+	content = append(content, "")
+	help := "j/k to move • x to stop after the running command • ESC or Q to return (the runbook keeps running; :run shows it again)"
+	if rb.done {
+		help = "j/k to move • ESC or Q to return"
+	}
+	content = append(content, textStyle.Render(help))
+	return strings.Join(content, "\n")
+}
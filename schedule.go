@@ -0,0 +1,397 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const scheduleReconcile = "reconcile"
+
+// Schedule runs an operation at the times matched by a cron expression.
+type Schedule struct {
+	Name     string `yaml:"name"`
+	Cron     string `yaml:"cron"`               // minute hour day-of-month month day-of-week, or @hourly/@daily/@weekly/@monthly
+	Timezone string `yaml:"timezone,omitempty"` // IANA zone the cron expression is read in (default UTC)
+	Network  string `yaml:"network"`
+	Action   string `yaml:"action"` // reconcile: bring the applications to their targets
+}
+
+// cronSpec is a parsed cron expression; each field is a bitset of the values
+// it matches.
+type cronSpec struct {
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+}
+
+// parseCron parses a five-field cron expression. Fields accept *, values,
+// ranges (1-5), lists (1,3) and steps (*/15, 0-30/10, 5/15); day-of-week is 0-6
+// with 0 (or 7) for Sunday.
+func parseCron(expr string) (cronSpec, error) {
+	if alias, ok := cronAliases[strings.TrimSpace(expr)]; ok {
+		expr = alias
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return cronSpec{}, fmt.Errorf("cron %q: expected 5 fields (minute hour day-of-month month day-of-week)", expr)
+	}
+	var spec cronSpec
+	var err error
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	targets := [5]*uint64{&spec.minute, &spec.hour, &spec.dom, &spec.month, &spec.dow}
+	for i, field := range fields {
+		if *targets[i], err = parseCronField(field, bounds[i][0], bounds[i][1]); err != nil {
+			return cronSpec{}, fmt.Errorf("cron %q: %w", expr, err)
+		}
+	}
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1 // 7 is Sunday too
+	}
+	// As in cron, a day field starting with * is unrestricted, while an
+	// explicit 1-31 or 0-6 still takes part in the day-of-month or
+	// day-of-week match
+	spec.domAny = strings.HasPrefix(fields[2], "*")
+	spec.dowAny = strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+func parseCronField(field string, low, high int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		step, stepped := 1, false
+		if i := strings.Index(part, "/"); i >= 0 {
+			stepped = true
+			var err error
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			part = part[:i]
+		}
+		from, to := low, high
+		if part != "*" {
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if from, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if stepped {
+				to = high // As in cron, 5/15 steps from 5 to the end of the range
+			}
+			if len(bounds) == 2 {
+				if to, err = strconv.Atoi(bounds[1]); err != nil {
+					return 0, fmt.Errorf("invalid range %q", part)
+				}
+			}
+			if from < low || to > high || from > to {
+				return 0, fmt.Errorf("%q is out of range %d-%d", part, low, high)
+			}
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// matches reports whether the minute of t is matched. As in cron, when both
+// day fields are restricted a day matching either one is enough.
+func (c cronSpec) matches(t time.Time) bool {
+	if c.minute&(1<<t.Minute()) == 0 || c.hour&(1<<t.Hour()) == 0 || c.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	dom := c.dom&(1<<t.Day()) != 0
+	dow := c.dow&(1<<int(t.Weekday())) != 0
+	switch {
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// next returns the first matched minute after t, or the zero time if none
+// comes within a year.
+func (c cronSpec) next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for limit := t.AddDate(1, 0, 0); t.Before(limit); t = t.Add(time.Minute) {
+		if c.matches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(s.Timezone)
+}
+
+// due reports whether the schedule fires at the minute of t.
+func (s Schedule) due(t time.Time) bool {
+	spec, err := parseCron(s.Cron)
+	if err != nil {
+		return false
+	}
+	loc, err := s.location()
+	if err != nil {
+		return false
+	}
+	return spec.matches(t.In(loc))
+}
+
+func validateSchedules(config *Config) error {
+	names := make(map[string]bool)
+	for _, s := range config.Config.Schedules {
+		if s.Name == "" || names[s.Name] {
+			return fmt.Errorf("schedules: every schedule needs a unique name")
+		}
+		names[s.Name] = true
+		if _, err := parseCron(s.Cron); err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		if _, err := s.location(); err != nil {
+			return fmt.Errorf("schedule %s: %w", s.Name, err)
+		}
+		if _, exists := config.Config.Networks[s.Network]; !exists {
+			return fmt.Errorf("schedule %s: network not found: %s", s.Name, s.Network)
+		}
+		if s.Action != scheduleReconcile {
+			return fmt.Errorf("schedule %s: unknown action %q (reconcile)", s.Name, s.Action)
+		}
+	}
+	return nil
+}
+
+// claimRun records that schedule fires at the minute at, and returns false if
+// another GASMS process on this host already claimed it.
+func claimRun(schedule Schedule, at time.Time) bool {
+	path, err := dataPath("schedules", safeFileName(schedule.Name)+"-"+at.UTC().Format("200601021504"))
+	if err != nil {
+		logger.Error("failed to claim scheduled run", "schedule", schedule.Name, "error", err)
+		return false
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return false
+	}
+	fmt.Fprintln(f, auditOperator())
+	f.Close()
+
+	// Earlier runs need no marker any more
+	older, _ := filepath.Glob(filepath.Join(filepath.Dir(path), safeFileName(schedule.Name)+"-*"))
+	for _, marker := range older {
+		if marker < path {
+			os.Remove(marker)
+		}
+	}
+	return true
+}
+
+// scheduleCommand is the audit log command of a scheduled run.
+func scheduleCommand(schedule Schedule) string {
+	return "schedule " + schedule.Name
+}
+
+type scheduleTickMsg struct {
+	at time.Time
+}
+
+type scheduledPlanMsg struct {
+	schedule Schedule
+	plan     *stakePlan
+	err      error
+}
+
+// scheduleTickCmd ticks at the start of the next minute.
+func scheduleTickCmd() tea.Cmd {
+	now := time.Now()
+	return tea.Tick(now.Truncate(time.Minute).Add(time.Minute).Sub(now), func(t time.Time) tea.Msg {
+		return scheduleTickMsg{at: t.Truncate(time.Minute)}
+	})
+}
+
+// runDueSchedules plans the schedules due at msg.at on the current network.
+// Only admin sessions run schedules.
+func (m model) runDueSchedules(msg scheduleTickMsg) (model, tea.Cmd) {
+	cmds := []tea.Cmd{scheduleTickCmd()}
+	if m.config == nil || m.role() != roleAdmin {
+		return m, cmds[0]
+	}
+	config := m.config
+	for _, schedule := range config.Config.Schedules {
+		if !schedule.due(msg.at) {
+			continue
+		}
+		if schedule.Network != m.currentNetwork {
+			// Left to the scheduler daemon or a session on that network
+			logger.Info("scheduled run left to another session", "schedule", schedule.Name, "network", schedule.Network)
+			continue
+		}
+		if !claimRun(schedule, msg.at) {
+			continue
+		}
+		logger.Info("scheduled run started", "schedule", schedule.Name, "network", schedule.Network, "action", schedule.Action)
+		schedule := schedule
+		cmds = append(cmds, func() tea.Msg {
+			plan, err := computePlan(config, schedule.Network)
+			return scheduledPlanMsg{schedule: schedule, plan: plan, err: err}
+		})
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// scheduledPlan runs the plan of a scheduled reconcile like a confirmed one,
+// so that the approval queue and two-person rule still apply.
+func (m model) scheduledPlan(msg scheduledPlanMsg) (model, tea.Cmd) {
+	name := msg.schedule.Name
+	switch {
+	case msg.err != nil:
+		logger.Error("scheduled run failed", "schedule", name, "error", msg.err)
+		return m, m.notify(toastError, fmt.Sprintf("Schedule %s failed: %v", name, msg.err))
+	case len(msg.plan.Items) == 0:
+		logger.Info("scheduled run had nothing to do", "schedule", name)
+		return m, nil
+	case msg.plan.BankRequiredUpokt > msg.plan.BankBalanceUpokt:
+		logger.Error("scheduled run refused: insufficient bank balance", "schedule", name,
+			"bank_required_upokt", msg.plan.BankRequiredUpokt, "bank_balance_upokt", msg.plan.BankBalanceUpokt)
+		return m, m.notify(toastError, fmt.Sprintf("Schedule %s refused: bank needs %s %s, has %s",
+			name, m.formatAmount(msg.plan.BankRequiredUpokt), m.unitLabel(), m.formatAmount(msg.plan.BankBalanceUpokt)))
+	case msg.schedule.Network != m.currentNetwork:
+		logger.Warn("scheduled run skipped: other network selected", "schedule", name, "network", msg.schedule.Network)
+		return m, m.notify(toastWarning, fmt.Sprintf("Schedule %s skipped: switch to %s to run it", name, msg.schedule.Network))
+	case m.reconcileCh != nil:
+		logger.Warn("scheduled run skipped: reconcile running", "schedule", name)
+		return m, m.notify(toastWarning, fmt.Sprintf("Schedule %s skipped: another reconcile is running", name))
+	}
+	next, cmd := m.startPlan(msg.plan, scheduleCommand(msg.schedule))
+	return next, tea.Batch(cmd, m.notify(toastInfo, fmt.Sprintf("Schedule %s: %d transactions", name, len(msg.plan.Items))))
+}
+
+// runScheduler implements "gasms scheduler", which runs the configured
+// schedules until interrupted.
+func runScheduler(args []string) error {
+	flags := flag.NewFlagSet("scheduler", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	flags.Parse(args)
+
+	config, err := loadCLIConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if len(config.Config.Schedules) == 0 {
+		return fmt.Errorf("no schedules configured in %s", *configPath)
+	}
+	if configReadOnly(config) {
+		return fmt.Errorf("read-only is enabled: schedules cannot submit transactions")
+	}
+	if config.Config.ApprovalQueue {
+		return fmt.Errorf("approval-queue is enabled: scheduled transactions must be approved in a GASMS session, which runs the schedules itself")
+	}
+	for _, schedule := range config.Config.Schedules {
+		spec, _ := parseCron(schedule.Cron)
+		loc, _ := schedule.location()
+		fmt.Printf("%s: %s on %s, next at %s\n", schedule.Name, schedule.Action, schedule.Network,
+			spec.next(time.Now().In(loc)).Format("2006-01-02 15:04 MST"))
+	}
+	logger.Info("scheduler started", "schedules", len(config.Config.Schedules))
+
+	for {
+		now := time.Now()
+		at := now.Truncate(time.Minute).Add(time.Minute)
+		time.Sleep(at.Sub(now))
+		for _, schedule := range config.Config.Schedules {
+			if schedule.due(at) && claimRun(schedule, at) {
+				runScheduled(config, schedule)
+			}
+		}
+	}
+}
+
+// runScheduled runs one schedule without a UI, recording every transaction in
+// the audit log and exporting the receipts when configured.
+func runScheduled(config *Config, schedule Schedule) batchReport {
+	command := scheduleCommand(schedule)
+	started := time.Now()
+	logger.Info("scheduled run started", "schedule", schedule.Name, "network", schedule.Network, "action", schedule.Action)
+	fmt.Printf("%s %s: started\n", started.Format(time.RFC3339), schedule.Name)
+
+	plan, err := computePlan(config, schedule.Network)
+	if err == nil {
+		err = unattendedRefusal(config, plan)
+	}
+	if err != nil {
+		logger.Error("scheduled run failed", "schedule", schedule.Name, "error", err)
+		fmt.Printf("%s %s: failed: %v\n", time.Now().Format(time.RFC3339), schedule.Name, err)
+		report := batchReport{Kind: schedule.Action, Command: command, Network: schedule.Network, Operator: auditOperator(), StartedAt: started, FinishedAt: time.Now()}
+		report.Text = fmt.Sprintf("GASMS %s on %s failed: %v", command, schedule.Network, err)
+		report.Error = err.Error()
+		exportUnattended(config, report)
+		return report
+	}
+
+	var receipts []planReceipt
+	for _, item := range plan.Items {
+		receipt := applyItem(config, plan, item)
+		auditPlanReceipt(plan, command, receipt)
+		receipts = append(receipts, receipt)
+	}
+	report := planReport(schedule.Action, command, plan, receipts, started)
+	logger.Info("scheduled run finished", "schedule", schedule.Name, "included", report.Included, "failed", report.Failed)
+	fmt.Printf("%s %s: %s\n", time.Now().Format(time.RFC3339), schedule.Name, report.Text)
+	exportUnattended(config, report)
+	return report
+}
+
+// unattendedRefusal returns why plan may not be applied without an operator
+// at hand, or nil if it may.
+func unattendedRefusal(config *Config, plan *stakePlan) error {
+	switch {
+	case configReadOnly(config):
+		return errors.New("read-only is enabled")
+	case config.Config.ApprovalQueue:
+		return errors.New("approval-queue is enabled; transactions must be approved in a GASMS session")
+	case plan.BankRequiredUpokt > plan.BankBalanceUpokt:
+		return fmt.Errorf("insufficient bank balance: need %s, have %s",
+			formatPlanAmount(plan.BankRequiredUpokt), formatPlanAmount(plan.BankBalanceUpokt))
+	}
+	var total int64
+	for _, item := range plan.Items {
+		total += item.AmountUpokt
+	}
+	if twoPerson := configTwoPerson(config); twoPerson.enabled() && total >= twoPerson.Threshold {
+		return errors.New("moves at least the two-person threshold and needs a second approver")
+	}
+	return nil
+}
+
+// exportUnattended exports the receipts of a run made without a UI, when
+// receipt export is configured.
+func exportUnattended(config *Config, report batchReport) {
+	if !config.Config.Receipts.enabled() {
+		return
+	}
+	if path, err := exportReport(config.Config.Receipts, report); err != nil {
+		logger.Error("failed to export receipts", "command", report.Command, "error", err)
+	} else {
+		logger.Info("receipts exported", "command", report.Command, "path", path)
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// searchFields maps the field prefixes of a column search to the field they
+// search.
+var searchFields = map[string]string{
+	"addr":    "address",
+	"address": "address",
+	"svc":     "service",
+	"service": "service",
+	"gw":      "gateway",
+	"gateway": "gateway",
+	"status":  "status",
+	"stake":   "stake",
+	"balance": "balance",
+	"bal":     "balance",
+}
+
+// numericSearchFields are compared against an amount rather than matched as
+// text.
+var numericSearchFields = map[string]bool{"stake": true, "balance": true}
+
+// searchOperators are the comparisons of numeric fields, longest first so
+// "<=" is not read as "<".
+var searchOperators = []string{"<=", ">=", "<", ">", "="}
+
+// stakeStatusNames are the values of the status field, as shown by the
+// status column.
+var stakeStatusNames = []string{"green", "yellow", "red", "unstaking", "transferring"}
+
+// searchTerm is one word of a search: text matched in the address or service
+// ID, text matched in one field ("svc:eth"), or an amount compared with a
+// numeric field ("stake:<1000").
+type searchTerm struct {
+	field string // "" for the address or service ID
+	op    string // Comparison of a numeric field
+	text  string // Lowercased text to match
+	upokt int64  // Amount of a numeric field
+}
+
+// parseSearch parses a search into terms, all of which an application must
+// match; "and" between them is optional. Amounts are in the display unit
+// unless a unit is given.
+func (m model) parseSearch(input string) ([]searchTerm, error) {
+	var terms []searchTerm
+	for _, word := range strings.Fields(input) {
+		if strings.EqualFold(word, "and") {
+			continue
+		}
+		prefix, value, found := strings.Cut(word, ":")
+		if !found {
+			terms = append(terms, searchTerm{text: strings.ToLower(word)})
+			continue
+		}
+		field, known := searchFields[strings.ToLower(prefix)]
+		if !known {
+			return nil, fmt.Errorf("unknown search field %q: use addr, svc, gw, status, stake or balance", prefix)
+		}
+		if field == "status" && !slices.Contains(stakeStatusNames, strings.ToLower(value)) {
+			return nil, fmt.Errorf("unknown status %q: use %s", value, strings.Join(stakeStatusNames, ", "))
+		}
+		if !numericSearchFields[field] {
+			terms = append(terms, searchTerm{field: field, text: strings.ToLower(value)})
+			continue
+		}
+		op := "="
+		for _, candidate := range searchOperators {
+			if strings.HasPrefix(value, candidate) {
+				op, value = candidate, strings.TrimPrefix(value, candidate)
+				break
+			}
+		}
+		amount, err := m.parseDisplayAmount(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", word, err)
+		}
+		terms = append(terms, searchTerm{field: field, op: op, upokt: amount})
+	}
+	return terms, nil
+}
+
+// matches reports whether app matches term.
+func (m model) matches(app Application, term searchTerm) bool {
+	switch term.field {
+	case "":
+		return strings.Contains(strings.ToLower(app.Address), term.text) ||
+			strings.Contains(strings.ToLower(app.ServiceID), term.text)
+	case "address":
+		return strings.Contains(strings.ToLower(app.Address), term.text)
+	case "service":
+		return strings.Contains(strings.ToLower(app.ServiceID), term.text)
+	case "gateway":
+		return strings.Contains(strings.ToLower(m.rowGateway(app)), term.text)
+	case "status":
+		return m.stakeStatusName(app) == term.text
+	}
+
+	amount := app.BalanceUpokt
+	if term.field == "stake" {
+		amount = stakeUpokt(app)
+	}
+	switch term.op {
+	case "<":
+		return amount < term.upokt
+	case "<=":
+		return amount <= term.upokt
+	case ">":
+		return amount > term.upokt
+	case ">=":
+		return amount >= term.upokt
+	default:
+		return amount == term.upokt
+	}
+}
+
+// stakeStatusName names the status of app shown in the status column.
+func (m model) stakeStatusName(app Application) string {
+	warningThreshold, dangerThreshold := m.stakeThresholds()
+	switch stake := stakeUpokt(app); {
+	case isUnstaking(app):
+		return "unstaking"
+	case isTransferring(app):
+		return "transferring"
+	case stake >= warningThreshold:
+		return "green"
+	case stake >= dangerThreshold:
+		return "yellow"
+	default:
+		return "red"
+	}
+}
+
+// searchMatches returns the indexes of the applications matching every term
+// of input.
+func (m model) searchMatches(input string) ([]int, error) {
+	terms, err := m.parseSearch(input)
+	if err != nil {
+		return nil, err
+	}
+	matches := []int{}
+	for i, app := range m.applications {
+		matched := true
+		for _, term := range terms {
+			if !m.matches(app, term) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, i)
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// serviceChange is a re-stake of an application for a different set of
+// services, awaiting confirmation.
+type serviceChange struct {
+	address    string
+	before     []string
+	after      []string
+	stakeUpokt int64
+	memo       string // Attached to the transaction
+}
+
+type serviceChangedMsg struct {
+	txID   int
+	txHash string
+}
+
+// handleServiceCommand stages "svc <address> <service_id>[,<service_id>...]",
+// which replaces the services of an application, or "svc <address>
+// +<service_id>", which adds one.
+func (m model) handleServiceCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 3 {
+		m.err = fmt.Errorf("usage: svc <address> <service_id>[,<service_id>...] or svc <address> +<service_id>")
+		return m, nil
+	}
+	address, spec := parts[1], parts[2]
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	var app *Application
+	for i := range m.applications {
+		if m.applications[i].Address == address {
+			app = &m.applications[i]
+			break
+		}
+	}
+	if app == nil {
+		m.err = fmt.Errorf("application not found: %s", address)
+		return m, nil
+	}
+	if isUnstaking(*app) {
+		m.err = fmt.Errorf("application %s is unstaking", TruncateAddress(address, 13))
+		return m, nil
+	}
+
+	var after []string
+	if added := strings.TrimPrefix(spec, "+"); added != spec {
+		after = append(after, app.ServiceIDs...)
+		for _, serviceID := range app.ServiceIDs {
+			if serviceID == added {
+				m.err = fmt.Errorf("application is already staked for %s", added)
+				return m, nil
+			}
+		}
+		after = append(after, added)
+	} else {
+		after = splitServiceIDs(spec)
+	}
+	for _, serviceID := range after {
+		if serviceID == "" {
+			m.err = fmt.Errorf("invalid service ID list: %s", spec)
+			return m, nil
+		}
+	}
+	if joinServiceIDs(after) == joinServiceIDs(app.ServiceIDs) {
+		m.err = fmt.Errorf("application is already staked for %s", joinServiceIDs(after))
+		return m, nil
+	}
+
+	m.svcChange = &serviceChange{
+		address:    address,
+		before:     app.ServiceIDs,
+		after:      after,
+		stakeUpokt: stakeUpokt(*app),
+		memo:       m.config.Config.Memo,
+	}
+	m.state = stateServiceChange
+	return m, nil
+}
+
+// restakeApplication re-stakes address for serviceIDs, keeping its current
+// on-chain stake.
+func restakeApplication(address string, serviceIDs []string, config *Config, networkName string) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("config not loaded")
+	}
+	network, exists := config.Config.Networks[networkName]
+	if !exists {
+		return "", fmt.Errorf("network not found: %s", networkName)
+	}
+
+	var current *Application
+	err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+		var err error
+		current, err = ShowApplication(address, endpoint, config.pocketdHome(networkName), networkName)
+		return err
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get current stake: %v", err)
+	}
+	if current == nil {
+		return "", fmt.Errorf("application %s is not staked", address)
+	}
+	return stakeApplication(address, serviceIDs, stakeUpokt(*current), current.DelegateeGateways, config, networkName)
+}
+
+func (m model) executeServiceChange(txID int, change serviceChange) tea.Cmd {
+	return func() tea.Msg {
+		txHash, err := restakeApplication(change.address, change.after, withMemo(m.config, change.memo), m.currentNetwork)
+		if err != nil {
+			return newTxFailedMsg(txID, "service change", []string{change.address}, 0, err)
+		}
+		return serviceChangedMsg{txID: txID, txHash: txHash}
+	}
+}
+
+func (m model) updateServiceChange(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		change := *m.svcChange
+		m.svcChange = nil
+		m.state = stateTable
+		command := fmt.Sprintf("svc %s %s", change.address, joinServiceIDs(change.after))
+		txID := m.trackTx("restake", command, []string{change.address}, 0)
+		return m, m.submitTracked(command, m.executeServiceChange(txID, change), txID)
+	case "n", "N", "esc", "q":
+		m.svcChange = nil
+		m.state = stateTable
+		return m, m.notify(toastInfo, "Service change cancelled")
+	}
+	return m, nil
+}
+
+// renderServiceChange shows the services of the application before and after
+// the staged re-stake.
+func (m model) renderServiceChange() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	addedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green
+		Padding(0, 2)
+	removedStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red
+		Padding(0, 2)
+	promptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	change := m.svcChange
+	if change == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render(fmt.Sprintf("⚡ CHANGE SERVICES • %s", change.address)), ""}
+	content = append(content, textStyle.Render(fmt.Sprintf("Stake kept at %s %s", m.formatAmount(change.stakeUpokt), m.unitLabel())))
+	content = append(content, "")
+
+	inAfter := make(map[string]bool, len(change.after))
+	for _, serviceID := range change.after {
+		inAfter[serviceID] = true
+	}
+	inBefore := make(map[string]bool, len(change.before))
+	content = append(content, textStyle.Render("Before:"))
+	for _, serviceID := range change.before {
+		inBefore[serviceID] = true
+		if inAfter[serviceID] {
+			content = append(content, textStyle.Render("    "+serviceID))
+		} else {
+			content = append(content, removedStyle.Render("  - "+serviceID))
+		}
+	}
+	content = append(content, textStyle.Render("After:"))
+	for _, serviceID := range change.after {
+		if inBefore[serviceID] {
+			content = append(content, textStyle.Render("    "+serviceID))
+		} else {
+			content = append(content, addedStyle.Render("  + "+serviceID))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render(fmt.Sprintf("Re-stakes the application with a %s fee; removed services are unstaked.",
+		m.fixedFee())))
+	content = append(content, promptStyle.Render("Submit? y to confirm • n or ESC to cancel"))
+	return strings.Join(content, "\n")
+}
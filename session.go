@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// appSession is the current session of an application for one service.
+type appSession struct {
+	ServiceID        string
+	SessionID        string
+	SessionNumber    int64
+	StartHeight      int64
+	EndHeight        int64
+	BlocksPerSession int64
+	Suppliers        []string
+}
+
+type sessionsLoadedMsg struct {
+	address  string
+	sessions []appSession
+	err      error
+}
+
+// QuerySession returns the current session of an application for a service.
+func QuerySession(address, serviceID, rpcEndpoint, pocketdHome, networkName string) (appSession, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return appSession{}, err
+	}
+	args := []string{"q", "session", "get-session", address, serviceID, "-o", "json", "--node", rpcEndpoint, "--chain-id", chainID}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+	output, err := runPocketd(args)
+	if err != nil {
+		return appSession{}, fmt.Errorf("failed to execute pocketd command: %w, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Session struct {
+			Header struct {
+				ServiceID               string  `json:"service_id"`
+				SessionID               string  `json:"session_id"`
+				SessionStartBlockHeight flexInt `json:"session_start_block_height"`
+				SessionEndBlockHeight   flexInt `json:"session_end_block_height"`
+			} `json:"header"`
+			SessionNumber       flexInt `json:"session_number"`
+			NumBlocksPerSession flexInt `json:"num_blocks_per_session"`
+			Suppliers           []struct {
+				OperatorAddress string `json:"operator_address"`
+			} `json:"suppliers"`
+		} `json:"session"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return appSession{}, fmt.Errorf("failed to parse JSON response: %w", err)
+	}
+
+	session := response.Session
+	result := appSession{
+		ServiceID:        session.Header.ServiceID,
+		SessionID:        session.Header.SessionID,
+		SessionNumber:    int64(session.SessionNumber),
+		StartHeight:      int64(session.Header.SessionStartBlockHeight),
+		EndHeight:        int64(session.Header.SessionEndBlockHeight),
+		BlocksPerSession: int64(session.NumBlocksPerSession),
+	}
+	if result.ServiceID == "" {
+		result.ServiceID = serviceID
+	}
+	for _, supplier := range session.Suppliers {
+		result.Suppliers = append(result.Suppliers, supplier.OperatorAddress)
+	}
+	return result, nil
+}
+
+// serviceIDsFromDetails extracts the staked service IDs from the raw
+// show-application output.
+func serviceIDsFromDetails(details string) []string {
+	var response struct {
+		Application struct {
+			ServiceConfigs []struct {
+				ServiceID string `json:"service_id"`
+			} `json:"service_configs"`
+		} `json:"application"`
+	}
+	if err := json.Unmarshal([]byte(details), &response); err != nil {
+		return nil
+	}
+	var ids []string
+	for _, config := range response.Application.ServiceConfigs {
+		ids = append(ids, config.ServiceID)
+	}
+	return ids
+}
+
+// loadSessionsCmd queries the current session of address for every service.
+func (m model) loadSessionsCmd(address string, serviceIDs []string) tea.Cmd {
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists || len(serviceIDs) == 0 {
+		return nil
+	}
+	networkName, pocketdHome := m.currentNetwork, m.config.pocketdHome(m.currentNetwork)
+	return func() tea.Msg {
+		var sessions []appSession
+		for _, serviceID := range serviceIDs {
+			var session appSession
+			err := withFailover(networkName, network.RPCEndpoint, func(endpoint string) error {
+				var err error
+				session, err = QuerySession(address, serviceID, endpoint, pocketdHome, networkName)
+				return err
+			})
+			if err != nil {
+				return sessionsLoadedMsg{address: address, sessions: sessions, err: err}
+			}
+			sessions = append(sessions, session)
+		}
+		return sessionsLoadedMsg{address: address, sessions: sessions}
+	}
+}
+
+// refreshSessions queries the sessions of the application in the details
+// view for each of its staked services.
+func (m model) refreshSessions() (model, tea.Cmd) {
+	m.sessions = nil
+	m.sessionsErr = nil
+	cmd := m.loadSessionsCmd(m.selectedAppAddress, serviceIDsFromDetails(m.applicationDetails))
+	if cmd == nil {
+		m.sessionsLoading = false
+		return m, nil
+	}
+	m.sessionsLoading = true
+	return m, tea.Batch(cmd, m.startSpinner())
+}
+
+// renderSessions renders the current sessions of the application shown in
+// the details view.
+func (m model) renderSessions() string {
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")) // Soft grey-green
+	labelStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")) // Red for errors
+
+	var lines []string
+	for _, session := range m.sessions {
+		lines = append(lines, "  "+labelStyle.Render(session.ServiceID)+textStyle.Render(fmt.Sprintf(" • session #%d %s", session.SessionNumber, session.SessionID)))
+
+		blocks := fmt.Sprintf("    Blocks %d–%d (%d per session)", session.StartHeight, session.EndHeight, session.BlocksPerSession)
+		if health, _, ok := rpcPool.status(m.currentNetwork); ok && health.Height > 0 {
+			if left := session.EndHeight - health.Height; left >= 0 {
+				blocks += fmt.Sprintf(", %d blocks left at height %d", left, health.Height)
+			}
+		}
+		lines = append(lines, textStyle.Render(blocks))
+
+		if len(session.Suppliers) == 0 {
+			lines = append(lines, errorStyle.Render("    No suppliers in this session: relays for this service cannot be served"))
+			continue
+		}
+		lines = append(lines, textStyle.Render(fmt.Sprintf("    %d suppliers:", len(session.Suppliers))))
+		for _, supplier := range session.Suppliers {
+			lines = append(lines, textStyle.Render("      "+supplier))
+		}
+	}
+
+	switch {
+	case m.sessionsErr != nil:
+		lines = append(lines, errorStyle.Render(fmt.Sprintf("  Failed to query session: %v", m.sessionsErr)))
+	case m.sessionsLoading:
+		lines = append(lines, textStyle.Render("  "+m.spinner()+" Querying current session..."))
+	case len(lines) == 0:
+		lines = append(lines, textStyle.Render("  No staked services"))
+	}
+	return strings.Join(lines, "\n")
+}
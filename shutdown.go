@@ -0,0 +1,280 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const quitCheckInterval = 500 * time.Millisecond
+
+type quitCheckMsg struct{}
+
+// batchRunning reports whether transactions are still being broadcast, so
+// quitting now would abandon part of a batch.
+func (m model) batchRunning() bool {
+	if m.upstakeAllRunning || m.reconcileCh != nil {
+		return true
+	}
+	for _, tx := range m.txs {
+		if tx.status == txSubmitting {
+			return true
+		}
+	}
+	return false
+}
+
+// requestQuit quits, or asks what to do with the batch still running.
+func (m model) requestQuit() (model, tea.Cmd) {
+	if !m.batchRunning() {
+		return m, tea.Quit
+	}
+	m.state = stateQuitConfirm
+	return m, nil
+}
+
+func (m model) updateQuitConfirm(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "w":
+		m.state = stateTable
+		m.quitWhenIdle = true
+		logger.Info("quitting once the running batch finishes")
+		return m, tea.Batch(m.notify(toastInfo, "Quitting once the in-flight transactions are broadcast"), quitCheckCmd())
+	case "s":
+		m.state = stateTable
+		m.quitWhenIdle = true
+		operations.cancelAll()
+		logger.Info("stopping the running batch to quit", "operator", m.operator())
+		return m, tea.Batch(m.notify(toastInfo, "Stopping after the current transaction; the rest is saved for gasms resume"), quitCheckCmd())
+	case "Q", "ctrl+c":
+		logger.Warn("quit with a batch still running", "operator", m.operator())
+		return m, tea.Quit
+	case "esc", "n":
+		m.state = stateTable
+	}
+	return m, nil
+}
+
+func quitCheckCmd() tea.Cmd {
+	return tea.Tick(quitCheckInterval, func(time.Time) tea.Msg {
+		return quitCheckMsg{}
+	})
+}
+
+// quitWhenDone quits once the running batch has finished.
+func (m model) quitWhenDone() (model, tea.Cmd) {
+	if !m.quitWhenIdle {
+		return m, nil
+	}
+	if m.batchRunning() {
+		return m, quitCheckCmd()
+	}
+	return m, tea.Quit
+}
+
+// renderQuitConfirm asks what to do with a running batch before quitting.
+func (m model) renderQuitConfirm() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render("⚠️  TRANSACTIONS IN FLIGHT"), ""}
+	switch {
+	case m.reconcileCh != nil:
+		content = append(content, warningStyle.Render(fmt.Sprintf("%s is running: %d of %d transactions done.",
+			m.reconcileAudit, m.reconcileDone, len(m.reconcilePlan.Items))))
+	case m.upstakeAllRunning:
+		content = append(content, warningStyle.Render("Upstake all is running."))
+	default:
+		content = append(content, warningStyle.Render("Transactions are still being broadcast."))
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render("w  Wait for the batch to finish, then quit"))
+	content = append(content, textStyle.Render("s  Stop after the current transaction, save the rest for `gasms resume`, then quit"))
+	content = append(content, textStyle.Render("Q  Quit now and abandon the batch"))
+	content = append(content, "")
+	content = append(content, textStyle.Render("ESC to keep running"))
+	return strings.Join(content, "\n")
+}
+
+// resumePath returns the file the unfinished transactions of network are
+// saved to.
+func resumePath(network string) (string, error) {
+	return dataPath("resume", safeFileName(network)+".json")
+}
+
+// saveResume adds items to the resume file of network, for "gasms resume",
+// recording operator as the one who planned them.
+// Items keep the state they were planned against, so a resumed item that was
+// done some other way in the meantime is skipped.
+func saveResume(network, bank, operator string, items []planItem) (string, error) {
+	path, err := resumePath(network)
+	if err != nil {
+		return "", err
+	}
+	plan := stakePlan{Version: planVersion, Network: network, Bank: bank}
+	if err := readJSONFile(path, &plan); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+	plan.CreatedAt = time.Now()
+	plan.Operator = operator
+	plan.Items = append(plan.Items, items...)
+	if err := writeJSONFile(path, plan); err != nil {
+		return "", err
+	}
+	logger.Info("unfinished transactions saved", "network", network, "items", len(items), "path", path)
+	return path, nil
+}
+
+// notifyResume saves the cancelled items of a batch and tells the operator
+// how to finish them.
+func (m *model) notifyResume(network, bank string, items []planItem) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+	path, err := saveResume(network, bank, m.operator(), items)
+	if err != nil {
+		logger.Error("failed to save unfinished transactions", "error", err)
+		return m.notify(toastError, fmt.Sprintf("%d transactions were not sent and could not be saved: %v", len(items), err))
+	}
+	return m.notify(toastWarning, fmt.Sprintf("%d transactions were not sent; saved to %s, finish them with gasms resume", len(items), path))
+}
+
+// upstakeResumeItems returns the upstakes of an upstake-all over
+// applications that were cancelled before being broadcast.
+func upstakeResumeItems(applications []Application, receipts []UpstakeReceipt, amount int64) []planItem {
+	apps := make(map[string]Application, len(applications))
+	for _, app := range applications {
+		apps[app.Address] = app
+	}
+	var items []planItem
+	for _, receipt := range receipts {
+		app, known := apps[receipt.appAddress]
+		if receipt.error != errCancelled.Error() || !known {
+			continue
+		}
+		stake := stakeUpokt(app)
+		items = append(items, planItem{
+			Action:       planUpstake,
+			Address:      app.Address,
+			ServiceID:    app.ServiceID,
+			AmountUpokt:  amount,
+			CurrentUpokt: stake,
+			TargetUpokt:  stake + amount,
+		})
+	}
+	return items
+}
+
+// fundResumeItems returns the funds of amount to the recipients of a
+// fund-all over applications that were cancelled before being sent.
+func fundResumeItems(applications []Application, recipients []string, amount int64) []planItem {
+	apps := make(map[string]Application, len(applications))
+	for _, app := range applications {
+		apps[app.Address] = app
+	}
+	var items []planItem
+	for _, address := range recipients {
+		app, known := apps[address]
+		if !known {
+			continue
+		}
+		items = append(items, planItem{
+			Action:       planFund,
+			Address:      app.Address,
+			AmountUpokt:  amount,
+			CurrentUpokt: app.BalanceUpokt,
+			TargetUpokt:  app.BalanceUpokt + amount,
+		})
+	}
+	return items
+}
+
+// runResume implements "gasms resume [network]", which applies the
+// transactions saved when a batch was stopped before finishing.
+func runResume(args []string) error {
+	flags := flag.NewFlagSet("resume", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	autoApprove := flags.Bool("auto-approve", false, "skip interactive approval")
+	approvalSignature := flags.String("approval-signature", "", "second approver's signature of the resume file, when the saved transactions are above the two-person threshold")
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		return fmt.Errorf("usage: gasms resume [-config file] [-auto-approve] [-approval-signature signature] [network]")
+	}
+
+	network := flags.Arg(0)
+	if network == "" {
+		config, err := loadCLIConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		if network, err = defaultNetwork(config); err != nil {
+			return fmt.Errorf("%w (gasms resume <network>)", err)
+		}
+	}
+	path, err := resumePath(network)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			fmt.Printf("Nothing to resume on %s.\n", network)
+			return nil
+		}
+		return err
+	}
+
+	// Apply writes its receipts next to the plan; only items that did not
+	// make it on-chain stay in the resume file
+	receiptsPath := strings.TrimSuffix(path, ".json") + ".receipts.json"
+	os.Remove(receiptsPath)
+	applyArgs := []string{"-config", *configPath, "-approval-signature", *approvalSignature}
+	if *autoApprove {
+		applyArgs = append(applyArgs, "-auto-approve")
+	}
+	applyErr := runApply(append(applyArgs, path))
+
+	var receipts []planReceipt
+	if err := readJSONFile(receiptsPath, &receipts); err != nil {
+		return applyErr // Not applied
+	}
+	var plan stakePlan
+	if err := readJSONFile(path, &plan); err != nil {
+		return err
+	}
+	var remaining []planItem
+	for _, receipt := range receipts {
+		if receipt.Result != string(txIncluded) && receipt.Result != "skipped" {
+			remaining = append(remaining, receipt.planItem)
+		}
+	}
+	if len(remaining) == 0 {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s.\n", path)
+		return applyErr
+	}
+	plan.Items = remaining
+	if err := writeJSONFile(path, plan); err != nil {
+		return err
+	}
+	fmt.Printf("%d failed transactions kept in %s.\n", len(remaining), path)
+	return applyErr
+}
@@ -0,0 +1,399 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const snapshotDir = "snapshots"
+
+// stakeSnapshot records the stakes and balances of every loaded application
+// at one point in time.
+type stakeSnapshot struct {
+	Name             string        `json:"name"`
+	CreatedAt        time.Time     `json:"created_at"`
+	Operator         string        `json:"operator"`
+	Network          string        `json:"network"`
+	Gateway          string        `json:"gateway"`
+	BankBalanceUpokt int64         `json:"bank_balance_upokt"`
+	Applications     []snapshotApp `json:"applications"`
+}
+
+type snapshotApp struct {
+	Address      string `json:"address"`
+	ServiceID    string `json:"service_id"`
+	StakeUpokt   int64  `json:"stake_upokt"`
+	BalanceUpokt int64  `json:"balance_upokt"`
+}
+
+// snapshotRow compares one application between a snapshot and now.
+type snapshotRow struct {
+	address string
+	then    *snapshotApp // nil if the application was not in the snapshot
+	now     *Application // nil if the application is no longer loaded
+}
+
+func snapshotPath(name string) (string, error) {
+	return dataPath(snapshotDir, safeFileName(name)+".json")
+}
+
+// takeSnapshot captures the loaded applications under name.
+func (m model) takeSnapshot(name string) stakeSnapshot {
+	snapshot := stakeSnapshot{
+		Name:             name,
+		CreatedAt:        time.Now(),
+		Operator:         m.operator(),
+		Network:          m.currentNetwork,
+		Gateway:          m.currentGateway,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	for _, app := range m.applications {
+		snapshot.Applications = append(snapshot.Applications, snapshotApp{
+			Address:      app.Address,
+			ServiceID:    app.ServiceID,
+			StakeUpokt:   stakeUpokt(app),
+			BalanceUpokt: app.BalanceUpokt,
+		})
+	}
+	return snapshot
+}
+
+func loadSnapshot(name string) (*stakeSnapshot, error) {
+	path, err := snapshotPath(name)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot stakeSnapshot
+	if err := readJSONFile(path, &snapshot); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no snapshot named %q", name)
+		}
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// listSnapshots returns every saved snapshot, newest first.
+func listSnapshots() ([]stakeSnapshot, error) {
+	path, err := dataPath(snapshotDir, "")
+	if err != nil {
+		return nil, err
+	}
+	files, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var snapshots []stakeSnapshot
+	for _, file := range files {
+		var snapshot stakeSnapshot
+		if err := readJSONFile(file, &snapshot); err != nil {
+			logger.Warn("skipping unreadable snapshot", "path", file, "error", err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.After(snapshots[j].CreatedAt)
+	})
+	return snapshots, nil
+}
+
+// handleSnapshotCommand handles "snapshot <name>", "snapshots" and
+// "compare <name>".
+func (m model) handleSnapshotCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	switch parts[0] {
+	case "snapshots":
+		snapshots, err := listSnapshots()
+		if err != nil {
+			return m, m.notify(toastError, fmt.Sprintf("Failed to list snapshots: %v", err))
+		}
+		m.snapshots = snapshots
+		m.snapshotCursor = 0
+		m.state = stateSnapshots
+		return m, nil
+
+	case "compare":
+		if len(parts) != 2 {
+			m.err = fmt.Errorf("usage: compare <snapshot name>")
+			return m, nil
+		}
+		snapshot, err := loadSnapshot(parts[1])
+		if err != nil {
+			return m, m.notify(toastError, fmt.Sprintf("Failed to load snapshot: %v", err))
+		}
+		return m.openCompare(snapshot)
+	}
+
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: snapshot <name>")
+		return m, nil
+	}
+	name := parts[1]
+	if err := m.balancesReady(); err != nil {
+		return m, m.notify(toastWarning, fmt.Sprintf("Cannot snapshot yet: %v", err))
+	}
+	path, err := snapshotPath(name)
+	if err != nil {
+		return m, m.notify(toastError, fmt.Sprintf("Snapshot failed: %v", err))
+	}
+	if _, err := os.Stat(path); err == nil {
+		return m, m.notify(toastError, fmt.Sprintf("Snapshot %q already exists", name))
+	}
+	snapshot := m.takeSnapshot(name)
+	if err := writeJSONFile(path, snapshot); err != nil {
+		return m, m.notify(toastError, fmt.Sprintf("Snapshot failed: %v", err))
+	}
+	logger.Info("snapshot saved", "name", name, "network", snapshot.Network, "gateway", snapshot.Gateway, "applications", len(snapshot.Applications))
+	return m, m.notify(toastSuccess, fmt.Sprintf("Saved snapshot %q of %d applications", name, len(snapshot.Applications)))
+}
+
+// openCompare shows the compare view for a snapshot of the current network.
+func (m model) openCompare(snapshot *stakeSnapshot) (model, tea.Cmd) {
+	if snapshot.Network != m.currentNetwork {
+		return m, m.notify(toastError, fmt.Sprintf("Snapshot %q was taken on %s; switch to that network to compare", snapshot.Name, snapshot.Network))
+	}
+	m.compareWith = snapshot
+	m.compareScroll = 0
+	m.stagedPlan = nil
+	m.state = stateCompare
+	return m, nil
+}
+
+// compareRows pairs the applications of the compared snapshot with the
+// loaded ones, in snapshot order followed by applications added since.
+func (m model) compareRows() []snapshotRow {
+	loaded := make(map[string]*Application, len(m.applications))
+	for i := range m.applications {
+		loaded[m.applications[i].Address] = &m.applications[i]
+	}
+	var rows []snapshotRow
+	seen := make(map[string]bool)
+	for i := range m.compareWith.Applications {
+		then := &m.compareWith.Applications[i]
+		seen[then.Address] = true
+		rows = append(rows, snapshotRow{address: then.Address, then: then, now: loaded[then.Address]})
+	}
+	for i := range m.applications {
+		if !seen[m.applications[i].Address] {
+			rows = append(rows, snapshotRow{address: m.applications[i].Address, now: &m.applications[i]})
+		}
+	}
+	return rows
+}
+
+// restorePlan returns the transactions that bring every application back to
+// at least its snapshot stake. Stakes can only grow, so applications staked
+// above their snapshot level are left alone.
+func (m model) restorePlan() *stakePlan {
+	network := m.config.Config.Networks[m.currentNetwork]
+	plan := &stakePlan{
+		Version:          planVersion,
+		CreatedAt:        time.Now(),
+		Operator:         m.operator(),
+		Network:          m.currentNetwork,
+		Bank:             network.Bank,
+		BankBalanceUpokt: int64(math.Round(m.bankBalance * upoktPerPOKT)),
+	}
+	var funds, upstakes []planItem
+	for _, row := range m.compareRows() {
+		if row.then == nil || row.now == nil {
+			continue
+		}
+		fund, upstake, _ := planApplication(row.address, row.now, row.now.BalanceUpokt, Targets{Stake: row.then.StakeUpokt}, m.minStake(), network.appFeeUpokt())
+		if fund != nil {
+			funds = append(funds, *fund)
+			plan.BankRequiredUpokt += fund.AmountUpokt + network.txFeeUpokt()
+		}
+		if upstake != nil {
+			upstakes = append(upstakes, *upstake)
+		}
+	}
+	plan.Items = append(funds, upstakes...)
+	return plan
+}
+
+func (m model) updateSnapshots(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.snapshotCursor > 0 {
+			m.snapshotCursor--
+		}
+	case "down", "j":
+		if m.snapshotCursor < len(m.snapshots)-1 {
+			m.snapshotCursor++
+		}
+	case "enter":
+		if m.snapshotCursor < len(m.snapshots) {
+			snapshot := m.snapshots[m.snapshotCursor]
+			return m.openCompare(&snapshot)
+		}
+	}
+	return m, nil
+}
+
+func (m model) updateCompare(msg tea.KeyMsg) (model, tea.Cmd) {
+	if m.stagedPlan != nil {
+		return m.confirmStagedPlan(msg, "snapshot restore "+m.compareWith.Name)
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.compareScroll > 0 {
+			m.compareScroll--
+		}
+	case "down", "j":
+		if m.compareScroll < len(m.compareWith.Applications)+len(m.applications)-1 {
+			m.compareScroll++
+		}
+	case "R":
+		return m.stagePlan(m.restorePlan(), "Nothing to restore: every application is staked at or above its snapshot level")
+	}
+	return m, nil
+}
+
+// renderSnapshots lists saved snapshots.
+func (m model) renderSnapshots() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Padding(0, 2)
+
+	content := []string{headerStyle.Render(fmt.Sprintf("📸 SNAPSHOTS (%d) 📸", len(m.snapshots))), ""}
+	if len(m.snapshots) == 0 {
+		content = append(content, textStyle.Render("No snapshots yet. Use :snapshot <name> to record the current stakes and balances."))
+	}
+	for i, snapshot := range m.snapshots {
+		line := fmt.Sprintf("%-24s %s  %-12s %-20s %4d apps  %s",
+			snapshot.Name, snapshot.CreatedAt.Local().Format("2006-01-02 15:04"), snapshot.Network,
+			TruncateAddress(snapshot.Gateway, 20), len(snapshot.Applications), snapshot.Operator)
+		if i == m.snapshotCursor {
+			content = append(content, selectedStyle.Render(line))
+		} else {
+			content = append(content, textStyle.Render(line))
+		}
+	}
+
+	content = append(content, "")
+	content = append(content, textStyle.Render("Enter to compare with current state • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
+
+// renderCompare shows the stake and balance deltas between a snapshot and the
+// loaded applications.
+func (m model) renderCompare() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	columnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("65")). // Muted green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	upStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	downStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+	promptStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	snapshot := m.compareWith
+	title := fmt.Sprintf("📸 COMPARE WITH %q • %s", snapshot.Name, snapshot.CreatedAt.Local().Format("2006-01-02 15:04"))
+	content := []string{headerStyle.Render(title), ""}
+	if snapshot.Gateway != m.currentGateway {
+		content = append(content, promptStyle.Render(fmt.Sprintf("Snapshot was taken for gateway %s", snapshot.Gateway)), "")
+	}
+
+	unit := m.unitLabel()
+	delta := func(then, now int64) string {
+		switch {
+		case now == then:
+			return "="
+		case now > then:
+			return "+" + m.formatAmount(now-then)
+		default:
+			return m.formatAmount(now - then)
+		}
+	}
+
+	var lines []string
+	for _, row := range m.compareRows() {
+		address := TruncateAddress(row.address, 15)
+		switch {
+		case row.now == nil:
+			lines = append(lines, downStyle.Render(fmt.Sprintf("%-15s %14s %14s %14s   gone since snapshot",
+				address, m.formatAmount(row.then.StakeUpokt), "-", "-")))
+		case row.then == nil:
+			lines = append(lines, upStyle.Render(fmt.Sprintf("%-15s %14s %14s %14s   new since snapshot",
+				address, "-", m.formatAmount(stakeUpokt(*row.now)), "-")))
+		default:
+			stake := stakeUpokt(*row.now)
+			line := fmt.Sprintf("%-15s %14s %14s %14s %14s %14s %14s",
+				address,
+				m.formatAmount(row.then.StakeUpokt), m.formatAmount(stake), delta(row.then.StakeUpokt, stake),
+				m.formatAmount(row.then.BalanceUpokt), m.formatAmount(row.now.BalanceUpokt), delta(row.then.BalanceUpokt, row.now.BalanceUpokt))
+			switch {
+			case stake < row.then.StakeUpokt:
+				lines = append(lines, downStyle.Render(line))
+			case stake > row.then.StakeUpokt:
+				lines = append(lines, upStyle.Render(line))
+			default:
+				lines = append(lines, textStyle.Render(line))
+			}
+		}
+	}
+
+	// Scroll the rows, keeping the title, column names and footer in place
+	visible := max(m.height-14, 1)
+	scroll := min(m.compareScroll, max(len(lines)-visible, 0))
+	end := scroll + visible
+	if end > len(lines) {
+		end = len(lines)
+	}
+	content = append(content, columnStyle.Render(fmt.Sprintf("%-15s %14s %14s %14s %14s %14s %14s",
+		"Address", "Stake then", "Stake now", "Δ Stake", "Balance then", "Balance now", "Δ Balance")))
+	content = append(content, lines[scroll:end]...)
+	content = append(content, "")
+	content = append(content, textStyle.Render(fmt.Sprintf("Bank balance: %s → %s %s",
+		m.formatAmount(snapshot.BankBalanceUpokt), m.formatAmount(int64(math.Round(m.bankBalance*upoktPerPOKT))), unit)))
+
+	content = append(content, "")
+	if status := m.reconcileStatus(); status != "" {
+		content = append(content, promptStyle.Render(status))
+	} else {
+		content = append(content, textStyle.Render("R to restore snapshot stake levels • j/k to scroll • ESC or Q to return"))
+	}
+	return strings.Join(content, "\n")
+}
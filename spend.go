@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	spendDays  = 7 // Daily rows shown per bank
+	spendWeeks = 4 // Weekly rows shown per bank
+)
+
+// spendTotals sums the POKT that left a bank and its applications, in upokt.
+type spendTotals struct {
+	Fund    int64
+	Upstake int64
+	Fees    int64
+	Txs     int
+}
+
+func (t spendTotals) total() int64 {
+	return t.Fund + t.Upstake + t.Fees
+}
+
+func (t *spendTotals) add(record auditRecord) {
+	if record.Kind == "faucet" {
+		return // Sent and paid for by the faucet
+	}
+	// Failed transactions that made it into a block still paid their fee
+	t.Fees += record.FeeUpokt
+	t.Txs++
+	if record.Result != string(txIncluded) {
+		return
+	}
+	switch record.Kind {
+	case "fund":
+		t.Fund += record.AmountUpokt
+	case "fund-all":
+		// Every application receives the amount
+		t.Fund += record.AmountUpokt * int64(len(record.Addresses))
+	case "upstake", "upstake-all":
+		t.Upstake += record.AmountUpokt
+	}
+}
+
+type spendPeriod struct {
+	label  string
+	totals spendTotals
+}
+
+// spendReport is the outflow of one network/bank pair.
+type spendReport struct {
+	network string
+	bank    string
+	days    []spendPeriod // Most recent first
+	weeks   []spendPeriod // Most recent first
+	allTime spendTotals
+}
+
+// startOfDay truncates t to local midnight.
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Local().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+// startOfWeek returns local midnight of the Monday starting t's week.
+func startOfWeek(t time.Time) time.Time {
+	day := startOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	return day.AddDate(0, 0, -offset)
+}
+
+// daysBetween returns the number of calendar days from one local midnight to
+// another, tolerating daylight saving shifts.
+func daysBetween(from, to time.Time) int {
+	return int(math.Round(to.Sub(from).Hours() / 24))
+}
+
+// summarizeSpend aggregates the final result of every audited transaction
+// into daily, weekly and all-time totals per network/bank.
+func summarizeSpend(records []auditRecord, now time.Time) []spendReport {
+	reports := make(map[string]*spendReport)
+	today := startOfDay(now)
+	thisWeek := startOfWeek(now)
+
+	for _, record := range records {
+		// Only records with a known outcome carry amounts that left the chain
+		if record.Height == 0 {
+			continue
+		}
+
+		key := record.Network + "\x00" + record.Bank
+		report, ok := reports[key]
+		if !ok {
+			report = &spendReport{network: record.Network, bank: record.Bank}
+			for i := 0; i < spendDays; i++ {
+				day := today.AddDate(0, 0, -i)
+				report.days = append(report.days, spendPeriod{label: day.Format("Mon 2006-01-02")})
+			}
+			for i := 0; i < spendWeeks; i++ {
+				week := thisWeek.AddDate(0, 0, -7*i)
+				report.weeks = append(report.weeks, spendPeriod{label: "Week of " + week.Format("2006-01-02")})
+			}
+			reports[key] = report
+		}
+
+		report.allTime.add(record)
+		if i := daysBetween(startOfDay(record.Time), today); i >= 0 && i < spendDays {
+			report.days[i].totals.add(record)
+		}
+		if i := daysBetween(startOfWeek(record.Time), thisWeek) / 7; i >= 0 && i < spendWeeks {
+			report.weeks[i].totals.add(record)
+		}
+	}
+
+	var result []spendReport
+	for _, report := range reports {
+		result = append(result, *report)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].network != result[j].network {
+			return result[i].network < result[j].network
+		}
+		return result[i].bank < result[j].bank
+	})
+	return result
+}
+
+// handleSpendCommand opens the spend report built from the audit log.
+func (m model) handleSpendCommand() (model, tea.Cmd) {
+	records, err := loadAudit()
+	if err != nil {
+		m.err = fmt.Errorf("failed to read audit log: %w", err)
+		return m, nil
+	}
+	m.spendReports = summarizeSpend(records, time.Now())
+	m.spendScroll = 0
+	m.state = stateSpend
+	return m, nil
+}
+
+func (m model) updateSpend(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "esc", "q":
+		m.state = stateTable
+	case "up", "k":
+		if m.spendScroll > 0 {
+			m.spendScroll--
+		}
+	case "down", "j":
+		if m.spendScroll < len(m.spendLines())-1 {
+			m.spendScroll++
+		}
+	case "g":
+		m.spendScroll = 0
+	}
+	return m, nil
+}
+
+// spendLines renders the daily and weekly outflow of every bank.
+func (m model) spendLines() []string {
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	columnStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("65")). // Muted green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+
+	unit := m.unitLabel()
+	row := func(label string, totals spendTotals) string {
+		return fmt.Sprintf("%-20s %14s %14s %12s %14s %5d",
+			label,
+			m.formatAmount(totals.Fund),
+			m.formatAmount(totals.Upstake),
+			m.formatAmount(totals.Fees),
+			m.formatAmount(totals.total()),
+			totals.Txs)
+	}
+
+	var lines []string
+	if len(m.spendReports) == 0 {
+		lines = append(lines, textStyle.Render("No confirmed fund or upstake transactions recorded yet."))
+	}
+	for _, report := range m.spendReports {
+		bank := report.bank
+		if bank == "" {
+			bank = "(no bank configured)"
+		}
+		lines = append(lines, sectionStyle.Render(fmt.Sprintf("🏦 %s • %s", report.network, bank)))
+		lines = append(lines, columnStyle.Render(fmt.Sprintf("%-20s %14s %14s %12s %14s %5s",
+			"Period", "Fund ("+unit+")", "Upstake ("+unit+")", "Fees ("+unit+")", "Total ("+unit+")", "Txs")))
+		for _, day := range report.days {
+			lines = append(lines, textStyle.Render(row(day.label, day.totals)))
+		}
+		lines = append(lines, "")
+		for _, week := range report.weeks {
+			lines = append(lines, textStyle.Render(row(week.label, week.totals)))
+		}
+		lines = append(lines, "")
+		lines = append(lines, sectionStyle.Render(row("All time", report.allTime)))
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// renderSpend renders the scrollable spend report.
+func (m model) renderSpend() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+
+	lines := m.spendLines()
+
+	// Scroll the report body, keeping the title and footer in place
+	visible := max(m.height-10, 1)
+	scroll := min(m.spendScroll, max(len(lines)-visible, 0))
+	end := scroll + visible
+	if end > len(lines) {
+		end = len(lines)
+	}
+
+	content := []string{headerStyle.Render("📊 SPEND REPORT 📊"), ""}
+	content = append(content, lines[scroll:end]...)
+	content = append(content, textStyle.Render("Upstakes are paid from application balances • j/k to scroll • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
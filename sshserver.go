@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/charmbracelet/wish/activeterm"
+	bm "github.com/charmbracelet/wish/bubbletea"
+	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const defaultSSHListen = ":2222"
+
+// sessionUserKey holds the user an SSH connection authenticated as.
+type sessionUserKey struct{}
+
+// SSHServer configures "gasms serve". Users are only read from the base
+// config, like roles, so that nobody can add their own key.
+type SSHServer struct {
+	Listen  string              `yaml:"listen,omitempty"`   // Address of the SSH server (default :2222)
+	HostKey string              `yaml:"host-key,omitempty"` // Host key file, created if missing (default ~/.gasms/ssh_host_ed25519)
+	Users   map[string][]string `yaml:"users,omitempty"`    // User -> public keys in authorized_keys format
+}
+
+func (s SSHServer) validate() error {
+	for name, keys := range s.Users {
+		for _, key := range keys {
+			if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key)); err != nil {
+				return fmt.Errorf("ssh: invalid public key for %s: %v", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// keyUser returns the user whose keys include key, if any.
+func (s SSHServer) keyUser(key ssh.PublicKey) (string, bool) {
+	for name, keys := range s.Users {
+		for _, authorized := range keys {
+			parsed, _, _, _, err := ssh.ParseAuthorizedKey([]byte(authorized))
+			if err == nil && ssh.KeysEqual(parsed, key) {
+				return name, true
+			}
+		}
+	}
+	return "", false
+}
+
+func (s SSHServer) hostKeyPath() (string, error) {
+	if s.HostKey != "" {
+		return expandHome(s.HostKey), nil
+	}
+	dir, err := baseDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ssh_host_ed25519"), nil
+}
+
+// runServe serves the TUI over SSH: "gasms serve". Each connection gets its
+// own session, whose roles and audit records are those of the user its key
+// belongs to. Everyone connects with the same SSH user name, as in
+// "ssh gasms@ops-host".
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	configPath := flags.String("config", configFile, "config file")
+	flags.Parse(args)
+
+	config, err := loadCLIConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	settings := config.Config.SSH
+	listen := settings.Listen
+	if listen == "" {
+		listen = defaultSSHListen
+	}
+	hostKey, err := settings.hostKeyPath()
+	if err != nil {
+		return err
+	}
+
+	// The styles use the default renderer, which would otherwise take the
+	// colors of the terminal gasms serve was started from, if any
+	lipgloss.SetColorProfile(termenv.ANSI256)
+	lipgloss.SetHasDarkBackground(true)
+
+	server, err := wish.NewServer(
+		wish.WithAddress(listen),
+		wish.WithHostKeyPath(hostKey),
+		wish.WithPublicKeyAuth(func(ctx ssh.Context, key ssh.PublicKey) bool {
+			source, err := roleConfig(config)
+			if err != nil || source == nil {
+				return false
+			}
+			name, ok := source.Config.SSH.keyUser(key)
+			if !ok {
+				logger.Warn("ssh login refused", "remote", ctx.RemoteAddr().String(), "key", gossh.FingerprintSHA256(key))
+				return false
+			}
+			ctx.SetValue(sessionUserKey{}, name)
+			return true
+		}),
+		wish.WithMiddleware(
+			bm.MiddlewareWithColorProfile(serveSession, termenv.ANSI256),
+			activeterm.Middleware(),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("ssh server: %w", err)
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+	logger.Info("ssh server started", "listen", listen, "host_key", hostKey)
+	fmt.Printf("gasms serving the TUI over SSH on %s\n", listen)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-serverErr:
+		if errors.Is(err, ssh.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("ssh server: %w", err)
+	case sig := <-signals:
+		logger.Info("ssh server stopping", "signal", sig.String())
+		operations.cancelAll()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		// Sessions still open after the timeout are cut
+		err := server.Shutdown(shutdown)
+		if errors.Is(err, context.DeadlineExceeded) {
+			return server.Close()
+		}
+		return err
+	}
+}
+
+// serveSession starts the TUI for an SSH session as the user it
+// authenticated as.
+func serveSession(sess ssh.Session) (tea.Model, []tea.ProgramOption) {
+	name, _ := sess.Context().Value(sessionUserKey{}).(string)
+	if name == "" {
+		return nil, nil
+	}
+	logger.Info("ssh session started", "user", name, "remote", sess.RemoteAddr().String())
+	go func() {
+		<-sess.Context().Done()
+		logger.Info("ssh session ended", "user", name, "remote", sess.RemoteAddr().String())
+	}()
+	m := initialModel()
+	m.sessionUser = name
+	return m, []tea.ProgramOption{tea.WithAltScreen()}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stakeEdit is a target stake being typed in the stake cell of a row, staged
+// as the upstake that reaches it.
+type stakeEdit struct {
+	address string
+	input   string
+}
+
+// openStakeEdit starts editing the target stake of the selected row.
+func (m model) openStakeEdit() model {
+	if len(m.applications) == 0 || m.cursor >= len(m.applications) {
+		return m
+	}
+	app := m.applications[m.cursor]
+	if isUnstaking(app) {
+		m.err = fmt.Errorf("application %s is unstaking", TruncateAddress(app.Address, 13))
+		return m
+	}
+	m.stakeEdit = &stakeEdit{address: app.Address}
+	m.state = stateStakeEdit
+	return m
+}
+
+// parseDisplayAmount parses an amount typed where amounts are shown in the
+// display denomination, such as the stake column, into upokt. A bare number
+// is in the display denomination.
+func (m model) parseDisplayAmount(input string) (int64, error) {
+	input = strings.TrimSpace(input)
+	if !strings.ContainsFunc(input, unicode.IsLetter) {
+		input += m.displayUnit
+	}
+	return parseAmount(input)
+}
+
+// stakeEditUpstake returns the upstake that takes the edited application to
+// the typed target stake.
+func (m model) stakeEditUpstake() (int64, error) {
+	edit := m.stakeEdit
+	app, ok := m.findApplication(edit.address)
+	if !ok {
+		return 0, fmt.Errorf("application %s is no longer loaded", TruncateAddress(edit.address, 13))
+	}
+	target, err := m.parseDisplayAmount(edit.input)
+	if err != nil {
+		return 0, err
+	}
+	stake := stakeUpokt(app)
+	if target <= stake {
+		return 0, fmt.Errorf("target %s %s is not above the current stake of %s %s; stakes can only be raised",
+			m.formatAmount(target), m.unitLabel(), m.formatAmount(stake), m.unitLabel())
+	}
+	return target - stake, nil
+}
+
+func (m model) updateStakeEdit(msg tea.KeyMsg) (model, tea.Cmd) {
+	edit := m.stakeEdit
+	switch msg.String() {
+	case "esc":
+		m.stakeEdit = nil
+		m.state = stateTable
+	case "enter":
+		if strings.TrimSpace(edit.input) == "" {
+			m.err = fmt.Errorf("type the target stake, or press ESC to cancel")
+			return m, nil
+		}
+		amount, err := m.stakeEditUpstake()
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		// Stage through command mode, so roles, guards and --memo apply
+		m.stakeEdit = nil
+		m.commandInput = fmt.Sprintf("u %s %dupokt", edit.address, amount)
+		return m.updateCommand(msg)
+	case "backspace":
+		if len(edit.input) > 0 {
+			edit.input = edit.input[:len(edit.input)-1]
+		}
+	default:
+		if msg.Type == tea.KeyRunes {
+			edit.input += string(msg.Runes)
+		}
+	}
+	return m, nil
+}
+
+// stakeEditCell renders the stake cell being edited, width cells wide.
+func (m model) stakeEditCell(width int) string {
+	editStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Bold(true)
+	return editStyle.Render(padToWidth(m.stakeEdit.input+"█", width))
+}
+
+// stakeEditPrompt describes the edit in the command area: the target typed
+// so far and the upstake it stages.
+func (m model) stakeEditPrompt() string {
+	edit := m.stakeEdit
+	prompt := fmt.Sprintf("Target stake of %s (%s unless a unit is given): %s", TruncateAddress(edit.address, 13), m.unitLabel(), edit.input)
+	if strings.TrimSpace(edit.input) == "" {
+		return prompt + " • ESC to cancel"
+	}
+	amount, err := m.stakeEditUpstake()
+	if err != nil {
+		return prompt + " • " + err.Error()
+	}
+	return prompt + fmt.Sprintf(" • Enter to upstake %s %s • ESC to cancel", m.formatAmount(amount), m.unitLabel())
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// allServicesTemplate is the stake template applied whatever the services.
+const allServicesTemplate = "*"
+
+// stakeConfigFields are written by gasms into every stake config, so stake
+// templates cannot set them.
+var stakeConfigFields = []string{"stake_amount", "service_ids", "address"}
+
+// stakeConfig renders the stake-application config staking address with stake
+// upokt for serviceIDs. The fields of network's stake templates for "*" and
+// for each of serviceIDs follow, later ones overriding earlier ones, with
+// {address} replaced and {delegatee_gateways} expanded to gateways, the
+// application's current delegatee gateways.
+func stakeConfig(network Network, address string, serviceIDs []string, stake int64, gateways []string) ([]byte, error) {
+	services := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, serviceID := range serviceIDs {
+		services.Content = append(services.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: serviceID, Style: yaml.DoubleQuotedStyle})
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	setMappingValue(root, "stake_amount", &yaml.Node{Kind: yaml.ScalarNode, Value: strconv.FormatInt(stake, 10) + unitUPOKT})
+	setMappingValue(root, "service_ids", services)
+	setMappingValue(root, "address", &yaml.Node{Kind: yaml.ScalarNode, Value: address})
+
+	for _, key := range append([]string{allServicesTemplate}, serviceIDs...) {
+		template, ok := network.StakeTemplates[key]
+		if !ok {
+			continue
+		}
+		for i := 0; i+1 < len(template.Content); i += 2 {
+			setMappingValue(root, template.Content[i].Value, expandStakeTemplate(template.Content[i+1], address, gateways))
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to render stake config: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to render stake config: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// setMappingValue sets key of mapping to value, replacing an existing value.
+func setMappingValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = value
+			return
+		}
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+// expandStakeTemplate returns a copy of the template value node with its
+// placeholders filled in for address.
+func expandStakeTemplate(node *yaml.Node, address string, gateways []string) *yaml.Node {
+	if node.Kind == yaml.ScalarNode && node.Value == "{delegatee_gateways}" {
+		list := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, gateway := range gateways {
+			list.Content = append(list.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: gateway})
+		}
+		return list
+	}
+	expanded := *node
+	expanded.Content = nil
+	if node.Kind == yaml.ScalarNode {
+		expanded.Value = strings.ReplaceAll(node.Value, "{address}", address)
+	}
+	for _, child := range node.Content {
+		expanded.Content = append(expanded.Content, expandStakeTemplate(child, address, gateways))
+	}
+	return &expanded
+}
+
+// validateStakeTemplates checks that every stake template is a mapping of
+// fields gasms does not write itself.
+func validateStakeTemplates(config *Config) error {
+	for name, network := range config.Config.Networks {
+		for service, template := range network.StakeTemplates {
+			if template.Kind != yaml.MappingNode {
+				return fmt.Errorf("network %s: stake template %s must be a mapping of stake config fields", name, service)
+			}
+			for i := 0; i < len(template.Content); i += 2 {
+				if field := template.Content[i].Value; slices.Contains(stakeConfigFields, field) {
+					return fmt.Errorf("network %s: stake template %s cannot set %s, which gasms writes itself", name, service, field)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// modeNames name each view in the status bar.
+var modeNames = map[state]string{
+	stateLoading:            "LOADING",
+	stateTable:              "TABLE",
+	stateCommand:            "COMMAND",
+	stateSearch:             "SEARCH",
+	stateNetworkSelect:      "NETWORKS",
+	stateGatewaySelect:      "GATEWAYS",
+	stateHelp:               "HELP",
+	stateApplicationDetails: "DETAILS",
+	stateUpstakeAllReceipts: "UPSTAKE RECEIPTS",
+	stateAudit:              "AUDIT",
+	stateSpend:              "SPEND",
+	stateDiff:               "DRIFT",
+	stateDiscrepancies:      "DISCREPANCIES",
+	stateSnapshots:          "SNAPSHOTS",
+	stateCompare:            "COMPARE",
+	stateRewards:            "REWARDS",
+	stateParams:             "PARAMS",
+	stateGov:                "GOVERNANCE",
+	stateServiceChange:      "SERVICE CHANGE",
+	stateTransfer:           "TRANSFER",
+	stateConfigEditor:       "CONFIG",
+	stateFeeGrants:          "FEE GRANTS",
+	stateQueue:              "APPROVAL QUEUE",
+	stateCoApproval:         "CO-APPROVAL",
+	stateQuitConfirm:        "QUIT",
+	stateErrors:             "ERRORS",
+	stateAmountForm:         "AMOUNT",
+	stateRunbook:            "RUNBOOK",
+	stateCalc:               "CALCULATOR",
+	stateGatewayHealth:      "GATEWAY HEALTH",
+	stateJobs:               "JOBS",
+	stateJob:                "JOB",
+	statePassphrase:         "PASSPHRASE",
+	statePassthrough:        "POCKETD",
+	stateQueryConsole:       "QUERY",
+	stateTxLookup:           "TRANSACTION",
+	stateUpstakeAllConfirm:  "UPSTAKE ALL",
+	stateFundAllReceipt:     "FUND ALL RECEIPT",
+	stateGasEstimate:        "GAS ESTIMATE",
+	stateStakeEdit:          "EDIT STAKE",
+	statePlanReview:         "EDITED PLAN",
+	stateFilterPicker:       "FILTERS",
+}
+
+// renderStatusBar renders the one-line status bar at the bottom of every
+// view: the mode, the search and selection that scope commands, the work in
+// flight and the age of the data.
+func (m model) renderStatusBar() string {
+	barStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 1).
+		MaxWidth(max(m.width, 1))
+	modeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true)
+	activeStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")) // Yellow
+
+	segments := []string{modeStyle.Render(modeNames[m.state])}
+
+	// Hidden state that changes what commands act on
+	if m.showAllApps {
+		segments = append(segments, activeStyle.Render("all staked apps"))
+	}
+	if m.searchInput != "" {
+		segments = append(segments, activeStyle.Render(fmt.Sprintf("search %q (%d matches)", m.searchInput, len(m.searchResults))))
+	}
+	if selected := len(m.selectedAddresses()); selected > 0 {
+		segments = append(segments, fmt.Sprintf("%d selected", selected))
+	}
+
+	var pending []string
+	if count := m.pendingTxCount(); count > 0 {
+		pending = append(pending, fmt.Sprintf("%d pending txs", count))
+	}
+	if len(m.cooling) > 0 {
+		pending = append(pending, fmt.Sprintf("%d cooling down", len(m.cooling)))
+	}
+	if len(m.txQueue) > 0 {
+		pending = append(pending, fmt.Sprintf("%d awaiting approval", len(m.txQueue)))
+	}
+	running := 0
+	for _, j := range m.jobs {
+		if status := j.status(); status == jobRunning || status == jobWaiting {
+			running++
+		}
+	}
+	if running > 0 {
+		pending = append(pending, fmt.Sprintf("%d jobs running", running))
+	}
+	if m.reconcileCh != nil {
+		pending = append(pending, "reconciling")
+	}
+	if len(pending) > 0 {
+		segments = append(segments, activeStyle.Render(strings.Join(pending, ", ")))
+	} else {
+		segments = append(segments, "no pending txs")
+	}
+
+	switch {
+	case m.loading:
+		segments = append(segments, m.spinner()+" refreshing")
+	case !m.staleSince.IsZero():
+		segments = append(segments, activeStyle.Render("stale data saved "+formatAge(time.Since(m.staleSince))))
+	case !m.refreshedAt.IsZero():
+		segments = append(segments, fmt.Sprintf("refreshed %s (%s)", formatAge(time.Since(m.refreshedAt)), m.refreshedAt.Format("15:04:05")))
+	}
+
+	return barStyle.Render(strings.Join(segments, " │ "))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// staleTempFileAge is how old a temporary file must be for startup to remove
+// it: younger ones may belong to another running gasms, older ones were left
+// by one that was killed mid-transaction.
+const staleTempFileAge = time.Hour
+
+// legacyStakeConfigPattern matches the stake configs earlier versions wrote
+// to the shared temporary directory.
+const legacyStakeConfigPattern = "gasms_upstake_*.yaml"
+
+// tempDir returns ~/.gasms/tmp, the temporary directory only the current user
+// can read, creating it if needed.
+func tempDir() (string, error) {
+	base, err := baseDataDir()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(base, "tmp")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	// Tighten a directory created with looser permissions
+	if err := os.Chmod(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// writeTempFile writes data to a new file with a unique name made from
+// pattern (see os.CreateTemp) in the private temporary directory, readable
+// only by the current user, and returns its path.
+func writeTempFile(pattern string, data []byte) (string, error) {
+	dir, err := tempDir()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.CreateTemp(dir, pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "", err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// isStaleTempFile reports whether path is old enough that no running gasms
+// still uses it.
+func isStaleTempFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) > staleTempFileAge
+}
+
+// cleanTempFiles removes temporary files left behind by earlier runs: stale
+// files in the private temporary directory, and stale stake configs of
+// earlier versions in the shared one.
+func cleanTempFiles() {
+	var stale []string
+	if dir, err := tempDir(); err == nil {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			if path := filepath.Join(dir, entry.Name()); isStaleTempFile(path) {
+				stale = append(stale, path)
+			}
+		}
+	}
+	legacy, _ := filepath.Glob(filepath.Join(os.TempDir(), legacyStakeConfigPattern))
+	for _, path := range legacy {
+		if isStaleTempFile(path) {
+			stale = append(stale, path)
+		}
+	}
+
+	removed := 0
+	for _, path := range stale {
+		if err := os.RemoveAll(path); err == nil {
+			removed++
+		}
+	}
+	if removed > 0 {
+		logger.Info("removed leftover temporary files", "count", removed)
+	}
+}
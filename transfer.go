@@ -0,0 +1,198 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// stakeTransfer is a transfer of an application stake to a new owner
+// address, awaiting confirmation.
+type stakeTransfer struct {
+	source      string
+	destination string
+	stakeUpokt  int64
+	serviceIDs  []string
+	memo        string // Attached to the transaction
+}
+
+type transferSubmittedMsg struct {
+	txID   int
+	txHash string
+}
+
+// isTransferring reports whether app has a pending stake transfer.
+func isTransferring(app Application) bool {
+	return app.TransferTo != ""
+}
+
+// transferBadge describes the pending transfer of app for the table.
+func (m model) transferBadge(app Application) string {
+	if !isTransferring(app) {
+		return "-"
+	}
+	to := TruncateAddress(app.TransferTo, 13)
+	if left, ok := m.blocksUntil(app.TransferHeight); ok && left > 0 {
+		return fmt.Sprintf("→ %s, session ends in %d blocks", to, left)
+	}
+	return fmt.Sprintf("→ %s, after session %d", to, app.TransferHeight)
+}
+
+// handleTransferCommand stages "transfer <address> <new_address>".
+func (m model) handleTransferCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 3 {
+		m.err = fmt.Errorf("usage: transfer <address> <new_address>")
+		return m, nil
+	}
+	source, destination := parts[1], parts[2]
+
+	var app *Application
+	for i := range m.applications {
+		if m.applications[i].Address == source {
+			app = &m.applications[i]
+			break
+		}
+	}
+	switch {
+	case app == nil:
+		m.err = fmt.Errorf("application not found: %s", source)
+		return m, nil
+	case destination == source:
+		m.err = fmt.Errorf("new address must differ from %s", source)
+		return m, nil
+	case validateAddress(destination) != nil:
+		m.err = validateAddress(destination)
+		return m, nil
+	case isUnstaking(*app):
+		m.err = fmt.Errorf("application %s is unstaking", TruncateAddress(source, 13))
+		return m, nil
+	case isTransferring(*app):
+		m.err = fmt.Errorf("application %s is already transferring to %s", TruncateAddress(source, 13), app.TransferTo)
+		return m, nil
+	}
+
+	m.transfer = &stakeTransfer{
+		source:      source,
+		destination: destination,
+		stakeUpokt:  stakeUpokt(*app),
+		serviceIDs:  app.ServiceIDs,
+		memo:        m.config.Config.Memo,
+	}
+	m.state = stateTransfer
+	return m, nil
+}
+
+// transferApplication starts the transfer of the stake of source to
+// destination, signed by source.
+func transferApplication(source, destination string, config *Config, networkName string) (string, error) {
+	if config == nil {
+		return "", fmt.Errorf("config not loaded")
+	}
+	network, exists := config.Config.Networks[networkName]
+	if !exists {
+		return "", fmt.Errorf("network not found: %s", networkName)
+	}
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return "", err
+	}
+
+	var output []byte
+	err = withBroadcastFailover(networkName, network.RPCEndpoint, func(node string) error {
+		args := []string{"tx", "application", "transfer",
+			source,
+			destination,
+			"--from=" + source,
+			"--node=" + node,
+			"--chain-id=" + chainID,
+			network.feesArg()}
+		args = append(args, feeGranterArgs(network)...)
+		args = append(args, memoArgs(config)...)
+
+		args = AppendPocketdFlags(args, config.keyringBackend(networkName), config.txHome(networkName))
+
+		args = append(args, "-y")
+		var err error
+		output, err = runPocketd(args)
+		if err != nil {
+			return fmt.Errorf("pocketd command failed: %v, output: %s", err, string(output))
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the transaction hash; the node rejected it if code is set
+	return broadcastHash(output)
+}
+
+func (m model) executeTransfer(txID int, transfer stakeTransfer) tea.Cmd {
+	return func() tea.Msg {
+		txHash, err := transferApplication(transfer.source, transfer.destination, withMemo(m.config, transfer.memo), m.currentNetwork)
+		if err != nil {
+			return newTxFailedMsg(txID, "transfer", []string{transfer.source, transfer.destination}, 0, err)
+		}
+		return transferSubmittedMsg{txID: txID, txHash: txHash}
+	}
+}
+
+func (m model) updateTransfer(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "Y":
+		transfer := *m.transfer
+		m.transfer = nil
+		m.state = stateTable
+		command := fmt.Sprintf("transfer %s %s", transfer.source, transfer.destination)
+		txID := m.trackTx("transfer", command, []string{transfer.source}, 0)
+		return m, m.submitTracked(command, m.executeTransfer(txID, transfer), txID)
+	case "n", "N", "esc", "q":
+		m.transfer = nil
+		m.state = stateTable
+		return m, m.notify(toastInfo, "Transfer cancelled")
+	}
+	return m, nil
+}
+
+// renderTransfer asks for confirmation of a staged stake transfer.
+func (m model) renderTransfer() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	transfer := m.transfer
+	if transfer == nil {
+		return ""
+	}
+	row := func(label, value string) string {
+		return textStyle.Render(fmt.Sprintf("%-10s %s", label, value))
+	}
+	content := []string{headerStyle.Render("🔀 TRANSFER APPLICATION STAKE"), ""}
+	content = append(content, row("From", transfer.source))
+	content = append(content, row("To", transfer.destination))
+	content = append(content, row("Stake", m.formatAmount(transfer.stakeUpokt)+" "+m.unitLabel()))
+	content = append(content, row("Services", joinServiceIDs(transfer.serviceIDs)))
+	content = append(content, row("Fee", m.fixedFee()))
+	if transfer.memo != "" {
+		content = append(content, row("Memo", transfer.memo))
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render("The stake, services and delegations move to the new address once the waiting period after the current session ends."))
+	content = append(content, textStyle.Render("Until then the table shows the pending transfer in the transfer column."))
+	content = append(content, warningStyle.Render("Submit? y to confirm • n or ESC to cancel"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// txDetails is a transaction looked up by hash with :tx.
+type txDetails struct {
+	Hash      string
+	Height    int64
+	Timestamp string
+	Code      int
+	Codespace string
+	RawLog    string
+	GasWanted int64
+	GasUsed   int64
+	Memo      string
+	Fee       []txCoin
+	FeePayer  string
+	Granter   string
+	Messages  []map[string]interface{}
+	Events    []txEvent
+}
+
+type txCoin struct {
+	Denom  string  `json:"denom"`
+	Amount flexInt `json:"amount"`
+}
+
+type txEvent struct {
+	Type       string `json:"type"`
+	Attributes []struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+	} `json:"attributes"`
+}
+
+// txLookup is the state of the :tx view.
+type txLookup struct {
+	hash    string
+	network string
+	loading bool
+	details *txDetails
+	audit   *auditRecord // Record of the transaction in the audit log, if sent from here
+	err     error
+	scroll  int
+}
+
+type txLookupMsg struct {
+	lookup  *txLookup
+	details txDetails
+	found   bool
+	err     error
+}
+
+// normalizeTxHash checks a transaction hash pasted from elsewhere and returns
+// it in the upper case pocketd prints.
+func normalizeTxHash(hash string) (string, error) {
+	hash = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(hash), "0x"), "0X")
+	if len(hash) != 64 || !isHexString(hash) {
+		return "", fmt.Errorf("invalid transaction hash %q: expected 64 hex characters", hash)
+	}
+	return strings.ToUpper(hash), nil
+}
+
+// queryTxDetails looks up a transaction by hash with its messages, fee, gas
+// and events. A transaction that is not indexed is reported with found=false.
+func queryTxDetails(hash, rpcEndpoint, networkName, pocketdHome string) (txDetails, bool, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return txDetails{}, false, err
+	}
+	args := []string{"query", "tx", hash,
+		"--node=" + rpcEndpoint,
+		"--chain-id=" + chainID,
+		"--output=json"}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+
+	output, err := runPocketd(args)
+	if err != nil {
+		if strings.Contains(string(output), "not found") {
+			return txDetails{}, false, nil
+		}
+		return txDetails{}, false, fmt.Errorf("query failed: %v, output: %s", err, string(output))
+	}
+
+	var response struct {
+		TxHash    string    `json:"txhash"`
+		Height    flexInt   `json:"height"`
+		Timestamp string    `json:"timestamp"`
+		Code      int       `json:"code"`
+		Codespace string    `json:"codespace"`
+		RawLog    string    `json:"raw_log"`
+		GasWanted flexInt   `json:"gas_wanted"`
+		GasUsed   flexInt   `json:"gas_used"`
+		Events    []txEvent `json:"events"`
+		Tx        struct {
+			Body struct {
+				Messages []map[string]interface{} `json:"messages"`
+				Memo     string                   `json:"memo"`
+			} `json:"body"`
+			AuthInfo struct {
+				Fee struct {
+					Amount  []txCoin `json:"amount"`
+					Payer   string   `json:"payer"`
+					Granter string   `json:"granter"`
+				} `json:"fee"`
+			} `json:"auth_info"`
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return txDetails{}, false, fmt.Errorf("failed to parse JSON output: %v", err)
+	}
+	return txDetails{
+		Hash:      response.TxHash,
+		Height:    int64(response.Height),
+		Timestamp: response.Timestamp,
+		Code:      response.Code,
+		Codespace: response.Codespace,
+		RawLog:    response.RawLog,
+		GasWanted: int64(response.GasWanted),
+		GasUsed:   int64(response.GasUsed),
+		Memo:      response.Tx.Body.Memo,
+		Fee:       response.Tx.AuthInfo.Fee.Amount,
+		FeePayer:  response.Tx.AuthInfo.Fee.Payer,
+		Granter:   response.Tx.AuthInfo.Fee.Granter,
+		Messages:  response.Tx.Body.Messages,
+		Events:    response.Events,
+	}, true, nil
+}
+
+// findAuditTx returns the latest audit record of the transaction hash, if it
+// was sent from GASMS.
+func findAuditTx(hash string) *auditRecord {
+	records, err := loadAudit()
+	if err != nil {
+		return nil
+	}
+	for i := len(records) - 1; i >= 0; i-- {
+		if strings.EqualFold(records[i].TxHash, hash) {
+			return &records[i]
+		}
+	}
+	return nil
+}
+
+func lookupTxCmd(lookup *txLookup, rpcEndpoint, pocketdHome string) tea.Cmd {
+	return func() tea.Msg {
+		var details txDetails
+		var found bool
+		err := withFailover(lookup.network, rpcEndpoint, func(endpoint string) error {
+			var err error
+			details, found, err = queryTxDetails(lookup.hash, endpoint, lookup.network, pocketdHome)
+			return err
+		})
+		return txLookupMsg{lookup: lookup, details: details, found: found, err: err}
+	}
+}
+
+// handleTxCommand looks up "tx <hash>" on the current network, or shows the
+// last lookup with a bare "tx".
+func (m model) handleTxCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) == 1 {
+		if m.txLookup == nil {
+			m.err = fmt.Errorf("usage: tx <hash>")
+			return m, nil
+		}
+		m.state = stateTxLookup
+		return m, nil
+	}
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: tx <hash>")
+		return m, nil
+	}
+	hash, err := normalizeTxHash(parts[1])
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.config == nil {
+		m.err = fmt.Errorf("no config loaded")
+		return m, nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		m.err = fmt.Errorf("network %s not found in config", m.currentNetwork)
+		return m, nil
+	}
+	lookup := &txLookup{hash: hash, network: m.currentNetwork, loading: true}
+	m.txLookup = lookup
+	m.state = stateTxLookup
+	logger.Info("transaction lookup", "network", lookup.network, "hash", hash)
+	return m, tea.Batch(lookupTxCmd(lookup, network.RPCEndpoint, m.config.pocketdHome(m.currentNetwork)), m.startSpinner())
+}
+
+// applyTxLookup stores the result of a lookup still shown.
+func (m *model) applyTxLookup(msg txLookupMsg) {
+	lookup := msg.lookup
+	lookup.loading = false
+	switch {
+	case msg.err != nil:
+		lookup.err = msg.err
+		logger.Error("transaction lookup failed", "network", lookup.network, "hash", lookup.hash, "error", msg.err)
+	case !msg.found:
+		lookup.err = fmt.Errorf("transaction not found on %s; it may be on another network or pruned from the node", lookup.network)
+	default:
+		details := msg.details
+		lookup.details = &details
+		lookup.audit = findAuditTx(lookup.hash)
+	}
+}
+
+func (m model) updateTxLookup(msg tea.KeyMsg) (model, tea.Cmd) {
+	lookup := m.txLookup
+	switch msg.String() {
+	case "esc", "q":
+		if lookup.loading && msg.String() == "esc" {
+			return m, m.cancelInFlight()
+		}
+		m.state = stateTable
+	case "up", "k":
+		if lookup.scroll > 0 {
+			lookup.scroll--
+		}
+	case "down", "j":
+		if lookup.scroll < len(m.txLookupLines())-1 {
+			lookup.scroll++
+		}
+	case "g":
+		lookup.scroll = 0
+	case "r":
+		if !lookup.loading {
+			return m.handleTxCommand("tx " + lookup.hash)
+		}
+	}
+	return m, nil
+}
+
+// formatCoins renders coins, upokt in the display unit.
+func (m model) formatCoins(coins []txCoin) string {
+	if len(coins) == 0 {
+		return "none"
+	}
+	var parts []string
+	for _, coin := range coins {
+		if coin.Denom == "upokt" {
+			parts = append(parts, m.formatAmount(int64(coin.Amount))+" "+m.unitLabel())
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", coin.Amount, coin.Denom))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// messageFields flattens a decoded message into "path: value" lines. Coins
+// are shown in the display unit.
+func (m model) messageFields(prefix string, value interface{}) []string {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if denom, ok := v["denom"].(string); ok && len(v) == 2 {
+			if amount, ok := v["amount"].(string); ok {
+				var upokt flexInt
+				if denom == "upokt" && json.Unmarshal([]byte(amount), &upokt) == nil {
+					return []string{fmt.Sprintf("%s: %s %s", prefix, m.formatAmount(int64(upokt)), m.unitLabel())}
+				}
+				return []string{fmt.Sprintf("%s: %s %s", prefix, amount, denom)}
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if key != "@type" {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		var lines []string
+		for _, key := range keys {
+			path := key
+			if prefix != "" {
+				path = prefix + "." + key
+			}
+			lines = append(lines, m.messageFields(path, v[key])...)
+		}
+		return lines
+	case []interface{}:
+		if len(v) == 0 {
+			return []string{prefix + ": []"}
+		}
+		var lines []string
+		for i, item := range v {
+			lines = append(lines, m.messageFields(fmt.Sprintf("%s[%d]", prefix, i), item)...)
+		}
+		return lines
+	case nil:
+		return []string{prefix + ": null"}
+	default:
+		return []string{fmt.Sprintf("%s: %v", prefix, v)}
+	}
+}
+
+// txLookupLines renders the body of the :tx view.
+func (m model) txLookupLines() []string {
+	sectionStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Padding(0, 2)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("120")). // Green for success
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+
+	lookup := m.txLookup
+	if lookup == nil || lookup.details == nil {
+		return nil
+	}
+	d := lookup.details
+	width := m.width - 6
+	row := func(label, value string) string {
+		return textStyle.Render(truncateToWidth(fmt.Sprintf("%-12s %s", label, value), width))
+	}
+
+	var lines []string
+	if d.Code == 0 {
+		lines = append(lines, successStyle.Render("✅ Succeeded"))
+	} else {
+		lines = append(lines, errorStyle.Render(truncateToWidth("❌ Failed: "+txFailureReason(uint32(d.Code), d.Codespace, d.RawLog), width)))
+	}
+	lines = append(lines, row("Height", fmt.Sprintf("%d", d.Height)))
+	if t, err := time.Parse(time.RFC3339, d.Timestamp); err == nil {
+		lines = append(lines, row("Time", t.Local().Format("2006-01-02 15:04:05")))
+	}
+	lines = append(lines, row("Fee", m.formatCoins(d.Fee)))
+	if d.Granter != "" {
+		lines = append(lines, row("Fee granter", d.Granter))
+	}
+	if d.FeePayer != "" {
+		lines = append(lines, row("Fee payer", d.FeePayer))
+	}
+	gas := fmt.Sprintf("%d used of %d wanted", d.GasUsed, d.GasWanted)
+	if d.GasWanted > 0 {
+		gas += fmt.Sprintf(" (%d%%)", d.GasUsed*100/d.GasWanted)
+	}
+	lines = append(lines, row("Gas", gas))
+	if d.Memo != "" {
+		lines = append(lines, row("Memo", d.Memo))
+	}
+	if a := lookup.audit; a != nil {
+		lines = append(lines, row("Sent by", fmt.Sprintf("%s with \"%s\" at %s", a.Operator, a.Command, a.Time.Local().Format("2006-01-02 15:04:05"))))
+	}
+
+	lines = append(lines, "", sectionStyle.Render(fmt.Sprintf("Messages (%d)", len(d.Messages))))
+	for i, message := range d.Messages {
+		msgType, _ := message["@type"].(string)
+		if dot := strings.LastIndex(msgType, "."); dot >= 0 {
+			msgType = msgType[dot+1:]
+		}
+		lines = append(lines, textStyle.Render(fmt.Sprintf("%d. %s", i+1, msgType)))
+		for _, field := range m.messageFields("", message) {
+			lines = append(lines, textStyle.Render(truncateToWidth("     "+field, width)))
+		}
+	}
+
+	lines = append(lines, "", sectionStyle.Render(fmt.Sprintf("Events (%d)", len(d.Events))))
+	for _, event := range d.Events {
+		var attributes []string
+		for _, attribute := range event.Attributes {
+			if attribute.Key == "msg_index" || attribute.Key == "mode" {
+				continue
+			}
+			attributes = append(attributes, attribute.Key+"="+strings.Trim(attribute.Value, `"`))
+		}
+		lines = append(lines, textStyle.Render(truncateToWidth(fmt.Sprintf("%-24s %s", event.Type, strings.Join(attributes, " ")), width)))
+	}
+	return lines
+}
+
+// renderTxLookup renders the scrollable details of the looked up
+// transaction.
+func (m model) renderTxLookup() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")). // Red for errors
+		Padding(0, 2)
+
+	lookup := m.txLookup
+	if lookup == nil {
+		return ""
+	}
+	content := []string{headerStyle.Render("🧾 TRANSACTION • " + lookup.network), ""}
+	content = append(content, textStyle.Render(lookup.hash), "")
+	switch {
+	case lookup.loading:
+		content = append(content, textStyle.Render(m.spinner()+" Querying the transaction..."))
+	case lookup.err != nil:
+		content = append(content, errorStyle.Render(lookup.err.Error()))
+	default:
+		// Scroll the details, keeping the title and footer in place
+		lines := m.txLookupLines()
+		visible := max(m.height-12, 1)
+		scroll := min(lookup.scroll, max(len(lines)-visible, 0))
+		end := min(scroll+visible, len(lines))
+		content = append(content, lines[scroll:end]...)
+	}
+	content = append(content, "")
+	content = append(content, textStyle.Render("j/k to scroll • r to query again • ESC or Q to return"))
+	return strings.Join(content, "\n")
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// broadcastResult is the response pocketd prints after broadcasting a
+// transaction. A non-zero Code means the node rejected it.
+type broadcastResult struct {
+	TxHash    string `json:"txhash"`
+	Code      uint32 `json:"code"`
+	Codespace string `json:"codespace"`
+	RawLog    string `json:"raw_log"`
+}
+
+// txCodeHints explains the rejections operators can act on, by codespace and
+// code.
+var txCodeHints = map[string]map[uint32]string{
+	"sdk": {
+		4:  "signature rejected: check that the key is in the keyring and matches the address",
+		5:  "insufficient funds: the sender cannot cover the amount plus fees; fund it first",
+		7:  "invalid address",
+		10: "invalid amount",
+		11: "out of gas: the transaction needs a higher gas limit",
+		12: "memo too large: shorten the memo",
+		13: "fee too low for this node's minimum gas price",
+		19: "already broadcast: the same transaction is in the mempool",
+		20: "mempool is full: retry in a few blocks",
+		32: "account sequence mismatch: another transaction from this account is pending; retry once it is included",
+	},
+	"feegrant": {
+		2: "fee grant limit exceeded: top up the grant with :grant",
+		3: "fee grant expired: renew it with :grant",
+		5: "no fee grant from the bank: create it with :grant",
+		7: "message not allowed by the fee grant: recreate it with :grant",
+	},
+}
+
+// txError is a transaction the chain rejected.
+type txError struct {
+	hash      string
+	code      uint32
+	codespace string
+	rawLog    string
+}
+
+func (e txError) Error() string {
+	if e.hash == "" {
+		return "transaction rejected: " + e.reason()
+	}
+	return fmt.Sprintf("transaction %s rejected: %s", e.hash, e.reason())
+}
+
+// reason describes the rejection without the hash.
+func (e txError) reason() string {
+	return txFailureReason(e.code, e.codespace, e.rawLog)
+}
+
+// txFailureReason describes a failed transaction, with a hint when the code
+// is a common one.
+func txFailureReason(code uint32, codespace, rawLog string) string {
+	if codespace == "" {
+		codespace = "sdk"
+	}
+	hint, known := txCodeHints[codespace][code]
+	switch {
+	case known && rawLog != "":
+		return fmt.Sprintf("%s (%s code %d: %s)", hint, codespace, code, rawLog)
+	case known:
+		return fmt.Sprintf("%s (%s code %d)", hint, codespace, code)
+	case rawLog != "":
+		return fmt.Sprintf("%s code %d: %s", codespace, code, rawLog)
+	}
+	return fmt.Sprintf("%s code %d", codespace, code)
+}
+
+// err returns the rejection of the transaction, or nil if the node accepted
+// it.
+func (r broadcastResult) err() error {
+	if r.Code == 0 {
+		return nil
+	}
+	return txError{hash: r.TxHash, code: r.Code, codespace: r.Codespace, rawLog: r.RawLog}
+}
+
+// parseBroadcast parses the output of a pocketd transaction: JSON, or the
+// "key: value" text output of older versions. Stderr lines such as the "gas
+// estimate" of --gas=auto may come before the JSON.
+func parseBroadcast(output string) (broadcastResult, error) {
+	var result broadcastResult
+	if err := json.Unmarshal([]byte(output), &result); err == nil {
+		return result, nil
+	}
+	lines := strings.Split(output, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var lineResult broadcastResult
+		if err := json.Unmarshal([]byte(line), &lineResult); err == nil {
+			return lineResult, nil
+		}
+	}
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		key, value, found := strings.Cut(line, ":")
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch {
+		case found && strings.EqualFold(key, "txhash"):
+			result.TxHash = value
+		case found && key == "code":
+			code, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return result, fmt.Errorf("invalid code %q", value)
+			}
+			result.Code = uint32(code)
+		case found && key == "codespace":
+			result.Codespace = value
+		case found && key == "raw_log":
+			result.RawLog = value
+		case result.TxHash == "" && len(line) == 64 && isHexString(line):
+			// A bare transaction hash
+			result.TxHash = line
+		}
+	}
+	return result, nil
+}
+
+// broadcastHash returns the hash of a broadcast transaction, or why the node
+// rejected it.
+func broadcastHash(output []byte) (string, error) {
+	result, err := parseBroadcast(string(output))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse pocketd output: %v", err)
+	}
+	if err := result.err(); err != nil {
+		return "", err
+	}
+	if result.TxHash == "" {
+		return "", fmt.Errorf("no transaction hash in pocketd output")
+	}
+	return result.TxHash, nil
+}
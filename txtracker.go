@@ -0,0 +1,397 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	txPollInterval   = 3 * time.Second
+	txConfirmTimeout = 2 * time.Minute
+	// Finished transactions stay in the panel this long
+	txPanelRetention = 2 * time.Minute
+	txPanelMaxRows   = 6
+)
+
+type txStatus string
+
+const (
+	txSubmitting  txStatus = "submitting"
+	txBroadcast   txStatus = "broadcast"
+	txIncluded    txStatus = "included"
+	txFailed      txStatus = "failed"
+	txUnconfirmed txStatus = "unconfirmed"
+	txQueued      txStatus = "queued"   // Waiting for approval in the queue
+	txRejected    txStatus = "rejected" // Rejected in the queue, never broadcast
+	txCooling     txStatus = "cooling"  // Held for the pre-submit cooldown
+)
+
+// trackedTx is a transaction submitted from GASMS, followed until it is
+// included in a block or fails.
+type trackedTx struct {
+	id          int
+	kind        string // upstake, fund, fund-all, ...
+	command     string // Command line that submitted the transaction
+	network     string
+	bank        string // Bank address of the network when submitted
+	operator    string // Who submitted it, as user@host
+	addresses   []string
+	target      string // Address or description of the recipients
+	amount      int64  // Amount in upokt (0 if not applicable)
+	hash        string
+	status      txStatus
+	height      int64
+	fee         int64 // Fee paid in upokt, known once included
+	err         string
+	submittedAt time.Time
+	updatedAt   time.Time
+}
+
+func (t trackedTx) finished() bool {
+	return t.status == txIncluded || t.status == txFailed || t.status == txUnconfirmed || t.status == txRejected
+}
+
+// txFailedMsg reports a transaction that could not be broadcast, or that the
+// node rejected, in which case hash is set.
+type txFailedMsg struct {
+	txID      int
+	operation string // e.g. upstake, fund, transfer
+	addresses []string
+	amount    int64 // upokt, 0 if the operation moves none
+	hash      string
+	err       error
+}
+
+// newTxFailedMsg reports the failure of operation on addresses.
+func newTxFailedMsg(txID int, operation string, addresses []string, amount int64, err error) txFailedMsg {
+	msg := txFailedMsg{txID: txID, operation: operation, addresses: addresses, amount: amount, err: err}
+	var rejected txError
+	if errors.As(err, &rejected) {
+		msg.hash = rejected.hash
+	}
+	return msg
+}
+
+// reason describes the failure, without the hash of a rejected transaction.
+func (msg txFailedMsg) reason() string {
+	var rejected txError
+	if errors.As(msg.err, &rejected) {
+		return rejected.reason()
+	}
+	return msg.err.Error()
+}
+
+// text is the notification of the failure.
+func (msg txFailedMsg) text() string {
+	if msg.hash != "" {
+		return "TXHASH: " + msg.hash + ". ERROR: " + msg.reason()
+	}
+	return fmt.Sprintf("%s%s failed: %v", strings.ToUpper(msg.operation[:1]), msg.operation[1:], msg.err)
+}
+
+// txStatusMsg is the result of polling a broadcast transaction.
+type txStatusMsg struct {
+	txID      int
+	found     bool
+	height    int64
+	code      int
+	codespace string
+	rawLog    string
+	fee       int64 // upokt
+}
+
+// failure describes why an included transaction failed.
+func (s txStatusMsg) failure() string {
+	return txFailureReason(uint32(s.code), s.codespace, s.rawLog)
+}
+
+// trackTx registers a transaction that is about to be submitted and returns its id.
+func (m *model) trackTx(kind, command string, addresses []string, amount int64) int {
+	target := fmt.Sprintf("%d apps", len(addresses))
+	if len(addresses) == 1 {
+		target = addresses[0]
+	}
+	var bank string
+	if m.config != nil {
+		bank = m.config.Config.Networks[m.currentNetwork].Bank
+	}
+	m.nextTxID++
+	now := time.Now()
+	m.txs = append(m.txs, trackedTx{
+		id:          m.nextTxID,
+		kind:        kind,
+		command:     command,
+		network:     m.currentNetwork,
+		bank:        bank,
+		operator:    m.operator(),
+		addresses:   addresses,
+		target:      target,
+		amount:      amount,
+		status:      txSubmitting,
+		submittedAt: now,
+		updatedAt:   now,
+	})
+	logger.Info("transaction submitting", "tx_id", m.nextTxID, "kind", kind, "network", m.currentNetwork, "target", target, "amount_upokt", amount)
+	return m.nextTxID
+}
+
+func (m *model) findTx(id int) *trackedTx {
+	for i := range m.txs {
+		if m.txs[i].id == id {
+			return &m.txs[i]
+		}
+	}
+	return nil
+}
+
+// txBroadcasted records the hash of a submitted transaction and starts
+// polling for its inclusion.
+func (m *model) txBroadcasted(id int, hash string) tea.Cmd {
+	tx := m.findTx(id)
+	if tx == nil {
+		return nil
+	}
+	tx.hash = hash
+	tx.status = txBroadcast
+	tx.updatedAt = time.Now()
+	logger.Info("transaction broadcast", "tx_id", id, "kind", tx.kind, "hash", hash)
+	auditTx(*tx)
+	if hash == "" {
+		return nil
+	}
+	return m.pollTxCmd(*tx)
+}
+
+// txFailedWith marks a transaction as failed.
+func (m *model) txFailedWith(id int, hash, reason string) {
+	tx := m.findTx(id)
+	if tx == nil {
+		return
+	}
+	if hash != "" {
+		tx.hash = hash
+	}
+	tx.status = txFailed
+	tx.err = reason
+	tx.updatedAt = time.Now()
+	logger.Error("transaction failed", "tx_id", id, "kind", tx.kind, "target", tx.target, "hash", tx.hash, "error", reason)
+	auditTx(*tx)
+}
+
+// applyTxStatus updates a transaction from a poll result and keeps polling
+// until it is found or the confirmation timeout expires.
+func (m *model) applyTxStatus(msg txStatusMsg) tea.Cmd {
+	tx := m.findTx(msg.txID)
+	if tx == nil || tx.finished() {
+		return nil
+	}
+	tx.updatedAt = time.Now()
+	if !msg.found {
+		if time.Since(tx.submittedAt) > txConfirmTimeout {
+			tx.status = txUnconfirmed
+			logger.Warn("transaction unconfirmed", "tx_id", tx.id, "kind", tx.kind, "hash", tx.hash)
+			auditTx(*tx)
+			return m.notify(toastWarning, fmt.Sprintf("%s %s not found after %s", tx.kind, tx.hash, txConfirmTimeout))
+		}
+		return m.pollTxCmd(*tx)
+	}
+	tx.height = msg.height
+	tx.fee = msg.fee
+	if msg.code != 0 {
+		tx.status = txFailed
+		tx.err = msg.failure()
+		logger.Error("transaction failed", "tx_id", tx.id, "kind", tx.kind, "target", tx.target, "hash", tx.hash, "height", tx.height, "error", tx.err)
+		auditTx(*tx)
+		return m.notify(toastError, fmt.Sprintf("%s %s failed at height %d: %s", tx.kind, tx.hash, tx.height, tx.err))
+	}
+	tx.status = txIncluded
+	logger.Info("transaction included", "tx_id", tx.id, "kind", tx.kind, "target", tx.target, "hash", tx.hash, "height", tx.height)
+	auditTx(*tx)
+	return m.notify(toastSuccess, fmt.Sprintf("%s %s included at height %d", tx.kind, tx.hash, tx.height))
+}
+
+func (m model) pollTxCmd(tx trackedTx) tea.Cmd {
+	config := m.config
+	return tea.Tick(txPollInterval, func(t time.Time) tea.Msg {
+		if config == nil {
+			return txStatusMsg{txID: tx.id}
+		}
+		network, exists := config.Config.Networks[tx.network]
+		if !exists {
+			return txStatusMsg{txID: tx.id}
+		}
+		var status txStatusMsg
+		withFailover(tx.network, network.RPCEndpoint, func(endpoint string) error {
+			var err error
+			status, err = queryTxStatus(tx.hash, endpoint, tx.network, config.pocketdHome(tx.network))
+			return err
+		})
+		status.txID = tx.id
+		return status
+	})
+}
+
+// queryTxStatus looks up a transaction by hash. A transaction that is not
+// (yet) indexed is reported with found=false and no error.
+func queryTxStatus(hash, rpcEndpoint, networkName, pocketdHome string) (txStatusMsg, error) {
+	chainID, err := chainIDFor(networkName)
+	if err != nil {
+		return txStatusMsg{}, err
+	}
+
+	args := []string{"query", "tx", hash,
+		"--node=" + rpcEndpoint,
+		"--chain-id=" + chainID,
+		"--output=json"}
+	if pocketdHome != "" {
+		args = append(args, "--home="+pocketdHome)
+	}
+
+	output, err := runPocketd(args)
+	if err != nil {
+		if strings.Contains(string(output), "not found") {
+			return txStatusMsg{found: false}, nil
+		}
+		return txStatusMsg{}, fmt.Errorf("query failed: %v, output: %s", err, string(output))
+	}
+
+	var response struct {
+		Height    flexInt `json:"height"`
+		Code      int     `json:"code"`
+		Codespace string  `json:"codespace"`
+		RawLog    string  `json:"raw_log"`
+		Tx        struct {
+			AuthInfo struct {
+				Fee struct {
+					Amount []struct {
+						Denom  string  `json:"denom"`
+						Amount flexInt `json:"amount"`
+					} `json:"amount"`
+				} `json:"fee"`
+			} `json:"auth_info"`
+		} `json:"tx"`
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return txStatusMsg{}, fmt.Errorf("failed to parse JSON output: %v", err)
+	}
+
+	var fee int64
+	for _, coin := range response.Tx.AuthInfo.Fee.Amount {
+		if coin.Denom == "upokt" {
+			fee += int64(coin.Amount)
+		}
+	}
+
+	return txStatusMsg{
+		found:     true,
+		height:    int64(response.Height),
+		code:      response.Code,
+		codespace: response.Codespace,
+		rawLog:    response.RawLog,
+		fee:       fee,
+	}, nil
+}
+
+// pruneTxs drops finished transactions older than the panel retention,
+// keeping those of batches whose receipts are not exported yet.
+func (m *model) pruneTxs() {
+	exporting := make(map[int]bool)
+	for _, batch := range m.pendingBatches {
+		for _, id := range batch.txIDs {
+			exporting[id] = true
+		}
+	}
+	var kept []trackedTx
+	for _, tx := range m.txs {
+		if tx.finished() && time.Since(tx.updatedAt) > txPanelRetention && !exporting[tx.id] {
+			m.keepFundAllTx(tx)
+			continue
+		}
+		kept = append(kept, tx)
+	}
+	m.txs = kept
+}
+
+// pendingTxCount returns the number of transactions that have not finished.
+func (m model) pendingTxCount() int {
+	count := 0
+	for _, tx := range m.txs {
+		if !tx.finished() {
+			count++
+		}
+	}
+	return count
+}
+
+// renderTxPanel renders the in-flight and recently finished transactions.
+func (m model) renderTxPanel() string {
+	if len(m.txs) == 0 {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true)
+	pendingStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")) // Yellow
+	successStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("46")) // Bright green
+	errorStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("196")) // Bright red
+
+	lines := []string{titleStyle.Render(fmt.Sprintf("💸 TRANSACTIONS (%d pending)", m.pendingTxCount()))}
+
+	// Most recent first
+	start := 0
+	if len(m.txs) > txPanelMaxRows {
+		start = len(m.txs) - txPanelMaxRows
+	}
+	for i := len(m.txs) - 1; i >= start; i-- {
+		tx := m.txs[i]
+		line := fmt.Sprintf("%-11s %-13s", tx.kind, TruncateAddress(tx.target, 13))
+		if tx.amount > 0 {
+			line += fmt.Sprintf(" %s %s", m.formatAmount(tx.amount), m.unitLabel())
+		}
+
+		var style lipgloss.Style
+		switch tx.status {
+		case txSubmitting:
+			style = pendingStyle
+			line = fmt.Sprintf("%s %s  submitting...", m.spinner(), line)
+		case txBroadcast:
+			style = pendingStyle
+			line = fmt.Sprintf("%s %s  broadcast %s, waiting for inclusion", m.spinner(), line, tx.hash)
+		case txIncluded:
+			style = successStyle
+			line = fmt.Sprintf("✅ %s  included at height %d  %s", line, tx.height, tx.hash)
+		case txUnconfirmed:
+			style = pendingStyle
+			line = fmt.Sprintf("❔ %s  not found after %s  %s", line, txConfirmTimeout, tx.hash)
+		case txQueued:
+			style = pendingStyle
+			line = fmt.Sprintf("⏸️  %s  waiting for approval (:queue)", line)
+		case txCooling:
+			style = pendingStyle
+			left, _ := m.cooldownLeft(tx.id)
+			line = fmt.Sprintf("⏳ %s  broadcasting in %ds, ESC to cancel", line, max(left, 0))
+		case txRejected:
+			style = errorStyle
+			line = fmt.Sprintf("🚫 %s  rejected", line)
+		default:
+			style = errorStyle
+			line = fmt.Sprintf("❌ %s  failed: %s", line, tx.err)
+			if tx.hash != "" {
+				line += "  " + tx.hash
+			}
+		}
+		lines = append(lines, style.Render(truncateToWidth(line, max(m.width, 20))))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const uiStateFile = "ui-state.json"
+
+// freshFlag is set by --fresh to start with the defaults instead of the
+// saved UI state.
+var freshFlag bool
+
+// uiState is the part of the UI saved on exit and restored on the next
+// launch.
+type uiState struct {
+	Network   string              `json:"network"`
+	Gateway   string              `json:"gateway"`
+	SortBy    string              `json:"sort_by"`
+	SortDesc  bool                `json:"sort_desc"`
+	Columns   []string            `json:"columns,omitempty"`   // Columns chosen with :columns, nil for the config's
+	Selected  string              `json:"selected,omitempty"`  // Address under the cursor
+	Search    string              `json:"search,omitempty"`    // Active search or saved filter expression
+	Watchlist map[string][]string `json:"watchlist,omitempty"` // Pinned applications by network
+}
+
+func uiStatePath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, uiStateFile), nil
+}
+
+// loadUIState reads the saved UI state, reporting false if there is none.
+func loadUIState() (uiState, bool) {
+	var state uiState
+	path, err := uiStatePath()
+	if err != nil {
+		return state, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Warn("failed to read UI state", "path", path, "error", err)
+		}
+		return state, false
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		logger.Warn("ignoring unreadable UI state", "path", path, "error", err)
+		return state, false
+	}
+	return state, true
+}
+
+// saveUIState writes the UI state of m for the next launch. Nothing is saved
+// before a network was shown, nor by SSH sessions, which would overwrite the
+// state of the account running gasms serve.
+func saveUIState(m model) {
+	if m.config == nil || m.currentNetwork == "" || m.sessionUser != "" {
+		return
+	}
+	state := uiState{
+		Network:   m.currentNetwork,
+		Gateway:   m.currentGateway,
+		SortBy:    m.sortBy,
+		SortDesc:  m.sortDesc,
+		Search:    m.searchInput,
+		Watchlist: m.watchlist,
+	}
+	if !slices.Equal(m.visibleColumns, m.config.Config.Columns) {
+		state.Columns = m.visibleColumns
+	}
+	if m.cursor < len(m.applications) {
+		state.Selected = m.applications[m.cursor].Address
+	}
+	path, err := uiStatePath()
+	if err == nil {
+		err = writeJSONFile(path, state)
+	}
+	if err != nil {
+		logger.Warn("failed to save UI state", "error", err)
+		return
+	}
+	logger.Debug("UI state saved", "path", path, "network", state.Network, "gateway", state.Gateway)
+}
+
+// restoreUIState applies the saved UI state once the config is loaded,
+// skipping networks, gateways and columns that are no longer configured or
+// valid. The cursor is restored once the applications are shown.
+func (m *model) restoreUIState() {
+	state, ok := loadUIState()
+	if !ok {
+		return
+	}
+	// Pins survive --fresh, which only resets the view
+	m.watchlist = state.Watchlist
+	if freshFlag {
+		return
+	}
+	if network, exists := m.config.Config.Networks[state.Network]; exists && len(network.Gateways) > 0 {
+		m.currentNetwork = state.Network
+		if slices.Contains(network.Gateways, state.Gateway) {
+			m.currentGateway = state.Gateway
+		}
+	}
+	if state.SortBy != "" {
+		m.sortBy = state.SortBy
+		m.sortDesc = state.SortDesc
+	}
+	if len(state.Columns) > 0 && validateColumns(state.Columns) == nil {
+		m.visibleColumns = state.Columns
+	}
+	m.restoreSelected = state.Selected
+	m.searchInput = state.Search
+	logger.Info("UI state restored", "network", m.currentNetwork, "gateway", m.currentGateway, "sort", m.sortBy)
+}
+
+// restoreSearch matches the active search, such as the one restored from the
+// UI state, against the applications shown without moving the cursor. A
+// saved search that no longer parses is dropped.
+func (m *model) restoreSearch() {
+	if m.searchInput == "" || len(m.applications) == 0 {
+		return
+	}
+	matches, err := m.searchMatches(m.searchInput)
+	if err != nil {
+		logger.Warn("ignoring saved search", "search", m.searchInput, "error", err)
+		m.searchInput = ""
+		matches = nil
+	}
+	m.searchResults = matches
+}
+
+// restoreCursor moves the cursor to the application selected when the UI
+// state was saved. It keeps trying until the first refresh, when final is
+// set.
+func (m *model) restoreCursor(final bool) {
+	if m.restoreSelected == "" {
+		return
+	}
+	for i, app := range m.applications {
+		if app.Address == m.restoreSelected {
+			m.cursor = i
+			m.restoreSelected = ""
+			return
+		}
+	}
+	if final {
+		m.restoreSelected = ""
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	upoktPerPOKT = 1_000_000
+
+	unitPOKT  = "pokt"
+	unitUPOKT = "upokt"
+
+	defaultPrecision = 2
+	maxPrecision     = 6 // uPOKT is the smallest unit, so more decimals add nothing
+)
+
+// parseDisplayUnit normalizes a configured or typed denomination.
+func parseDisplayUnit(unit string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(unit)) {
+	case "", unitPOKT:
+		return unitPOKT, nil
+	case unitUPOKT:
+		return unitUPOKT, nil
+	default:
+		return "", fmt.Errorf("unknown denomination: %s (options: upokt, pokt)", unit)
+	}
+}
+
+func validatePrecision(precision int) error {
+	if precision < 0 || precision > maxPrecision {
+		return fmt.Errorf("precision must be between 0 and %d: %d", maxPrecision, precision)
+	}
+	return nil
+}
+
+// unitLabel returns the display label of the current denomination.
+func (m model) unitLabel() string {
+	if m.displayUnit == unitUPOKT {
+		return "uPOKT"
+	}
+	return "POKT"
+}
+
+// formatAmount renders a upokt amount in the current display denomination.
+func (m model) formatAmount(upokt int64) string {
+	if m.displayUnit == unitUPOKT {
+		return strconv.FormatInt(upokt, 10)
+	}
+	return strconv.FormatFloat(float64(upokt)/upoktPerPOKT, 'f', m.displayPrecision, 64)
+}
+
+// formatPOKT renders an amount already converted to POKT in the current
+// display denomination.
+func (m model) formatPOKT(pokt float64) string {
+	return m.formatAmount(int64(math.Round(pokt * upoktPerPOKT)))
+}
+
+// amountUnits are the denominations accepted in typed amounts, in upokt.
+var amountUnits = map[string]int64{
+	"upokt": 1,
+	"pokt":  upoktPerPOKT,
+	"kpokt": 1000 * upoktPerPOKT,
+}
+
+// parseAmount parses a typed amount such as 250000000, 250000000upokt,
+// 1500pokt or 2.5kpokt into upokt. A bare number is in upokt, so decimals
+// need a unit.
+func parseAmount(s string) (int64, error) {
+	text := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), "_", ""))
+	number := strings.TrimRight(text, "abcdefghijklmnopqrstuvwxyz")
+	unit := strings.TrimSpace(text[len(number):])
+	number = strings.TrimSpace(number)
+	if number == "" || number == "." {
+		return 0, fmt.Errorf("invalid amount %s: missing number", s)
+	}
+	if unit == "" {
+		if strings.Contains(number, ".") {
+			return 0, fmt.Errorf("invalid amount %s: add a unit to decimal amounts (e.g. %spokt)", s, number)
+		}
+		unit = unitUPOKT
+	}
+	scale, known := amountUnits[unit]
+	if !known {
+		return 0, fmt.Errorf("invalid amount %s: unknown unit %q (options: upokt, pokt, kpokt)", s, unit)
+	}
+
+	whole, fraction, _ := strings.Cut(number, ".")
+	if whole == "" {
+		whole = "0"
+	}
+	wholeValue, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || wholeValue < 0 {
+		return 0, fmt.Errorf("invalid amount %s: not a number", s)
+	}
+	if wholeValue > math.MaxInt64/scale {
+		return 0, fmt.Errorf("invalid amount %s: too large", s)
+	}
+	amount := wholeValue * scale
+
+	// Scale the fraction digit by digit, so no precision is lost to floats
+	digitScale := scale
+	for _, digit := range fraction {
+		if digit < '0' || digit > '9' {
+			return 0, fmt.Errorf("invalid amount %s: not a number", s)
+		}
+		if digit == '0' {
+			digitScale /= 10
+			continue
+		}
+		if digitScale < 10 {
+			return 0, fmt.Errorf("invalid amount %s: finer than 1 upokt", s)
+		}
+		digitScale /= 10
+		amount += int64(digit-'0') * digitScale
+	}
+	if amount <= 0 {
+		return 0, fmt.Errorf("invalid amount %s: must be positive", s)
+	}
+	return amount, nil
+}
+
+// stakeUpokt returns the exact stake of app in upokt.
+func stakeUpokt(app Application) int64 {
+	amount, err := strconv.ParseInt(app.StakeAmount, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return amount
+}
+
+// handleUnitCommand switches the display denomination: "unit <upokt|pokt> [precision]".
+func (m model) handleUnitCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) < 2 {
+		m.err = fmt.Errorf("usage: unit <upokt|pokt> [precision]")
+		return m, nil
+	}
+
+	unit, err := parseDisplayUnit(parts[1])
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if len(parts) >= 3 {
+		precision, err := strconv.Atoi(parts[2])
+		if err != nil {
+			m.err = fmt.Errorf("precision must be an integer: %s", parts[2])
+			return m, nil
+		}
+		if err := validatePrecision(precision); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.displayPrecision = precision
+	}
+
+	m.displayUnit = unit
+	return m, nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// isUnstaking reports whether app has a pending unstake.
+func isUnstaking(app Application) bool {
+	return app.UnstakingHeight > 0
+}
+
+// blocksUntil returns the blocks until height on the current network, or
+// false while its current height is unknown.
+func (m model) blocksUntil(height int64) (int64, bool) {
+	health, _, ok := rpcPool.status(m.currentNetwork)
+	if !ok || health.Height <= 0 {
+		return 0, false
+	}
+	return max64(height-health.Height, 0), true
+}
+
+// unstakingBlocksLeft returns the blocks until the unstake of app completes,
+// or false while the current height of the network is unknown.
+func (m model) unstakingBlocksLeft(app Application) (int64, bool) {
+	return m.blocksUntil(app.UnstakingHeight)
+}
+
+// unstakingBadge describes the pending unstake of app for the table.
+func (m model) unstakingBadge(app Application) string {
+	if !isUnstaking(app) {
+		return "-"
+	}
+	if left, ok := m.unstakingBlocksLeft(app); ok {
+		return fmt.Sprintf("unstaking, %d blocks left", left)
+	}
+	return fmt.Sprintf("unstaking at %d", app.UnstakingHeight)
+}
+
+// withoutUnstaking drops the addresses of applications with a pending
+// unstake, which the chain refuses to upstake, and returns the ones dropped.
+func (m model) withoutUnstaking(addresses []string) (kept, skipped []string) {
+	unstaking := make(map[string]bool)
+	for _, app := range m.applications {
+		if isUnstaking(app) {
+			unstaking[app.Address] = true
+		}
+	}
+	for _, address := range addresses {
+		if unstaking[address] {
+			skipped = append(skipped, address)
+			continue
+		}
+		kept = append(kept, address)
+	}
+	return kept, skipped
+}
+
+// describeSkipped formats skipped addresses for a notification.
+func describeSkipped(addresses []string) string {
+	short := make([]string, len(addresses))
+	for i, address := range addresses {
+		short[i] = TruncateAddress(address, 13)
+	}
+	return strings.Join(short, ", ")
+}
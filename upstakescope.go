@@ -0,0 +1,218 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Scopes of upstake-all: the configured applications of the gateway, every
+// application shown in the table, or the selected ones.
+const (
+	upstakeScopeConfigured = "configured"
+	upstakeScopeDisplayed  = "displayed"
+	upstakeScopeSelected   = "selected"
+)
+
+var upstakeScopes = []string{upstakeScopeConfigured, upstakeScopeDisplayed, upstakeScopeSelected}
+
+// upstakeAllConfirm is an upstake-all with an explicit scope, awaiting
+// confirmation of its targets.
+type upstakeAllConfirm struct {
+	verb             string // "ua", "upstake-all!", ...
+	amountArg        string // As typed
+	amount           int64
+	scope            string
+	includeUnstaking bool
+}
+
+// command returns the upstake-all command confirm runs, as recorded in the
+// audit log and the approval queue.
+func (confirm upstakeAllConfirm) command() string {
+	command := fmt.Sprintf("%s %s --scope=%s", confirm.verb, confirm.amountArg, confirm.scope)
+	if confirm.includeUnstaking {
+		command += " --include-unstaking"
+	}
+	return command
+}
+
+// selectedAddresses returns the applications matching the last search, or
+// the one under the cursor when nothing was searched.
+func (m model) selectedAddresses() []string {
+	if m.searchInput != "" {
+		// An invalid search selects nothing
+		matches, _ := m.searchMatches(m.searchInput)
+		var addresses []string
+		for _, i := range matches {
+			addresses = append(addresses, m.applications[i].Address)
+		}
+		return addresses
+	}
+	if m.cursor < len(m.applications) {
+		return []string{m.applications[m.cursor].Address}
+	}
+	return nil
+}
+
+// upstakeScopeAddresses returns the applications in scope.
+func (m model) upstakeScopeAddresses(scope string) []string {
+	switch scope {
+	case upstakeScopeDisplayed:
+		addresses := make([]string, len(m.applications))
+		for i, app := range m.applications {
+			addresses[i] = app.Address
+		}
+		return addresses
+	case upstakeScopeSelected:
+		return m.selectedAddresses()
+	}
+	if m.config == nil {
+		return nil
+	}
+	return m.config.Config.Networks[m.currentNetwork].applicationsFor(m.currentGateway)
+}
+
+// upstakeAllTargets returns the applications confirm would upstake, and
+// those skipped for a pending unstake. It fails when the scope selects
+// nothing to upstake, saying why.
+func (m model) upstakeAllTargets(confirm upstakeAllConfirm) (targets, skipped []string, err error) {
+	if confirm.scope == upstakeScopeSelected && m.searchInput != "" {
+		if _, err := m.parseSearch(m.searchInput); err != nil {
+			return nil, nil, fmt.Errorf("invalid search for --scope=selected: %v", err)
+		}
+	}
+	targets = m.upstakeScopeAddresses(confirm.scope)
+	if !confirm.includeUnstaking {
+		targets, skipped = m.withoutUnstaking(targets)
+	}
+	switch {
+	case len(targets) == 0 && len(skipped) > 0:
+		return nil, skipped, fmt.Errorf("every %s app is unstaking (use --include-unstaking to upstake them)", confirm.scope)
+	case len(targets) == 0:
+		return nil, nil, fmt.Errorf("no %s applications to upstake", confirm.scope)
+	}
+	return targets, skipped, nil
+}
+
+func (m model) updateUpstakeAllConfirm(msg tea.KeyMsg) (model, tea.Cmd) {
+	confirm := m.upstakeAll
+	switch msg.String() {
+	case "y", "Y":
+		if _, _, err := m.upstakeAllTargets(*confirm); err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.upstakeAll = nil
+		m.state = stateTable
+		return m.startUpstakeAll(confirm.command(), confirm.amount, confirm.scope, confirm.includeUnstaking, strings.HasSuffix(confirm.verb, "!"))
+	case "tab":
+		i := slices.Index(upstakeScopes, confirm.scope)
+		confirm.scope = upstakeScopes[(i+1)%len(upstakeScopes)]
+	case "shift+tab":
+		i := slices.Index(upstakeScopes, confirm.scope)
+		confirm.scope = upstakeScopes[(i+len(upstakeScopes)-1)%len(upstakeScopes)]
+	case "n", "N", "esc", "q":
+		m.upstakeAll = nil
+		m.state = stateTable
+		return m, m.notify(toastInfo, "Upstake all cancelled")
+	}
+	return m, nil
+}
+
+// renderUpstakeAllConfirm shows the targets of an upstake-all in the chosen
+// scope before it runs.
+func (m model) renderUpstakeAllConfirm() string {
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("150")). // Light grey-green
+		Bold(true).
+		Border(lipgloss.DoubleBorder()).
+		BorderForeground(lipgloss.Color("65")). // Muted green for border
+		Padding(0, 1).
+		Width(m.width - 4)
+	textStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")). // Soft grey-green
+		Padding(0, 2)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("22")). // Dark green
+		Foreground(lipgloss.Color("230")).
+		Bold(true)
+	warningStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true).
+		Padding(0, 2)
+
+	confirm := m.upstakeAll
+	if confirm == nil {
+		return ""
+	}
+	targets, skipped, targetsErr := m.upstakeAllTargets(*confirm)
+
+	var scopes []string
+	for _, scope := range upstakeScopes {
+		label := fmt.Sprintf(" %s (%d) ", scope, len(m.upstakeScopeAddresses(scope)))
+		if scope == confirm.scope {
+			label = selectedStyle.Render(label)
+		}
+		scopes = append(scopes, label)
+	}
+	content := []string{headerStyle.Render("⬆️ UPSTAKE ALL • " + m.currentNetwork), ""}
+	content = append(content, textStyle.Render("Scope: ")+strings.Join(scopes, " "))
+	content = append(content, "")
+
+	fee := int64(0)
+	if m.config != nil {
+		fee = m.config.Config.Networks[m.currentNetwork].appFeeUpokt()
+	}
+	content = append(content, textStyle.Render(fmt.Sprintf("Targets: %d applications, %s %s each, %s %s in total plus %s %s of fees paid by the applications",
+		len(targets), m.formatAmount(confirm.amount), m.unitLabel(),
+		m.formatAmount(confirm.amount*int64(len(targets))), m.unitLabel(),
+		m.formatAmount(fee*int64(len(targets))), m.unitLabel())))
+	if targetsErr != nil {
+		content = append(content, warningStyle.Render(fmt.Sprintf("Nothing to upstake: %v", targetsErr)))
+	}
+	if len(skipped) > 0 {
+		content = append(content, textStyle.Render(fmt.Sprintf("Skipping %d unstaking applications (--include-unstaking to upstake them)", len(skipped))))
+	}
+	unmanaged := 0
+	stakes := make(map[string]int64, len(m.applications))
+	for _, app := range m.applications {
+		stakes[app.Address] = stakeUpokt(app)
+		if slices.Contains(targets, app.Address) && m.isUnmanaged(app) {
+			unmanaged++
+		}
+	}
+	if unmanaged > 0 {
+		content = append(content, warningStyle.Render(fmt.Sprintf("%d targets are not in config; their keys must be in the keyring to sign the upstake", unmanaged)))
+	}
+	content = append(content, "")
+
+	// List the targets that fit, with their stake before and after
+	visible := max(m.height-16, 1)
+	for i, address := range targets {
+		if i == visible && len(targets) > visible {
+			content = append(content, textStyle.Render(fmt.Sprintf("… and %d more", len(targets)-visible)))
+			break
+		}
+		name := address
+		if label := m.appLabel(address); label != "" {
+			name = label + " " + TruncateAddress(address, 20)
+		}
+		stake, loaded := stakes[address]
+		line := fmt.Sprintf("%-45s not loaded, skipped", truncateToWidth(name, 45))
+		if loaded {
+			line = fmt.Sprintf("%-45s %s → %s %s", truncateToWidth(name, 45),
+				m.formatAmount(stake), m.formatAmount(stake+confirm.amount), m.unitLabel())
+		}
+		content = append(content, textStyle.Render(line))
+	}
+	content = append(content, "")
+	if len(targets) == 0 {
+		content = append(content, warningStyle.Render("No applications in this scope • tab to change scope • n or ESC to cancel"))
+	} else {
+		content = append(content, warningStyle.Render("Submit? y to confirm • tab to change scope • n or ESC to cancel"))
+	}
+	return strings.Join(content, "\n")
+}
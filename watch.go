@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Wait for related transactions to settle before refreshing
+	watchRefreshDelay = 2 * time.Second
+	watchMaxBackoff   = time.Minute
+)
+
+// relevantTxMsg is sent when a transaction touching a watched address is included.
+type relevantTxMsg struct {
+	watcher *blockWatcher
+	height  int64
+}
+
+// watchStatusMsg is sent when the websocket connects or disconnects.
+type watchStatusMsg struct {
+	watcher   *blockWatcher
+	connected bool
+}
+
+type watchRefreshMsg struct{}
+
+// blockWatcher subscribes to transaction events over the RPC websocket and
+// reports the ones that involve our bank, gateway or application addresses.
+type blockWatcher struct {
+	network string
+	events  chan tea.Msg
+	cancel  context.CancelFunc
+
+	mu        sync.Mutex
+	addresses map[string]bool
+}
+
+// websocketURL converts a pocketd --node value into its CometBFT websocket URL.
+func websocketURL(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/websocket"
+	return u.String()
+}
+
+// startBlockWatcher connects to the active endpoint of network and keeps the
+// subscription alive (reconnecting with backoff) until stopped.
+func startBlockWatcher(network, fallback string) *blockWatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &blockWatcher{
+		network:   network,
+		events:    make(chan tea.Msg, 16),
+		cancel:    cancel,
+		addresses: make(map[string]bool),
+	}
+	go w.run(ctx, fallback)
+	return w
+}
+
+func (w *blockWatcher) stop() {
+	w.cancel()
+}
+
+// setAddresses replaces the set of addresses whose transactions trigger a refresh.
+func (w *blockWatcher) setAddresses(addresses []string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.addresses = make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		if address != "" {
+			w.addresses[address] = true
+		}
+	}
+}
+
+func (w *blockWatcher) watching(value string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.addresses[strings.Trim(value, `"`)]
+}
+
+func (w *blockWatcher) send(ctx context.Context, msg tea.Msg) {
+	select {
+	case w.events <- msg:
+	case <-ctx.Done():
+	}
+}
+
+func (w *blockWatcher) run(ctx context.Context, fallback string) {
+	defer close(w.events)
+
+	backoff := time.Second
+	for {
+		connected := w.subscribe(ctx, rpcPool.active(w.network, fallback))
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = time.Second
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > watchMaxBackoff {
+			backoff = watchMaxBackoff
+		}
+	}
+}
+
+// subscribe holds a single websocket session, returning when it drops. It
+// reports whether the subscription was established.
+func (w *blockWatcher) subscribe(ctx context.Context, endpoint string) bool {
+	wsURL := websocketURL(endpoint)
+	if wsURL == "" {
+		return false
+	}
+
+	dialer := websocket.Dialer{HandshakeTimeout: probeTimeout}
+	conn, _, err := dialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	// Close the connection when the watcher is stopped to unblock reads
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close()
+		case <-done:
+		}
+	}()
+
+	subscribe := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "subscribe",
+		"id":      1,
+		"params":  map[string]string{"query": "tm.event='Tx'"},
+	}
+	if err := conn.WriteJSON(subscribe); err != nil {
+		return false
+	}
+
+	w.send(ctx, watchStatusMsg{watcher: w, connected: true})
+	defer w.send(ctx, watchStatusMsg{watcher: w, connected: false})
+
+	for {
+		var event struct {
+			Result struct {
+				Events map[string][]string `json:"events"`
+			} `json:"result"`
+		}
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return true
+		}
+		if json.Unmarshal(data, &event) != nil || len(event.Result.Events) == 0 {
+			continue // Subscription acknowledgement or unrelated message
+		}
+		if w.relevant(event.Result.Events) {
+			var height int64
+			if heights := event.Result.Events["tx.height"]; len(heights) > 0 {
+				height, _ = strconv.ParseInt(heights[0], 10, 64)
+			}
+			w.send(ctx, relevantTxMsg{watcher: w, height: height})
+		}
+	}
+}
+
+// relevant reports whether any event attribute references a watched address.
+func (w *blockWatcher) relevant(events map[string][]string) bool {
+	for _, values := range events {
+		for _, value := range values {
+			if w.watching(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// waitForWatchEventCmd waits for the next event from the watcher.
+func waitForWatchEventCmd(w *blockWatcher) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-w.events
+		if !ok {
+			return nil // Watcher stopped
+		}
+		return msg
+	}
+}
+
+// watchEnabled reports whether websocket-driven refresh is turned on.
+func (m model) watchEnabled() bool {
+	return m.config != nil && m.config.Config.WatchBlocks
+}
+
+// restartWatcher stops any running watcher and subscribes on the current network.
+func (m *model) restartWatcher() tea.Cmd {
+	if m.watcher != nil {
+		m.watcher.stop()
+		m.watcher = nil
+		m.watchConnected = false
+	}
+	if !m.watchEnabled() {
+		return nil
+	}
+	network, exists := m.config.Config.Networks[m.currentNetwork]
+	if !exists {
+		return nil
+	}
+	m.watcher = startBlockWatcher(m.currentNetwork, network.RPCEndpoint)
+	m.updateWatchedAddresses()
+	return waitForWatchEventCmd(m.watcher)
+}
+
+// updateWatchedAddresses points the watcher at the addresses currently displayed.
+func (m model) updateWatchedAddresses() {
+	if m.watcher == nil || m.config == nil {
+		return
+	}
+	addresses := []string{m.currentGateway}
+	if network, exists := m.config.Config.Networks[m.currentNetwork]; exists {
+		addresses = append(addresses, network.Bank)
+		addresses = append(addresses, network.Applications...)
+	}
+	for _, app := range m.applications {
+		addresses = append(addresses, app.Address)
+	}
+	m.watcher.setAddresses(addresses)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maxWatchlistRows bounds the watchlist above the table so the table keeps
+// most of the screen.
+const maxWatchlistRows = 8
+
+// pinned reports whether address is on the watchlist of the current network.
+func (m model) pinned(address string) bool {
+	return slices.Contains(m.watchlist[m.currentNetwork], address)
+}
+
+// setPinned adds address to the watchlist of the current network, or removes
+// it, and saves the watchlist right away.
+func (m *model) setPinned(address string, pin bool) {
+	if m.watchlist == nil {
+		m.watchlist = make(map[string][]string)
+	}
+	list := slices.DeleteFunc(slices.Clone(m.watchlist[m.currentNetwork]), func(a string) bool { return a == address })
+	if pin {
+		list = append(list, address)
+	}
+	if len(list) == 0 {
+		delete(m.watchlist, m.currentNetwork)
+	} else {
+		m.watchlist[m.currentNetwork] = list
+	}
+	saveUIState(*m)
+}
+
+// togglePinned pins the application under the cursor to the watchlist, or
+// unpins it.
+func (m model) togglePinned() (model, tea.Cmd) {
+	if m.cursor >= len(m.applications) {
+		return m, nil
+	}
+	address := m.applications[m.cursor].Address
+	pin := !m.pinned(address)
+	m.setPinned(address, pin)
+	if pin {
+		return m, m.notify(toastInfo, "Pinned "+TruncateAddress(address, 13)+" to the watchlist")
+	}
+	return m, m.notify(toastInfo, "Unpinned "+TruncateAddress(address, 13))
+}
+
+// handlePinCommand pins or unpins an application of the current network:
+// "pin <address>" or "unpin <address>".
+func (m model) handlePinCommand(cmd string) (model, tea.Cmd) {
+	parts := strings.Fields(cmd)
+	if len(parts) != 2 {
+		m.err = fmt.Errorf("usage: %s <address>", parts[0])
+		return m, nil
+	}
+	address, pin := parts[1], parts[0] == "pin"
+	if err := validateAddress(address); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if m.pinned(address) == pin {
+		return m, m.notify(toastInfo, fmt.Sprintf("%s is already %sned", TruncateAddress(address, 13), parts[0]))
+	}
+	m.setPinned(address, pin)
+	return m, m.notify(toastInfo, fmt.Sprintf("%sned %s", strings.ToUpper(parts[0][:1])+parts[0][1:], TruncateAddress(address, 13)))
+}
+
+// watchedApplications returns the loaded applications on the watchlist in
+// the order they were pinned, and how many pinned ones are not loaded.
+func (m model) watchedApplications() ([]Application, int) {
+	var apps []Application
+	missing := 0
+	for _, address := range m.watchlist[m.currentNetwork] {
+		if app, ok := m.findApplication(address); ok {
+			apps = append(apps, app)
+		} else {
+			missing++
+		}
+	}
+	return apps, missing
+}
+
+// renderWatchlist renders the pinned applications shown above the table
+// whatever its sort or search, at most maxRows lines. It is empty without
+// pins.
+func (m model) renderWatchlist(columns []layoutColumn, widths []int, maxRows int) []string {
+	if len(m.watchlist[m.currentNetwork]) == 0 || maxRows < 2 {
+		return nil
+	}
+	titleStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("220")). // Yellow
+		Bold(true)
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("236")). // Dark grey background
+		Foreground(lipgloss.Color("150"))  // Light grey-green text
+	normalStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("108")) // Soft grey-green
+
+	apps, missing := m.watchedApplications()
+	title := fmt.Sprintf("📌 WATCHLIST (%d pinned, p to unpin)", len(m.watchlist[m.currentNetwork]))
+	if missing > 0 {
+		title += fmt.Sprintf(" • %d not in the table", missing)
+	}
+	lines := []string{titleStyle.Render(title)}
+	for i, app := range apps {
+		if len(lines) == maxRows-1 && i < len(apps)-1 {
+			lines = append(lines, normalStyle.Render(fmt.Sprintf("… and %d more", len(apps)-i)))
+			break
+		}
+		selected := m.cursor < len(m.applications) && m.applications[m.cursor].Address == app.Address
+		_, rowStyle := m.getStakeStatus(app, selectedStyle, normalStyle, selected)
+		lines = append(lines, m.renderTableRow(columns, widths, app, rowStyle, selected))
+	}
+	return lines
+}